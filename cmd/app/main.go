@@ -1,15 +1,30 @@
 package main
 
 import (
+	"context"
 	"d-payroll/config"
 	"d-payroll/controller/http"
+	"d-payroll/controller/job"
+	emailnotifier "d-payroll/notifier/email"
+	pdfrenderer "d-payroll/renderer/pdf"
 	repository "d-payroll/repository/db"
 	attendanceservice "d-payroll/service/attendance"
+	attendancedeviceservice "d-payroll/service/attendancedevice"
 	authservice "d-payroll/service/auth"
+	disbursementservice "d-payroll/service/disbursement"
+	journalservice "d-payroll/service/journal"
+	loanservice "d-payroll/service/loan"
+	notificationservice "d-payroll/service/notification"
 	overtimeservice "d-payroll/service/overtime"
 	payrollservice "d-payroll/service/payroll"
+	payslipservice "d-payroll/service/payslip"
 	reimbursementservice "d-payroll/service/reimbursement"
+	reportservice "d-payroll/service/report"
+	shiftservice "d-payroll/service/shift"
+	taxservice "d-payroll/service/tax"
+	thrservice "d-payroll/service/thr"
 	userservice "d-payroll/service/user"
+	worklocationservice "d-payroll/service/worklocation"
 )
 
 func main() {
@@ -27,15 +42,40 @@ func main() {
 	reimbursementDB := repository.NewReimbursementDB(db.DB)
 	overtimeDB := repository.NewOvertimeDB(db.DB)
 	payrollDB := repository.NewPayrollDB(db.DB)
+	loanDB := repository.NewLoanDB(db.DB)
+	payslipDB := repository.NewPayslipDB(db.DB)
+	shiftDB := repository.NewShiftDB(db.DB)
+	workLocationDB := repository.NewWorkLocationDB(db.DB)
+	attendanceDeviceDB := repository.NewAttendanceDeviceDB(db.DB)
+
+	// renderers
+
+	payslipRenderer := pdfrenderer.NewPayslipRenderer(config)
+	taxCertificateRenderer := pdfrenderer.NewTaxCertificateRenderer(config)
+
+	// notifiers
+
+	mailer := emailnotifier.NewSmtpMailer(config)
 
 	// services
 
 	userSvc := userservice.NewUserService(userDB)
 	authSvc := authservice.NewAuthService(config, userSvc)
-	attendanceSvc := attendanceservice.NewAttendanceService(attendanceDB)
+	shiftSvc := shiftservice.NewShiftService(config, shiftDB, userSvc)
+	workLocationSvc := worklocationservice.NewWorkLocationService(config, workLocationDB, userSvc)
+	attendanceSvc := attendanceservice.NewAttendanceService(config, attendanceDB, shiftSvc, workLocationSvc, userSvc)
+	attendanceDeviceSvc := attendancedeviceservice.NewAttendanceDeviceService(attendanceDeviceDB, attendanceSvc)
 	reimbursementSvc := reimbursementservice.NewReimbursementService(reimbursementDB)
 	overtimeSvc := overtimeservice.NewOvertimeService(config, overtimeDB, attendanceSvc)
-	payrollSvc := payrollservice.NewPayrollService(config, payrollDB, userSvc, attendanceSvc, reimbursementSvc, overtimeSvc)
+	loanSvc := loanservice.NewLoanService(config, loanDB, userSvc)
+	payrollSvc := payrollservice.NewPayrollService(config, payrollDB, userSvc, attendanceSvc, reimbursementSvc, overtimeSvc, loanSvc)
+	thrSvc := thrservice.NewThrService(userSvc, payrollSvc)
+	payslipSvc := payslipservice.NewPayslipService(config, payslipRenderer, payslipDB, payrollSvc, userSvc)
+	notificationSvc := notificationservice.NewNotificationService(config, mailer, payslipDB, payslipSvc, payrollSvc, userSvc)
+	disbursementSvc := disbursementservice.NewDisbursementService(config, payrollSvc, userSvc)
+	journalSvc := journalservice.NewJournalService(config, payrollSvc, userSvc)
+	reportSvc := reportservice.NewReportService(config, payrollSvc, attendanceSvc, userSvc)
+	taxSvc := taxservice.NewTaxService(config, taxCertificateRenderer, payrollSvc, userSvc)
 
 	// deliveries http
 
@@ -44,9 +84,28 @@ func main() {
 	http.NewUserHttp(httpApp, userSvc)
 	http.NewAuthHttp(httpApp, authSvc)
 	http.NewAttendanceHttp(httpApp, attendanceSvc)
+	http.NewAttendanceDeviceHttp(httpApp, attendanceDeviceSvc)
+	http.NewShiftHttp(httpApp, shiftSvc)
+	http.NewWorkLocationHttp(httpApp, workLocationSvc)
 	http.NewReimbursementHttp(httpApp, reimbursementSvc)
 	http.NewOvertimeHttp(httpApp, overtimeSvc)
-	http.NewPayrollHttp(httpApp, payrollSvc)
+	http.NewPayrollHttp(httpApp, payrollSvc, notificationSvc)
+	http.NewThrHttp(httpApp, thrSvc)
+	http.NewLoanHttp(httpApp, loanSvc)
+	http.NewPayslipHttp(httpApp, payslipSvc)
+	http.NewNotificationHttp(httpApp, notificationSvc)
+	http.NewDisbursementHttp(httpApp, disbursementSvc)
+	http.NewJournalHttp(httpApp, journalSvc)
+	http.NewReportHttp(httpApp, reportSvc)
+	http.NewTaxHttp(httpApp, taxSvc)
+
+	// scheduled jobs
+
+	if err := payslipSvc.FailInterruptedArchiveJobs(context.Background()); err != nil {
+		panic(err)
+	}
+
+	job.NewAttendanceJob(config, attendanceSvc, notificationSvc).Start(context.Background())
 
 	httpApp.Listen()
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	repository "d-payroll/repository/db"
+	attendanceservice "d-payroll/service/attendance"
+	shiftservice "d-payroll/service/shift"
+	userservice "d-payroll/service/user"
+	worklocationservice "d-payroll/service/worklocation"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+)
+
+// import-attendances adds the punches of a biometric device log as attendances, the same way as the import endpoint:
+//
+//	go run cmd/import-attendances/main.go -file attlog.txt -dry-run
+func main() {
+	path := flag.String("file", "", "device log to import, the attlog download or the CSV export")
+	dryRun := flag.Bool("dry-run", false, "report the import without adding the attendances")
+	flag.Parse()
+
+	if *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	config := config.NewConfig()
+	db, err := repository.NewDBHelper(*config)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	userDB := repository.NewUserDB(db.DB)
+	attendanceDB := repository.NewAttendanceDB(db.DB)
+	shiftDB := repository.NewShiftDB(db.DB)
+	workLocationDB := repository.NewWorkLocationDB(db.DB)
+
+	userSvc := userservice.NewUserService(userDB)
+	shiftSvc := shiftservice.NewShiftService(config, shiftDB, userSvc)
+	workLocationSvc := worklocationservice.NewWorkLocationService(config, workLocationDB, userSvc)
+	attendanceSvc := attendanceservice.NewAttendanceService(config, attendanceDB, shiftSvc, workLocationSvc, userSvc)
+
+	report, err := attendanceSvc.ImportDeviceLog(context.Background(), file, *dryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	printReport(report)
+}
+
+func printReport(report *entity.AttendanceImport) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer writer.Flush()
+
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Would import"
+	}
+	fmt.Fprintf(writer, "%s %d attendances from %d punches, %d already recorded\n", verb, len(report.Attendances), report.Punches, report.Duplicates)
+
+	if len(report.UnmatchedPins) > 0 {
+		fmt.Fprintln(writer, "\nUnmatched PINs")
+		fmt.Fprintln(writer, "PIN\tPUNCHES")
+		for _, unmatched := range report.UnmatchedPins {
+			fmt.Fprintf(writer, "%s\t%d\n", unmatched.Pin, unmatched.Punches)
+		}
+	}
+
+	if len(report.Anomalies) > 0 {
+		fmt.Fprintln(writer, "\nAnomalies")
+		fmt.Fprintln(writer, "LINE\tPIN\tPUNCHED AT\tTYPE\tMESSAGE")
+		for _, anomaly := range report.Anomalies {
+			punchedAt := ""
+			if anomaly.PunchedAt != nil {
+				punchedAt = anomaly.PunchedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\n", anomaly.Line, anomaly.Pin, punchedAt, anomaly.Type, anomaly.Message)
+		}
+	}
+}
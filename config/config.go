@@ -1,6 +1,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/spf13/viper"
 )
 
@@ -20,6 +22,10 @@ type AdminUserConfig struct {
 type HttpConfig struct {
 	Port int32
 	Host string
+	// ProxyHeader is the header carrying the client IP set by the load balancer, it is only read on the requests of
+	// the TrustedProxies
+	ProxyHeader    string
+	TrustedProxies []string
 }
 
 type AuthConfig struct {
@@ -28,11 +34,110 @@ type AuthConfig struct {
 
 type OvertimeConfig struct {
 	MaxDurationPerDayMilis int
+	// MaxDurationPerWeekMilis and MaxDurationPerMonthMilis cap the overtimes submitted in a calendar week and month, 0
+	// disables the cap
+	MaxDurationPerWeekMilis  int
+	MaxDurationPerMonthMilis int
+	// PlanToleranceMilis is how far the duration submitted against a plan can be from it to be approved right away
+	PlanToleranceMilis int
+}
+
+// AttendanceConfig is the work schedule the attendances are evaluated against
+type AttendanceConfig struct {
+	// WorkStartTime and WorkEndTime bound the working day, as HH:MM
+	WorkStartTime string
+	WorkEndTime   string
+	// GraceMinutes is tolerated on a late arrival or an early departure before it is reported
+	GraceMinutes int
+	// MaxSessionHours bounds how long after a check-in its checkout is paired, so a session can cross midnight
+	MaxSessionHours int
+	// DefaultTimezone is the IANA zone of the employees without their own, Local is the server zone
+	DefaultTimezone string
+	// AutoClosePolicy is how a session still open AutoCloseCutoffMinutes after the end of its shift is closed, one of
+	// PAY_ZERO, PAY_SCHEDULED or REQUIRE_CORRECTION. The open sessions of the last AutoCloseLookbackDays are looked for
+	// every AutoCloseIntervalMinutes, 0 disables the job
+	AutoClosePolicy          string
+	AutoCloseCutoffMinutes   int
+	AutoCloseLookbackDays    int
+	AutoCloseIntervalMinutes int
+	// Once an employee is late on PenaltyLateDaysThreshold days of a payroll, each late day from then on deducts
+	// PenaltyLateDayAmount from the payslip, 0 disables it
+	PenaltyLateDaysThreshold int
+	PenaltyLateDayAmount     int
 }
 
 type PayrollConfig struct {
 	DayPerMonthProrate    int
 	MaxWorkingMilisPerDay int
+	// PreviewChangeThresholdPercent is the default take home pay change a payroll preview flags
+	PreviewChangeThresholdPercent float64
+}
+
+type LoanConfig struct {
+	// MinTakeHomePay is the floor the loan installments can never push the take home pay below
+	MinTakeHomePay int
+}
+
+// CompanyConfig is printed as the letterhead of the generated documents
+type CompanyConfig struct {
+	Name    string
+	Address string
+	Phone   string
+	Email   string
+	// Locale is the default language of the generated documents, id or en
+	Locale string
+}
+
+type PayslipConfig struct {
+	// OwnerPassword unlocks every protected payslip, the employees open theirs with their own password. When it is not
+	// set each payslip gets a random one nobody knows
+	OwnerPassword string
+	// ArchiveSyncMaxUsers is the largest payroll archived within the request, larger ones are archived in the background
+	ArchiveSyncMaxUsers int
+	// ArchiveDir is where the background archives are written
+	ArchiveDir string
+}
+
+type SmtpConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// MaxAttempts bounds the sends of a payslip email when the failures are transient
+	MaxAttempts     int
+	RetryDelayMilis int
+	// PayslipOnRoll emails the employees their payslips once a payroll is rolled
+	PayslipOnRoll bool
+}
+
+// DisbursementConfig is the company account the take home pays are transferred from
+type DisbursementConfig struct {
+	// CorporateID identifies the company on the bank portal
+	CorporateID        string
+	DebitBankCode      string
+	DebitAccountNumber string
+	DebitAccountName   string
+}
+
+// JournalConfig is the chart of accounts the payroll journal is booked to
+type JournalConfig struct {
+	SalaryExpenseAccount        string
+	OvertimeExpenseAccount      string
+	ReimbursementExpenseAccount string
+	LoanReceivableAccount       string
+	NetSalaryPayableAccount     string
+	// DefaultCostCenter books the expenses of the employees without a cost center
+	DefaultCostCenter string
+	Currency          string
+}
+
+// TaxConfig identifies the company as the withholder on the tax certificates
+type TaxConfig struct {
+	Npwp string
+	// SignerName and SignerNpwp are the authorized signer of the certificates
+	SignerName string
+	SignerNpwp string
 }
 
 type Config struct {
@@ -42,6 +147,15 @@ type Config struct {
 	Auth      *AuthConfig
 	Overtime  *OvertimeConfig
 	Payroll   *PayrollConfig
+	Loan      *LoanConfig
+	Company   *CompanyConfig
+	Payslip   *PayslipConfig
+	Smtp      *SmtpConfig
+
+	Disbursement *DisbursementConfig
+	Journal      *JournalConfig
+	Tax          *TaxConfig
+	Attendance   *AttendanceConfig
 }
 
 // TODO: config error handling and logging
@@ -61,13 +175,22 @@ func NewConfig() *Config {
 		AdminUser: initAdminUser(v),
 		Http:      initHttpConfig(v),
 		Auth:      initAuthConfig(v),
-		Overtime: &OvertimeConfig{
-			MaxDurationPerDayMilis: 1000 * 60 * 60 * 3,
-		},
+		Overtime:  initOvertimeConfig(v),
 		Payroll: &PayrollConfig{
 			DayPerMonthProrate:    22, // preference, could be 20, 30, etc..
 			MaxWorkingMilisPerDay: 8 * 60 * 60 * 1000,
+
+			PreviewChangeThresholdPercent: 20,
 		},
+		Loan:    initLoanConfig(v),
+		Company: initCompanyConfig(v),
+		Payslip: initPayslipConfig(v),
+		Smtp:    initSmtpConfig(v),
+
+		Disbursement: initDisbursementConfig(v),
+		Journal:      initJournalConfig(v),
+		Tax:          initTaxConfig(v),
+		Attendance:   initAttendanceConfig(v),
 	}
 }
 
@@ -100,10 +223,21 @@ func initAdminUser(v *viper.Viper) *AdminUserConfig {
 func initHttpConfig(v *viper.Viper) *HttpConfig {
 	v.SetDefault("HTTP_PORT", "3000")
 	v.SetDefault("HTTP_HOST", "0.0.0.0")
+	v.SetDefault("HTTP_PROXY_HEADER", "")
+	v.SetDefault("HTTP_TRUSTED_PROXIES", "")
+
+	var trustedProxies []string
+	for _, proxy := range strings.Split(v.GetString("HTTP_TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
 
 	return &HttpConfig{
-		Port: v.GetInt32("HTTP_PORT"),
-		Host: v.GetString("HTTP_HOST"),
+		Port:           v.GetInt32("HTTP_PORT"),
+		Host:           v.GetString("HTTP_HOST"),
+		ProxyHeader:    v.GetString("HTTP_PROXY_HEADER"),
+		TrustedProxies: trustedProxies,
 	}
 }
 
@@ -114,3 +248,151 @@ func initAuthConfig(v *viper.Viper) *AuthConfig {
 		JwtSecret: v.GetString("AUTH_JWT_SECRET"),
 	}
 }
+
+func initOvertimeConfig(v *viper.Viper) *OvertimeConfig {
+	// the statutory overtime is 3 hours a day and 14 hours a week
+	v.SetDefault("OVERTIME_MAX_HOURS_PER_WEEK", "14")
+	v.SetDefault("OVERTIME_MAX_HOURS_PER_MONTH", "0")
+	v.SetDefault("OVERTIME_PLAN_TOLERANCE_MINUTES", "15")
+
+	return &OvertimeConfig{
+		MaxDurationPerDayMilis:   1000 * 60 * 60 * 3,
+		MaxDurationPerWeekMilis:  1000 * 60 * 60 * v.GetInt("OVERTIME_MAX_HOURS_PER_WEEK"),
+		MaxDurationPerMonthMilis: 1000 * 60 * 60 * v.GetInt("OVERTIME_MAX_HOURS_PER_MONTH"),
+		PlanToleranceMilis:       1000 * 60 * v.GetInt("OVERTIME_PLAN_TOLERANCE_MINUTES"),
+	}
+}
+
+func initAttendanceConfig(v *viper.Viper) *AttendanceConfig {
+	v.SetDefault("ATTENDANCE_WORK_START_TIME", "09:00")
+	v.SetDefault("ATTENDANCE_WORK_END_TIME", "17:00")
+	v.SetDefault("ATTENDANCE_GRACE_MINUTES", "15")
+	v.SetDefault("ATTENDANCE_MAX_SESSION_HOURS", "16")
+	v.SetDefault("ATTENDANCE_DEFAULT_TIMEZONE", "Local")
+	v.SetDefault("ATTENDANCE_AUTO_CLOSE_POLICY", "REQUIRE_CORRECTION")
+	v.SetDefault("ATTENDANCE_AUTO_CLOSE_CUTOFF_MINUTES", "120")
+	v.SetDefault("ATTENDANCE_AUTO_CLOSE_LOOKBACK_DAYS", "7")
+	v.SetDefault("ATTENDANCE_AUTO_CLOSE_INTERVAL_MINUTES", "15")
+	v.SetDefault("ATTENDANCE_PENALTY_LATE_DAYS_THRESHOLD", "0")
+	v.SetDefault("ATTENDANCE_PENALTY_LATE_DAY_AMOUNT", "0")
+
+	return &AttendanceConfig{
+		WorkStartTime:   v.GetString("ATTENDANCE_WORK_START_TIME"),
+		WorkEndTime:     v.GetString("ATTENDANCE_WORK_END_TIME"),
+		GraceMinutes:    v.GetInt("ATTENDANCE_GRACE_MINUTES"),
+		MaxSessionHours: v.GetInt("ATTENDANCE_MAX_SESSION_HOURS"),
+		DefaultTimezone: v.GetString("ATTENDANCE_DEFAULT_TIMEZONE"),
+
+		AutoClosePolicy:          v.GetString("ATTENDANCE_AUTO_CLOSE_POLICY"),
+		AutoCloseCutoffMinutes:   v.GetInt("ATTENDANCE_AUTO_CLOSE_CUTOFF_MINUTES"),
+		AutoCloseLookbackDays:    v.GetInt("ATTENDANCE_AUTO_CLOSE_LOOKBACK_DAYS"),
+		AutoCloseIntervalMinutes: v.GetInt("ATTENDANCE_AUTO_CLOSE_INTERVAL_MINUTES"),
+
+		PenaltyLateDaysThreshold: v.GetInt("ATTENDANCE_PENALTY_LATE_DAYS_THRESHOLD"),
+		PenaltyLateDayAmount:     v.GetInt("ATTENDANCE_PENALTY_LATE_DAY_AMOUNT"),
+	}
+}
+
+func initLoanConfig(v *viper.Viper) *LoanConfig {
+	v.SetDefault("LOAN_MIN_TAKE_HOME_PAY", "0")
+
+	return &LoanConfig{
+		MinTakeHomePay: v.GetInt("LOAN_MIN_TAKE_HOME_PAY"),
+	}
+}
+
+func initCompanyConfig(v *viper.Viper) *CompanyConfig {
+	v.SetDefault("COMPANY_NAME", "d-payroll")
+	v.SetDefault("COMPANY_ADDRESS", "")
+	v.SetDefault("COMPANY_PHONE", "")
+	v.SetDefault("COMPANY_EMAIL", "")
+	v.SetDefault("COMPANY_LOCALE", "id")
+
+	return &CompanyConfig{
+		Name:    v.GetString("COMPANY_NAME"),
+		Address: v.GetString("COMPANY_ADDRESS"),
+		Phone:   v.GetString("COMPANY_PHONE"),
+		Email:   v.GetString("COMPANY_EMAIL"),
+		Locale:  v.GetString("COMPANY_LOCALE"),
+	}
+}
+
+func initPayslipConfig(v *viper.Viper) *PayslipConfig {
+	v.SetDefault("PAYSLIP_OWNER_PASSWORD", "")
+	v.SetDefault("PAYSLIP_ARCHIVE_SYNC_MAX_USERS", "50")
+	v.SetDefault("PAYSLIP_ARCHIVE_DIR", "tmp/payslip-archives")
+
+	return &PayslipConfig{
+		OwnerPassword:       v.GetString("PAYSLIP_OWNER_PASSWORD"),
+		ArchiveSyncMaxUsers: v.GetInt("PAYSLIP_ARCHIVE_SYNC_MAX_USERS"),
+		ArchiveDir:          v.GetString("PAYSLIP_ARCHIVE_DIR"),
+	}
+}
+
+func initSmtpConfig(v *viper.Viper) *SmtpConfig {
+	v.SetDefault("SMTP_HOST", "localhost")
+	v.SetDefault("SMTP_PORT", "1025")
+	v.SetDefault("SMTP_USERNAME", "")
+	v.SetDefault("SMTP_PASSWORD", "")
+	v.SetDefault("SMTP_FROM", "payroll@d-payroll.local")
+	v.SetDefault("SMTP_MAX_ATTEMPTS", "3")
+	v.SetDefault("SMTP_RETRY_DELAY_MILIS", "2000")
+	v.SetDefault("SMTP_PAYSLIP_ON_ROLL", "false")
+
+	return &SmtpConfig{
+		Host:            v.GetString("SMTP_HOST"),
+		Port:            v.GetInt("SMTP_PORT"),
+		Username:        v.GetString("SMTP_USERNAME"),
+		Password:        v.GetString("SMTP_PASSWORD"),
+		From:            v.GetString("SMTP_FROM"),
+		MaxAttempts:     v.GetInt("SMTP_MAX_ATTEMPTS"),
+		RetryDelayMilis: v.GetInt("SMTP_RETRY_DELAY_MILIS"),
+		PayslipOnRoll:   v.GetBool("SMTP_PAYSLIP_ON_ROLL"),
+	}
+}
+
+func initDisbursementConfig(v *viper.Viper) *DisbursementConfig {
+	v.SetDefault("DISBURSEMENT_CORPORATE_ID", "")
+	v.SetDefault("DISBURSEMENT_DEBIT_BANK_CODE", "BCA")
+	v.SetDefault("DISBURSEMENT_DEBIT_ACCOUNT_NUMBER", "")
+	v.SetDefault("DISBURSEMENT_DEBIT_ACCOUNT_NAME", "")
+
+	return &DisbursementConfig{
+		CorporateID:        v.GetString("DISBURSEMENT_CORPORATE_ID"),
+		DebitBankCode:      v.GetString("DISBURSEMENT_DEBIT_BANK_CODE"),
+		DebitAccountNumber: v.GetString("DISBURSEMENT_DEBIT_ACCOUNT_NUMBER"),
+		DebitAccountName:   v.GetString("DISBURSEMENT_DEBIT_ACCOUNT_NAME"),
+	}
+}
+
+func initJournalConfig(v *viper.Viper) *JournalConfig {
+	v.SetDefault("JOURNAL_SALARY_EXPENSE_ACCOUNT", "6101")
+	v.SetDefault("JOURNAL_OVERTIME_EXPENSE_ACCOUNT", "6102")
+	v.SetDefault("JOURNAL_REIMBURSEMENT_EXPENSE_ACCOUNT", "6103")
+	v.SetDefault("JOURNAL_LOAN_RECEIVABLE_ACCOUNT", "1105")
+	v.SetDefault("JOURNAL_NET_SALARY_PAYABLE_ACCOUNT", "2101")
+	v.SetDefault("JOURNAL_DEFAULT_COST_CENTER", "GENERAL")
+	v.SetDefault("JOURNAL_CURRENCY", "IDR")
+
+	return &JournalConfig{
+		SalaryExpenseAccount:        v.GetString("JOURNAL_SALARY_EXPENSE_ACCOUNT"),
+		OvertimeExpenseAccount:      v.GetString("JOURNAL_OVERTIME_EXPENSE_ACCOUNT"),
+		ReimbursementExpenseAccount: v.GetString("JOURNAL_REIMBURSEMENT_EXPENSE_ACCOUNT"),
+		LoanReceivableAccount:       v.GetString("JOURNAL_LOAN_RECEIVABLE_ACCOUNT"),
+		NetSalaryPayableAccount:     v.GetString("JOURNAL_NET_SALARY_PAYABLE_ACCOUNT"),
+		DefaultCostCenter:           v.GetString("JOURNAL_DEFAULT_COST_CENTER"),
+		Currency:                    v.GetString("JOURNAL_CURRENCY"),
+	}
+}
+
+func initTaxConfig(v *viper.Viper) *TaxConfig {
+	v.SetDefault("TAX_NPWP", "")
+	v.SetDefault("TAX_SIGNER_NAME", "")
+	v.SetDefault("TAX_SIGNER_NPWP", "")
+
+	return &TaxConfig{
+		Npwp:       v.GetString("TAX_NPWP"),
+		SignerName: v.GetString("TAX_SIGNER_NAME"),
+		SignerNpwp: v.GetString("TAX_SIGNER_NPWP"),
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"d-payroll/entity"
 	internalerror "d-payroll/internal-error"
 	attendanceservice "d-payroll/service/attendance"
+	"d-payroll/utils"
 	"errors"
 	"strconv"
 
@@ -26,7 +27,15 @@ func NewAttendanceHttp(http *httpApp, attendanceSvc attendanceservice.Attendance
 
 	attendanceHttp.http.App.Post("/attendances/checkin", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee}), attendanceHttp.Checkin)
 	attendanceHttp.http.App.Post("/attendances/checkout", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee}), attendanceHttp.Checkout)
+	attendanceHttp.http.App.Post("/attendances/break-start", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee}), attendanceHttp.StartBreak)
+	attendanceHttp.http.App.Post("/attendances/break-end", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee}), attendanceHttp.EndBreak)
 	attendanceHttp.http.App.Get("/attendances", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee, entity.UserRoleAdmin}), attendanceHttp.GetAttendancesByUserID)
+	attendanceHttp.http.App.Post("/attendances/import", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), attendanceHttp.ImportDeviceLog)
+
+	attendanceHttp.http.App.Post("/attendance-corrections", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee}), attendanceHttp.RequestCorrection)
+	attendanceHttp.http.App.Get("/attendance-corrections", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee, entity.UserRoleAdmin}), attendanceHttp.GetCorrections)
+	attendanceHttp.http.App.Post("/attendance-corrections/:correctionId/approve", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), attendanceHttp.ApproveCorrection)
+	attendanceHttp.http.App.Post("/attendance-corrections/:correctionId/reject", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), attendanceHttp.RejectCorrection)
 
 	return attendanceHttp
 }
@@ -39,7 +48,19 @@ func (a *AttendanceHttp) Checkin(c *fiber.Ctx) error {
 		return err
 	}
 
-	attendance, err := a.attendanceSvc.Checkin(c.Context(), authPayload.ID)
+	body := new(dto.CheckinBodyDto)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(body); err != nil {
+			return cc.BadRequest("Invalid request body")
+		}
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	attendance, err := a.attendanceSvc.Checkin(c.Context(), authPayload.ID, body.ToAttendanceEvidenceEntity(c.IP()))
 	if err != nil {
 		if errors.Is(err, &internalerror.AttendanceAlreadyCheckedInError{}) {
 			return cc.Conflict("User already checked in")
@@ -48,6 +69,14 @@ func (a *AttendanceHttp) Checkin(c *fiber.Ctx) error {
 		if errors.Is(err, &internalerror.AttendanceWeekendError{}) {
 			return cc.UnprocessableEntity("User cannot checked in on weekend")
 		}
+
+		if errors.Is(err, &internalerror.AttendanceDayOffError{}) {
+			return cc.UnprocessableEntity("User cannot checked in on a day off")
+		}
+
+		if errors.Is(err, &internalerror.AttendanceOutOfAreaError{}) {
+			return cc.Forbidden("User cannot checked in out of the work location")
+		}
 		return err
 	}
 
@@ -84,6 +113,54 @@ func (a *AttendanceHttp) Checkout(c *fiber.Ctx) error {
 
 }
 
+func (a *AttendanceHttp) StartBreak(c *fiber.Ctx) error {
+	cc := customctx.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	attendance, err := a.attendanceSvc.StartBreak(c.Context(), authPayload.ID)
+	if err != nil {
+		if errors.Is(err, &internalerror.AttendanceNotCheckedInError{}) {
+			return cc.UnprocessableEntity("User cannot start a break because it is not checked in")
+		}
+
+		if errors.Is(err, &internalerror.AttendanceAlreadyOnBreakError{}) {
+			return cc.Conflict("User already on break")
+		}
+		return err
+	}
+
+	var response dto.AttendanceResponseDto
+	response.FromUserAttendanceEntity(attendance)
+
+	return cc.Ok(response, nil)
+}
+
+func (a *AttendanceHttp) EndBreak(c *fiber.Ctx) error {
+	cc := customctx.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	attendance, err := a.attendanceSvc.EndBreak(c.Context(), authPayload.ID)
+	if err != nil {
+		if errors.Is(err, &internalerror.AttendanceNotOnBreakError{}) {
+			return cc.UnprocessableEntity("User cannot end a break because it is not on break")
+		}
+		return err
+	}
+
+	var response dto.AttendanceResponseDto
+	response.FromUserAttendanceEntity(attendance)
+
+	return cc.Ok(response, nil)
+}
+
 func (a *AttendanceHttp) GetAttendancesByUserID(c *fiber.Ctx) error {
 	cc := customctx.CustomContext{Ctx: c}
 
@@ -116,3 +193,198 @@ func (a *AttendanceHttp) GetAttendancesByUserID(c *fiber.Ctx) error {
 
 	return cc.Ok(responses, nil)
 }
+
+// ImportDeviceLog imports the log uploaded as the file field, dry_run reports the import without adding anything
+func (a *AttendanceHttp) ImportDeviceLog(c *fiber.Ctx) error {
+	cc := customctx.CustomContext{Ctx: c}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return cc.BadRequest("Expected the device log as the file field")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := a.attendanceSvc.ImportDeviceLog(c.Context(), file, c.QueryBool("dry_run"))
+	if err != nil {
+		var invalidLogError *internalerror.AttendanceLogInvalidError
+		if errors.As(err, &invalidLogError) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.AttendanceImportDto
+	response.FromAttendanceImportEntity(report)
+
+	return cc.Ok(response, nil)
+}
+
+func (a *AttendanceHttp) RequestCorrection(c *fiber.Ctx) error {
+	cc := customctx.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	body := new(dto.RequestAttendanceCorrectionBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	correction, err := a.attendanceSvc.RequestCorrection(c.Context(), body.ToAttendanceCorrectionEntity(authPayload.ID))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Attendance not found")
+		}
+
+		if errors.Is(err, &internalerror.AttendanceCorrectionSupersededError{}) {
+			return cc.Conflict(err.Error())
+		}
+
+		if errors.Is(err, &internalerror.AttendanceCorrectionInFutureError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		if errors.Is(err, &internalerror.AttendanceCorrectionNoOpenSessionError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+		if errors.Is(err, &internalerror.AttendanceCorrectionOverlapError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+		return err
+	}
+
+	var response dto.AttendanceCorrectionResponseDto
+	response.FromAttendanceCorrectionEntity(correction)
+
+	c.Status(fiber.StatusAccepted)
+	return cc.Ok(response, nil)
+}
+
+// GetCorrections lists the corrections, an employee only sees their own
+func (a *AttendanceHttp) GetCorrections(c *fiber.Ctx) error {
+	cc := customctx.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	var userID *uint
+	if userIdParam := c.Query("user_id"); userIdParam != "" {
+		userIdInt, err := strconv.ParseUint(userIdParam, 10, 32)
+		if err != nil {
+			return cc.BadRequest("Invalid user ID query")
+		}
+		converted := uint(userIdInt)
+		userID = &converted
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee {
+		if userID != nil && *userID != authPayload.ID {
+			return cc.Unauthorized("Unauthorized to access other user's attendance corrections")
+		}
+		userID = &authPayload.ID
+	}
+
+	var status *entity.AttendanceCorrectionStatus
+	if statusQuery := c.Query("status"); statusQuery != "" {
+		converted := entity.AttendanceCorrectionStatus(statusQuery)
+		switch converted {
+		case entity.AttendanceCorrectionStatusPending, entity.AttendanceCorrectionStatusApproved, entity.AttendanceCorrectionStatusRejected:
+		default:
+			return cc.BadRequest("Invalid status query")
+		}
+		status = &converted
+	}
+
+	corrections, err := a.attendanceSvc.GetCorrections(c.Context(), userID, status)
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.AttendanceCorrectionResponseDto, len(corrections))
+	for i, correction := range corrections {
+		var response dto.AttendanceCorrectionResponseDto
+		response.FromAttendanceCorrectionEntity(correction)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (a *AttendanceHttp) ApproveCorrection(c *fiber.Ctx) error {
+	return a.reviewCorrection(c, true)
+}
+
+func (a *AttendanceHttp) RejectCorrection(c *fiber.Ctx) error {
+	return a.reviewCorrection(c, false)
+}
+
+func (a *AttendanceHttp) reviewCorrection(c *fiber.Ctx, approved bool) error {
+	cc := customctx.CustomContext{Ctx: c}
+
+	correctionId := c.Params("correctionId")
+	correctionIdInt, err := strconv.ParseUint(correctionId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid correction ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	body := new(dto.ReviewAttendanceCorrectionBodyDto)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(body); err != nil {
+			return cc.BadRequest("Invalid request body")
+		}
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	correction, err := a.attendanceSvc.ReviewCorrection(c.Context(), uint(correctionIdInt), authPayload.ID, approved, body.Note)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Attendance correction not found")
+		}
+		if errors.Is(err, &internalerror.AttendanceCorrectionSelfReviewError{}) {
+			return cc.Forbidden(err.Error())
+		}
+		if errors.Is(err, &internalerror.AttendanceCorrectionReviewedError{}) {
+			return cc.Conflict(err.Error())
+		}
+		if errors.Is(err, &internalerror.AttendanceCorrectionSupersededError{}) {
+			return cc.Conflict(err.Error())
+		}
+		if errors.Is(err, &internalerror.AttendanceCorrectionNoOpenSessionError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+		if errors.Is(err, &internalerror.AttendanceCorrectionOverlapError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.AttendanceCorrectionResponseDto
+	response.FromAttendanceCorrectionEntity(correction)
+
+	return cc.Ok(response, nil)
+}
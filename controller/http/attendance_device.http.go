@@ -0,0 +1,143 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	attendancedeviceservice "d-payroll/service/attendancedevice"
+	"d-payroll/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AttendanceDeviceHttp struct {
+	http                *httpApp
+	attendanceDeviceSvc attendancedeviceservice.AttendanceDeviceService
+}
+
+func NewAttendanceDeviceHttp(http *httpApp, attendanceDeviceSvc attendancedeviceservice.AttendanceDeviceService) {
+	attendanceDeviceHttp := &AttendanceDeviceHttp{
+		http:                http,
+		attendanceDeviceSvc: attendanceDeviceSvc,
+	}
+
+	attendanceDeviceHttp.http.App.Post("/attendance-devices", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), attendanceDeviceHttp.RegisterDevice)
+	attendanceDeviceHttp.http.App.Get("/attendance-devices", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), attendanceDeviceHttp.GetDevices)
+	attendanceDeviceHttp.http.App.Post("/attendance-devices/:deviceId/api-key", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), attendanceDeviceHttp.RegenerateApiKey)
+
+	// the devices authenticate with their own API key instead of a user token
+	attendanceDeviceHttp.http.App.Post("/attendance-devices/push", attendanceDeviceHttp.PushRecords)
+}
+
+func (a *AttendanceDeviceHttp) RegisterDevice(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	body := new(dto.RegisterAttendanceDeviceBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err := utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	device, err := a.attendanceDeviceSvc.RegisterDevice(c.Context(), body.ToAttendanceDeviceEntity())
+	if err != nil {
+		if errors.Is(err, &internalerror.AttendanceDeviceSerialTakenError{}) {
+			return cc.Conflict(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.AttendanceDeviceResponseDto
+	response.FromAttendanceDeviceEntity(device)
+
+	return cc.Ok(response, nil)
+}
+
+func (a *AttendanceDeviceHttp) GetDevices(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	devices, err := a.attendanceDeviceSvc.GetDevices(c.Context())
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.AttendanceDeviceResponseDto, len(devices))
+	for i, device := range devices {
+		var response dto.AttendanceDeviceResponseDto
+		response.FromAttendanceDeviceEntity(device)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+// RegenerateApiKey replaces the API key of a device, e.g. when the key leaked
+func (a *AttendanceDeviceHttp) RegenerateApiKey(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	deviceId := c.Params("deviceId")
+	deviceIdInt, err := strconv.ParseUint(deviceId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid device ID param")
+	}
+
+	device, err := a.attendanceDeviceSvc.RegenerateApiKey(c.Context(), uint(deviceIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Attendance device not found")
+		}
+
+		return err
+	}
+
+	var response dto.AttendanceDeviceResponseDto
+	response.FromAttendanceDeviceEntity(device)
+
+	return cc.Ok(response, nil)
+}
+
+// PushRecords imports the punches a device pushes, the device sends its API key in the X-Device-Key header
+func (a *AttendanceDeviceHttp) PushRecords(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	device, err := a.attendanceDeviceSvc.AuthenticateDevice(c.Context(), c.Get("X-Device-Key"))
+	if err != nil {
+		if errors.Is(err, &internalerror.AttendanceDeviceKeyInvalidError{}) {
+			return cc.Unauthorized("Missing or invalid device key")
+		}
+
+		return err
+	}
+
+	body := new(dto.PushAttendanceDeviceRecordsBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	report, err := a.attendanceDeviceSvc.PushRecords(c.Context(), device, body.SerialNumber, body.ToAttendanceDeviceRecordEntities())
+	if err != nil {
+		if errors.Is(err, &internalerror.AttendanceDeviceSerialMismatchError{}) {
+			return cc.Forbidden(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.AttendanceImportDto
+	response.FromAttendanceImportEntity(report)
+
+	return cc.Ok(response, nil)
+}
@@ -24,10 +24,14 @@ func (c *CustomContext) Ok(data any, msg *string) error {
 }
 
 func (c *CustomContext) jsonError(status int, msg string) error {
+	return c.jsonErrorWithData(status, msg, nil)
+}
+
+func (c *CustomContext) jsonErrorWithData(status int, msg string, data any) error {
 	return c.Ctx.Status(status).JSON(entity.HttpResponse{
 		Success: false,
 		Message: msg,
-		Data:    nil,
+		Data:    data,
 	})
 }
 
@@ -51,6 +55,11 @@ func (c *CustomContext) UnprocessableEntity(msg string) error {
 	return c.jsonError(fiber.StatusUnprocessableEntity, msg)
 }
 
+// UnprocessableEntityWithData also returns what made the request unprocessable
+func (c *CustomContext) UnprocessableEntityWithData(msg string, data any) error {
+	return c.jsonErrorWithData(fiber.StatusUnprocessableEntity, msg, data)
+}
+
 func (c *CustomContext) Conflict(msg string) error {
 	return c.jsonError(fiber.StatusConflict, msg)
 }
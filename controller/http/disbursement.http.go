@@ -0,0 +1,84 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	disbursementservice "d-payroll/service/disbursement"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DisbursementHttp struct {
+	http            *httpApp
+	disbursementSvc disbursementservice.DisbursementService
+}
+
+func NewDisbursementHttp(http *httpApp, disbursementSvc disbursementservice.DisbursementService) {
+	disbursementHttp := &DisbursementHttp{
+		http:            http,
+		disbursementSvc: disbursementSvc,
+	}
+
+	disbursementHttp.http.App.Get("/payrolls/:payrollId/disbursement", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), disbursementHttp.Disbursement)
+}
+
+func (d *DisbursementHttp) Disbursement(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	format := entity.DisbursementFormat(c.Query("format", string(entity.DisbursementFormatCsv)))
+
+	var executedAt *time.Time
+	if executedAtQuery := c.Query("executed_at"); executedAtQuery != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", executedAtQuery, time.Local)
+		if err != nil {
+			return cc.BadRequest("Invalid executed at query, expected YYYY-MM-DD")
+		}
+		executedAt = &parsed
+	}
+
+	file, err := d.disbursementSvc.RenderDisbursement(c.Context(), uint(payrollIdInt), format, executedAt)
+	if err != nil {
+		var issuesError *internalerror.DisbursementIssuesError
+		if errors.As(err, &issuesError) {
+			issues := make([]*dto.DisbursementIssueDto, len(issuesError.Issues))
+			for i, issue := range issuesError.Issues {
+				issues[i] = &dto.DisbursementIssueDto{}
+				issues[i].FromDisbursementIssueEntity(issue)
+			}
+
+			return cc.UnprocessableEntityWithData("Some employees cannot be paid", issues)
+		}
+
+		if errors.Is(err, &internalerror.DisbursementFormatNotSupportedError{}) {
+			return cc.BadRequest("Invalid format query, expected csv, bca, mandiri or pain001")
+		}
+
+		if errors.Is(err, &internalerror.PayrollNotRolledError{}) {
+			return cc.UnprocessableEntity("Payroll is not rolled yet")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+
+	return c.Send(file.Content)
+}
@@ -5,16 +5,186 @@ import (
 	"time"
 )
 
+// CheckinBodyDto is the evidence of where a check-in is made from, the IP address is read from the request
+type CheckinBodyDto struct {
+	Latitude  *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	WifiBSSID *string  `json:"wifi_bssid" validate:"omitempty,mac"`
+}
+
+func (c *CheckinBodyDto) ToAttendanceEvidenceEntity(ipAddress string) *entity.AttendanceEvidence {
+	evidence := &entity.AttendanceEvidence{
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
+		WifiBSSID: c.WifiBSSID,
+	}
+	if ipAddress != "" {
+		evidence.IPAddress = &ipAddress
+	}
+	return evidence
+}
+
 type AttendanceResponseDto struct {
-	Id        *uint      `json:"id"`
-	Type      string     `json:"type"`
-	CreatedAt *time.Time `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
+	Id                       *uint      `json:"id"`
+	Type                     string     `json:"type"`
+	Latitude                 *float64   `json:"latitude,omitempty"`
+	Longitude                *float64   `json:"longitude,omitempty"`
+	WifiBSSID                *string    `json:"wifi_bssid,omitempty"`
+	IPAddress                *string    `json:"ip_address,omitempty"`
+	WorkLocationID           *uint      `json:"work_location_id,omitempty"`
+	IsOutOfArea              bool       `json:"is_out_of_area"`
+	IsRemote                 bool       `json:"is_remote"`
+	CorrectionID             *uint      `json:"correction_id,omitempty"`
+	SupersededByCorrectionID *uint      `json:"superseded_by_correction_id,omitempty"`
+	AutoClosePolicy          *string    `json:"auto_close_policy,omitempty"`
+	DeviceID                 *uint      `json:"device_id,omitempty"`
+	CreatedAt                *time.Time `json:"created_at"`
+	UpdatedAt                *time.Time `json:"updated_at"`
 }
 
 func (a *AttendanceResponseDto) FromUserAttendanceEntity(attendance *entity.UserAttendance) {
 	a.Id = attendance.ID
 	a.Type = string(attendance.Type)
+	if attendance.Evidence != nil {
+		a.Latitude = attendance.Evidence.Latitude
+		a.Longitude = attendance.Evidence.Longitude
+		a.WifiBSSID = attendance.Evidence.WifiBSSID
+		a.IPAddress = attendance.Evidence.IPAddress
+	}
+	a.WorkLocationID = attendance.WorkLocationID
+	a.IsOutOfArea = attendance.IsOutOfArea
+	a.IsRemote = attendance.IsRemote
+	a.CorrectionID = attendance.CorrectionID
+	a.SupersededByCorrectionID = attendance.SupersededByCorrectionID
+	if attendance.AutoClosePolicy != nil {
+		policy := string(*attendance.AutoClosePolicy)
+		a.AutoClosePolicy = &policy
+	}
+	a.DeviceID = attendance.DeviceID
 	a.CreatedAt = attendance.CreatedAt
 	a.UpdatedAt = attendance.UpdatedAt
 }
+
+// RequestAttendanceCorrectionBodyDto is a correction of the attendances of the employee, a wrong time names the
+// attendance it corrects
+type RequestAttendanceCorrectionBodyDto struct {
+	Type         string    `json:"type" validate:"required,oneof=MISSING_CHECKIN MISSING_CHECKOUT WRONG_TIME"`
+	AttendanceID *uint     `json:"attendance_id" validate:"required_if=Type WRONG_TIME"`
+	CorrectedAt  time.Time `json:"corrected_at" validate:"required"`
+	Reason       string    `json:"reason" validate:"required,max=255"`
+}
+
+func (r *RequestAttendanceCorrectionBodyDto) ToAttendanceCorrectionEntity(userID uint) *entity.AttendanceCorrection {
+	return &entity.AttendanceCorrection{
+		UserID:       userID,
+		Type:         entity.AttendanceCorrectionType(r.Type),
+		AttendanceID: r.AttendanceID,
+		CorrectedAt:  r.CorrectedAt,
+		Reason:       r.Reason,
+	}
+}
+
+type ReviewAttendanceCorrectionBodyDto struct {
+	Note *string `json:"note" validate:"omitempty,max=255"`
+}
+
+type AttendanceCorrectionResponseDto struct {
+	ID               *uint      `json:"id"`
+	UserID           uint       `json:"user_id"`
+	Type             string     `json:"type"`
+	AttendanceID     *uint      `json:"attendance_id"`
+	CorrectedAt      time.Time  `json:"corrected_at"`
+	Reason           string     `json:"reason"`
+	Status           string     `json:"status"`
+	ReviewedByUserID *uint      `json:"reviewed_by_user_id"`
+	ReviewedAt       *time.Time `json:"reviewed_at"`
+	ReviewNote       *string    `json:"review_note"`
+	CreatedAt        *time.Time `json:"created_at"`
+	UpdatedAt        *time.Time `json:"updated_at"`
+}
+
+func (a *AttendanceCorrectionResponseDto) FromAttendanceCorrectionEntity(correction *entity.AttendanceCorrection) {
+	a.ID = correction.ID
+	a.UserID = correction.UserID
+	a.Type = string(correction.Type)
+	a.AttendanceID = correction.AttendanceID
+	a.CorrectedAt = correction.CorrectedAt
+	a.Reason = correction.Reason
+	a.Status = string(correction.Status)
+	a.ReviewedByUserID = correction.ReviewedByUserID
+	a.ReviewedAt = correction.ReviewedAt
+	a.ReviewNote = correction.ReviewNote
+	a.CreatedAt = correction.CreatedAt
+	a.UpdatedAt = correction.UpdatedAt
+}
+
+type AttendanceImportAnomalyDto struct {
+	Line      int        `json:"line"`
+	Pin       string     `json:"pin,omitempty"`
+	UserID    *uint      `json:"user_id,omitempty"`
+	PunchedAt *time.Time `json:"punched_at,omitempty"`
+	Type      string     `json:"type"`
+	Message   string     `json:"message,omitempty"`
+}
+
+type AttendanceImportUnmatchedPinDto struct {
+	Pin     string `json:"pin"`
+	Punches int    `json:"punches"`
+}
+
+type ImportedAttendanceDto struct {
+	// Id is null on a dry run, nothing is added
+	Id        *uint      `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Type      string     `json:"type"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type AttendanceImportDto struct {
+	DryRun        bool                               `json:"dry_run"`
+	Punches       int                                `json:"punches"`
+	Imported      int                                `json:"imported"`
+	Duplicates    int                                `json:"duplicates"`
+	UnmatchedPins []*AttendanceImportUnmatchedPinDto `json:"unmatched_pins"`
+	Anomalies     []*AttendanceImportAnomalyDto      `json:"anomalies"`
+	Attendances   []*ImportedAttendanceDto           `json:"attendances"`
+}
+
+func (a *AttendanceImportDto) FromAttendanceImportEntity(report *entity.AttendanceImport) {
+	a.DryRun = report.DryRun
+	a.Punches = report.Punches
+	a.Imported = len(report.Attendances)
+	a.Duplicates = report.Duplicates
+
+	a.UnmatchedPins = make([]*AttendanceImportUnmatchedPinDto, len(report.UnmatchedPins))
+	for i, unmatched := range report.UnmatchedPins {
+		a.UnmatchedPins[i] = &AttendanceImportUnmatchedPinDto{
+			Pin:     unmatched.Pin,
+			Punches: unmatched.Punches,
+		}
+	}
+
+	a.Anomalies = make([]*AttendanceImportAnomalyDto, len(report.Anomalies))
+	for i, anomaly := range report.Anomalies {
+		a.Anomalies[i] = &AttendanceImportAnomalyDto{
+			Line:      anomaly.Line,
+			Pin:       anomaly.Pin,
+			UserID:    anomaly.UserID,
+			PunchedAt: anomaly.PunchedAt,
+			Type:      string(anomaly.Type),
+			Message:   anomaly.Message,
+		}
+	}
+
+	a.Attendances = make([]*ImportedAttendanceDto, len(report.Attendances))
+	for i, attendance := range report.Attendances {
+		a.Attendances[i] = &ImportedAttendanceDto{
+			UserID:    attendance.UserID,
+			Type:      string(attendance.Type),
+			CreatedAt: attendance.CreatedAt,
+		}
+		if !report.DryRun {
+			a.Attendances[i].Id = attendance.ID
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"d-payroll/entity"
+	"time"
+)
+
+type RegisterAttendanceDeviceBodyDto struct {
+	SerialNumber string `json:"serial_number" validate:"required,max=64"`
+	Name         string `json:"name" validate:"required,max=100"`
+}
+
+func (r *RegisterAttendanceDeviceBodyDto) ToAttendanceDeviceEntity() *entity.AttendanceDevice {
+	return &entity.AttendanceDevice{
+		SerialNumber: r.SerialNumber,
+		Name:         r.Name,
+	}
+}
+
+type AttendanceDeviceResponseDto struct {
+	ID           *uint  `json:"id"`
+	SerialNumber string `json:"serial_number"`
+	Name         string `json:"name"`
+	// ApiKey is only returned when the key is generated
+	ApiKey     *string    `json:"api_key,omitempty"`
+	LastPushAt *time.Time `json:"last_push_at"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+}
+
+func (a *AttendanceDeviceResponseDto) FromAttendanceDeviceEntity(device *entity.AttendanceDevice) {
+	a.ID = device.ID
+	a.SerialNumber = device.SerialNumber
+	a.Name = device.Name
+	a.ApiKey = device.ApiKey
+	a.LastPushAt = device.LastPushAt
+	a.CreatedAt = device.CreatedAt
+	a.UpdatedAt = device.UpdatedAt
+}
+
+type AttendanceDeviceRecordDto struct {
+	Pin string `json:"pin" validate:"required,max=24"`
+	// PunchedAt is the wall clock of the device, e.g. 2025-06-16 08:55:12
+	PunchedAt string `json:"punched_at" validate:"required"`
+	State     string `json:"state"`
+}
+
+type PushAttendanceDeviceRecordsBodyDto struct {
+	SerialNumber string                       `json:"serial_number" validate:"required"`
+	Records      []*AttendanceDeviceRecordDto `json:"records" validate:"required,min=1,max=1000,dive,required"`
+}
+
+func (p *PushAttendanceDeviceRecordsBodyDto) ToAttendanceDeviceRecordEntities() []*entity.AttendanceDeviceRecord {
+	records := make([]*entity.AttendanceDeviceRecord, len(p.Records))
+	for i, record := range p.Records {
+		records[i] = &entity.AttendanceDeviceRecord{
+			Pin:       record.Pin,
+			PunchedAt: record.PunchedAt,
+			State:     record.State,
+		}
+	}
+	return records
+}
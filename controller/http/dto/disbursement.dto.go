@@ -0,0 +1,15 @@
+package dto
+
+import "d-payroll/entity"
+
+type DisbursementIssueDto struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Reason   string `json:"reason"`
+}
+
+func (d *DisbursementIssueDto) FromDisbursementIssueEntity(issue *entity.DisbursementIssue) {
+	d.UserID = issue.UserID
+	d.Username = issue.Username
+	d.Reason = issue.Reason
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"d-payroll/entity"
+	"time"
+)
+
+type CreateLoanBodyDto struct {
+	UserID           uint      `json:"user_id" validate:"required"`
+	Type             string    `json:"type" validate:"required,oneof=LOAN ADVANCE"`
+	Description      string    `json:"description" validate:"required"`
+	Principal        int       `json:"principal" validate:"required,min=1"`
+	InstallmentCount int       `json:"installment_count" validate:"required,min=1"`
+	StartedAt        time.Time `json:"started_at" validate:"required"`
+}
+
+func (c *CreateLoanBodyDto) ToUserLoanEntity(createdByUserID uint) *entity.UserLoan {
+	return &entity.UserLoan{
+		UserID:           c.UserID,
+		Type:             entity.LoanType(c.Type),
+		Description:      c.Description,
+		Principal:        c.Principal,
+		InstallmentCount: c.InstallmentCount,
+		StartedAt:        c.StartedAt,
+		CreatedByUserID:  &createdByUserID,
+	}
+}
+
+type LoanResponseDto struct {
+	ID                *uint      `json:"id"`
+	UserID            uint       `json:"user_id"`
+	Type              string     `json:"type"`
+	Description       string     `json:"description"`
+	Principal         int        `json:"principal"`
+	InstallmentCount  int        `json:"installment_count"`
+	InstallmentAmount int        `json:"installment_amount"`
+	RemainingBalance  int        `json:"remaining_balance"`
+	StartedAt         time.Time  `json:"started_at"`
+	CreatedByUserID   *uint      `json:"created_by_user_id"`
+	CreatedAt         *time.Time `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+}
+
+func (l *LoanResponseDto) FromUserLoanEntity(loan *entity.UserLoan) {
+	l.ID = loan.ID
+	l.UserID = loan.UserID
+	l.Type = string(loan.Type)
+	l.Description = loan.Description
+	l.Principal = loan.Principal
+	l.InstallmentCount = loan.InstallmentCount
+	l.InstallmentAmount = loan.InstallmentAmount
+	l.RemainingBalance = loan.RemainingBalance
+	l.StartedAt = loan.StartedAt
+	l.CreatedByUserID = loan.CreatedByUserID
+	l.CreatedAt = loan.CreatedAt
+	l.UpdatedAt = loan.UpdatedAt
+}
+
+type LoanInstallmentDto struct {
+	ID        *uint     `json:"id"`
+	PayrollID uint      `json:"payroll_id"`
+	Amount    int       `json:"amount"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+func (l *LoanInstallmentDto) FromLoanInstallmentEntity(installment *entity.LoanInstallment) {
+	l.ID = installment.ID
+	l.PayrollID = installment.PayrollID
+	l.Amount = installment.Amount
+	l.PaidAt = installment.PaidAt
+}
+
+type LoanStatementDto struct {
+	Loan                  *LoanResponseDto      `json:"loan"`
+	Installments          []*LoanInstallmentDto `json:"installments"`
+	PaidAmount            int                   `json:"paid_amount"`
+	RemainingInstallments int                   `json:"remaining_installments"`
+}
+
+func (l *LoanStatementDto) FromLoanStatementEntity(statement *entity.LoanStatement) {
+	l.Loan = &LoanResponseDto{}
+	l.Loan.FromUserLoanEntity(statement.Loan)
+	l.PaidAmount = statement.PaidAmount
+	l.RemainingInstallments = statement.RemainingInstallments
+
+	installments := make([]*LoanInstallmentDto, len(statement.Installments))
+	for i, installment := range statement.Installments {
+		dto := &LoanInstallmentDto{}
+		dto.FromLoanInstallmentEntity(installment)
+		installments[i] = dto
+	}
+	l.Installments = installments
+}
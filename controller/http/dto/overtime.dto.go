@@ -9,20 +9,24 @@ type CreateOvertimeBodyDto struct {
 	Description   string    `json:"description" validate:"required"`
 	OvertimeAt    time.Time `json:"overtime_at" validate:"required"`
 	DurationMilis int       `json:"duration_milis" validate:"required,min=1"`
+	// OvertimePlanID is the plan the overtime was worked against, if any
+	OvertimePlanID *uint `json:"overtime_plan_id"`
 }
 
 func (c *CreateOvertimeBodyDto) ToOvertimeEntity(userID uint) *entity.UserOvertime {
 	return &entity.UserOvertime{
-		UserID:        userID,
-		Description:   c.Description,
-		OvertimeAt:    c.OvertimeAt,
-		DurationMilis: c.DurationMilis,
+		UserID:         userID,
+		OvertimePlanID: c.OvertimePlanID,
+		Description:    c.Description,
+		OvertimeAt:     c.OvertimeAt,
+		DurationMilis:  c.DurationMilis,
 	}
 }
 
 type OvertimeResponseDto struct {
 	ID              *uint      `json:"id"`
 	UserID          uint       `json:"user_id"`
+	OvertimePlanID  *uint      `json:"overtime_plan_id"`
 	Description     string     `json:"description"`
 	OvertimeAt      time.Time  `json:"overtime_at"`
 	DurationMilis   int        `json:"duration_milis"`
@@ -35,6 +39,7 @@ type OvertimeResponseDto struct {
 func (o *OvertimeResponseDto) FromOvertimeEntity(overtime *entity.UserOvertime) {
 	o.ID = overtime.ID
 	o.UserID = overtime.UserID
+	o.OvertimePlanID = overtime.OvertimePlanID
 	o.Description = overtime.Description
 	o.OvertimeAt = overtime.OvertimeAt
 	o.DurationMilis = overtime.DurationMilis
@@ -43,3 +48,44 @@ func (o *OvertimeResponseDto) FromOvertimeEntity(overtime *entity.UserOvertime)
 	o.CreatedAt = overtime.CreatedAt
 	o.UpdatedAt = overtime.UpdatedAt
 }
+
+type CreateOvertimePlanBodyDto struct {
+	UserID        uint      `json:"user_id" validate:"required"`
+	Description   string    `json:"description" validate:"required"`
+	PlannedAt     time.Time `json:"planned_at" validate:"required"`
+	DurationMilis int       `json:"duration_milis" validate:"required,min=1"`
+}
+
+func (c *CreateOvertimePlanBodyDto) ToOvertimePlanEntity(createdByUserID uint) *entity.OvertimePlan {
+	return &entity.OvertimePlan{
+		UserID:          c.UserID,
+		Description:     c.Description,
+		PlannedAt:       c.PlannedAt,
+		DurationMilis:   c.DurationMilis,
+		CreatedByUserID: createdByUserID,
+	}
+}
+
+type OvertimePlanResponseDto struct {
+	ID              *uint      `json:"id"`
+	UserID          uint       `json:"user_id"`
+	Description     string     `json:"description"`
+	PlannedAt       time.Time  `json:"planned_at"`
+	DurationMilis   int        `json:"duration_milis"`
+	CreatedByUserID uint       `json:"created_by_user_id"`
+	OvertimeID      *uint      `json:"overtime_id"`
+	CreatedAt       *time.Time `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+func (o *OvertimePlanResponseDto) FromOvertimePlanEntity(plan *entity.OvertimePlan) {
+	o.ID = plan.ID
+	o.UserID = plan.UserID
+	o.Description = plan.Description
+	o.PlannedAt = plan.PlannedAt
+	o.DurationMilis = plan.DurationMilis
+	o.CreatedByUserID = plan.CreatedByUserID
+	o.OvertimeID = plan.OvertimeID
+	o.CreatedAt = plan.CreatedAt
+	o.UpdatedAt = plan.UpdatedAt
+}
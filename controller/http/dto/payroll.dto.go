@@ -7,6 +7,7 @@ import (
 
 type CreatePayrollBodyDto struct {
 	Name      string    `json:"name" validate:"required"`
+	Type      string    `json:"type" validate:"omitempty,oneof=REGULAR BONUS THR CORRECTION"`
 	StartedAt time.Time `json:"started_at" validate:"required"`
 	EndedAt   time.Time `json:"ended_at" validate:"required"`
 }
@@ -14,6 +15,7 @@ type CreatePayrollBodyDto struct {
 func (c *CreatePayrollBodyDto) ToPayrollEntity(userID uint) *entity.Payroll {
 	return &entity.Payroll{
 		Name:            c.Name,
+		Type:            entity.PayrollType(c.Type),
 		StartedAt:       c.StartedAt,
 		EndedAt:         c.EndedAt,
 		CreatedByUserID: &userID,
@@ -23,6 +25,7 @@ func (c *CreatePayrollBodyDto) ToPayrollEntity(userID uint) *entity.Payroll {
 type PayrollResponseDto struct {
 	ID              *uint      `json:"id"`
 	Name            string     `json:"name"`
+	Type            string     `json:"type"`
 	StartedAt       time.Time  `json:"started_at"`
 	EndedAt         time.Time  `json:"ended_at"`
 	IsRolled        *bool      `json:"is_rolled"`
@@ -35,6 +38,7 @@ type PayrollResponseDto struct {
 func (p *PayrollResponseDto) FromPayrollEntity(payroll *entity.Payroll) {
 	p.ID = payroll.ID
 	p.Name = payroll.Name
+	p.Type = string(payroll.Type)
 	p.StartedAt = payroll.StartedAt
 	p.EndedAt = payroll.EndedAt
 	p.IsRolled = payroll.IsRolled
@@ -43,3 +47,160 @@ func (p *PayrollResponseDto) FromPayrollEntity(payroll *entity.Payroll) {
 	p.CreatedAt = payroll.CreatedAt
 	p.UpdatedAt = payroll.UpdatedAt
 }
+
+type AddPayrollUsersBodyDto struct {
+	UserIDs []uint `json:"user_ids" validate:"required,min=1"`
+}
+
+type CreatePayrollEarningBodyDto struct {
+	UserID      uint   `json:"user_id" validate:"required"`
+	Description string `json:"description" validate:"required"`
+	Amount      int    `json:"amount" validate:"required,min=1"`
+}
+
+func (c *CreatePayrollEarningBodyDto) ToPayrollEarningEntity(payrollID uint, createdByUserID uint) *entity.PayrollEarning {
+	return &entity.PayrollEarning{
+		PayrollID:       payrollID,
+		UserID:          c.UserID,
+		Description:     c.Description,
+		Amount:          c.Amount,
+		CreatedByUserID: &createdByUserID,
+	}
+}
+
+type PayrollEarningResponseDto struct {
+	ID              *uint      `json:"id"`
+	PayrollID       uint       `json:"payroll_id"`
+	UserID          uint       `json:"user_id"`
+	Description     string     `json:"description"`
+	Amount          int        `json:"amount"`
+	CreatedByUserID *uint      `json:"created_by_user_id"`
+	CreatedAt       *time.Time `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+func (p *PayrollEarningResponseDto) FromPayrollEarningEntity(earning *entity.PayrollEarning) {
+	p.ID = earning.ID
+	p.PayrollID = earning.PayrollID
+	p.UserID = earning.UserID
+	p.Description = earning.Description
+	p.Amount = earning.Amount
+	p.CreatedByUserID = earning.CreatedByUserID
+	p.CreatedAt = earning.CreatedAt
+	p.UpdatedAt = earning.UpdatedAt
+}
+
+type PayrollRetroAdjustmentResponseDto struct {
+	ID              *uint      `json:"id"`
+	PayrollID       uint       `json:"payroll_id"`
+	SourcePayrollID uint       `json:"source_payroll_id"`
+	UserID          uint       `json:"user_id"`
+	Description     string     `json:"description"`
+	Amount          int        `json:"amount"`
+	CreatedAt       *time.Time `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+func (p *PayrollRetroAdjustmentResponseDto) FromPayrollRetroAdjustmentEntity(adjustment *entity.PayrollRetroAdjustment) {
+	p.ID = adjustment.ID
+	p.PayrollID = adjustment.PayrollID
+	p.SourcePayrollID = adjustment.SourcePayrollID
+	p.UserID = adjustment.UserID
+	p.Description = adjustment.Description
+	p.Amount = adjustment.Amount
+	p.CreatedAt = adjustment.CreatedAt
+	p.UpdatedAt = adjustment.UpdatedAt
+}
+
+type UserYearToDateResponseDto struct {
+	UserID           uint `json:"user_id"`
+	Year             int  `json:"year"`
+	PayrollCount     int  `json:"payroll_count"`
+	FirstMonth       int  `json:"first_month"`
+	LastMonth        int  `json:"last_month"`
+	Salary           int  `json:"salary"`
+	Overtime         int  `json:"overtime"`
+	Reimbursement    int  `json:"reimbursement"`
+	Earning          int  `json:"earning"`
+	Bonus            int  `json:"bonus"`
+	GrossPay         int  `json:"gross_pay"`
+	LoanDeduction    int  `json:"loan_deduction"`
+	PenaltyDeduction int  `json:"penalty_deduction"`
+	TotalDeduction   int  `json:"total_deduction"`
+	TotalTakeHomePay int  `json:"total_take_home_pay"`
+}
+
+func (u *UserYearToDateResponseDto) FromUserYearToDateEntity(yearToDate *entity.UserYearToDate) {
+	u.UserID = yearToDate.UserID
+	u.Year = yearToDate.Year
+	u.PayrollCount = yearToDate.PayrollCount
+	u.FirstMonth = yearToDate.FirstMonth
+	u.LastMonth = yearToDate.LastMonth
+	u.Salary = yearToDate.Salary
+	u.Overtime = yearToDate.Overtime
+	u.Reimbursement = yearToDate.Reimbursement
+	u.Earning = yearToDate.Earning
+	u.Bonus = yearToDate.Bonus
+	u.GrossPay = yearToDate.GrossPay
+	u.LoanDeduction = yearToDate.LoanDeduction
+	u.PenaltyDeduction = yearToDate.PenaltyDeduction
+	u.TotalDeduction = yearToDate.TotalDeduction
+	u.TotalTakeHomePay = yearToDate.TotalTakeHomePay
+}
+
+type PayrollPreviewEntryDto struct {
+	UserID              uint        `json:"user_id"`
+	TakeHomePay         int         `json:"take_home_pay"`
+	PreviousTakeHomePay *int        `json:"previous_take_home_pay"`
+	ChangePercent       *float64    `json:"change_percent"`
+	Flags               []string    `json:"flags"`
+	Payslip             *PayslipDto `json:"payslip"`
+}
+
+func (p *PayrollPreviewEntryDto) FromPayrollPreviewEntryEntity(entry *entity.PayrollPreviewEntry) {
+	p.UserID = entry.UserID
+	p.TakeHomePay = entry.TakeHomePay
+	p.PreviousTakeHomePay = entry.PreviousTakeHomePay
+	p.ChangePercent = entry.ChangePercent
+
+	p.Flags = make([]string, len(entry.Flags))
+	for i, flag := range entry.Flags {
+		p.Flags[i] = string(flag)
+	}
+
+	if entry.Payslip != nil {
+		p.Payslip = &PayslipDto{}
+		p.Payslip.FromPayslipEntity(entry.Payslip)
+	}
+}
+
+type PayrollPreviewDto struct {
+	Payroll                  *PayrollResponseDto       `json:"payroll"`
+	PreviousPayroll          *PayrollResponseDto       `json:"previous_payroll"`
+	ThresholdPercent         float64                   `json:"threshold_percent"`
+	TotalTakeHomePay         int                       `json:"total_take_home_pay"`
+	PreviousTotalTakeHomePay int                       `json:"previous_total_take_home_pay"`
+	FlaggedCount             int                       `json:"flagged_count"`
+	Entries                  []*PayrollPreviewEntryDto `json:"entries"`
+}
+
+func (p *PayrollPreviewDto) FromPayrollPreviewEntity(preview *entity.PayrollPreview) {
+	p.Payroll = &PayrollResponseDto{}
+	p.Payroll.FromPayrollEntity(preview.Payroll)
+	if preview.PreviousPayroll != nil {
+		p.PreviousPayroll = &PayrollResponseDto{}
+		p.PreviousPayroll.FromPayrollEntity(preview.PreviousPayroll)
+	}
+	p.ThresholdPercent = preview.ThresholdPercent
+	p.TotalTakeHomePay = preview.TotalTakeHomePay
+	p.PreviousTotalTakeHomePay = preview.PreviousTotalTakeHomePay
+	p.FlaggedCount = preview.FlaggedCount
+
+	entries := make([]*PayrollPreviewEntryDto, len(preview.Entries))
+	for i, entry := range preview.Entries {
+		dto := &PayrollPreviewEntryDto{}
+		dto.FromPayrollPreviewEntryEntity(entry)
+		entries[i] = dto
+	}
+	p.Entries = entries
+}
@@ -6,15 +6,24 @@ import (
 )
 
 type PayslipAttendanceDetailDto struct {
-	CheckinAt     time.Time  `json:"checkin_at"`
-	CheckoutAt    *time.Time `json:"checkout_at"`
-	DurationMilis int        `json:"duration_milis"`
+	CheckinAt          time.Time  `json:"checkin_at"`
+	CheckoutAt         *time.Time `json:"checkout_at"`
+	BreakDurationMilis int        `json:"break_duration_milis"`
+	DurationMilis      int        `json:"duration_milis"`
+	MissingCheckout    bool       `json:"missing_checkout"`
+	AutoClosePolicy    *string    `json:"auto_close_policy"`
 }
 
 func (p *PayslipAttendanceDetailDto) FromPayslipAttendanceDetailEntity(attendance *entity.PayslipAttendanceDetail) {
 	p.CheckinAt = attendance.CheckinAt
 	p.CheckoutAt = attendance.CheckoutAt
+	p.BreakDurationMilis = attendance.BreakDurationMilis
 	p.DurationMilis = attendance.DurationMilis
+	p.MissingCheckout = attendance.MissingCheckout
+	if attendance.AutoClosePolicy != nil {
+		policy := string(*attendance.AutoClosePolicy)
+		p.AutoClosePolicy = &policy
+	}
 }
 
 type PayslipAttendanceDto struct {
@@ -98,19 +107,117 @@ func (p *PayslipReimburseDto) FromPayslipReimburseEntity(reimburse *entity.Paysl
 	p.Details = details
 }
 
+type PayslipEarningDetailDto struct {
+	Description string    `json:"description"`
+	Amount      int       `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (p *PayslipEarningDetailDto) FromPayslipEarningDetailEntity(earning *entity.PayslipEarningDetail) {
+	p.Description = earning.Description
+	p.Amount = earning.Amount
+	p.CreatedAt = earning.CreatedAt
+}
+
+type PayslipEarningDto struct {
+	Details     []*PayslipEarningDetailDto `json:"details"`
+	TotalAmount float32                    `json:"total_amount"`
+}
+
+func (p *PayslipEarningDto) FromPayslipEarningEntity(earning *entity.PayslipEarning) {
+	p.TotalAmount = earning.TotalAmount
+
+	details := make([]*PayslipEarningDetailDto, len(earning.Details))
+	for i, detail := range earning.Details {
+		dto := &PayslipEarningDetailDto{}
+		dto.FromPayslipEarningDetailEntity(detail)
+		details[i] = dto
+	}
+	p.Details = details
+}
+
+type PayslipRetroAdjustmentDetailDto struct {
+	SourcePayrollID uint      `json:"source_payroll_id"`
+	Description     string    `json:"description"`
+	Amount          int       `json:"amount"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (p *PayslipRetroAdjustmentDetailDto) FromPayslipRetroAdjustmentDetailEntity(adjustment *entity.PayslipRetroAdjustmentDetail) {
+	p.SourcePayrollID = adjustment.SourcePayrollID
+	p.Description = adjustment.Description
+	p.Amount = adjustment.Amount
+	p.CreatedAt = adjustment.CreatedAt
+}
+
+type PayslipRetroAdjustmentDto struct {
+	Details     []*PayslipRetroAdjustmentDetailDto `json:"details"`
+	TotalAmount float32                            `json:"total_amount"`
+}
+
+func (p *PayslipRetroAdjustmentDto) FromPayslipRetroAdjustmentEntity(adjustment *entity.PayslipRetroAdjustment) {
+	p.TotalAmount = adjustment.TotalAmount
+
+	details := make([]*PayslipRetroAdjustmentDetailDto, len(adjustment.Details))
+	for i, detail := range adjustment.Details {
+		dto := &PayslipRetroAdjustmentDetailDto{}
+		dto.FromPayslipRetroAdjustmentDetailEntity(detail)
+		details[i] = dto
+	}
+	p.Details = details
+}
+
+type PayslipDeductionDetailDto struct {
+	Type           string     `json:"type"`
+	ReferenceID    *uint      `json:"reference_id"`
+	AttendanceDate *time.Time `json:"attendance_date,omitempty"`
+	Description    string     `json:"description"`
+	Amount         int        `json:"amount"`
+}
+
+func (p *PayslipDeductionDetailDto) FromPayslipDeductionDetailEntity(deduction *entity.PayslipDeductionDetail) {
+	p.Type = string(deduction.Type)
+	p.ReferenceID = deduction.ReferenceID
+	p.AttendanceDate = deduction.AttendanceDate
+	p.Description = deduction.Description
+	p.Amount = deduction.Amount
+}
+
+type PayslipDeductionDto struct {
+	Details     []*PayslipDeductionDetailDto `json:"details"`
+	TotalAmount float32                      `json:"total_amount"`
+}
+
+func (p *PayslipDeductionDto) FromPayslipDeductionEntity(deduction *entity.PayslipDeduction) {
+	p.TotalAmount = deduction.TotalAmount
+
+	details := make([]*PayslipDeductionDetailDto, len(deduction.Details))
+	for i, detail := range deduction.Details {
+		dto := &PayslipDeductionDetailDto{}
+		dto.FromPayslipDeductionDetailEntity(detail)
+		details[i] = dto
+	}
+	p.Details = details
+}
+
 type PayslipDto struct {
-	PayrollID   uint                  `json:"payroll_id"`
-	UserID      uint                  `json:"user_id"`
-	Salary      int                   `json:"salary"`
-	ProRate     float32               `json:"pro_rate"`
-	Attendance  *PayslipAttendanceDto `json:"attendance"`
-	Overtime    *PayslipOvertimeDto   `json:"overtime"`
-	Reimburse   *PayslipReimburseDto  `json:"reimburse"`
-	TakeHomePay float32               `json:"take_home_pay"`
+	PayrollID       uint                       `json:"payroll_id"`
+	PayrollType     string                     `json:"payroll_type"`
+	UserID          uint                       `json:"user_id"`
+	Salary          int                        `json:"salary"`
+	ProRate         float32                    `json:"pro_rate"`
+	Attendance      *PayslipAttendanceDto      `json:"attendance"`
+	Overtime        *PayslipOvertimeDto        `json:"overtime"`
+	Reimburse       *PayslipReimburseDto       `json:"reimburse"`
+	Earning         *PayslipEarningDto         `json:"earning"`
+	RetroAdjustment *PayslipRetroAdjustmentDto `json:"retro_adjustment"`
+	Deduction       *PayslipDeductionDto       `json:"deduction"`
+	TakeHomePay     float32                    `json:"take_home_pay"`
 }
 
 func (p *PayslipDto) FromPayslipEntity(payslip *entity.Payslip) {
 	p.PayrollID = payslip.PayrollID
+	p.PayrollType = string(payslip.PayrollType)
 	p.UserID = payslip.UserID
 	p.Salary = payslip.Salary
 	p.ProRate = payslip.ProRate
@@ -127,6 +234,18 @@ func (p *PayslipDto) FromPayslipEntity(payslip *entity.Payslip) {
 		p.Reimburse = &PayslipReimburseDto{}
 		p.Reimburse.FromPayslipReimburseEntity(payslip.Reimburse)
 	}
+	if payslip.Earning != nil {
+		p.Earning = &PayslipEarningDto{}
+		p.Earning.FromPayslipEarningEntity(payslip.Earning)
+	}
+	if payslip.RetroAdjustment != nil {
+		p.RetroAdjustment = &PayslipRetroAdjustmentDto{}
+		p.RetroAdjustment.FromPayslipRetroAdjustmentEntity(payslip.RetroAdjustment)
+	}
+	if payslip.Deduction != nil {
+		p.Deduction = &PayslipDeductionDto{}
+		p.Deduction.FromPayslipDeductionEntity(payslip.Deduction)
+	}
 	p.TakeHomePay = payslip.TakeHomePay
 }
 
@@ -145,3 +264,57 @@ func (u *UserPayslipSummaryDto) FromUserPayslipSummaryEntity(summary *entity.Use
 	u.CreatedAt = *summary.CreatedAt
 	u.UpdatedAt = *summary.UpdatedAt
 }
+
+type PayslipArchiveJobDto struct {
+	ID              *uint      `json:"id"`
+	PayrollID       uint       `json:"payroll_id"`
+	Locale          string     `json:"locale"`
+	Status          string     `json:"status"`
+	TotalCount      int        `json:"total_count"`
+	ProcessedCount  int        `json:"processed_count"`
+	ErrorMessage    *string    `json:"error_message"`
+	CreatedByUserID *uint      `json:"created_by_user_id"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	CreatedAt       *time.Time `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+func (p *PayslipArchiveJobDto) FromPayslipArchiveJobEntity(job *entity.PayslipArchiveJob) {
+	p.ID = job.ID
+	p.PayrollID = job.PayrollID
+	p.Locale = string(job.Locale)
+	p.Status = string(job.Status)
+	p.TotalCount = job.TotalCount
+	p.ProcessedCount = job.ProcessedCount
+	p.ErrorMessage = job.ErrorMessage
+	p.CreatedByUserID = job.CreatedByUserID
+	p.FinishedAt = job.FinishedAt
+	p.CreatedAt = job.CreatedAt
+	p.UpdatedAt = job.UpdatedAt
+}
+
+type PayslipDeliveryDto struct {
+	ID        *uint      `json:"id"`
+	PayrollID uint       `json:"payroll_id"`
+	UserID    uint       `json:"user_id"`
+	Email     *string    `json:"email"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	LastError *string    `json:"last_error"`
+	SentAt    *time.Time `json:"sent_at"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+func (p *PayslipDeliveryDto) FromPayslipDeliveryEntity(delivery *entity.PayslipDelivery) {
+	p.ID = delivery.ID
+	p.PayrollID = delivery.PayrollID
+	p.UserID = delivery.UserID
+	p.Email = delivery.Email
+	p.Status = string(delivery.Status)
+	p.Attempts = delivery.Attempts
+	p.LastError = delivery.LastError
+	p.SentAt = delivery.SentAt
+	p.CreatedAt = delivery.CreatedAt
+	p.UpdatedAt = delivery.UpdatedAt
+}
@@ -0,0 +1,170 @@
+package dto
+
+import (
+	"d-payroll/entity"
+	"time"
+)
+
+type CreateShiftBodyDto struct {
+	Name         string `json:"name" validate:"required,max=100"`
+	StartTime    string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime      string `json:"end_time" validate:"required,datetime=15:04"`
+	BreakMinutes int    `json:"break_minutes" validate:"min=0"`
+	GraceMinutes int    `json:"grace_minutes" validate:"min=0"`
+}
+
+func (c *CreateShiftBodyDto) ToShiftEntity() *entity.Shift {
+	return &entity.Shift{
+		Name:         c.Name,
+		StartTime:    c.StartTime,
+		EndTime:      c.EndTime,
+		BreakMinutes: c.BreakMinutes,
+		GraceMinutes: c.GraceMinutes,
+	}
+}
+
+type ShiftResponseDto struct {
+	ID           *uint      `json:"id"`
+	Name         string     `json:"name"`
+	StartTime    string     `json:"start_time"`
+	EndTime      string     `json:"end_time"`
+	BreakMinutes int        `json:"break_minutes"`
+	GraceMinutes int        `json:"grace_minutes"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+}
+
+func (s *ShiftResponseDto) FromShiftEntity(shift *entity.Shift) {
+	s.ID = shift.ID
+	s.Name = shift.Name
+	s.StartTime = shift.StartTime
+	s.EndTime = shift.EndTime
+	s.BreakMinutes = shift.BreakMinutes
+	s.GraceMinutes = shift.GraceMinutes
+	s.CreatedAt = shift.CreatedAt
+	s.UpdatedAt = shift.UpdatedAt
+}
+
+type CreateShiftRotationBodyDto struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// ShiftIDs are the shifts of the consecutive days of the rotation, null for a day off
+	ShiftIDs []*uint `json:"shift_ids" validate:"required,min=1,max=31"`
+}
+
+func (c *CreateShiftRotationBodyDto) ToShiftRotationEntity() *entity.ShiftRotation {
+	days := make([]*entity.ShiftRotationDay, len(c.ShiftIDs))
+	for i, shiftID := range c.ShiftIDs {
+		days[i] = &entity.ShiftRotationDay{
+			DayIndex: i,
+			ShiftID:  shiftID,
+		}
+	}
+
+	return &entity.ShiftRotation{
+		Name: c.Name,
+		Days: days,
+	}
+}
+
+type ShiftRotationDayResponseDto struct {
+	DayIndex int               `json:"day_index"`
+	ShiftID  *uint             `json:"shift_id"`
+	Shift    *ShiftResponseDto `json:"shift"`
+}
+
+type ShiftRotationResponseDto struct {
+	ID        *uint                          `json:"id"`
+	Name      string                         `json:"name"`
+	Days      []*ShiftRotationDayResponseDto `json:"days"`
+	CreatedAt *time.Time                     `json:"created_at"`
+	UpdatedAt *time.Time                     `json:"updated_at"`
+}
+
+func (s *ShiftRotationResponseDto) FromShiftRotationEntity(rotation *entity.ShiftRotation) {
+	s.ID = rotation.ID
+	s.Name = rotation.Name
+	s.CreatedAt = rotation.CreatedAt
+	s.UpdatedAt = rotation.UpdatedAt
+
+	s.Days = make([]*ShiftRotationDayResponseDto, len(rotation.Days))
+	for i, day := range rotation.Days {
+		s.Days[i] = &ShiftRotationDayResponseDto{
+			DayIndex: day.DayIndex,
+			ShiftID:  day.ShiftID,
+		}
+
+		if day.Shift != nil {
+			s.Days[i].Shift = &ShiftResponseDto{}
+			s.Days[i].Shift.FromShiftEntity(day.Shift)
+		}
+	}
+}
+
+type AssignShiftRotationBodyDto struct {
+	RotationID  uint      `json:"rotation_id" validate:"required"`
+	EffectiveAt time.Time `json:"effective_at" validate:"required"`
+}
+
+func (a *AssignShiftRotationBodyDto) ToUserShiftRotationEntity(userID uint, createdByUserID uint) *entity.UserShiftRotation {
+	return &entity.UserShiftRotation{
+		UserID:          userID,
+		RotationID:      a.RotationID,
+		EffectiveAt:     a.EffectiveAt,
+		CreatedByUserID: &createdByUserID,
+	}
+}
+
+type UserShiftRotationResponseDto struct {
+	ID              *uint                     `json:"id"`
+	UserID          uint                      `json:"user_id"`
+	RotationID      uint                      `json:"rotation_id"`
+	Rotation        *ShiftRotationResponseDto `json:"rotation"`
+	EffectiveAt     time.Time                 `json:"effective_at"`
+	CreatedByUserID *uint                     `json:"created_by_user_id"`
+	CreatedAt       *time.Time                `json:"created_at"`
+	UpdatedAt       *time.Time                `json:"updated_at"`
+}
+
+func (u *UserShiftRotationResponseDto) FromUserShiftRotationEntity(assignment *entity.UserShiftRotation) {
+	u.ID = assignment.ID
+	u.UserID = assignment.UserID
+	u.RotationID = assignment.RotationID
+	u.EffectiveAt = assignment.EffectiveAt
+	u.CreatedByUserID = assignment.CreatedByUserID
+	u.CreatedAt = assignment.CreatedAt
+	u.UpdatedAt = assignment.UpdatedAt
+
+	if assignment.Rotation != nil {
+		u.Rotation = &ShiftRotationResponseDto{}
+		u.Rotation.FromShiftRotationEntity(assignment.Rotation)
+	}
+}
+
+type WorkScheduleResponseDto struct {
+	Date         string     `json:"date"`
+	DayOff       bool       `json:"day_off"`
+	ShiftID      *uint      `json:"shift_id"`
+	ShiftName    string     `json:"shift_name"`
+	StartAt      *time.Time `json:"start_at"`
+	EndAt        *time.Time `json:"end_at"`
+	BreakMinutes int        `json:"break_minutes"`
+	GraceMinutes int        `json:"grace_minutes"`
+}
+
+// FromWorkScheduleEntity places the schedule on the day, a nil schedule is a day off
+func (w *WorkScheduleResponseDto) FromWorkScheduleEntity(day time.Time, schedule *entity.WorkSchedule) {
+	w.Date = day.Format("2006-01-02")
+	if schedule == nil {
+		w.DayOff = true
+		return
+	}
+
+	startAt := schedule.StartAt(day)
+	endAt := schedule.EndAt(day)
+	w.ShiftID = schedule.ShiftID
+	w.ShiftName = schedule.ShiftName
+	w.StartAt = &startAt
+	w.EndAt = &endAt
+	w.BreakMinutes = int(schedule.Break.Minutes())
+	w.GraceMinutes = int(schedule.Grace.Minutes())
+}
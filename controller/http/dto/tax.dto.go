@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"d-payroll/entity"
+	"time"
+)
+
+type TaxCertificateResponseDto struct {
+	Number     string    `json:"number"`
+	Year       int       `json:"year"`
+	FirstMonth int       `json:"first_month"`
+	LastMonth  int       `json:"last_month"`
+	Date       time.Time `json:"date"`
+	UserID     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Nik        string    `json:"nik"`
+	Npwp       string    `json:"npwp"`
+	Address    string    `json:"address"`
+	Gender     string    `json:"gender"`
+	TaxStatus  string    `json:"tax_status"`
+	Position   string    `json:"position"`
+
+	Salary              int `json:"salary"`
+	TaxAllowance        int `json:"tax_allowance"`
+	OtherAllowance      int `json:"other_allowance"`
+	Honorarium          int `json:"honorarium"`
+	InsurancePremium    int `json:"insurance_premium"`
+	BenefitInKind       int `json:"benefit_in_kind"`
+	Bonus               int `json:"bonus"`
+	GrossIncome         int `json:"gross_income"`
+	OccupationalCost    int `json:"occupational_cost"`
+	PensionContribution int `json:"pension_contribution"`
+	TotalDeduction      int `json:"total_deduction"`
+	NetIncome           int `json:"net_income"`
+	PreviousNetIncome   int `json:"previous_net_income"`
+	AnnualNetIncome     int `json:"annual_net_income"`
+	Ptkp                int `json:"ptkp"`
+	TaxableIncome       int `json:"taxable_income"`
+	AnnualTax           int `json:"annual_tax"`
+	PreviousTax         int `json:"previous_tax"`
+	TaxDue              int `json:"tax_due"`
+	TaxWithheld         int `json:"tax_withheld"`
+}
+
+func (t *TaxCertificateResponseDto) FromTaxCertificateEntity(certificate *entity.TaxCertificate) {
+	t.Number = certificate.Number
+	t.Year = certificate.Year
+	t.FirstMonth = certificate.FirstMonth
+	t.LastMonth = certificate.LastMonth
+	t.Date = certificate.Date
+	t.UserID = certificate.UserID
+	t.Name = certificate.Name
+	t.Nik = certificate.Nik
+	t.Npwp = certificate.Npwp
+	t.Address = certificate.Address
+	t.Gender = string(certificate.Gender)
+	t.TaxStatus = string(certificate.TaxStatus)
+	t.Position = certificate.Position
+
+	t.Salary = certificate.Salary
+	t.TaxAllowance = certificate.TaxAllowance
+	t.OtherAllowance = certificate.OtherAllowance
+	t.Honorarium = certificate.Honorarium
+	t.InsurancePremium = certificate.InsurancePremium
+	t.BenefitInKind = certificate.BenefitInKind
+	t.Bonus = certificate.Bonus
+	t.GrossIncome = certificate.GrossIncome
+	t.OccupationalCost = certificate.OccupationalCost
+	t.PensionContribution = certificate.PensionContribution
+	t.TotalDeduction = certificate.TotalDeduction
+	t.NetIncome = certificate.NetIncome
+	t.PreviousNetIncome = certificate.PreviousNetIncome
+	t.AnnualNetIncome = certificate.AnnualNetIncome
+	t.Ptkp = certificate.Ptkp
+	t.TaxableIncome = certificate.TaxableIncome
+	t.AnnualTax = certificate.AnnualTax
+	t.PreviousTax = certificate.PreviousTax
+	t.TaxDue = certificate.TaxDue
+	t.TaxWithheld = certificate.TaxWithheld
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"d-payroll/entity"
+	"time"
+)
+
+type CalculateThrBodyDto struct {
+	Name     string    `json:"name" validate:"required"`
+	Holiday  string    `json:"holiday" validate:"required,oneof=EID_AL_FITR CHRISTMAS NYEPI VESAK CHINESE_NEW_YEAR"`
+	PayoutAt time.Time `json:"payout_at" validate:"required"`
+}
+
+func (c *CalculateThrBodyDto) ToThrCalculationEntity(userID uint) *entity.ThrCalculation {
+	return &entity.ThrCalculation{
+		Name:            c.Name,
+		Holiday:         entity.ThrHoliday(c.Holiday),
+		PayoutAt:        c.PayoutAt,
+		CreatedByUserID: userID,
+	}
+}
+
+type ThrEntryDto struct {
+	UserID         uint       `json:"user_id"`
+	JoinedAt       *time.Time `json:"joined_at"`
+	TenureMonths   int        `json:"tenure_months"`
+	MonthlySalary  int        `json:"monthly_salary"`
+	Amount         int        `json:"amount"`
+	ExcludedReason *string    `json:"excluded_reason"`
+}
+
+func (t *ThrEntryDto) FromThrEntryEntity(entry *entity.ThrEntry) {
+	t.UserID = entry.UserID
+	t.JoinedAt = entry.JoinedAt
+	t.TenureMonths = entry.TenureMonths
+	t.MonthlySalary = entry.MonthlySalary
+	t.Amount = entry.Amount
+	if entry.ExcludedReason != nil {
+		reason := string(*entry.ExcludedReason)
+		t.ExcludedReason = &reason
+	}
+}
+
+type ThrResultDto struct {
+	Payroll     *PayrollResponseDto `json:"payroll"`
+	Entries     []*ThrEntryDto      `json:"entries"`
+	TotalAmount int                 `json:"total_amount"`
+}
+
+func (t *ThrResultDto) FromThrResultEntity(result *entity.ThrResult) {
+	t.Payroll = &PayrollResponseDto{}
+	t.Payroll.FromPayrollEntity(result.Payroll)
+	t.TotalAmount = result.TotalAmount
+
+	entries := make([]*ThrEntryDto, len(result.Entries))
+	for i, entry := range result.Entries {
+		dto := &ThrEntryDto{}
+		dto.FromThrEntryEntity(entry)
+		entries[i] = dto
+	}
+	t.Entries = entries
+}
@@ -6,22 +6,75 @@ import (
 )
 
 type CreateUserInfoBodyDto struct {
-	MonthlySalary *int `json:"monthly_salary" validate:"required"`
+	MonthlySalary *int       `json:"monthly_salary" validate:"required"`
+	Religion      *string    `json:"religion" validate:"omitempty,oneof=ISLAM PROTESTANT CATHOLIC HINDU BUDDHIST CONFUCIAN"`
+	ThrHoliday    *string    `json:"thr_holiday" validate:"omitempty,oneof=EID_AL_FITR CHRISTMAS NYEPI VESAK CHINESE_NEW_YEAR"`
+	JoinedAt      *time.Time `json:"joined_at"`
+	BirthDate     *time.Time `json:"birth_date"`
+	Email         *string    `json:"email" validate:"omitempty,email"`
+	Department    *string    `json:"department" validate:"omitempty,max=64"`
+	CostCenter    *string    `json:"cost_center" validate:"omitempty,max=32"`
+	FullName      *string    `json:"full_name" validate:"omitempty,max=100"`
+	Nik           *string    `json:"nik" validate:"omitempty,numeric,len=16"`
+	Npwp          *string    `json:"npwp" validate:"omitempty,numeric,min=15,max=16"`
+	Address       *string    `json:"address" validate:"omitempty,max=255"`
+	Gender        *string    `json:"gender" validate:"omitempty,oneof=MALE FEMALE"`
+	TaxStatus     *string    `json:"tax_status" validate:"omitempty,oneof=TK/0 TK/1 TK/2 TK/3 K/0 K/1 K/2 K/3"`
+	Position      *string    `json:"position" validate:"omitempty,max=64"`
+	Timezone      *string    `json:"timezone" validate:"omitempty,timezone"`
+	DevicePin     *string    `json:"device_pin" validate:"omitempty,alphanum,max=24"`
+
+	AttendancePenaltyExempt bool `json:"attendance_penalty_exempt"`
+}
+
+func (c *CreateUserInfoBodyDto) toUserInfoEntity() *entity.UserInfo {
+	userInfo := &entity.UserInfo{
+		MonthlySalary: c.MonthlySalary,
+		JoinedAt:      c.JoinedAt,
+		BirthDate:     c.BirthDate,
+		Email:         c.Email,
+		Department:    c.Department,
+		CostCenter:    c.CostCenter,
+		FullName:      c.FullName,
+		Nik:           c.Nik,
+		Npwp:          c.Npwp,
+		Address:       c.Address,
+		Position:      c.Position,
+		Timezone:      c.Timezone,
+		DevicePin:     c.DevicePin,
+
+		AttendancePenaltyExempt: c.AttendancePenaltyExempt,
+	}
+	if c.Religion != nil {
+		religion := entity.Religion(*c.Religion)
+		userInfo.Religion = &religion
+	}
+	if c.ThrHoliday != nil {
+		thrHoliday := entity.ThrHoliday(*c.ThrHoliday)
+		userInfo.ThrHoliday = &thrHoliday
+	}
+	if c.Gender != nil {
+		gender := entity.Gender(*c.Gender)
+		userInfo.Gender = &gender
+	}
+	if c.TaxStatus != nil {
+		taxStatus := entity.TaxStatus(*c.TaxStatus)
+		userInfo.TaxStatus = &taxStatus
+	}
+	return userInfo
 }
 
 type CreateUserBodyDto struct {
 	Username string                 `json:"username" validate:"required"`
 	Password string                 `json:"password" validate:"required"`
-	Role     string                 `json:"role" validate:"required,oneof=ADMIN EMPLOYEE"`
+	Role     string                 `json:"role" validate:"required,oneof=ADMIN EMPLOYEE FINANCE"`
 	UserInfo *CreateUserInfoBodyDto `json:"user_info"`
 }
 
 func (c *CreateUserBodyDto) ToUserEntity() *entity.User {
 	var userInfo *entity.UserInfo
 	if c.UserInfo != nil {
-		userInfo = &entity.UserInfo{
-			MonthlySalary: c.UserInfo.MonthlySalary,
-		}
+		userInfo = c.UserInfo.toUserInfoEntity()
 	}
 	return &entity.User{
 		Username: c.Username,
@@ -32,7 +85,27 @@ func (c *CreateUserBodyDto) ToUserEntity() *entity.User {
 }
 
 type userInfoDto struct {
-	MonthlySalary *int `json:"monthly_salary"`
+	MonthlySalary  *int       `json:"monthly_salary"`
+	Religion       *string    `json:"religion"`
+	ThrHoliday     *string    `json:"thr_holiday"`
+	JoinedAt       *time.Time `json:"joined_at"`
+	BirthDate      *time.Time `json:"birth_date"`
+	Email          *string    `json:"email"`
+	Department     *string    `json:"department"`
+	CostCenter     *string    `json:"cost_center"`
+	FullName       *string    `json:"full_name"`
+	Nik            *string    `json:"nik"`
+	Npwp           *string    `json:"npwp"`
+	Address        *string    `json:"address"`
+	Gender         *string    `json:"gender"`
+	TaxStatus      *string    `json:"tax_status"`
+	Position       *string    `json:"position"`
+	Timezone       *string    `json:"timezone"`
+	WorkLocationID *uint      `json:"work_location_id"`
+	RemoteWork     bool       `json:"remote_work"`
+	DevicePin      *string    `json:"device_pin"`
+
+	AttendancePenaltyExempt bool `json:"attendance_penalty_exempt"`
 }
 
 type userResponseDto struct {
@@ -50,7 +123,39 @@ func (r *userResponseDto) fromUserEntity(user *entity.User) {
 	r.Role = string(user.Role)
 	if user.UserInfo != nil {
 		r.UserInfo = &userInfoDto{
-			MonthlySalary: user.UserInfo.MonthlySalary,
+			MonthlySalary:  user.UserInfo.MonthlySalary,
+			JoinedAt:       user.UserInfo.JoinedAt,
+			BirthDate:      user.UserInfo.BirthDate,
+			Email:          user.UserInfo.Email,
+			Department:     user.UserInfo.Department,
+			CostCenter:     user.UserInfo.CostCenter,
+			FullName:       user.UserInfo.FullName,
+			Nik:            user.UserInfo.Nik,
+			Npwp:           user.UserInfo.Npwp,
+			Address:        user.UserInfo.Address,
+			Position:       user.UserInfo.Position,
+			Timezone:       user.UserInfo.Timezone,
+			WorkLocationID: user.UserInfo.WorkLocationID,
+			RemoteWork:     user.UserInfo.RemoteWork,
+			DevicePin:      user.UserInfo.DevicePin,
+
+			AttendancePenaltyExempt: user.UserInfo.AttendancePenaltyExempt,
+		}
+		if user.UserInfo.Religion != nil {
+			religion := string(*user.UserInfo.Religion)
+			r.UserInfo.Religion = &religion
+		}
+		if user.UserInfo.ThrHoliday != nil {
+			thrHoliday := string(*user.UserInfo.ThrHoliday)
+			r.UserInfo.ThrHoliday = &thrHoliday
+		}
+		if user.UserInfo.Gender != nil {
+			gender := string(*user.UserInfo.Gender)
+			r.UserInfo.Gender = &gender
+		}
+		if user.UserInfo.TaxStatus != nil {
+			taxStatus := string(*user.UserInfo.TaxStatus)
+			r.UserInfo.TaxStatus = &taxStatus
 		}
 	}
 	r.CreatedAt = user.CreatedAt
@@ -67,3 +172,146 @@ func (c *CreateUserResponseDto) FromUserEntity(user *entity.User) {
 func (g *GetUserByIdResponseDto) FromUserEntity(user *entity.User) {
 	(*userResponseDto)(g).fromUserEntity(user)
 }
+
+type AssignDevicePinBodyDto struct {
+	// DevicePin is null to stop importing the punches of the user
+	DevicePin *string `json:"device_pin" validate:"omitempty,alphanum,max=24"`
+}
+
+type SetAttendancePenaltyExemptionBodyDto struct {
+	Exempt *bool `json:"exempt" validate:"required"`
+}
+
+type ChangeSalaryBodyDto struct {
+	MonthlySalary int       `json:"monthly_salary" validate:"required,min=1"`
+	EffectiveAt   time.Time `json:"effective_at" validate:"required"`
+}
+
+func (c *ChangeSalaryBodyDto) ToUserSalaryEntity(userID uint, createdByUserID uint) *entity.UserSalary {
+	return &entity.UserSalary{
+		UserID:          userID,
+		MonthlySalary:   c.MonthlySalary,
+		EffectiveAt:     c.EffectiveAt,
+		CreatedByUserID: &createdByUserID,
+	}
+}
+
+type UserSalaryResponseDto struct {
+	ID              *uint      `json:"id"`
+	UserID          uint       `json:"user_id"`
+	MonthlySalary   int        `json:"monthly_salary"`
+	EffectiveAt     time.Time  `json:"effective_at"`
+	CreatedByUserID *uint      `json:"created_by_user_id"`
+	CreatedAt       *time.Time `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+func (u *UserSalaryResponseDto) FromUserSalaryEntity(salary *entity.UserSalary) {
+	u.ID = salary.ID
+	u.UserID = salary.UserID
+	u.MonthlySalary = salary.MonthlySalary
+	u.EffectiveAt = salary.EffectiveAt
+	u.CreatedByUserID = salary.CreatedByUserID
+	u.CreatedAt = salary.CreatedAt
+	u.UpdatedAt = salary.UpdatedAt
+}
+
+type RequestBankAccountChangeBodyDto struct {
+	PaymentMethod string `json:"payment_method" validate:"required,oneof=TRANSFER CASH EWALLET"`
+	BankCode      string `json:"bank_code" validate:"required_unless=PaymentMethod CASH,omitempty,oneof=BCA MANDIRI BNI BRI CIMB PERMATA GOPAY OVO DANA SHOPEEPAY"`
+	AccountNumber string `json:"account_number" validate:"required_unless=PaymentMethod CASH,omitempty,numeric,max=34"`
+	AccountName   string `json:"account_name" validate:"required,max=70"`
+}
+
+func (r *RequestBankAccountChangeBodyDto) ToUserBankAccountChangeEntity(userID uint, requestedByUserID uint) *entity.UserBankAccountChange {
+	change := &entity.UserBankAccountChange{
+		UserID:            userID,
+		PaymentMethod:     entity.PaymentMethod(r.PaymentMethod),
+		BankCode:          entity.BankCode(r.BankCode),
+		AccountNumber:     r.AccountNumber,
+		AccountName:       r.AccountName,
+		RequestedByUserID: requestedByUserID,
+	}
+
+	// nothing but the name is kept for the cash payments
+	if change.PaymentMethod == entity.PaymentMethodCash {
+		change.BankCode = ""
+		change.AccountNumber = ""
+	}
+
+	return change
+}
+
+type ReviewBankAccountChangeBodyDto struct {
+	Note *string `json:"note" validate:"omitempty,max=255"`
+}
+
+type UserBankAccountResponseDto struct {
+	ID              *uint      `json:"id"`
+	UserID          uint       `json:"user_id"`
+	PaymentMethod   string     `json:"payment_method"`
+	BankCode        string     `json:"bank_code"`
+	BankName        string     `json:"bank_name"`
+	AccountNumber   string     `json:"account_number"`
+	AccountName     string     `json:"account_name"`
+	UpdatedByUserID *uint      `json:"updated_by_user_id"`
+	CreatedAt       *time.Time `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+// FromUserBankAccountEntity maps the account, masking the account number unless unmasked
+func (u *UserBankAccountResponseDto) FromUserBankAccountEntity(account *entity.UserBankAccount, unmasked bool) {
+	u.ID = account.ID
+	u.UserID = account.UserID
+	u.PaymentMethod = string(account.PaymentMethod)
+	u.BankCode = string(account.BankCode)
+	u.BankName = account.BankCode.Name()
+	u.AccountNumber = account.AccountNumber
+	u.AccountName = account.AccountName
+	u.UpdatedByUserID = account.UpdatedByUserID
+	u.CreatedAt = account.CreatedAt
+	u.UpdatedAt = account.UpdatedAt
+
+	if !unmasked {
+		u.AccountNumber = entity.MaskAccountNumber(account.AccountNumber)
+	}
+}
+
+type UserBankAccountChangeResponseDto struct {
+	ID                *uint      `json:"id"`
+	UserID            uint       `json:"user_id"`
+	PaymentMethod     string     `json:"payment_method"`
+	BankCode          string     `json:"bank_code"`
+	BankName          string     `json:"bank_name"`
+	AccountNumber     string     `json:"account_number"`
+	AccountName       string     `json:"account_name"`
+	Status            string     `json:"status"`
+	RequestedByUserID uint       `json:"requested_by_user_id"`
+	ReviewedByUserID  *uint      `json:"reviewed_by_user_id"`
+	ReviewedAt        *time.Time `json:"reviewed_at"`
+	ReviewNote        *string    `json:"review_note"`
+	CreatedAt         *time.Time `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+}
+
+// FromUserBankAccountChangeEntity maps the change, masking the account number unless unmasked
+func (u *UserBankAccountChangeResponseDto) FromUserBankAccountChangeEntity(change *entity.UserBankAccountChange, unmasked bool) {
+	u.ID = change.ID
+	u.UserID = change.UserID
+	u.PaymentMethod = string(change.PaymentMethod)
+	u.BankCode = string(change.BankCode)
+	u.BankName = change.BankCode.Name()
+	u.AccountNumber = change.AccountNumber
+	u.AccountName = change.AccountName
+	u.Status = string(change.Status)
+	u.RequestedByUserID = change.RequestedByUserID
+	u.ReviewedByUserID = change.ReviewedByUserID
+	u.ReviewedAt = change.ReviewedAt
+	u.ReviewNote = change.ReviewNote
+	u.CreatedAt = change.CreatedAt
+	u.UpdatedAt = change.UpdatedAt
+
+	if !unmasked {
+		u.AccountNumber = entity.MaskAccountNumber(change.AccountNumber)
+	}
+}
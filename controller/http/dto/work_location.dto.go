@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"d-payroll/entity"
+	"time"
+)
+
+type GeoPointDto struct {
+	Latitude  float64 `json:"latitude" validate:"latitude"`
+	Longitude float64 `json:"longitude" validate:"longitude"`
+}
+
+type CreateWorkLocationBodyDto struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	Latitude     *float64 `json:"latitude" validate:"required_with=RadiusMeters,omitempty,latitude"`
+	Longitude    *float64 `json:"longitude" validate:"required_with=RadiusMeters,omitempty,longitude"`
+	RadiusMeters *float64 `json:"radius_meters" validate:"omitempty,gt=0"`
+	// Polygon are the vertices of the area in order, it takes precedence over the radius
+	Polygon    []*GeoPointDto `json:"polygon" validate:"omitempty,min=3,max=100,dive,required"`
+	WifiBSSIDs []string       `json:"wifi_bssids" validate:"omitempty,dive,mac"`
+	IPRanges   []string       `json:"ip_ranges" validate:"omitempty,dive,cidr"`
+	Policy     string         `json:"policy" validate:"required,oneof=FLAG REJECT"`
+}
+
+func (c *CreateWorkLocationBodyDto) ToWorkLocationEntity() *entity.WorkLocation {
+	polygon := make([]*entity.GeoPoint, len(c.Polygon))
+	for i, point := range c.Polygon {
+		polygon[i] = &entity.GeoPoint{Latitude: point.Latitude, Longitude: point.Longitude}
+	}
+
+	return &entity.WorkLocation{
+		Name:         c.Name,
+		Latitude:     c.Latitude,
+		Longitude:    c.Longitude,
+		RadiusMeters: c.RadiusMeters,
+		Polygon:      polygon,
+		WifiBSSIDs:   c.WifiBSSIDs,
+		IPRanges:     c.IPRanges,
+		Policy:       entity.WorkLocationPolicy(c.Policy),
+	}
+}
+
+type WorkLocationResponseDto struct {
+	ID           *uint          `json:"id"`
+	Name         string         `json:"name"`
+	Latitude     *float64       `json:"latitude"`
+	Longitude    *float64       `json:"longitude"`
+	RadiusMeters *float64       `json:"radius_meters"`
+	Polygon      []*GeoPointDto `json:"polygon"`
+	WifiBSSIDs   []string       `json:"wifi_bssids"`
+	IPRanges     []string       `json:"ip_ranges"`
+	Policy       string         `json:"policy"`
+	CreatedAt    *time.Time     `json:"created_at"`
+	UpdatedAt    *time.Time     `json:"updated_at"`
+}
+
+func (w *WorkLocationResponseDto) FromWorkLocationEntity(workLocation *entity.WorkLocation) {
+	w.ID = workLocation.ID
+	w.Name = workLocation.Name
+	w.Latitude = workLocation.Latitude
+	w.Longitude = workLocation.Longitude
+	w.RadiusMeters = workLocation.RadiusMeters
+	w.Polygon = make([]*GeoPointDto, len(workLocation.Polygon))
+	for i, point := range workLocation.Polygon {
+		w.Polygon[i] = &GeoPointDto{Latitude: point.Latitude, Longitude: point.Longitude}
+	}
+	w.WifiBSSIDs = workLocation.WifiBSSIDs
+	w.IPRanges = workLocation.IPRanges
+	w.Policy = string(workLocation.Policy)
+	w.CreatedAt = workLocation.CreatedAt
+	w.UpdatedAt = workLocation.UpdatedAt
+}
+
+type AssignWorkLocationBodyDto struct {
+	// WorkLocationID is null to stop verifying the check-ins of the user
+	WorkLocationID *uint `json:"work_location_id"`
+	RemoteWork     bool  `json:"remote_work"`
+}
@@ -17,6 +17,12 @@ type httpApp struct {
 
 func NewHttpApp(config *config.Config) *httpApp {
 	app := fiber.New(fiber.Config{
+		// behind a load balancer the client IP is read from its header, only when the request comes from a trusted
+		// proxy, so the IP ranges of the work locations are not matched by the address of the proxy
+		ProxyHeader:             config.Http.ProxyHeader,
+		EnableTrustedProxyCheck: config.Http.ProxyHeader != "",
+		TrustedProxies:          config.Http.TrustedProxies,
+		EnableIPValidation:      true,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 
 			var validationError *internalerror.ValidationError
@@ -0,0 +1,62 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	journalservice "d-payroll/service/journal"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JournalHttp struct {
+	http       *httpApp
+	journalSvc journalservice.JournalService
+}
+
+func NewJournalHttp(http *httpApp, journalSvc journalservice.JournalService) {
+	journalHttp := &JournalHttp{
+		http:       http,
+		journalSvc: journalSvc,
+	}
+
+	journalHttp.http.App.Get("/payrolls/:payrollId/journal", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), journalHttp.Journal)
+}
+
+func (j *JournalHttp) Journal(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	format := entity.JournalFormat(c.Query("format", string(entity.JournalFormatCsv)))
+
+	file, err := j.journalSvc.RenderPayrollJournal(c.Context(), uint(payrollIdInt), format)
+	if err != nil {
+		if errors.Is(err, &internalerror.JournalFormatNotSupportedError{}) {
+			return cc.BadRequest("Invalid format query, expected csv or json")
+		}
+
+		if errors.Is(err, &internalerror.PayrollNotRolledError{}) {
+			return cc.UnprocessableEntity("Payroll is not rolled yet")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+
+	return c.Send(file.Content)
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	loanservice "d-payroll/service/loan"
+	"d-payroll/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LoanHttp struct {
+	http    *httpApp
+	loanSvc loanservice.LoanService
+}
+
+func NewLoanHttp(http *httpApp, loanSvc loanservice.LoanService) {
+	loanHttp := &LoanHttp{
+		http:    http,
+		loanSvc: loanSvc,
+	}
+
+	loanHttp.http.App.Post("/loans", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), loanHttp.CreateLoan)
+	loanHttp.http.App.Get("/loans", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee, entity.UserRoleAdmin}), loanHttp.GetUserLoans)
+	loanHttp.http.App.Get("/loans/:loanId/statement", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee, entity.UserRoleAdmin}), loanHttp.GetLoanStatement)
+}
+
+func (l *LoanHttp) CreateLoan(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	loan := new(dto.CreateLoanBodyDto)
+	if err := c.BodyParser(loan); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(loan)
+	if err != nil {
+		return err
+	}
+
+	createdLoan, err := l.loanSvc.CreateLoan(c.Context(), loan.ToUserLoanEntity(authPayload.ID))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+
+		return err
+	}
+
+	var response dto.LoanResponseDto
+	response.FromUserLoanEntity(createdLoan)
+
+	return cc.Ok(response, nil)
+}
+
+func (l *LoanHttp) GetUserLoans(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	userIdParam := c.Query("user_id")
+	userId, err := strconv.ParseUint(userIdParam, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid user ID query")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee && authPayload.ID != uint(userId) {
+		return cc.Unauthorized("Unauthorized to access other user's loans")
+	}
+
+	loans, err := l.loanSvc.GetLoansByUserID(c.Context(), uint(userId))
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.LoanResponseDto, len(loans))
+	for i, loan := range loans {
+		var response dto.LoanResponseDto
+		response.FromUserLoanEntity(loan)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (l *LoanHttp) GetLoanStatement(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	loanId := c.Params("loanId")
+	loanIdInt, err := strconv.ParseUint(loanId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid loan ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	statement, err := l.loanSvc.GetLoanStatement(c.Context(), uint(loanIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Loan not found")
+		}
+
+		return err
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee && authPayload.ID != statement.Loan.UserID {
+		return cc.Unauthorized("Unauthorized to access other user's loans")
+	}
+
+	var response dto.LoanStatementDto
+	response.FromLoanStatementEntity(statement)
+
+	return cc.Ok(response, nil)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	notificationservice "d-payroll/service/notification"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationHttp struct {
+	http            *httpApp
+	notificationSvc notificationservice.NotificationService
+}
+
+func NewNotificationHttp(http *httpApp, notificationSvc notificationservice.NotificationService) {
+	notificationHttp := &NotificationHttp{
+		http:            http,
+		notificationSvc: notificationSvc,
+	}
+
+	notificationHttp.http.App.Post("/payrolls/:payrollId/payslips/deliveries", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), notificationHttp.SendPayslips)
+	notificationHttp.http.App.Get("/payrolls/:payrollId/payslips/deliveries", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), notificationHttp.GetPayslipDeliveries)
+	notificationHttp.http.App.Post("/payrolls/:payrollId/payslips/deliveries/:userId/resend", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), notificationHttp.ResendPayslip)
+}
+
+func toPayslipDeliveryDtos(deliveries []*entity.PayslipDelivery) []*dto.PayslipDeliveryDto {
+	deliveriesDto := make([]*dto.PayslipDeliveryDto, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveriesDto[i] = &dto.PayslipDeliveryDto{}
+		deliveriesDto[i].FromPayslipDeliveryEntity(delivery)
+	}
+
+	return deliveriesDto
+}
+
+func (n *NotificationHttp) SendPayslips(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	deliveries, err := n.notificationSvc.SendPayslips(c.Context(), uint(payrollIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.PayrollNotRolledError{}) {
+			return cc.UnprocessableEntity("Payroll is not rolled yet")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+
+		return err
+	}
+
+	c.Status(fiber.StatusAccepted)
+	return cc.Ok(toPayslipDeliveryDtos(deliveries), nil)
+}
+
+func (n *NotificationHttp) GetPayslipDeliveries(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	deliveries, err := n.notificationSvc.GetPayslipDeliveries(c.Context(), uint(payrollIdInt))
+	if err != nil {
+		return err
+	}
+
+	return cc.Ok(toPayslipDeliveryDtos(deliveries), nil)
+}
+
+func (n *NotificationHttp) ResendPayslip(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	userId := c.Params("userId")
+	userIdInt, err := strconv.ParseUint(userId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid user ID param")
+	}
+
+	delivery, err := n.notificationSvc.ResendPayslip(c.Context(), uint(payrollIdInt), uint(userIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.PayrollNotRolledError{}) {
+			return cc.UnprocessableEntity("Payroll is not rolled yet")
+		}
+
+		if errors.Is(err, &internalerror.PayrollUserNotSelectedError{}) {
+			return cc.NotFound("User is not paid by payroll")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+
+		return err
+	}
+
+	deliveryDto := &dto.PayslipDeliveryDto{}
+	deliveryDto.FromPayslipDeliveryEntity(delivery)
+
+	return cc.Ok(deliveryDto, nil)
+}
@@ -28,6 +28,9 @@ func NewOvertimeHttp(http *httpApp, overtimeSvc overtimeservice.OvertimeService)
 	overtimeHttp.http.App.Post("/overtimes", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee}), overtimeHttp.CreateOvertime)
 	overtimeHttp.http.App.Post("/overtimes/:overtimeId/approve", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), overtimeHttp.ApproveOvertime)
 	overtimeHttp.http.App.Get("/overtimes", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee, entity.UserRoleAdmin}), overtimeHttp.GetUserOvertimes)
+
+	overtimeHttp.http.App.Post("/overtime-plans", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), overtimeHttp.CreateOvertimePlan)
+	overtimeHttp.http.App.Get("/overtime-plans", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleEmployee, entity.UserRoleAdmin}), overtimeHttp.GetUserOvertimePlans)
 }
 
 func (o *OvertimeHttp) CreateOvertime(c *fiber.Ctx) error {
@@ -58,6 +61,22 @@ func (o *OvertimeHttp) CreateOvertime(c *fiber.Ctx) error {
 			return cc.UnprocessableEntity("Overtime submit before checkout")
 		}
 
+		if errors.Is(err, &internalerror.OvertimeExceedsWeeklyLimitError{}) || errors.Is(err, &internalerror.OvertimeExceedsMonthlyLimitError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		if errors.Is(err, &internalerror.OvertimePlanNotDueError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Overtime plan not found")
+		}
+
+		if errors.Is(err, &internalerror.OvertimePlanAlreadySubmittedError{}) {
+			return cc.Conflict(err.Error())
+		}
+
 		return err
 	}
 
@@ -129,3 +148,78 @@ func (o *OvertimeHttp) GetUserOvertimes(c *fiber.Ctx) error {
 
 	return cc.Ok(responses, nil)
 }
+
+// CreateOvertimePlan assigns an overtime to an employee in advance
+func (o *OvertimeHttp) CreateOvertimePlan(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	plan := new(dto.CreateOvertimePlanBodyDto)
+	if err := c.BodyParser(plan); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(plan)
+	if err != nil {
+		return err
+	}
+
+	createdPlan, err := o.overtimeSvc.CreateOvertimePlan(c.Context(), plan.ToOvertimePlanEntity(authPayload.ID))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+
+		if errors.Is(err, &internalerror.OvertimePlanInPastError{}) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		if errors.Is(err, &internalerror.OvertimeExceedsLimitError{}) {
+			return cc.UnprocessableEntity("Overtime exceeds limit")
+		}
+
+		return err
+	}
+
+	var response dto.OvertimePlanResponseDto
+	response.FromOvertimePlanEntity(createdPlan)
+
+	return cc.Ok(response, nil)
+}
+
+func (o *OvertimeHttp) GetUserOvertimePlans(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	userIdParam := c.Query("user_id")
+	userId, err := strconv.ParseUint(userIdParam, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid user ID query")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee && authPayload.ID != uint(userId) {
+		return cc.Unauthorized("Unauthorized to access other user's overtime plans")
+	}
+
+	plans, err := o.overtimeSvc.GetOvertimePlansByUserID(c.Context(), uint(userId))
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.OvertimePlanResponseDto, len(plans))
+	for i, plan := range plans {
+		var response dto.OvertimePlanResponseDto
+		response.FromOvertimePlanEntity(plan)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
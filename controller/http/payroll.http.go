@@ -6,28 +6,39 @@ import (
 	"d-payroll/controller/http/middleware"
 	"d-payroll/entity"
 	internalerror "d-payroll/internal-error"
+	notificationservice "d-payroll/service/notification"
 	payrollservice "d-payroll/service/payroll"
 	"d-payroll/utils"
 	"errors"
+	"log"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type PayrollHttp struct {
-	http       *httpApp
-	payrollSvc payrollservice.PayrollService
+	http            *httpApp
+	payrollSvc      payrollservice.PayrollService
+	notificationSvc notificationservice.NotificationService
 }
 
-func NewPayrollHttp(http *httpApp, payrollSvc payrollservice.PayrollService) {
+func NewPayrollHttp(http *httpApp, payrollSvc payrollservice.PayrollService, notificationSvc notificationservice.NotificationService) {
 	payrollHttp := &PayrollHttp{
-		http:       http,
-		payrollSvc: payrollSvc,
+		http:            http,
+		payrollSvc:      payrollSvc,
+		notificationSvc: notificationSvc,
 	}
 
 	payrollHttp.http.App.Post("/payrolls", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.CreatePayroll)
 	payrollHttp.http.App.Get("/payrolls", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.GetUserPayrolls)
 	payrollHttp.http.App.Post("/payrolls/:payrollId/roll", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.RollPayroll)
+	payrollHttp.http.App.Get("/payrolls/year-to-date", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance, entity.UserRoleEmployee}), payrollHttp.YearToDate)
+	payrollHttp.http.App.Post("/payrolls/:payrollId/users", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.AddPayrollUsers)
+	payrollHttp.http.App.Post("/payrolls/:payrollId/earnings", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.AddPayrollEarning)
+	payrollHttp.http.App.Get("/payrolls/:payrollId/earnings", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.GetPayrollEarnings)
+	payrollHttp.http.App.Post("/payrolls/:payrollId/preview", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.PreviewPayroll)
+	payrollHttp.http.App.Post("/payrolls/:payrollId/retro-adjustments/detect", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.DetectRetroAdjustments)
+	payrollHttp.http.App.Get("/payrolls/:payrollId/retro-adjustments", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.GetRetroAdjustments)
 	payrollHttp.http.App.Post("/payrolls/:payrollId/payslips", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleEmployee}), payrollHttp.Payslips)
 
 	payrollHttp.http.App.Post("/payrolls/:payrollId/payslip-summaries", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payrollHttp.PayslipSummaries)
@@ -107,9 +118,267 @@ func (p *PayrollHttp) RollPayroll(c *fiber.Ctx) error {
 		return err
 	}
 
+	if p.http.config.Smtp.PayslipOnRoll {
+		// the roll is already done and must not be reported as failed, or it would be retried as already rolled.
+		// The deliveries are tracked per recipient and can be sent again through the deliveries endpoint
+		if _, err := p.notificationSvc.SendPayslips(c.Context(), uint(payrollIdInt)); err != nil {
+			log.Printf("payslip emails of payroll %d failed: %v", payrollIdInt, err)
+			message := "Payroll rolled, but the payslip emails could not be sent"
+			return cc.Ok(nil, &message)
+		}
+	}
+
+	return cc.Ok(nil, nil)
+}
+
+func (p *PayrollHttp) AddPayrollUsers(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	body := new(dto.AddPayrollUsersBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	err = p.payrollSvc.AddPayrollUsers(c.Context(), uint(payrollIdInt), body.UserIDs)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll or user not found")
+		}
+
+		if errors.Is(err, &internalerror.PayrollAlreadyRolledError{}) {
+			return cc.Conflict("Payroll already rolled")
+		}
+
+		if errors.Is(err, &internalerror.PayrollNotOffCycleError{}) {
+			return cc.UnprocessableEntity("Regular payroll already includes every employee")
+		}
+
+		return err
+	}
+
 	return cc.Ok(nil, nil)
 }
 
+func (p *PayrollHttp) AddPayrollEarning(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	body := new(dto.CreatePayrollEarningBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	earning, err := p.payrollSvc.AddPayrollEarning(c.Context(), body.ToPayrollEarningEntity(uint(payrollIdInt), authPayload.ID))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll or user not found")
+		}
+
+		if errors.Is(err, &internalerror.PayrollAlreadyRolledError{}) {
+			return cc.Conflict("Payroll already rolled")
+		}
+
+		if errors.Is(err, &internalerror.PayrollUserNotSelectedError{}) {
+			return cc.UnprocessableEntity("User is not selected in payroll")
+		}
+
+		return err
+	}
+
+	var response dto.PayrollEarningResponseDto
+	response.FromPayrollEarningEntity(earning)
+
+	return cc.Ok(response, nil)
+}
+
+func (p *PayrollHttp) GetPayrollEarnings(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	earnings, err := p.payrollSvc.GetPayrollEarnings(c.Context(), uint(payrollIdInt))
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.PayrollEarningResponseDto, len(earnings))
+	for i, earning := range earnings {
+		var response dto.PayrollEarningResponseDto
+		response.FromPayrollEarningEntity(earning)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (p *PayrollHttp) PreviewPayroll(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	thresholdPercent := p.http.config.Payroll.PreviewChangeThresholdPercent
+	if thresholdParam := c.Query("threshold_percent"); thresholdParam != "" {
+		thresholdPercent, err = strconv.ParseFloat(thresholdParam, 64)
+		if err != nil || thresholdPercent < 0 {
+			return cc.BadRequest("Invalid threshold percent query")
+		}
+	}
+
+	preview, err := p.payrollSvc.PreviewPayroll(c.Context(), uint(payrollIdInt), thresholdPercent)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+
+		if errors.Is(err, &internalerror.PayrollAlreadyRolledError{}) {
+			return cc.Conflict("Payroll already rolled")
+		}
+		return err
+	}
+
+	var response dto.PayrollPreviewDto
+	response.FromPayrollPreviewEntity(preview)
+
+	return cc.Ok(response, nil)
+}
+
+func (p *PayrollHttp) DetectRetroAdjustments(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	adjustments, err := p.payrollSvc.DetectRetroAdjustments(c.Context(), uint(payrollIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+
+		if errors.Is(err, &internalerror.PayrollAlreadyRolledError{}) {
+			return cc.Conflict("Payroll already rolled")
+		}
+
+		if errors.Is(err, &internalerror.PayrollNotRegularError{}) {
+			return cc.UnprocessableEntity("Payroll is not a regular payroll")
+		}
+		return err
+	}
+
+	responses := make([]*dto.PayrollRetroAdjustmentResponseDto, len(adjustments))
+	for i, adjustment := range adjustments {
+		var response dto.PayrollRetroAdjustmentResponseDto
+		response.FromPayrollRetroAdjustmentEntity(adjustment)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (p *PayrollHttp) GetRetroAdjustments(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	adjustments, err := p.payrollSvc.GetRetroAdjustments(c.Context(), uint(payrollIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+		return err
+	}
+
+	responses := make([]*dto.PayrollRetroAdjustmentResponseDto, len(adjustments))
+	for i, adjustment := range adjustments {
+		var response dto.PayrollRetroAdjustmentResponseDto
+		response.FromPayrollRetroAdjustmentEntity(adjustment)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (p *PayrollHttp) YearToDate(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	// the caller's own year to date unless another user is asked for
+	userId := authPayload.ID
+	if userIdParam := c.Query("user_id"); userIdParam != "" {
+		userIdInt, err := strconv.ParseUint(userIdParam, 10, 32)
+		if err != nil {
+			return cc.BadRequest("Invalid user ID query")
+		}
+		userId = uint(userIdInt)
+	}
+
+	year := utils.TimeNow().Year()
+	if yearParam := c.Query("year"); yearParam != "" {
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			return cc.BadRequest("Invalid year query")
+		}
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee && authPayload.ID != userId {
+		return cc.Unauthorized("Unauthorized to access other user's year to date")
+	}
+
+	yearToDate, err := p.payrollSvc.GetYearToDate(c.Context(), userId, year)
+	if err != nil {
+		return err
+	}
+
+	var response dto.UserYearToDateResponseDto
+	response.FromUserYearToDateEntity(yearToDate)
+
+	return cc.Ok(response, nil)
+}
+
 func (p *PayrollHttp) Payslips(c *fiber.Ctx) error {
 	cc := ctxresponse.CustomContext{Ctx: c}
 
@@ -140,6 +409,14 @@ func (p *PayrollHttp) Payslips(c *fiber.Ctx) error {
 			return cc.UnprocessableEntity("Payroll is not rolled yet")
 		}
 
+		if errors.Is(err, &internalerror.PayrollUserNotSelectedError{}) {
+			return cc.NotFound("User is not selected in payroll")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll or user not found")
+		}
+
 		return err
 	}
 
@@ -0,0 +1,218 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	payslipservice "d-payroll/service/payslip"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PayslipHttp struct {
+	http       *httpApp
+	payslipSvc payslipservice.PayslipService
+}
+
+func NewPayslipHttp(http *httpApp, payslipSvc payslipservice.PayslipService) {
+	payslipHttp := &PayslipHttp{
+		http:       http,
+		payslipSvc: payslipSvc,
+	}
+
+	payslipHttp.http.App.Post("/payrolls/:payrollId/payslips/pdf", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleEmployee}), payslipHttp.PayslipPdf)
+	payslipHttp.http.App.Post("/payrolls/:payrollId/payslips/archive", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payslipHttp.PayslipArchive)
+	payslipHttp.http.App.Get("/payslip-archives/:jobId", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payslipHttp.GetPayslipArchiveJob)
+	payslipHttp.http.App.Get("/payslip-archives/:jobId/download", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), payslipHttp.DownloadPayslipArchive)
+}
+
+func (p *PayslipHttp) PayslipPdf(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	userIdParam := c.Query("user_id")
+	userId, err := strconv.ParseUint(userIdParam, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid user ID query")
+	}
+
+	locale := entity.Locale(c.Query("locale"))
+	if locale != "" && !locale.IsValid() {
+		return cc.BadRequest("Invalid locale query")
+	}
+
+	protected := c.QueryBool("protected", false)
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee && authPayload.ID != uint(userId) {
+		return cc.Unauthorized("Unauthorized to access other user's payslips")
+	}
+
+	pdf, err := p.payslipSvc.RenderPayslipPdf(c.Context(), uint(payrollIdInt), uint(userId), locale, protected)
+	if err != nil {
+		if errors.Is(err, &internalerror.PayrollNotRolledError{}) {
+			return cc.UnprocessableEntity("Payroll is not rolled yet")
+		}
+
+		if errors.Is(err, &internalerror.PayrollUserNotSelectedError{}) {
+			return cc.NotFound("User is not selected in payroll")
+		}
+
+		var passwordError *internalerror.PayslipPasswordMissingError
+		if errors.As(err, &passwordError) {
+			return cc.UnprocessableEntity("Birth date is required to protect the payslip")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll or user not found")
+		}
+
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="payslip-%d-%d.pdf"`, payrollIdInt, userId))
+
+	return c.Send(pdf)
+}
+
+// PayslipArchive streams a ZIP of every protected payslip of a rolled payroll,
+// payrolls larger than the sync limit or requested with async=true are archived in the background
+func (p *PayslipHttp) PayslipArchive(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid payroll ID param")
+	}
+
+	locale := entity.Locale(c.Query("locale"))
+	if locale != "" && !locale.IsValid() {
+		return cc.BadRequest("Invalid locale query")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	userIds, err := p.payslipSvc.GetPayslipUserIds(c.Context(), uint(payrollIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.PayrollNotRolledError{}) {
+			return cc.UnprocessableEntity("Payroll is not rolled yet")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payroll not found")
+		}
+
+		return err
+	}
+
+	// every archived payslip is protected, the employees without a birth date are listed before anything is written
+	if err := p.payslipSvc.CheckPayslipPasswords(c.Context(), userIds); err != nil {
+		var passwordError *internalerror.PayslipPasswordMissingError
+		if errors.As(err, &passwordError) {
+			return cc.UnprocessableEntityWithData("Birth date is required to protect the payslips", passwordError.UserIDs)
+		}
+
+		return err
+	}
+
+	if c.QueryBool("async", false) || len(userIds) > p.http.config.Payslip.ArchiveSyncMaxUsers {
+		job, err := p.payslipSvc.StartPayslipArchive(c.Context(), uint(payrollIdInt), userIds, locale, authPayload.ID)
+		if err != nil {
+			return err
+		}
+
+		jobDto := &dto.PayslipArchiveJobDto{}
+		jobDto.FromPayslipArchiveJobEntity(job)
+
+		c.Status(fiber.StatusAccepted)
+		return cc.Ok(jobDto, nil)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="payslips-%d.zip"`, payrollIdInt))
+
+	// the request context is released once the handler returns, the stream runs on its own context. The headers are
+	// already sent by then, a failure drops the connection so the client never gets a short archive that looks complete
+	requestCtx := c.Context()
+	requestCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := p.payslipSvc.WritePayslipArchive(context.Background(), uint(payrollIdInt), userIds, locale, w); err != nil {
+			log.Printf("payslip archive of payroll %d failed: %v", payrollIdInt, err)
+			requestCtx.Conn().Close()
+			return
+		}
+		w.Flush()
+	})
+
+	return nil
+}
+
+func (p *PayslipHttp) GetPayslipArchiveJob(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	jobId := c.Params("jobId")
+	jobIdInt, err := strconv.ParseUint(jobId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid job ID param")
+	}
+
+	job, err := p.payslipSvc.GetPayslipArchiveJob(c.Context(), uint(jobIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payslip archive not found")
+		}
+
+		return err
+	}
+
+	jobDto := &dto.PayslipArchiveJobDto{}
+	jobDto.FromPayslipArchiveJobEntity(job)
+
+	return cc.Ok(jobDto, nil)
+}
+
+func (p *PayslipHttp) DownloadPayslipArchive(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	jobId := c.Params("jobId")
+	jobIdInt, err := strconv.ParseUint(jobId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid job ID param")
+	}
+
+	filePath, err := p.payslipSvc.GetPayslipArchiveFilePath(c.Context(), uint(jobIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.PayslipArchiveNotReadyError{}) {
+			return cc.UnprocessableEntity("Payslip archive is not ready yet")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Payslip archive not found")
+		}
+
+		return err
+	}
+
+	return c.Download(filePath)
+}
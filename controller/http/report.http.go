@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	reportrenderer "d-payroll/renderer/report"
+	reportservice "d-payroll/service/report"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportHttp struct {
+	http      *httpApp
+	reportSvc reportservice.ReportService
+}
+
+func NewReportHttp(http *httpApp, reportSvc reportservice.ReportService) {
+	reportHttp := &ReportHttp{
+		http:      http,
+		reportSvc: reportSvc,
+	}
+
+	reportHttp.http.App.Get("/payrolls/:payrollId/reports/register", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), reportHttp.PayrollRegister)
+	reportHttp.http.App.Get("/payrolls/:payrollId/reports/cost", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), reportHttp.PayrollCostReport)
+	reportHttp.http.App.Get("/attendances/report", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance, entity.UserRoleEmployee}), reportHttp.AttendanceReport)
+}
+
+// maxAttendanceReportDays bounds the date range of an attendance report, every day is read per employee
+const maxAttendanceReportDays = 366
+
+// getReportPayroll validates the request before anything is streamed, once the stream starts the status is sent
+func (r *ReportHttp) getReportPayroll(c *fiber.Ctx) (*entity.Payroll, entity.ReportFormat, error) {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	payrollId := c.Params("payrollId")
+	payrollIdInt, err := strconv.ParseUint(payrollId, 10, 32)
+	if err != nil {
+		return nil, "", cc.BadRequest("Invalid payroll ID param")
+	}
+
+	format := entity.ReportFormat(c.Query("format", string(entity.ReportFormatJson)))
+	if !reportrenderer.IsFormatSupported(format) {
+		return nil, "", cc.BadRequest("Invalid format query, expected json, csv or xlsx")
+	}
+
+	payroll, err := r.reportSvc.GetRolledPayroll(c.Context(), uint(payrollIdInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.PayrollNotRolledError{}) {
+			return nil, "", cc.UnprocessableEntity("Payroll is not rolled yet")
+		}
+
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return nil, "", cc.NotFound("Payroll not found")
+		}
+
+		return nil, "", err
+	}
+
+	return payroll, format, nil
+}
+
+func (r *ReportHttp) PayrollRegister(c *fiber.Ctx) error {
+	payroll, format, err := r.getReportPayroll(c)
+	if payroll == nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, reportrenderer.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, reportrenderer.Filename(fmt.Sprintf("payroll-register-%d", *payroll.ID), format)))
+
+	// the headers are already sent once the stream starts, a failure can only truncate the report
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		r.reportSvc.WritePayrollRegister(context.Background(), payroll, format, w)
+		w.Flush()
+	})
+
+	return nil
+}
+
+func (r *ReportHttp) PayrollCostReport(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	groupBy := entity.ReportGroupBy(c.Query("group_by", string(entity.ReportGroupByDepartment)))
+	if !groupBy.IsValid() {
+		return cc.BadRequest("Invalid group by query, expected department or cost_center")
+	}
+
+	payroll, format, err := r.getReportPayroll(c)
+	if payroll == nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, reportrenderer.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, reportrenderer.Filename(fmt.Sprintf("payroll-cost-%d-%s", *payroll.ID, groupBy), format)))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		r.reportSvc.WritePayrollCostReport(context.Background(), payroll, groupBy, format, w)
+		w.Flush()
+	})
+
+	return nil
+}
+
+// AttendanceReport streams the attendance summary of an employee or a department over a date range, employees can
+// only read their own
+func (r *ReportHttp) AttendanceReport(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	var userId *uint
+	if userIdQuery := c.Query("user_id"); userIdQuery != "" {
+		userIdInt, err := strconv.ParseUint(userIdQuery, 10, 32)
+		if err != nil {
+			return cc.BadRequest("Invalid user ID query")
+		}
+		id := uint(userIdInt)
+		userId = &id
+	}
+
+	var department *string
+	if departmentQuery := c.Query("department"); departmentQuery != "" {
+		department = &departmentQuery
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee {
+		if department != nil || (userId != nil && *userId != authPayload.ID) {
+			return cc.Unauthorized("Unauthorized to access other user's attendance report")
+		}
+		userId = &authPayload.ID
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", c.Query("start_date"), time.Local)
+	if err != nil {
+		return cc.BadRequest("Invalid start date query, expected YYYY-MM-DD")
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", c.Query("end_date"), time.Local)
+	if err != nil {
+		return cc.BadRequest("Invalid end date query, expected YYYY-MM-DD")
+	}
+
+	if endDate.Before(startDate) {
+		return cc.BadRequest("End date must not be before start date")
+	}
+
+	if endDate.Sub(startDate) >= maxAttendanceReportDays*24*time.Hour {
+		return cc.BadRequest(fmt.Sprintf("Date range must not exceed %d days", maxAttendanceReportDays))
+	}
+
+	format := entity.ReportFormat(c.Query("format", string(entity.ReportFormatJson)))
+	if !reportrenderer.IsFormatSupported(format) {
+		return cc.BadRequest("Invalid format query, expected json, csv or xlsx")
+	}
+
+	users, err := r.reportSvc.GetAttendanceReportUsers(c.Context(), userId, department)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, reportrenderer.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, reportrenderer.Filename(fmt.Sprintf("attendance-%s-%s", startDate.Format("20060102"), endDate.Format("20060102")), format)))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		r.reportSvc.WriteAttendanceReport(context.Background(), users, startDate, endDate, format, w)
+		w.Flush()
+	})
+
+	return nil
+}
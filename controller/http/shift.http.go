@@ -0,0 +1,237 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	shiftservice "d-payroll/service/shift"
+	"d-payroll/utils"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ShiftHttp struct {
+	http     *httpApp
+	shiftSvc shiftservice.ShiftService
+}
+
+func NewShiftHttp(http *httpApp, shiftSvc shiftservice.ShiftService) {
+	shiftHttp := &ShiftHttp{
+		http:     http,
+		shiftSvc: shiftSvc,
+	}
+
+	shiftHttp.http.App.Post("/shifts", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), shiftHttp.CreateShift)
+	shiftHttp.http.App.Get("/shifts", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), shiftHttp.GetShifts)
+	shiftHttp.http.App.Post("/shift-rotations", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), shiftHttp.CreateShiftRotation)
+	shiftHttp.http.App.Get("/shift-rotations", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), shiftHttp.GetShiftRotations)
+	shiftHttp.http.App.Post("/users/:id/shift-rotations", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), shiftHttp.AssignShiftRotation)
+	shiftHttp.http.App.Get("/users/:id/shift-rotations", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), shiftHttp.GetUserShiftRotations)
+	shiftHttp.http.App.Get("/users/:id/work-schedule", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleEmployee}), shiftHttp.GetWorkSchedule)
+}
+
+func (s *ShiftHttp) CreateShift(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	shift := new(dto.CreateShiftBodyDto)
+	if err := c.BodyParser(shift); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err := utils.ValidateStruct(shift)
+	if err != nil {
+		return err
+	}
+
+	createdShift, err := s.shiftSvc.CreateShift(c.Context(), shift.ToShiftEntity())
+	if err != nil {
+		if errors.Is(err, &internalerror.ShiftBreakTooLongError{}) {
+			return cc.BadRequest(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.ShiftResponseDto
+	response.FromShiftEntity(createdShift)
+
+	return cc.Ok(response, nil)
+}
+
+func (s *ShiftHttp) GetShifts(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	shifts, err := s.shiftSvc.GetShifts(c.Context())
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.ShiftResponseDto, len(shifts))
+	for i, shift := range shifts {
+		var response dto.ShiftResponseDto
+		response.FromShiftEntity(shift)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (s *ShiftHttp) CreateShiftRotation(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	rotation := new(dto.CreateShiftRotationBodyDto)
+	if err := c.BodyParser(rotation); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err := utils.ValidateStruct(rotation)
+	if err != nil {
+		return err
+	}
+
+	createdRotation, err := s.shiftSvc.CreateShiftRotation(c.Context(), rotation.ToShiftRotationEntity())
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Shift not found")
+		}
+
+		return err
+	}
+
+	var response dto.ShiftRotationResponseDto
+	response.FromShiftRotationEntity(createdRotation)
+
+	return cc.Ok(response, nil)
+}
+
+func (s *ShiftHttp) GetShiftRotations(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	rotations, err := s.shiftSvc.GetShiftRotations(c.Context())
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.ShiftRotationResponseDto, len(rotations))
+	for i, rotation := range rotations {
+		var response dto.ShiftRotationResponseDto
+		response.FromShiftRotationEntity(rotation)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (s *ShiftHttp) AssignShiftRotation(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	assignment := new(dto.AssignShiftRotationBodyDto)
+	if err := c.BodyParser(assignment); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(assignment)
+	if err != nil {
+		return err
+	}
+
+	createdAssignment, err := s.shiftSvc.AssignShiftRotation(c.Context(), assignment.ToUserShiftRotationEntity(uint(idInt), authPayload.ID))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User or shift rotation not found")
+		}
+
+		return err
+	}
+
+	var response dto.UserShiftRotationResponseDto
+	response.FromUserShiftRotationEntity(createdAssignment)
+
+	return cc.Ok(response, nil)
+}
+
+func (s *ShiftHttp) GetUserShiftRotations(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	assignments, err := s.shiftSvc.GetUserShiftRotations(c.Context(), uint(idInt))
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.UserShiftRotationResponseDto, len(assignments))
+	for i, assignment := range assignments {
+		var response dto.UserShiftRotationResponseDto
+		response.FromUserShiftRotationEntity(assignment)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+// GetWorkSchedule returns the shift the user works on a day, today by default. Employees can only read their own
+func (s *ShiftHttp) GetWorkSchedule(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee && authPayload.ID != uint(idInt) {
+		return cc.Unauthorized("Unauthorized to access other user's work schedule")
+	}
+
+	loc, err := s.shiftSvc.GetUserLocation(c.Context(), uint(idInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+		return err
+	}
+
+	day := utils.StartOfDay(utils.TimeNow().In(loc))
+	if dateQuery := c.Query("date"); dateQuery != "" {
+		day, err = time.ParseInLocation("2006-01-02", dateQuery, loc)
+		if err != nil {
+			return cc.BadRequest("Invalid date query, expected YYYY-MM-DD")
+		}
+	}
+
+	schedule, err := s.shiftSvc.GetWorkSchedule(c.Context(), uint(idInt), day)
+	if err != nil {
+		return err
+	}
+
+	var response dto.WorkScheduleResponseDto
+	response.FromWorkScheduleEntity(day, schedule)
+
+	return cc.Ok(response, nil)
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	taxservice "d-payroll/service/tax"
+	"d-payroll/utils"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxHttp struct {
+	http   *httpApp
+	taxSvc taxservice.TaxService
+}
+
+func NewTaxHttp(http *httpApp, taxSvc taxservice.TaxService) {
+	taxHttp := &TaxHttp{
+		http:   http,
+		taxSvc: taxSvc,
+	}
+
+	taxHttp.http.App.Get("/tax-certificates/ebupot", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), taxHttp.Ebupot)
+	taxHttp.http.App.Get("/tax-certificates/:userId", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance, entity.UserRoleEmployee}), taxHttp.TaxCertificate)
+	taxHttp.http.App.Get("/tax-certificates/:userId/pdf", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance, entity.UserRoleEmployee}), taxHttp.TaxCertificatePdf)
+}
+
+func getYearQuery(c *fiber.Ctx) (int, error) {
+	year := utils.TimeNow().Year()
+	if yearParam := c.Query("year"); yearParam != "" {
+		return strconv.Atoi(yearParam)
+	}
+	return year, nil
+}
+
+// getCertificateQuery reads the user and the year of a certificate, employees can only read their own
+func (t *TaxHttp) getCertificateQuery(c *fiber.Ctx) (uint, int, error) {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	userId, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return 0, 0, cc.BadRequest("Invalid user ID param")
+	}
+
+	year, err := getYearQuery(c)
+	if err != nil {
+		return 0, 0, cc.BadRequest("Invalid year query")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if authPayload.Role == entity.UserRoleEmployee && authPayload.ID != uint(userId) {
+		return 0, 0, cc.Unauthorized("Unauthorized to access other user's tax certificate")
+	}
+
+	return uint(userId), year, nil
+}
+
+func (t *TaxHttp) TaxCertificate(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	userId, year, err := t.getCertificateQuery(c)
+	if userId == 0 {
+		return err
+	}
+
+	certificate, err := t.taxSvc.GetTaxCertificate(c.Context(), userId, year)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("No rolled payroll found for the user in the year")
+		}
+		var withheldError *internalerror.TaxNotWithheldError
+		if errors.As(err, &withheldError) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.TaxCertificateResponseDto
+	response.FromTaxCertificateEntity(certificate)
+
+	return cc.Ok(response, nil)
+}
+
+func (t *TaxHttp) TaxCertificatePdf(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	userId, year, err := t.getCertificateQuery(c)
+	if userId == 0 {
+		return err
+	}
+
+	content, err := t.taxSvc.RenderTaxCertificatePdf(c.Context(), userId, year)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("No rolled payroll found for the user in the year")
+		}
+		var withheldError *internalerror.TaxNotWithheldError
+		if errors.As(err, &withheldError) {
+			return cc.UnprocessableEntity(err.Error())
+		}
+
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="1721-a1-%d-%d.pdf"`, year, userId))
+
+	return c.Send(content)
+}
+
+func (t *TaxHttp) Ebupot(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	year, err := getYearQuery(c)
+	if err != nil {
+		return cc.BadRequest("Invalid year query")
+	}
+
+	// the employees whose certificate cannot be issued are listed before anything is written
+	if err := t.taxSvc.CheckEbupot(c.Context(), year); err != nil {
+		var withheldError *internalerror.TaxNotWithheldError
+		if errors.As(err, &withheldError) {
+			return cc.UnprocessableEntityWithData(err.Error(), withheldError.UserIDs)
+		}
+
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="ebupot-1721-a1-%d.csv"`, year))
+
+	// the headers are already sent once the stream starts, a failure drops the connection so the client never gets a
+	// short file that looks complete
+	requestCtx := c.Context()
+	requestCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := t.taxSvc.WriteEbupotCsv(context.Background(), year, w); err != nil {
+			log.Printf("e-Bupot export of %d failed: %v", year, err)
+			requestCtx.Conn().Close()
+			return
+		}
+		w.Flush()
+	})
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	thrservice "d-payroll/service/thr"
+	"d-payroll/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ThrHttp struct {
+	http   *httpApp
+	thrSvc thrservice.ThrService
+}
+
+func NewThrHttp(http *httpApp, thrSvc thrservice.ThrService) {
+	thrHttp := &ThrHttp{
+		http:   http,
+		thrSvc: thrSvc,
+	}
+
+	thrHttp.http.App.Post("/payrolls/thr", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), thrHttp.CalculateThr)
+}
+
+func (t *ThrHttp) CalculateThr(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	body := new(dto.CalculateThrBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	result, err := t.thrSvc.CalculateThr(c.Context(), body.ToThrCalculationEntity(authPayload.ID))
+	if err != nil {
+		return err
+	}
+
+	var response dto.ThrResultDto
+	response.FromThrResultEntity(result)
+
+	return cc.Ok(response, nil)
+}
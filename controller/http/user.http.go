@@ -5,8 +5,10 @@ import (
 	"d-payroll/controller/http/dto"
 	"d-payroll/controller/http/middleware"
 	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
 	userservice "d-payroll/service/user"
 	"d-payroll/utils"
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,6 +25,16 @@ func NewUserHttp(h *httpApp, userSvc userservice.UserService) {
 
 	h.App.Post("/users", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin}), userHttp.CreateUser)
 	h.App.Get("/users/:id", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin}), userHttp.getUserById)
+	h.App.Put("/users/:id/device-pin", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin}), userHttp.AssignDevicePin)
+	h.App.Put("/users/:id/attendance-penalty-exemption", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin}), userHttp.SetAttendancePenaltyExemption)
+	h.App.Post("/users/:id/salaries", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin}), userHttp.ChangeSalary)
+	h.App.Get("/users/:id/salaries", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin}), userHttp.GetSalaryHistory)
+	h.App.Put("/users/:id/bank-account", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), userHttp.RequestBankAccountChange)
+	h.App.Get("/users/:id/bank-account", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), userHttp.GetBankAccount)
+	h.App.Get("/users/:id/bank-account/changes", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), userHttp.GetBankAccountHistory)
+	h.App.Get("/bank-account-changes", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), userHttp.GetBankAccountChanges)
+	h.App.Post("/bank-account-changes/:changeId/approve", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), userHttp.ApproveBankAccountChange)
+	h.App.Post("/bank-account-changes/:changeId/reject", middleware.Authorization(h.config, []entity.UserRole{entity.UserRoleAdmin, entity.UserRoleFinance}), userHttp.RejectBankAccountChange)
 }
 
 func (u *UserHttp) CreateUser(c *fiber.Ctx) error {
@@ -40,6 +52,10 @@ func (u *UserHttp) CreateUser(c *fiber.Ctx) error {
 
 	createdUser, err := u.userSvc.CreateUser(c.Context(), user.ToUserEntity())
 	if err != nil {
+		if errors.Is(err, &internalerror.DevicePinTakenError{}) {
+			return cc.Conflict(err.Error())
+		}
+
 		return err
 	}
 
@@ -68,3 +84,324 @@ func (u *UserHttp) getUserById(c *fiber.Ctx) error {
 
 	return cc.Ok(response, nil)
 }
+
+// AssignDevicePin sets the PIN the user is enrolled with on the biometric devices
+func (u *UserHttp) AssignDevicePin(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	body := new(dto.AssignDevicePinBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userSvc.UpdateDevicePin(c.Context(), uint(idInt), body.DevicePin)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+
+		if errors.Is(err, &internalerror.DevicePinTakenError{}) {
+			return cc.Conflict(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.GetUserByIdResponseDto
+	response.FromUserEntity(user)
+
+	return cc.Ok(response, nil)
+}
+
+// SetAttendancePenaltyExemption exempts the user from the lateness and early departure deductions, or lifts it
+func (u *UserHttp) SetAttendancePenaltyExemption(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	body := new(dto.SetAttendancePenaltyExemptionBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userSvc.UpdateAttendancePenaltyExempt(c.Context(), uint(idInt), *body.Exempt)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+
+		return err
+	}
+
+	var response dto.GetUserByIdResponseDto
+	response.FromUserEntity(user)
+
+	return cc.Ok(response, nil)
+}
+
+func (u *UserHttp) ChangeSalary(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	salary := new(dto.ChangeSalaryBodyDto)
+	if err := c.BodyParser(salary); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(salary)
+	if err != nil {
+		return err
+	}
+
+	createdSalary, err := u.userSvc.ChangeSalary(c.Context(), salary.ToUserSalaryEntity(uint(idInt), authPayload.ID))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+
+		return err
+	}
+
+	var response dto.UserSalaryResponseDto
+	response.FromUserSalaryEntity(createdSalary)
+
+	return cc.Ok(response, nil)
+}
+
+func (u *UserHttp) GetSalaryHistory(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	salaries, err := u.userSvc.GetSalaryHistory(c.Context(), uint(idInt))
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.UserSalaryResponseDto, len(salaries))
+	for i, salary := range salaries {
+		var response dto.UserSalaryResponseDto
+		response.FromUserSalaryEntity(salary)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (u *UserHttp) RequestBankAccountChange(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	body := new(dto.RequestBankAccountChangeBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	change, err := u.userSvc.RequestBankAccountChange(c.Context(), body.ToUserBankAccountChangeEntity(uint(idInt), authPayload.ID))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User not found")
+		}
+		if errors.Is(err, &internalerror.BankAccountMethodMismatchError{}) {
+			return cc.BadRequest(err.Error())
+		}
+		if errors.Is(err, &internalerror.BankAccountChangePendingError{}) {
+			return cc.Conflict(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.UserBankAccountChangeResponseDto
+	response.FromUserBankAccountChangeEntity(change, authPayload.Role == entity.UserRoleFinance)
+
+	c.Status(fiber.StatusAccepted)
+	return cc.Ok(response, nil)
+}
+
+func (u *UserHttp) GetBankAccount(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	account, err := u.userSvc.GetBankAccount(c.Context(), uint(idInt))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Bank account not found")
+		}
+
+		return err
+	}
+
+	var response dto.UserBankAccountResponseDto
+	response.FromUserBankAccountEntity(account, authPayload.Role == entity.UserRoleFinance)
+
+	return cc.Ok(response, nil)
+}
+
+func (u *UserHttp) GetBankAccountHistory(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	userID := uint(idInt)
+	return u.respondBankAccountChanges(&cc, &userID, nil)
+}
+
+func (u *UserHttp) GetBankAccountChanges(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	var status *entity.BankAccountChangeStatus
+	if statusQuery := c.Query("status"); statusQuery != "" {
+		converted := entity.BankAccountChangeStatus(statusQuery)
+		switch converted {
+		case entity.BankAccountChangeStatusPending, entity.BankAccountChangeStatusApproved, entity.BankAccountChangeStatusRejected:
+		default:
+			return cc.BadRequest("Invalid status query")
+		}
+		status = &converted
+	}
+
+	return u.respondBankAccountChanges(&cc, nil, status)
+}
+
+func (u *UserHttp) respondBankAccountChanges(cc *ctxresponse.CustomContext, userID *uint, status *entity.BankAccountChangeStatus) error {
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	changes, err := u.userSvc.GetBankAccountChanges(cc.Context(), userID, status)
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.UserBankAccountChangeResponseDto, len(changes))
+	for i, change := range changes {
+		var response dto.UserBankAccountChangeResponseDto
+		response.FromUserBankAccountChangeEntity(change, authPayload.Role == entity.UserRoleFinance)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+func (u *UserHttp) ApproveBankAccountChange(c *fiber.Ctx) error {
+	return u.reviewBankAccountChange(c, true)
+}
+
+func (u *UserHttp) RejectBankAccountChange(c *fiber.Ctx) error {
+	return u.reviewBankAccountChange(c, false)
+}
+
+func (u *UserHttp) reviewBankAccountChange(c *fiber.Ctx, approved bool) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	changeId := c.Params("changeId")
+	changeIdInt, err := strconv.ParseUint(changeId, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid change ID param")
+	}
+
+	authPayload, err := cc.GetAuthPayload()
+	if err != nil {
+		return err
+	}
+
+	body := new(dto.ReviewBankAccountChangeBodyDto)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(body); err != nil {
+			return cc.BadRequest("Invalid request body")
+		}
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	change, err := u.userSvc.ReviewBankAccountChange(c.Context(), uint(changeIdInt), authPayload.ID, approved, body.Note)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("Bank account change not found")
+		}
+		if errors.Is(err, &internalerror.BankAccountChangeSelfReviewError{}) {
+			return cc.Forbidden(err.Error())
+		}
+		if errors.Is(err, &internalerror.BankAccountChangeReviewerRoleError{}) || errors.Is(err, &internalerror.BankAccountChangeOwnAccountReviewError{}) {
+			return cc.Forbidden(err.Error())
+		}
+		if errors.Is(err, &internalerror.BankAccountChangeReviewedError{}) {
+			return cc.Conflict(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.UserBankAccountChangeResponseDto
+	response.FromUserBankAccountChangeEntity(change, authPayload.Role == entity.UserRoleFinance)
+
+	return cc.Ok(response, nil)
+}
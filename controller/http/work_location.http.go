@@ -0,0 +1,112 @@
+package http
+
+import (
+	ctxresponse "d-payroll/controller/http/customctx"
+	"d-payroll/controller/http/dto"
+	"d-payroll/controller/http/middleware"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	worklocationservice "d-payroll/service/worklocation"
+	"d-payroll/utils"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WorkLocationHttp struct {
+	http            *httpApp
+	workLocationSvc worklocationservice.WorkLocationService
+}
+
+func NewWorkLocationHttp(http *httpApp, workLocationSvc worklocationservice.WorkLocationService) {
+	workLocationHttp := &WorkLocationHttp{
+		http:            http,
+		workLocationSvc: workLocationSvc,
+	}
+
+	workLocationHttp.http.App.Post("/work-locations", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), workLocationHttp.CreateWorkLocation)
+	workLocationHttp.http.App.Get("/work-locations", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), workLocationHttp.GetWorkLocations)
+	workLocationHttp.http.App.Put("/users/:id/work-location", middleware.Authorization(http.config, []entity.UserRole{entity.UserRoleAdmin}), workLocationHttp.AssignWorkLocation)
+}
+
+func (w *WorkLocationHttp) CreateWorkLocation(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	workLocation := new(dto.CreateWorkLocationBodyDto)
+	if err := c.BodyParser(workLocation); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err := utils.ValidateStruct(workLocation)
+	if err != nil {
+		return err
+	}
+
+	createdWorkLocation, err := w.workLocationSvc.CreateWorkLocation(c.Context(), workLocation.ToWorkLocationEntity())
+	if err != nil {
+		if errors.Is(err, &internalerror.WorkLocationAreaRequiredError{}) {
+			return cc.BadRequest(err.Error())
+		}
+
+		return err
+	}
+
+	var response dto.WorkLocationResponseDto
+	response.FromWorkLocationEntity(createdWorkLocation)
+
+	return cc.Ok(response, nil)
+}
+
+func (w *WorkLocationHttp) GetWorkLocations(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	workLocations, err := w.workLocationSvc.GetWorkLocations(c.Context())
+	if err != nil {
+		return err
+	}
+
+	responses := make([]*dto.WorkLocationResponseDto, len(workLocations))
+	for i, workLocation := range workLocations {
+		var response dto.WorkLocationResponseDto
+		response.FromWorkLocationEntity(workLocation)
+		responses[i] = &response
+	}
+
+	return cc.Ok(responses, nil)
+}
+
+// AssignWorkLocation sets the site the user checks in at and whether the user is allowed to work remotely
+func (w *WorkLocationHttp) AssignWorkLocation(c *fiber.Ctx) error {
+	cc := ctxresponse.CustomContext{Ctx: c}
+
+	id := c.Params("id")
+	idInt, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return cc.BadRequest("Invalid ID param")
+	}
+
+	body := new(dto.AssignWorkLocationBodyDto)
+	if err := c.BodyParser(body); err != nil {
+		return cc.BadRequest("Invalid request body")
+	}
+
+	err = utils.ValidateStruct(body)
+	if err != nil {
+		return err
+	}
+
+	user, err := w.workLocationSvc.AssignWorkLocation(c.Context(), uint(idInt), body.WorkLocationID, body.RemoteWork)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return cc.NotFound("User or work location not found")
+		}
+
+		return err
+	}
+
+	var response dto.GetUserByIdResponseDto
+	response.FromUserEntity(user)
+
+	return cc.Ok(response, nil)
+}
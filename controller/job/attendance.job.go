@@ -0,0 +1,67 @@
+package job
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	attendanceservice "d-payroll/service/attendance"
+	notificationservice "d-payroll/service/notification"
+	"log"
+	"time"
+)
+
+type AttendanceJob struct {
+	config          *config.Config
+	attendanceSvc   attendanceservice.AttendanceService
+	notificationSvc notificationservice.NotificationService
+}
+
+func NewAttendanceJob(config *config.Config, attendanceSvc attendanceservice.AttendanceService, notificationSvc notificationservice.NotificationService) *AttendanceJob {
+	return &AttendanceJob{
+		config:          config,
+		attendanceSvc:   attendanceSvc,
+		notificationSvc: notificationSvc,
+	}
+}
+
+// Start runs the auto-close of the missing checkouts in the background every ATTENDANCE_AUTO_CLOSE_INTERVAL_MINUTES
+// until the context is done, an interval of 0 disables it
+func (j *AttendanceJob) Start(ctx context.Context) {
+	interval := time.Duration(j.config.Attendance.AutoCloseIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := j.AutoCloseMissingCheckouts(ctx); err != nil {
+					log.Printf("attendance auto close failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// AutoCloseMissingCheckouts closes the sessions still open past the cutoff and notifies their employees, a failed
+// notification leaves the session closed
+func (j *AttendanceJob) AutoCloseMissingCheckouts(ctx context.Context) ([]*entity.UserAttendance, error) {
+	closed, err := j.attendanceSvc.AutoCloseMissingCheckouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, checkout := range closed {
+		if err := j.notificationSvc.NotifyAttendanceAutoClosed(ctx, checkout); err != nil {
+			log.Printf("attendance auto close notification to user %d failed: %v", checkout.UserID, err)
+		}
+	}
+
+	return closed, nil
+}
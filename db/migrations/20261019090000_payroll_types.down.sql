@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS payroll_earnings;
+DROP TABLE IF EXISTS payroll_users;
+
+ALTER TABLE payrolls DROP COLUMN IF EXISTS type;
+
+DROP TYPE IF EXISTS payroll_type;
+
+COMMIT;
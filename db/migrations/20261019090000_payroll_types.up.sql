@@ -0,0 +1,31 @@
+BEGIN;
+
+CREATE TYPE payroll_type AS ENUM ('REGULAR', 'BONUS', 'THR', 'CORRECTION');
+
+ALTER TABLE payrolls ADD COLUMN type payroll_type NOT NULL DEFAULT 'REGULAR';
+
+CREATE TABLE payroll_users (
+	id SERIAL PRIMARY KEY,
+	payroll_id INT NOT NULL,
+	user_id INT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL,
+	UNIQUE (payroll_id, user_id)
+);
+
+CREATE TABLE payroll_earnings (
+	id SERIAL PRIMARY KEY,
+	payroll_id INT NOT NULL,
+	user_id INT NOT NULL,
+	description TEXT NOT NULL,
+	amount INT NOT NULL,
+	created_by_user_id INT DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX payroll_earnings_payroll_id_user_id_idx ON payroll_earnings (payroll_id, user_id);
+
+COMMIT;
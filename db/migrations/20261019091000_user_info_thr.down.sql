@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_infos DROP COLUMN IF EXISTS joined_at;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS thr_holiday;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS religion;
+
+COMMIT;
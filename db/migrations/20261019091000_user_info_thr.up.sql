@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN religion VARCHAR(32) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN thr_holiday VARCHAR(32) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN joined_at DATE DEFAULT NULL;
+
+COMMIT;
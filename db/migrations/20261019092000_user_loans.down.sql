@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS loan_installments;
+DROP TABLE IF EXISTS user_loans;
+
+DROP TYPE IF EXISTS loan_type;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TYPE loan_type AS ENUM ('LOAN', 'ADVANCE');
+
+CREATE TABLE user_loans (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	type loan_type NOT NULL,
+	description TEXT NOT NULL,
+	principal INT NOT NULL,
+	installment_count INT NOT NULL,
+	installment_amount INT NOT NULL,
+	remaining_balance INT NOT NULL,
+	started_at TIMESTAMP NOT NULL,
+	created_by_user_id INT DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX user_loans_user_id_idx ON user_loans (user_id);
+
+CREATE TABLE loan_installments (
+	id SERIAL PRIMARY KEY,
+	loan_id INT NOT NULL,
+	payroll_id INT NOT NULL,
+	amount INT NOT NULL,
+	paid_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL,
+	UNIQUE (loan_id, payroll_id)
+);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS payroll_retro_adjustments;
+
+ALTER TABLE user_payslip_summaries DROP COLUMN IF EXISTS payslip;
+
+DROP TABLE IF EXISTS user_salaries;
+
+COMMIT;
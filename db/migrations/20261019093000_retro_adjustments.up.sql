@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TABLE user_salaries (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	monthly_salary INT NOT NULL,
+	effective_at TIMESTAMP NOT NULL,
+	created_by_user_id INT DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX user_salaries_user_id_effective_at_idx ON user_salaries (user_id, effective_at);
+
+ALTER TABLE user_payslip_summaries ADD COLUMN payslip JSONB DEFAULT NULL;
+
+CREATE TABLE payroll_retro_adjustments (
+	id SERIAL PRIMARY KEY,
+	payroll_id INT NOT NULL,
+	source_payroll_id INT NOT NULL,
+	user_id INT NOT NULL,
+	description TEXT NOT NULL,
+	amount INT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX payroll_retro_adjustments_payroll_id_idx ON payroll_retro_adjustments (payroll_id);
+CREATE INDEX payroll_retro_adjustments_source_payroll_id_user_id_idx ON payroll_retro_adjustments (source_payroll_id, user_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS payslip_archive_jobs;
+
+DROP TYPE IF EXISTS payslip_archive_status;
+
+ALTER TABLE user_infos DROP COLUMN IF EXISTS birth_date;
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN birth_date DATE DEFAULT NULL;
+
+CREATE TYPE payslip_archive_status AS ENUM ('PENDING', 'RUNNING', 'DONE', 'FAILED');
+
+CREATE TABLE payslip_archive_jobs (
+	id SERIAL PRIMARY KEY,
+	payroll_id INT NOT NULL,
+	locale VARCHAR(8) NOT NULL,
+	status payslip_archive_status NOT NULL DEFAULT 'PENDING',
+	total_count INT NOT NULL DEFAULT 0,
+	processed_count INT NOT NULL DEFAULT 0,
+	file_path TEXT DEFAULT NULL,
+	error_message TEXT DEFAULT NULL,
+	created_by_user_id INT DEFAULT NULL,
+	finished_at TIMESTAMP DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS payslip_deliveries;
+
+DROP TYPE IF EXISTS payslip_delivery_status;
+
+ALTER TABLE user_infos DROP COLUMN IF EXISTS email;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN email VARCHAR(255) DEFAULT NULL;
+
+CREATE TYPE payslip_delivery_status AS ENUM ('PENDING', 'SENT', 'FAILED', 'SKIPPED');
+
+CREATE TABLE payslip_deliveries (
+	id SERIAL PRIMARY KEY,
+	payroll_id INT NOT NULL,
+	user_id INT NOT NULL,
+	email VARCHAR(255) DEFAULT NULL,
+	status payslip_delivery_status NOT NULL DEFAULT 'PENDING',
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT DEFAULT NULL,
+	sent_at TIMESTAMP DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL,
+	UNIQUE (payroll_id, user_id)
+);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE user_bank_accounts (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL UNIQUE,
+	bank_code VARCHAR(16) NOT NULL,
+	account_number VARCHAR(34) NOT NULL,
+	account_name VARCHAR(70) NOT NULL,
+	updated_by_user_id INT DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS user_bank_account_changes;
+DROP TYPE IF EXISTS bank_account_change_status;
+
+ALTER TABLE user_bank_accounts DROP COLUMN IF EXISTS payment_method;
+DROP TYPE IF EXISTS payment_method;
+
+-- postgres cannot drop a value from an enum, FINANCE stays on user_role
+
+COMMIT;
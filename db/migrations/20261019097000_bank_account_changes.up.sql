@@ -0,0 +1,31 @@
+BEGIN;
+
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'FINANCE';
+
+CREATE TYPE payment_method AS ENUM ('TRANSFER', 'CASH', 'EWALLET');
+
+ALTER TABLE user_bank_accounts
+	ADD COLUMN payment_method payment_method NOT NULL DEFAULT 'TRANSFER';
+
+CREATE TYPE bank_account_change_status AS ENUM ('PENDING', 'APPROVED', 'REJECTED');
+
+CREATE TABLE user_bank_account_changes (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	payment_method payment_method NOT NULL,
+	bank_code VARCHAR(16) NOT NULL,
+	account_number VARCHAR(34) NOT NULL,
+	account_name VARCHAR(70) NOT NULL,
+	status bank_account_change_status NOT NULL DEFAULT 'PENDING',
+	requested_by_user_id INT NOT NULL,
+	reviewed_by_user_id INT DEFAULT NULL,
+	reviewed_at TIMESTAMP DEFAULT NULL,
+	review_note VARCHAR(255) DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX idx_user_bank_account_changes_user_id ON user_bank_account_changes (user_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE user_infos DROP COLUMN IF EXISTS cost_center;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN cost_center VARCHAR(32) DEFAULT NULL;
+
+COMMIT;
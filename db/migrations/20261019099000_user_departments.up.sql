@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN department VARCHAR(64) DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE user_infos DROP COLUMN IF EXISTS position;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS tax_status;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS gender;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS address;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS npwp;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS nik;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS full_name;
+
+COMMIT;
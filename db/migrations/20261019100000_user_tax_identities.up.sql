@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN full_name VARCHAR(100) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN nik VARCHAR(16) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN npwp VARCHAR(16) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN address VARCHAR(255) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN gender VARCHAR(8) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN tax_status VARCHAR(4) DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN position VARCHAR(64) DEFAULT NULL;
+
+COMMIT;
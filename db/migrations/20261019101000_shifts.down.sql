@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS user_shift_rotations;
+DROP TABLE IF EXISTS shift_rotation_days;
+DROP TABLE IF EXISTS shift_rotations;
+DROP TABLE IF EXISTS shifts;
+
+COMMIT;
@@ -0,0 +1,47 @@
+BEGIN;
+
+CREATE TABLE shifts (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	start_time VARCHAR(5) NOT NULL,
+	end_time VARCHAR(5) NOT NULL,
+	break_minutes INT NOT NULL DEFAULT 0,
+	grace_minutes INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE shift_rotations (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE shift_rotation_days (
+	id SERIAL PRIMARY KEY,
+	rotation_id INT NOT NULL,
+	day_index INT NOT NULL,
+	shift_id INT DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL,
+	UNIQUE (rotation_id, day_index)
+);
+
+CREATE TABLE user_shift_rotations (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	rotation_id INT NOT NULL,
+	effective_at TIMESTAMP NOT NULL,
+	created_by_user_id INT DEFAULT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX user_shift_rotations_user_id_effective_at_idx ON user_shift_rotations (user_id, effective_at);
+
+COMMIT;
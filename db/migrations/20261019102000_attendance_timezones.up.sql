@@ -0,0 +1,145 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN timezone VARCHAR(64) DEFAULT NULL;
+
+-- the existing times were recorded without a zone, in the zone of the server. It cannot be told from here, the
+-- payroll.server_timezone setting has to be set on the database before migrating one with data
+DO $$
+BEGIN
+	IF COALESCE(current_setting('payroll.server_timezone', true), '') = '' AND (
+		EXISTS (SELECT 1 FROM users)
+		OR EXISTS (SELECT 1 FROM user_attendances)
+		OR EXISTS (SELECT 1 FROM user_overtimes)
+		OR EXISTS (SELECT 1 FROM user_reimbursements)
+		OR EXISTS (SELECT 1 FROM user_payslip_summaries)
+		OR EXISTS (SELECT 1 FROM payrolls)
+		OR EXISTS (SELECT 1 FROM payroll_users)
+		OR EXISTS (SELECT 1 FROM payroll_earnings)
+		OR EXISTS (SELECT 1 FROM user_loans)
+		OR EXISTS (SELECT 1 FROM loan_installments)
+		OR EXISTS (SELECT 1 FROM user_salaries)
+		OR EXISTS (SELECT 1 FROM payroll_retro_adjustments)
+		OR EXISTS (SELECT 1 FROM payslip_archive_jobs)
+		OR EXISTS (SELECT 1 FROM payslip_deliveries)
+		OR EXISTS (SELECT 1 FROM user_bank_accounts)
+		OR EXISTS (SELECT 1 FROM user_bank_account_changes)
+		OR EXISTS (SELECT 1 FROM shifts)
+		OR EXISTS (SELECT 1 FROM shift_rotations)
+		OR EXISTS (SELECT 1 FROM shift_rotation_days)
+		OR EXISTS (SELECT 1 FROM user_shift_rotations)
+	) THEN
+		RAISE EXCEPTION 'payroll.server_timezone is not set, run ALTER DATABASE <db> SET payroll.server_timezone = ''<zone of the server>'' first';
+	END IF;
+END $$;
+
+ALTER TABLE users
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_attendances
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_overtimes
+	ALTER COLUMN overtime_at TYPE TIMESTAMPTZ USING overtime_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_reimbursements
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_payslip_summaries
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE payrolls
+	ALTER COLUMN started_at TYPE TIMESTAMPTZ USING started_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN ended_at TYPE TIMESTAMPTZ USING ended_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE payroll_users
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE payroll_earnings
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_loans
+	ALTER COLUMN started_at TYPE TIMESTAMPTZ USING started_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE loan_installments
+	ALTER COLUMN paid_at TYPE TIMESTAMPTZ USING paid_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_salaries
+	ALTER COLUMN effective_at TYPE TIMESTAMPTZ USING effective_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE payroll_retro_adjustments
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE payslip_archive_jobs
+	ALTER COLUMN finished_at TYPE TIMESTAMPTZ USING finished_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE payslip_deliveries
+	ALTER COLUMN sent_at TYPE TIMESTAMPTZ USING sent_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_bank_accounts
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_bank_account_changes
+	ALTER COLUMN reviewed_at TYPE TIMESTAMPTZ USING reviewed_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE shifts
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE shift_rotations
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE shift_rotation_days
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+ALTER TABLE user_shift_rotations
+	ALTER COLUMN effective_at TYPE TIMESTAMPTZ USING effective_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE current_setting('payroll.server_timezone', true),
+	ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE current_setting('payroll.server_timezone', true);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- an enum value cannot be dropped, the type is recreated without the breaks
+DELETE FROM user_attendances WHERE type IN ('BREAK_START', 'BREAK_END');
+
+ALTER TYPE attendance RENAME TO attendance_old;
+CREATE TYPE attendance AS ENUM ('CHECKIN', 'CHECKOUT');
+ALTER TABLE user_attendances ALTER COLUMN type TYPE attendance USING type::text::attendance;
+DROP TYPE attendance_old;
+
+COMMIT;
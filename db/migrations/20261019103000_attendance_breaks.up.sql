@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TYPE attendance ADD VALUE IF NOT EXISTS 'BREAK_START';
+ALTER TYPE attendance ADD VALUE IF NOT EXISTS 'BREAK_END';
+
+COMMIT;
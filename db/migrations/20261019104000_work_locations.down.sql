@@ -0,0 +1,18 @@
+BEGIN;
+
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS is_remote;
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS is_out_of_area;
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS work_location_id;
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS wifi_bssid;
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS longitude;
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS latitude;
+
+ALTER TABLE user_infos DROP COLUMN IF EXISTS remote_work;
+ALTER TABLE user_infos DROP COLUMN IF EXISTS work_location_id;
+
+DROP TABLE IF EXISTS work_location_networks;
+DROP TABLE IF EXISTS work_location_points;
+DROP TABLE IF EXISTS work_locations;
+
+COMMIT;
@@ -0,0 +1,50 @@
+BEGIN;
+
+CREATE TABLE work_locations (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	latitude DOUBLE PRECISION DEFAULT NULL,
+	longitude DOUBLE PRECISION DEFAULT NULL,
+	radius_meters DOUBLE PRECISION DEFAULT NULL,
+	policy VARCHAR(8) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	deleted_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE TABLE work_location_points (
+	id SERIAL PRIMARY KEY,
+	work_location_id INT NOT NULL,
+	point_index INT NOT NULL,
+	latitude DOUBLE PRECISION NOT NULL,
+	longitude DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	deleted_at TIMESTAMPTZ DEFAULT NULL,
+	UNIQUE (work_location_id, point_index)
+);
+
+CREATE TABLE work_location_networks (
+	id SERIAL PRIMARY KEY,
+	work_location_id INT NOT NULL,
+	type VARCHAR(8) NOT NULL,
+	value VARCHAR(64) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	deleted_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE INDEX work_location_networks_work_location_id_idx ON work_location_networks (work_location_id);
+
+ALTER TABLE user_infos ADD COLUMN work_location_id INT DEFAULT NULL;
+ALTER TABLE user_infos ADD COLUMN remote_work BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE user_attendances ADD COLUMN latitude DOUBLE PRECISION DEFAULT NULL;
+ALTER TABLE user_attendances ADD COLUMN longitude DOUBLE PRECISION DEFAULT NULL;
+ALTER TABLE user_attendances ADD COLUMN wifi_bssid VARCHAR(17) DEFAULT NULL;
+ALTER TABLE user_attendances ADD COLUMN ip_address VARCHAR(45) DEFAULT NULL;
+ALTER TABLE user_attendances ADD COLUMN work_location_id INT DEFAULT NULL;
+ALTER TABLE user_attendances ADD COLUMN is_out_of_area BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_attendances ADD COLUMN is_remote BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- the attendances added by a correction go with it, the superseded ones count again
+DELETE FROM user_attendances WHERE correction_id IS NOT NULL;
+
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS superseded_by_correction_id;
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS correction_id;
+
+DROP TABLE IF EXISTS attendance_corrections;
+DROP TYPE IF EXISTS attendance_correction_status;
+DROP TYPE IF EXISTS attendance_correction_type;
+
+COMMIT;
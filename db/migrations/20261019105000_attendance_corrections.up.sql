@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TYPE attendance_correction_type AS ENUM ('MISSING_CHECKIN', 'MISSING_CHECKOUT', 'WRONG_TIME');
+CREATE TYPE attendance_correction_status AS ENUM ('PENDING', 'APPROVED', 'REJECTED');
+
+CREATE TABLE attendance_corrections (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	type attendance_correction_type NOT NULL,
+	attendance_id INT DEFAULT NULL,
+	corrected_at TIMESTAMPTZ NOT NULL,
+	reason VARCHAR(255) NOT NULL,
+	status attendance_correction_status NOT NULL DEFAULT 'PENDING',
+	reviewed_by_user_id INT DEFAULT NULL,
+	reviewed_at TIMESTAMPTZ DEFAULT NULL,
+	review_note VARCHAR(255) DEFAULT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	deleted_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE INDEX idx_attendance_corrections_user_id ON attendance_corrections (user_id);
+
+ALTER TABLE user_attendances ADD COLUMN correction_id INT DEFAULT NULL;
+ALTER TABLE user_attendances ADD COLUMN superseded_by_correction_id INT DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- the sessions closed by the job are open again
+DELETE FROM user_attendances WHERE auto_close_policy IS NOT NULL;
+
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS auto_close_policy;
+
+COMMIT;
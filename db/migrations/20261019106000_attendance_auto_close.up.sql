@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE user_attendances ADD COLUMN auto_close_policy VARCHAR(20) DEFAULT NULL;
+
+COMMIT;
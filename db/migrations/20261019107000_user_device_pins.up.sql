@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN device_pin VARCHAR(24) DEFAULT NULL;
+
+CREATE UNIQUE INDEX user_infos_device_pin_idx ON user_infos (device_pin) WHERE device_pin IS NOT NULL;
+
+COMMIT;
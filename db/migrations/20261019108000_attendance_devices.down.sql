@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_attendances DROP COLUMN IF EXISTS device_id;
+
+DROP TABLE IF EXISTS attendance_devices;
+
+COMMIT;
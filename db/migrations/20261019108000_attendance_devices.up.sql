@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE attendance_devices (
+	id SERIAL PRIMARY KEY,
+	serial_number VARCHAR(64) NOT NULL,
+	name VARCHAR(100) NOT NULL,
+	api_key_hash VARCHAR(64) NOT NULL,
+	last_push_at TIMESTAMPTZ DEFAULT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	deleted_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX attendance_devices_serial_number_idx ON attendance_devices (serial_number) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX attendance_devices_api_key_hash_idx ON attendance_devices (api_key_hash);
+
+ALTER TABLE user_attendances ADD COLUMN device_id INT DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE user_infos ADD COLUMN attendance_penalty_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
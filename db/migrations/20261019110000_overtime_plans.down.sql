@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_overtimes DROP COLUMN IF EXISTS overtime_plan_id;
+
+DROP TABLE IF EXISTS overtime_plans;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE overtime_plans (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	description TEXT NOT NULL,
+	planned_at TIMESTAMPTZ NOT NULL,
+	duration_milis INT NOT NULL,
+	created_by_user_id INT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	deleted_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE INDEX overtime_plans_user_id_idx ON overtime_plans (user_id);
+
+-- a plan is worked once
+ALTER TABLE user_overtimes ADD COLUMN overtime_plan_id INT DEFAULT NULL;
+CREATE UNIQUE INDEX user_overtimes_overtime_plan_id_idx ON user_overtimes (overtime_plan_id) WHERE overtime_plan_id IS NOT NULL AND deleted_at IS NULL;
+
+COMMIT;
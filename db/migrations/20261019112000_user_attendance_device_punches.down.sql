@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS user_attendances_device_punch_idx;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- a punch imported twice before the index existed keeps its first row, the corrections of the other rows follow it
+CREATE TEMPORARY TABLE duplicate_device_punches ON COMMIT DROP AS
+SELECT id, MIN(id) OVER (PARTITION BY device_id, user_id, created_at) AS kept_id
+FROM user_attendances
+WHERE device_id IS NOT NULL;
+
+UPDATE attendance_corrections
+SET attendance_id = duplicate_device_punches.kept_id
+FROM duplicate_device_punches
+WHERE attendance_corrections.attendance_id = duplicate_device_punches.id
+	AND duplicate_device_punches.id <> duplicate_device_punches.kept_id;
+
+DELETE FROM user_attendances
+USING duplicate_device_punches
+WHERE user_attendances.id = duplicate_device_punches.id
+	AND duplicate_device_punches.id <> duplicate_device_punches.kept_id;
+
+CREATE UNIQUE INDEX user_attendances_device_punch_idx ON user_attendances (device_id, user_id, created_at);
+
+COMMIT;
@@ -7,18 +7,267 @@ type AttendanceType string
 const (
 	AttendanceTypeCheckIn  AttendanceType = "CHECKIN"
 	AttendanceTypeCheckOut AttendanceType = "CHECKOUT"
+	// a break is unpaid, it starts and ends within a session
+	AttendanceTypeBreakStart AttendanceType = "BREAK_START"
+	AttendanceTypeBreakEnd   AttendanceType = "BREAK_END"
 )
 
+// AttendanceAutoClosePolicy is how a session never checked out is closed by the auto-close job, PAY_SCHEDULED pays the
+// rest of the scheduled day, PAY_ZERO and REQUIRE_CORRECTION pay nothing, the latter until a correction is approved
+type AttendanceAutoClosePolicy string
+
+const (
+	AttendanceAutoClosePolicyPayZero           AttendanceAutoClosePolicy = "PAY_ZERO"
+	AttendanceAutoClosePolicyPayScheduled      AttendanceAutoClosePolicy = "PAY_SCHEDULED"
+	AttendanceAutoClosePolicyRequireCorrection AttendanceAutoClosePolicy = "REQUIRE_CORRECTION"
+)
+
+func (p AttendanceAutoClosePolicy) IsValid() bool {
+	switch p {
+	case AttendanceAutoClosePolicyPayZero, AttendanceAutoClosePolicyPayScheduled, AttendanceAutoClosePolicyRequireCorrection:
+		return true
+	}
+	return false
+}
+
 type UserAttendance struct {
-	ID        *uint
-	UserID    uint
-	Type      AttendanceType
+	ID       *uint
+	UserID   uint
+	Type     AttendanceType
+	Evidence *AttendanceEvidence
+	// WorkLocationID is the work location the check-in was verified against, IsOutOfArea flags a check-in accepted out
+	// of its area
+	WorkLocationID *uint
+	IsOutOfArea    bool
+	IsRemote       bool
+	// CorrectionID is the approved correction the attendance was added by, SupersededByCorrectionID the one that
+	// replaced it. A superseded attendance is kept for the audit but no longer counted
+	CorrectionID             *uint
+	SupersededByCorrectionID *uint
+	// AutoClosePolicy is set on a checkout added by the auto-close job, the policy the session is paid by
+	AutoClosePolicy *AttendanceAutoClosePolicy
+	// DeviceID is the time clock that pushed the punch
+	DeviceID  *uint
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
 }
 
+// UserAttendanceGroupedByDate is an attendance session, a check-in paired with its checkout and the breaks taken in
+// between. Date is the day the session started, the checkout can be on the next day. A day can have several sessions
 type UserAttendanceGroupedByDate struct {
 	Date     time.Time
 	CheckIn  *UserAttendance
 	CheckOut *UserAttendance
+	Breaks   []*UserAttendanceBreak
+}
+
+// UserAttendanceBreak is an unpaid break of a session, End is nil when the break was never ended
+type UserAttendanceBreak struct {
+	Start *UserAttendance
+	End   *UserAttendance
+}
+
+// BreakDuration sums the breaks of the session, a break never ended lasts until the checkout
+func (u *UserAttendanceGroupedByDate) BreakDuration() time.Duration {
+	var duration time.Duration
+	for _, attendanceBreak := range u.Breaks {
+		endAt := u.CheckOut
+		if attendanceBreak.End != nil {
+			endAt = attendanceBreak.End
+		}
+		if endAt == nil {
+			continue
+		}
+
+		duration += endAt.CreatedAt.Sub(*attendanceBreak.Start.CreatedAt)
+	}
+	return duration
+}
+
+// TracksBreaks tells whether a break was recorded in any of the sessions, the scheduled break of the shift is only
+// deducted on the days without
+func TracksBreaks(sessions []*UserAttendanceGroupedByDate) bool {
+	for _, session := range sessions {
+		if len(session.Breaks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAutoClosed tells whether the session was never checked out but closed by the auto-close job
+func (u *UserAttendanceGroupedByDate) IsAutoClosed() bool {
+	return u.CheckOut != nil && u.CheckOut.AutoClosePolicy != nil
+}
+
+// WorkedDuration is the time from the check-in to the checkout less the breaks, zero until the session is checked out
+// and on an auto-closed session
+func (u *UserAttendanceGroupedByDate) WorkedDuration() time.Duration {
+	if u.CheckIn == nil || u.CheckOut == nil || u.IsAutoClosed() {
+		return 0
+	}
+	return max(u.CheckOut.CreatedAt.Sub(*u.CheckIn.CreatedAt)-u.BreakDuration(), 0)
+}
+
+// WorkSchedule is the working day an attendance is evaluated against, the times are offsets from midnight and End
+// goes past 24 hours when the shift crosses midnight
+type WorkSchedule struct {
+	// ShiftID is nil on the company working day of the employees without an assigned shift
+	ShiftID   *uint
+	ShiftName string
+	Start     time.Duration
+	End       time.Duration
+	Break     time.Duration
+	// Grace is tolerated on a late arrival or an early departure
+	Grace time.Duration
+}
+
+// WorkingDuration is the time paid on the day, the break is unpaid
+func (w *WorkSchedule) WorkingDuration() time.Duration {
+	return w.End - w.Start - w.Break
+}
+
+func (w *WorkSchedule) StartAt(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()).Add(w.Start)
+}
+
+func (w *WorkSchedule) EndAt(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()).Add(w.End)
+}
+
+// AttendanceSummary evaluates the attendances of an employee over a date range against their work schedule,
+// only the working days up to today are scheduled and today is only counted once checked in
+type AttendanceSummary struct {
+	UserID    uint
+	StartDate time.Time
+	EndDate   time.Time
+
+	ScheduledDays int
+	PresentDays   int
+	AbsentDays    int
+	// LateArrivals counts the first check-ins of the day past the grace period, LateMinutes sums their delay from the
+	// scheduled start
+	LateArrivals int
+	LateMinutes  int
+	// EarlyDepartures counts the last checkouts of the day before the grace period, EarlyDepartureMinutes sums their
+	// advance on the scheduled end
+	EarlyDepartures       int
+	EarlyDepartureMinutes int
+	// MissingCheckouts counts the past days with a session never checked out, auto-closed included, the session is
+	// not worked
+	MissingCheckouts int
+	// OutOfAreaCheckins counts the check-ins accepted out of the area of the work location, flagged for review
+	OutOfAreaCheckins int
+	WorkedMilis       int
+	// Latenesses are the late arrivals and early departures counted, by day
+	Latenesses []*AttendanceLateness
+}
+
+type AttendanceLatenessType string
+
+const (
+	AttendanceLatenessTypeLateArrival    AttendanceLatenessType = "LATE_ARRIVAL"
+	AttendanceLatenessTypeEarlyDeparture AttendanceLatenessType = "EARLY_DEPARTURE"
+)
+
+// AttendanceLateness is a first check-in of the day past the grace period, or a last checkout before it
+type AttendanceLateness struct {
+	Type AttendanceLatenessType
+	Date time.Time
+	// AttendanceID is the check-in or the checkout judged
+	AttendanceID uint
+	// Minutes is the delay from the scheduled start or the advance on the scheduled end, GraceMinutes of it is
+	// tolerated
+	Minutes      int
+	GraceMinutes int
+}
+
+type AttendanceCorrectionType string
+
+const (
+	AttendanceCorrectionTypeMissingCheckIn  AttendanceCorrectionType = "MISSING_CHECKIN"
+	AttendanceCorrectionTypeMissingCheckOut AttendanceCorrectionType = "MISSING_CHECKOUT"
+	AttendanceCorrectionTypeWrongTime       AttendanceCorrectionType = "WRONG_TIME"
+)
+
+type AttendanceCorrectionStatus string
+
+const (
+	AttendanceCorrectionStatusPending  AttendanceCorrectionStatus = "PENDING"
+	AttendanceCorrectionStatusApproved AttendanceCorrectionStatus = "APPROVED"
+	AttendanceCorrectionStatusRejected AttendanceCorrectionStatus = "REJECTED"
+)
+
+// AttendanceCorrection is a correction requested by an employee on their own attendances, it is only applied once
+// approved by another user. An approved correction adds the attendance at CorrectedAt, a wrong time also supersedes
+// the attendance AttendanceID, the original rows are never overwritten
+type AttendanceCorrection struct {
+	ID               *uint
+	UserID           uint
+	Type             AttendanceCorrectionType
+	AttendanceID     *uint
+	CorrectedAt      time.Time
+	Reason           string
+	Status           AttendanceCorrectionStatus
+	ReviewedByUserID *uint
+	ReviewedAt       *time.Time
+	ReviewNote       *string
+	CreatedAt        *time.Time
+	UpdatedAt        *time.Time
+}
+
+// AttendancePunch is a record of a biometric device log, the PIN enrolled on the device punched at a wall clock time
+// of the device. Type is nil when the device does not record the state punched, it is then told from the session.
+// DeviceID is set on the punches pushed by a registered device, Line is then the position of the record in the push
+type AttendancePunch struct {
+	Line      int
+	Pin       string
+	PunchedAt time.Time
+	Type      *AttendanceType
+	DeviceID  *uint
+}
+
+type AttendanceImportAnomalyType string
+
+const (
+	// AttendanceImportAnomalyTypeInvalidLine is a line of the log that cannot be read
+	AttendanceImportAnomalyTypeInvalidLine AttendanceImportAnomalyType = "INVALID_LINE"
+	// AttendanceImportAnomalyTypeDoublePunch is a punch repeated within a minute of the previous one
+	AttendanceImportAnomalyTypeDoublePunch AttendanceImportAnomalyType = "DOUBLE_PUNCH"
+	AttendanceImportAnomalyTypeInFuture    AttendanceImportAnomalyType = "IN_FUTURE"
+	AttendanceImportAnomalyTypeDayOff      AttendanceImportAnomalyType = "DAY_OFF"
+	// AttendanceImportAnomalyTypeAlreadyCheckedIn is a check-in punched while a session is open
+	AttendanceImportAnomalyTypeAlreadyCheckedIn AttendanceImportAnomalyType = "ALREADY_CHECKED_IN"
+	// AttendanceImportAnomalyTypeNotCheckedIn is a checkout or a break punched without an open session
+	AttendanceImportAnomalyTypeNotCheckedIn   AttendanceImportAnomalyType = "NOT_CHECKED_IN"
+	AttendanceImportAnomalyTypeAlreadyOnBreak AttendanceImportAnomalyType = "ALREADY_ON_BREAK"
+	// AttendanceImportAnomalyTypeNotOnBreak is the end of a break punched without a break running
+	AttendanceImportAnomalyTypeNotOnBreak AttendanceImportAnomalyType = "NOT_ON_BREAK"
+)
+
+// AttendanceImportAnomaly is a punch of the log left out of the import
+type AttendanceImportAnomaly struct {
+	Line      int
+	Pin       string
+	UserID    *uint
+	PunchedAt *time.Time
+	Type      AttendanceImportAnomalyType
+	Message   string
+}
+
+// AttendanceImportUnmatchedPin is a PIN of the log no user is enrolled with, its punches are left out of the import
+type AttendanceImportUnmatchedPin struct {
+	Pin     string
+	Punches int
+}
+
+// AttendanceImport reports the import of a biometric device log. Duplicates counts the punches already recorded,
+// importing the same log twice adds nothing. A dry run reports the attendances it would add without adding them
+type AttendanceImport struct {
+	DryRun        bool
+	Punches       int
+	Duplicates    int
+	UnmatchedPins []*AttendanceImportUnmatchedPin
+	Anomalies     []*AttendanceImportAnomaly
+	Attendances   []*UserAttendance
 }
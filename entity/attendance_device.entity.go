@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// AttendanceDevice is a time clock pushing the punches of the employees as they happen, it authenticates with its own
+// API key
+type AttendanceDevice struct {
+	ID           *uint
+	SerialNumber string
+	Name         string
+	// ApiKey is only set right after the key is generated, the hash of the key is stored
+	ApiKey     *string
+	LastPushAt *time.Time
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
+}
+
+// AttendanceDeviceRecord is a punch pushed by a device, the time is the wall clock of the device
+type AttendanceDeviceRecord struct {
+	Pin       string
+	PunchedAt string
+	State     string
+}
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type BankCode string
+
+const (
+	BankCodeBca     BankCode = "BCA"
+	BankCodeMandiri BankCode = "MANDIRI"
+	BankCodeBni     BankCode = "BNI"
+	BankCodeBri     BankCode = "BRI"
+	BankCodeCimb    BankCode = "CIMB"
+	BankCodePermata BankCode = "PERMATA"
+
+	BankCodeGopay     BankCode = "GOPAY"
+	BankCodeOvo       BankCode = "OVO"
+	BankCodeDana      BankCode = "DANA"
+	BankCodeShopeepay BankCode = "SHOPEEPAY"
+)
+
+type bank struct {
+	name         string
+	clearingCode string
+	bic          string
+}
+
+var banks = map[BankCode]bank{
+	BankCodeBca:     {name: "Bank Central Asia", clearingCode: "014", bic: "CENAIDJA"},
+	BankCodeMandiri: {name: "Bank Mandiri", clearingCode: "008", bic: "BMRIIDJA"},
+	BankCodeBni:     {name: "Bank Negara Indonesia", clearingCode: "009", bic: "BNINIDJA"},
+	BankCodeBri:     {name: "Bank Rakyat Indonesia", clearingCode: "002", bic: "BRINIDJA"},
+	BankCodeCimb:    {name: "Bank CIMB Niaga", clearingCode: "022", bic: "BNIAIDJA"},
+	BankCodePermata: {name: "Bank Permata", clearingCode: "013", bic: "BBBAIDJA"},
+}
+
+var ewallets = map[BankCode]string{
+	BankCodeGopay:     "GoPay",
+	BankCodeOvo:       "OVO",
+	BankCodeDana:      "DANA",
+	BankCodeShopeepay: "ShopeePay",
+}
+
+func (b BankCode) IsValid() bool {
+	_, ok := banks[b]
+	return ok
+}
+
+func (b BankCode) IsEwallet() bool {
+	_, ok := ewallets[b]
+	return ok
+}
+
+func (b BankCode) Name() string {
+	if name, ok := ewallets[b]; ok {
+		return name
+	}
+	return banks[b].name
+}
+
+// ClearingCode is the national clearing code of the bank used by the interbank transfers
+func (b BankCode) ClearingCode() string {
+	return banks[b].clearingCode
+}
+
+func (b BankCode) Bic() string {
+	return banks[b].bic
+}
+
+type PaymentMethod string
+
+const (
+	PaymentMethodTransfer PaymentMethod = "TRANSFER"
+	PaymentMethodCash     PaymentMethod = "CASH"
+	PaymentMethodEwallet  PaymentMethod = "EWALLET"
+)
+
+// UserBankAccount is how the take home pay of a user is paid, the bank code holds the e-wallet
+// for the e-wallet payments and nothing is held for the cash payments
+type UserBankAccount struct {
+	ID              *uint
+	UserID          uint
+	PaymentMethod   PaymentMethod
+	BankCode        BankCode
+	AccountNumber   string
+	AccountName     string
+	UpdatedByUserID *uint
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+}
+
+// MaskAccountNumber keeps the last 4 digits of an account number
+func MaskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return strings.Repeat("*", len(accountNumber))
+	}
+
+	return strings.Repeat("*", len(accountNumber)-4) + accountNumber[len(accountNumber)-4:]
+}
+
+type BankAccountChangeStatus string
+
+const (
+	BankAccountChangeStatusPending  BankAccountChangeStatus = "PENDING"
+	BankAccountChangeStatusApproved BankAccountChangeStatus = "APPROVED"
+	BankAccountChangeStatusRejected BankAccountChangeStatus = "REJECTED"
+)
+
+// UserBankAccountChange is a requested bank account change, it is only applied once approved by another user
+// than the requester so a single account cannot redirect a pay. The approved changes are the account history
+type UserBankAccountChange struct {
+	ID                *uint
+	UserID            uint
+	PaymentMethod     PaymentMethod
+	BankCode          BankCode
+	AccountNumber     string
+	AccountName       string
+	Status            BankAccountChangeStatus
+	RequestedByUserID uint
+	ReviewedByUserID  *uint
+	ReviewedAt        *time.Time
+	ReviewNote        *string
+	CreatedAt         *time.Time
+	UpdatedAt         *time.Time
+}
@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+type DisbursementFormat string
+
+const (
+	DisbursementFormatCsv     DisbursementFormat = "csv"
+	DisbursementFormatBca     DisbursementFormat = "bca"
+	DisbursementFormatMandiri DisbursementFormat = "mandiri"
+	DisbursementFormatPain001 DisbursementFormat = "pain001"
+)
+
+// DisbursementTransfer is a single credit transfer paying the take home pay of a user
+type DisbursementTransfer struct {
+	UserID        uint
+	Username      string
+	BankCode      BankCode
+	AccountNumber string
+	AccountName   string
+	Amount        int
+}
+
+// DisbursementBatch is every transfer of a rolled payroll, debited from the company account
+type DisbursementBatch struct {
+	Payroll            *Payroll
+	DebitBankCode      BankCode
+	DebitAccountNumber string
+	DebitAccountName   string
+	CorporateID        string
+	ExecutedAt         time.Time
+	CreatedAt          time.Time
+	Transfers          []*DisbursementTransfer
+	TotalAmount        int
+}
+
+// DisbursementIssue is an employee who cannot be paid through the requested format
+type DisbursementIssue struct {
+	UserID   uint
+	Username string
+	Reason   string
+}
+
+// DisbursementFile is a rendered bulk transfer file ready to be uploaded to the bank
+type DisbursementFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
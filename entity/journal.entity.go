@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+type JournalFormat string
+
+const (
+	JournalFormatCsv  JournalFormat = "csv"
+	JournalFormatJson JournalFormat = "json"
+)
+
+type JournalAccount string
+
+const (
+	JournalAccountSalaryExpense        JournalAccount = "SALARY_EXPENSE"
+	JournalAccountOvertimeExpense      JournalAccount = "OVERTIME_EXPENSE"
+	JournalAccountReimbursementExpense JournalAccount = "REIMBURSEMENT_EXPENSE"
+	JournalAccountLoanReceivable       JournalAccount = "LOAN_RECEIVABLE"
+	JournalAccountNetSalaryPayable     JournalAccount = "NET_SALARY_PAYABLE"
+)
+
+var journalAccountNames = map[JournalAccount]string{
+	JournalAccountSalaryExpense:        "Salary Expense",
+	JournalAccountOvertimeExpense:      "Overtime Expense",
+	JournalAccountReimbursementExpense: "Reimbursement Expense",
+	JournalAccountLoanReceivable:       "Employee Loan Receivable",
+	JournalAccountNetSalaryPayable:     "Net Salary Payable",
+}
+
+func (j JournalAccount) Name() string {
+	return journalAccountNames[j]
+}
+
+// IsExpense tells whether the account is booked per cost center, the balance sheet accounts are booked company wide
+func (j JournalAccount) IsExpense() bool {
+	switch j {
+	case JournalAccountSalaryExpense, JournalAccountOvertimeExpense, JournalAccountReimbursementExpense:
+		return true
+	}
+	return false
+}
+
+// JournalLine is a single debit or credit, exactly one of Debit and Credit is positive
+type JournalLine struct {
+	Account     JournalAccount
+	AccountCode string
+	AccountName string
+	CostCenter  string
+	Description string
+	Debit       int
+	Credit      int
+}
+
+// Journal is the balanced double-entry journal of a rolled payroll
+type Journal struct {
+	Payroll     *Payroll
+	Reference   string
+	Date        time.Time
+	Currency    string
+	Lines       []*JournalLine
+	TotalDebit  int
+	TotalCredit int
+	CreatedAt   time.Time
+}
+
+// JournalFile is a rendered journal ready to be imported into the accounting system
+type JournalFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+type LoanType string
+
+const (
+	LoanTypeLoan    LoanType = "LOAN"
+	LoanTypeAdvance LoanType = "ADVANCE"
+)
+
+type UserLoan struct {
+	ID                *uint
+	UserID            uint
+	Type              LoanType
+	Description       string
+	Principal         int
+	InstallmentCount  int
+	InstallmentAmount int
+	RemainingBalance  int
+	StartedAt         time.Time
+	CreatedByUserID   *uint
+	CreatedAt         *time.Time
+	UpdatedAt         *time.Time
+}
+
+type LoanInstallment struct {
+	ID        *uint
+	LoanID    uint
+	PayrollID uint
+	Amount    int
+	PaidAt    time.Time
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+type LoanStatement struct {
+	Loan         *UserLoan
+	Installments []*LoanInstallment
+	PaidAmount   int
+	// RemainingInstallments is the projection of the installments still to be deducted
+	RemainingInstallments int
+}
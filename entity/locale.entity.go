@@ -0,0 +1,12 @@
+package entity
+
+type Locale string
+
+const (
+	LocaleIndonesian Locale = "id"
+	LocaleEnglish    Locale = "en"
+)
+
+func (l Locale) IsValid() bool {
+	return l == LocaleIndonesian || l == LocaleEnglish
+}
@@ -3,8 +3,10 @@ package entity
 import "time"
 
 type UserOvertime struct {
-	ID              *uint
-	UserID          uint
+	ID     *uint
+	UserID uint
+	// OvertimePlanID is the plan the overtime was worked against, nil on an unplanned overtime
+	OvertimePlanID  *uint
 	Description     string
 	OvertimeAt      time.Time
 	DurationMilis   int
@@ -13,3 +15,19 @@ type UserOvertime struct {
 	CreatedAt       *time.Time
 	UpdatedAt       *time.Time
 }
+
+// OvertimePlan is an overtime assigned in advance, the overtime submitted against it is approved right away when its
+// duration is within the tolerance of the plan
+type OvertimePlan struct {
+	ID          *uint
+	UserID      uint
+	Description string
+	// PlannedAt is when the overtime is planned to start
+	PlannedAt       time.Time
+	DurationMilis   int
+	CreatedByUserID uint
+	// OvertimeID is the overtime submitted against the plan, nil until it is
+	OvertimeID *uint
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
+}
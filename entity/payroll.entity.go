@@ -2,9 +2,24 @@ package entity
 
 import "time"
 
+type PayrollType string
+
+const (
+	PayrollTypeRegular    PayrollType = "REGULAR"
+	PayrollTypeBonus      PayrollType = "BONUS"
+	PayrollTypeThr        PayrollType = "THR"
+	PayrollTypeCorrection PayrollType = "CORRECTION"
+)
+
+// IsOffCycle reports whether the payroll only pays selected employees their explicitly added earnings
+func (t PayrollType) IsOffCycle() bool {
+	return t != "" && t != PayrollTypeRegular
+}
+
 type Payroll struct {
 	ID              *uint
 	Name            string
+	Type            PayrollType
 	StartedAt       time.Time
 	EndedAt         time.Time
 	IsRolled        *bool
@@ -14,6 +29,29 @@ type Payroll struct {
 	UpdatedAt       *time.Time
 }
 
+type PayrollEarning struct {
+	ID              *uint
+	PayrollID       uint
+	UserID          uint
+	Description     string
+	Amount          int
+	CreatedByUserID *uint
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+}
+
+// PayrollRetroAdjustment carries the difference between a rolled payslip and its recalculation into an open payroll
+type PayrollRetroAdjustment struct {
+	ID              *uint
+	PayrollID       uint
+	SourcePayrollID uint
+	UserID          uint
+	Description     string
+	Amount          int
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+}
+
 type UserPayslipSummary struct {
 	ID               *uint
 	PayrollID        uint
@@ -22,3 +60,55 @@ type UserPayslipSummary struct {
 	CreatedAt        *time.Time
 	UpdatedAt        *time.Time
 }
+
+// UserYearToDate sums the rolled payslips of an employee over a calendar year, amounts are rounded to whole rupiah
+type UserYearToDate struct {
+	UserID       uint
+	Year         int
+	PayrollCount int
+	// FirstMonth and LastMonth bound the months the employee was paid in, zero when nothing was paid
+	FirstMonth int
+	LastMonth  int
+	// Salary is the attendance pay with its retro adjustments, it absorbs the rounding of the other components
+	Salary        int
+	Overtime      int
+	Reimbursement int
+	Earning       int
+	// Bonus is what the bonus and THR payrolls paid
+	Bonus            int
+	GrossPay         int
+	LoanDeduction    int
+	PenaltyDeduction int
+	TotalDeduction   int
+	TotalTakeHomePay int
+}
+
+type PayrollPreviewFlag string
+
+const (
+	PayrollPreviewFlagChangeAboveThreshold PayrollPreviewFlag = "CHANGE_ABOVE_THRESHOLD"
+	PayrollPreviewFlagNewEmployee          PayrollPreviewFlag = "NEW_EMPLOYEE"
+	PayrollPreviewFlagMissingEmployee      PayrollPreviewFlag = "MISSING_EMPLOYEE"
+	PayrollPreviewFlagZeroPay              PayrollPreviewFlag = "ZERO_PAY"
+	PayrollPreviewFlagNegativePay          PayrollPreviewFlag = "NEGATIVE_PAY"
+)
+
+type PayrollPreviewEntry struct {
+	UserID uint
+	// Payslip is nil for the employees paid by the previous payroll but missing from this one
+	Payslip             *Payslip
+	TakeHomePay         int
+	PreviousTakeHomePay *int
+	ChangePercent       *float64
+	Flags               []PayrollPreviewFlag
+}
+
+type PayrollPreview struct {
+	Payroll                  *Payroll
+	PreviousPayroll          *Payroll
+	ThresholdPercent         float64
+	Entries                  []*PayrollPreviewEntry
+	TotalTakeHomePay         int
+	PreviousTotalTakeHomePay int
+	FlaggedCount             int
+}
@@ -26,10 +26,16 @@ type PayslipReimburse struct {
 	TotalAmount float32
 }
 
+// PayslipAttendanceDetail is a session paid, less its breaks
 type PayslipAttendanceDetail struct {
-	CheckinAt     time.Time
-	CheckoutAt    *time.Time
-	DurationMilis int
+	CheckinAt          time.Time
+	CheckoutAt         *time.Time
+	BreakDurationMilis int
+	DurationMilis      int
+	// MissingCheckout flags a session never checked out, it is paid the rest of the day until a correction is approved
+	MissingCheckout bool
+	// AutoClosePolicy is the policy a session closed by the auto-close job is paid by
+	AutoClosePolicy *AttendanceAutoClosePolicy
 }
 
 type PayslipAttendance struct {
@@ -38,13 +44,111 @@ type PayslipAttendance struct {
 	TotalAmount        float32
 }
 
+type PayslipEarningDetail struct {
+	Description string
+	Amount      int
+	CreatedAt   time.Time
+}
+
+type PayslipEarning struct {
+	Details     []*PayslipEarningDetail
+	TotalAmount float32
+}
+
+type PayslipDeductionType string
+
+const (
+	PayslipDeductionTypeLoan PayslipDeductionType = "LOAN"
+	// PayslipDeductionTypeLateDay is the fixed penalty of a late day once the threshold of the period is reached
+	PayslipDeductionTypeLateDay PayslipDeductionType = "LATE_DAY"
+)
+
+type PayslipDeductionDetail struct {
+	Type PayslipDeductionType
+	// ReferenceID points to the record the deduction comes from, e.g. the loan ID or the attendance penalized
+	ReferenceID *uint
+	// AttendanceDate is the day an attendance penalty is for
+	AttendanceDate *time.Time
+	Description    string
+	Amount         int
+}
+
+type PayslipDeduction struct {
+	Details     []*PayslipDeductionDetail
+	TotalAmount float32
+}
+
+type PayslipRetroAdjustmentDetail struct {
+	SourcePayrollID uint
+	Description     string
+	Amount          int
+	CreatedAt       time.Time
+}
+
+type PayslipRetroAdjustment struct {
+	Details     []*PayslipRetroAdjustmentDetail
+	TotalAmount float32
+}
+
 type Payslip struct {
-	PayrollID   uint
-	UserID      uint
-	Salary      int
-	ProRate     float32
-	Attendance  *PayslipAttendance
-	Overtime    *PayslipOvertime
-	Reimburse   *PayslipReimburse
-	TakeHomePay float32
+	PayrollID       uint
+	PayrollType     PayrollType
+	UserID          uint
+	Salary          int
+	ProRate         float32
+	Attendance      *PayslipAttendance
+	Overtime        *PayslipOvertime
+	Reimburse       *PayslipReimburse
+	Earning         *PayslipEarning
+	RetroAdjustment *PayslipRetroAdjustment
+	Deduction       *PayslipDeduction
+	TakeHomePay     float32
+}
+
+type PayslipArchiveStatus string
+
+const (
+	PayslipArchiveStatusPending PayslipArchiveStatus = "PENDING"
+	PayslipArchiveStatusRunning PayslipArchiveStatus = "RUNNING"
+	PayslipArchiveStatusDone    PayslipArchiveStatus = "DONE"
+	PayslipArchiveStatusFailed  PayslipArchiveStatus = "FAILED"
+)
+
+// PayslipArchiveJob tracks a ZIP archive of every payslip of a payroll generated in the background
+type PayslipArchiveJob struct {
+	ID              *uint
+	PayrollID       uint
+	Locale          Locale
+	Status          PayslipArchiveStatus
+	TotalCount      int
+	ProcessedCount  int
+	FilePath        *string
+	ErrorMessage    *string
+	CreatedByUserID *uint
+	FinishedAt      *time.Time
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+}
+
+type PayslipDeliveryStatus string
+
+const (
+	PayslipDeliveryStatusPending PayslipDeliveryStatus = "PENDING"
+	PayslipDeliveryStatusSent    PayslipDeliveryStatus = "SENT"
+	PayslipDeliveryStatusFailed  PayslipDeliveryStatus = "FAILED"
+	PayslipDeliveryStatusSkipped PayslipDeliveryStatus = "SKIPPED"
+)
+
+// PayslipDelivery tracks the payslip email of a recipient, a resend reuses the same delivery
+type PayslipDelivery struct {
+	ID        *uint
+	PayrollID uint
+	UserID    uint
+	Email     *string
+	Status    PayslipDeliveryStatus
+	Attempts  int
+	LastError *string
+	SentAt    *time.Time
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
 }
@@ -0,0 +1,61 @@
+package entity
+
+type ReportFormat string
+
+const (
+	ReportFormatJson ReportFormat = "json"
+	ReportFormatCsv  ReportFormat = "csv"
+	ReportFormatXlsx ReportFormat = "xlsx"
+)
+
+type ReportGroupBy string
+
+const (
+	ReportGroupByDepartment ReportGroupBy = "department"
+	ReportGroupByCostCenter ReportGroupBy = "cost_center"
+)
+
+func (r ReportGroupBy) IsValid() bool {
+	return r == ReportGroupByDepartment || r == ReportGroupByCostCenter
+}
+
+// PayslipRecord is a frozen payslip of a rolled payroll with the employee it belongs to
+type PayslipRecord struct {
+	UserID      uint
+	Username    string
+	Department  *string
+	CostCenter  *string
+	TakeHomePay int
+	Payslip     *Payslip
+}
+
+// PayrollRegisterEntry is the line of an employee in the payroll register, amounts are rounded to whole rupiah
+type PayrollRegisterEntry struct {
+	UserID          uint
+	Username        string
+	Department      string
+	CostCenter      string
+	BaseSalary      int
+	Attendance      int
+	Overtime        int
+	Reimbursement   int
+	Earning         int
+	RetroAdjustment int
+	// GrossPay is the net pay plus the deductions, it absorbs the rounding of the components
+	GrossPay         int
+	LoanDeduction    int
+	PenaltyDeduction int
+	TotalDeduction   int
+	NetPay           int
+}
+
+// PayrollCostGroup sums the register entries of a department or cost center
+type PayrollCostGroup struct {
+	Group          string
+	EmployeeCount  int
+	Overtime       int
+	Reimbursement  int
+	GrossPay       int
+	TotalDeduction int
+	NetPay         int
+}
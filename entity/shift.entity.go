@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Shift is a working period of the day, a shift ending at or before its start crosses midnight and ends the next day
+type Shift struct {
+	ID   *uint
+	Name string
+	// StartTime and EndTime are HH:MM clock times
+	StartTime    string
+	EndTime      string
+	BreakMinutes int
+	GraceMinutes int
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+}
+
+// ShiftRotation cycles through its days from the date it is assigned, a day without a shift is a day off
+type ShiftRotation struct {
+	ID        *uint
+	Name      string
+	Days      []*ShiftRotationDay
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+type ShiftRotationDay struct {
+	DayIndex int
+	ShiftID  *uint
+	Shift    *Shift
+}
+
+// UserShiftRotation assigns a rotation to an employee from EffectiveAt until the next assignment
+type UserShiftRotation struct {
+	ID              *uint
+	UserID          uint
+	RotationID      uint
+	Rotation        *ShiftRotation
+	EffectiveAt     time.Time
+	CreatedByUserID *uint
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+}
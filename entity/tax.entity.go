@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// TaxStatus is the PTKP status of an employee, TK is single and K is married followed by the number of dependents
+type TaxStatus string
+
+const (
+	TaxStatusTk0 TaxStatus = "TK/0"
+	TaxStatusTk1 TaxStatus = "TK/1"
+	TaxStatusTk2 TaxStatus = "TK/2"
+	TaxStatusTk3 TaxStatus = "TK/3"
+	TaxStatusK0  TaxStatus = "K/0"
+	TaxStatusK1  TaxStatus = "K/1"
+	TaxStatusK2  TaxStatus = "K/2"
+	TaxStatusK3  TaxStatus = "K/3"
+)
+
+const (
+	ptkpTaxpayer  = 54000000
+	ptkpMarried   = 4500000
+	ptkpDependent = 4500000
+)
+
+func (t TaxStatus) IsValid() bool {
+	switch t {
+	case TaxStatusTk0, TaxStatusTk1, TaxStatusTk2, TaxStatusTk3, TaxStatusK0, TaxStatusK1, TaxStatusK2, TaxStatusK3:
+		return true
+	}
+	return false
+}
+
+func (t TaxStatus) IsMarried() bool {
+	return strings.HasPrefix(string(t), "K/")
+}
+
+// Dependents is the number of dependents counted toward the PTKP, at most three
+func (t TaxStatus) Dependents() int {
+	if !t.IsValid() {
+		return 0
+	}
+	return int(t[len(t)-1] - '0')
+}
+
+// Ptkp is the yearly non taxable income of the status
+func (t TaxStatus) Ptkp() int {
+	ptkp := ptkpTaxpayer + t.Dependents()*ptkpDependent
+	if t.IsMarried() {
+		ptkp += ptkpMarried
+	}
+	return ptkp
+}
+
+// TaxCertificate holds the Form 1721-A1 of an employee for a calendar year, the lines are numbered as on the form
+// and the amounts are in whole rupiah
+type TaxCertificate struct {
+	// Number is the withholding slip number, 1.1-MM.YY-NNNNNNN
+	Number string
+	Year   int
+	// FirstMonth and LastMonth bound the months the employee was paid in
+	FirstMonth int
+	LastMonth  int
+	// Date is when the certificate is issued, the end of the last month paid
+	Date time.Time
+
+	UserID    uint
+	Name      string
+	Nik       string
+	Npwp      string
+	Address   string
+	Gender    Gender
+	TaxStatus TaxStatus
+	Position  string
+
+	Salary              int // 1
+	TaxAllowance        int // 2
+	OtherAllowance      int // 3
+	Honorarium          int // 4
+	InsurancePremium    int // 5
+	BenefitInKind       int // 6
+	Bonus               int // 7
+	GrossIncome         int // 8
+	OccupationalCost    int // 9
+	PensionContribution int // 10
+	TotalDeduction      int // 11
+	NetIncome           int // 12
+	PreviousNetIncome   int // 13
+	AnnualNetIncome     int // 14
+	Ptkp                int // 15
+	TaxableIncome       int // 16
+	AnnualTax           int // 17
+	PreviousTax         int // 18
+	TaxDue              int // 19
+	TaxWithheld         int // 20
+}
+
+// Amounts lists the lines 1 to 20 of the certificate in order
+func (t *TaxCertificate) Amounts() []int {
+	return []int{
+		t.Salary, t.TaxAllowance, t.OtherAllowance, t.Honorarium, t.InsurancePremium, t.BenefitInKind, t.Bonus, t.GrossIncome,
+		t.OccupationalCost, t.PensionContribution, t.TotalDeduction, t.NetIncome,
+		t.PreviousNetIncome, t.AnnualNetIncome, t.Ptkp, t.TaxableIncome, t.AnnualTax, t.PreviousTax, t.TaxDue, t.TaxWithheld,
+	}
+}
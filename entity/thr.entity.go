@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+type ThrHoliday string
+
+const (
+	ThrHolidayEidAlFitr      ThrHoliday = "EID_AL_FITR"
+	ThrHolidayChristmas      ThrHoliday = "CHRISTMAS"
+	ThrHolidayNyepi          ThrHoliday = "NYEPI"
+	ThrHolidayVesak          ThrHoliday = "VESAK"
+	ThrHolidayChineseNewYear ThrHoliday = "CHINESE_NEW_YEAR"
+)
+
+var religionThrHolidays = map[Religion]ThrHoliday{
+	ReligionIslam:      ThrHolidayEidAlFitr,
+	ReligionProtestant: ThrHolidayChristmas,
+	ReligionCatholic:   ThrHolidayChristmas,
+	ReligionHindu:      ThrHolidayNyepi,
+	ReligionBuddhist:   ThrHolidayVesak,
+	ReligionConfucian:  ThrHolidayChineseNewYear,
+}
+
+// GetThrHoliday returns the preferred holiday, falling back to the holiday of the religion
+func (u *UserInfo) GetThrHoliday() *ThrHoliday {
+	if u.ThrHoliday != nil {
+		return u.ThrHoliday
+	}
+
+	if u.Religion != nil {
+		if holiday, ok := religionThrHolidays[*u.Religion]; ok {
+			return &holiday
+		}
+	}
+
+	return nil
+}
+
+type ThrCalculation struct {
+	Name            string
+	Holiday         ThrHoliday
+	PayoutAt        time.Time
+	CreatedByUserID uint
+}
+
+type ThrExcludedReason string
+
+const (
+	ThrExcludedReasonTenureUnderOneMonth ThrExcludedReason = "TENURE_UNDER_ONE_MONTH"
+	ThrExcludedReasonMissingJoinedAt     ThrExcludedReason = "MISSING_JOINED_AT"
+	ThrExcludedReasonMissingSalary       ThrExcludedReason = "MISSING_SALARY"
+)
+
+type ThrEntry struct {
+	UserID         uint
+	JoinedAt       *time.Time
+	TenureMonths   int
+	MonthlySalary  int
+	Amount         int
+	ExcludedReason *ThrExcludedReason
+}
+
+type ThrResult struct {
+	Payroll     *Payroll
+	Entries     []*ThrEntry
+	TotalAmount int
+}
@@ -11,6 +11,8 @@ type UserRole string
 const (
 	UserRoleAdmin    UserRole = "ADMIN"
 	UserRoleEmployee UserRole = "EMPLOYEE"
+	// UserRoleFinance pays the employees, the only role seeing the full account numbers
+	UserRoleFinance UserRole = "FINANCE"
 )
 
 type User struct {
@@ -25,8 +27,54 @@ type User struct {
 	UpdatedAt *time.Time
 }
 
+type Religion string
+
+const (
+	ReligionIslam      Religion = "ISLAM"
+	ReligionProtestant Religion = "PROTESTANT"
+	ReligionCatholic   Religion = "CATHOLIC"
+	ReligionHindu      Religion = "HINDU"
+	ReligionBuddhist   Religion = "BUDDHIST"
+	ReligionConfucian  Religion = "CONFUCIAN"
+)
+
+type Gender string
+
+const (
+	GenderMale   Gender = "MALE"
+	GenderFemale Gender = "FEMALE"
+)
+
 type UserInfo struct {
 	MonthlySalary *int
+	Religion      *Religion
+	// ThrHoliday overrides the religious holiday the THR is paid on, defaults to the one of the religion
+	ThrHoliday *ThrHoliday
+	JoinedAt   *time.Time
+	BirthDate  *time.Time
+	// Email receives the payslips once a payroll is rolled
+	Email      *string
+	Department *string
+	// CostCenter books the payroll expenses of the user in the accounting journal
+	CostCenter *string
+	// FullName, Nik, Npwp, Address, Gender, TaxStatus and Position identify the user on the tax certificates
+	FullName  *string
+	Nik       *string
+	Npwp      *string
+	Address   *string
+	Gender    *Gender
+	TaxStatus *TaxStatus
+	Position  *string
+	// Timezone is the IANA zone the attendances of the user are judged in, e.g. Asia/Jayapura
+	Timezone *string
+	// WorkLocationID is the site the user checks in at, RemoteWork exempts the user from checking in there
+	WorkLocationID *uint
+	RemoteWork     bool
+	// DevicePin is the PIN the user is enrolled with on the biometric devices, the punches of the PIN are imported as
+	// the attendances of the user
+	DevicePin *string
+	// AttendancePenaltyExempt exempts the user from the lateness and early departure deductions
+	AttendancePenaltyExempt bool
 }
 
 func (u *User) HashPassword() error {
@@ -42,3 +90,25 @@ func (u *User) HashPassword() error {
 func (u *User) VerifyPassword(password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) == nil
 }
+
+// UserSalary is an entry of the salary history, the salary applies to the payrolls ending on or after EffectiveAt
+type UserSalary struct {
+	ID              *uint
+	UserID          uint
+	MonthlySalary   int
+	EffectiveAt     time.Time
+	CreatedByUserID *uint
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+}
+
+// GetPayslipPassword returns the password of the protected payslips, the birth date as DDMMYYYY. It is nil when
+// the birth date is unknown, the payslip cannot be protected then as the username is known by every colleague
+func (u *User) GetPayslipPassword() *string {
+	if u.UserInfo == nil || u.UserInfo.BirthDate == nil {
+		return nil
+	}
+
+	password := u.UserInfo.BirthDate.Format("02012006")
+	return &password
+}
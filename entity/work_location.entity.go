@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+type WorkLocationPolicy string
+
+const (
+	// WorkLocationPolicyFlag accepts a check-in out of the area and flags it for review
+	WorkLocationPolicyFlag WorkLocationPolicy = "FLAG"
+	// WorkLocationPolicyReject refuses a check-in out of the area
+	WorkLocationPolicyReject WorkLocationPolicy = "REJECT"
+)
+
+// WorkLocation is a site employees check in at. A check-in is within the area when its coordinates fall in the
+// polygon, or within the radius of the center without a polygon, or when it is made from one of the Wi-Fi access points
+// or IP ranges of the site
+type WorkLocation struct {
+	ID           *uint
+	Name         string
+	Latitude     *float64
+	Longitude    *float64
+	RadiusMeters *float64
+	Polygon      []*GeoPoint
+	WifiBSSIDs   []string
+	// IPRanges are CIDR blocks, e.g. 203.0.113.0/24
+	IPRanges  []string
+	Policy    WorkLocationPolicy
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// AttendanceEvidence is where an attendance was recorded from, every field is optional
+type AttendanceEvidence struct {
+	Latitude  *float64
+	Longitude *float64
+	WifiBSSID *string
+	IPAddress *string
+}
+
+// WorkLocationCheck is the verdict on the evidence of a check-in, WorkLocationID is nil when the employee is not
+// assigned to a work location
+type WorkLocationCheck struct {
+	WorkLocationID *uint
+	Policy         WorkLocationPolicy
+	IsOutOfArea    bool
+	// IsRemote is set for the employees allowed to work remotely, they are never out of the area
+	IsRemote bool
+}
@@ -83,6 +83,36 @@ func (o *OvertimeSubmitBeforeCheckoutError) Error() string {
 	return "Overtime cannot be submitted before checkout"
 }
 
+type OvertimeExceedsWeeklyLimitError struct{}
+
+func (o *OvertimeExceedsWeeklyLimitError) Error() string {
+	return "Overtime exceeds weekly limit"
+}
+
+type OvertimeExceedsMonthlyLimitError struct{}
+
+func (o *OvertimeExceedsMonthlyLimitError) Error() string {
+	return "Overtime exceeds monthly limit"
+}
+
+type OvertimePlanInPastError struct{}
+
+func (o *OvertimePlanInPastError) Error() string {
+	return "Overtime cannot be planned in the past"
+}
+
+type OvertimePlanAlreadySubmittedError struct{}
+
+func (o *OvertimePlanAlreadySubmittedError) Error() string {
+	return "Overtime already submitted against the plan"
+}
+
+type OvertimePlanNotDueError struct{}
+
+func (o *OvertimePlanNotDueError) Error() string {
+	return "Overtime is not submitted once the plan is worked"
+}
+
 type PayrollAlreadyRolledError struct{}
 
 func (p *PayrollAlreadyRolledError) Error() string {
@@ -94,3 +124,219 @@ type PayrollNotRolledError struct{}
 func (p *PayrollNotRolledError) Error() string {
 	return "Payroll not rolled"
 }
+
+type PayrollNotOffCycleError struct{}
+
+func (p *PayrollNotOffCycleError) Error() string {
+	return "Payroll is not an off-cycle payroll"
+}
+
+type PayrollNotRegularError struct{}
+
+func (p *PayrollNotRegularError) Error() string {
+	return "Payroll is not a regular payroll"
+}
+
+type PayrollUserNotSelectedError struct{}
+
+func (p *PayrollUserNotSelectedError) Error() string {
+	return "User is not selected in payroll"
+}
+
+type PayslipArchiveNotReadyError struct{}
+
+func (p *PayslipArchiveNotReadyError) Error() string {
+	return "Payslip archive is not ready"
+}
+
+type DisbursementFormatNotSupportedError struct{}
+
+func (d *DisbursementFormatNotSupportedError) Error() string {
+	return "Disbursement format is not supported"
+}
+
+// DisbursementIssuesError lists the employees who cannot be paid through the requested format
+type DisbursementIssuesError struct {
+	Issues []*entity.DisbursementIssue
+}
+
+func (d *DisbursementIssuesError) Error() string {
+	return fmt.Sprintf("Disbursement has %d employees who cannot be paid", len(d.Issues))
+}
+
+// PayslipPasswordMissingError lists the employees whose payslip cannot be protected, they have no birth date
+type PayslipPasswordMissingError struct {
+	UserIDs []uint
+}
+
+func (p *PayslipPasswordMissingError) Error() string {
+	return "Birth date is required to protect the payslip"
+}
+
+// TaxNotWithheldError lists the employees owing a tax the payrolls did not withhold, their certificate would not hold
+type TaxNotWithheldError struct {
+	UserIDs []uint
+}
+
+func (t *TaxNotWithheldError) Error() string {
+	return "Tax is due but was not withheld by the payrolls"
+}
+
+type BankAccountChangePendingError struct{}
+
+func (b *BankAccountChangePendingError) Error() string {
+	return "Bank account change is already pending"
+}
+
+type BankAccountChangeReviewedError struct{}
+
+func (b *BankAccountChangeReviewedError) Error() string {
+	return "Bank account change is already reviewed"
+}
+
+type BankAccountChangeSelfReviewError struct{}
+
+func (b *BankAccountChangeSelfReviewError) Error() string {
+	return "Bank account change cannot be reviewed by its requester"
+}
+
+type BankAccountChangeReviewerRoleError struct{}
+
+func (b *BankAccountChangeReviewerRoleError) Error() string {
+	return "Bank account change is reviewed by the other role than its requester"
+}
+
+type BankAccountChangeOwnAccountReviewError struct{}
+
+func (b *BankAccountChangeOwnAccountReviewError) Error() string {
+	return "Bank account change cannot be reviewed by its account holder"
+}
+
+type BankAccountMethodMismatchError struct{}
+
+func (b *BankAccountMethodMismatchError) Error() string {
+	return "Bank code does not match the payment method"
+}
+
+type JournalFormatNotSupportedError struct{}
+
+func (j *JournalFormatNotSupportedError) Error() string {
+	return "Journal format is not supported"
+}
+
+type ReportFormatNotSupportedError struct{}
+
+func (r *ReportFormatNotSupportedError) Error() string {
+	return "Report format is not supported"
+}
+
+type AttendanceDayOffError struct{}
+
+func (a *AttendanceDayOffError) Error() string {
+	return "Attendance cannot checked in on a day off"
+}
+
+type ShiftBreakTooLongError struct{}
+
+func (s *ShiftBreakTooLongError) Error() string {
+	return "Shift break must be shorter than the shift"
+}
+
+type AttendanceNotCheckedInError struct{}
+
+func (a *AttendanceNotCheckedInError) Error() string {
+	return "Attendance cannot start a break because it is not checked in"
+}
+
+type AttendanceAlreadyOnBreakError struct{}
+
+func (a *AttendanceAlreadyOnBreakError) Error() string {
+	return "Attendance already on break"
+}
+
+type AttendanceNotOnBreakError struct{}
+
+func (a *AttendanceNotOnBreakError) Error() string {
+	return "Attendance cannot end a break because it is not on break"
+}
+
+type AttendanceOutOfAreaError struct{}
+
+func (a *AttendanceOutOfAreaError) Error() string {
+	return "Attendance cannot checked in out of the work location"
+}
+
+type WorkLocationAreaRequiredError struct{}
+
+func (w *WorkLocationAreaRequiredError) Error() string {
+	return "Work location needs a radius, a polygon or a network"
+}
+
+type AttendanceCorrectionReviewedError struct{}
+
+func (a *AttendanceCorrectionReviewedError) Error() string {
+	return "Attendance correction already reviewed"
+}
+
+type AttendanceCorrectionSelfReviewError struct{}
+
+func (a *AttendanceCorrectionSelfReviewError) Error() string {
+	return "Attendance correction cannot be reviewed by its requester"
+}
+
+type AttendanceCorrectionSupersededError struct{}
+
+func (a *AttendanceCorrectionSupersededError) Error() string {
+	return "Attendance already corrected"
+}
+
+type AttendanceCorrectionNoOpenSessionError struct{}
+
+func (a *AttendanceCorrectionNoOpenSessionError) Error() string {
+	return "No check-in is missing its checkout before the corrected time"
+}
+
+type AttendanceCorrectionInFutureError struct{}
+
+func (a *AttendanceCorrectionInFutureError) Error() string {
+	return "Attendance correction cannot be in the future"
+}
+
+type AttendanceCorrectionOverlapError struct{}
+
+func (a *AttendanceCorrectionOverlapError) Error() string {
+	return "Corrected time overlaps the attendances around it"
+}
+
+type DevicePinTakenError struct{}
+
+func (d *DevicePinTakenError) Error() string {
+	return "Device PIN already enrolled by another user"
+}
+
+// AttendanceLogInvalidError tells why a biometric device log cannot be read at all
+type AttendanceLogInvalidError struct {
+	Reason string
+}
+
+func (a *AttendanceLogInvalidError) Error() string {
+	return fmt.Sprintf("Attendance log cannot be read: %s", a.Reason)
+}
+
+type AttendanceDeviceSerialTakenError struct{}
+
+func (a *AttendanceDeviceSerialTakenError) Error() string {
+	return "Attendance device serial number already registered"
+}
+
+type AttendanceDeviceKeyInvalidError struct{}
+
+func (a *AttendanceDeviceKeyInvalidError) Error() string {
+	return "Attendance device API key is invalid"
+}
+
+type AttendanceDeviceSerialMismatchError struct{}
+
+func (a *AttendanceDeviceSerialMismatchError) Error() string {
+	return "Attendance device serial number does not match its API key"
+}
@@ -0,0 +1,40 @@
+package emailnotifier
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"fmt"
+	"html/template"
+)
+
+var attendanceAutoCloseTemplates = template.Must(template.ParseFS(templateFS, "templates/attendance_auto_close.*.html"))
+
+var attendanceAutoCloseSubjects = map[entity.Locale]string{
+	entity.LocaleIndonesian: "Kehadiran %s ditutup otomatis - %s",
+	entity.LocaleEnglish:    "Attendance of %s closed automatically - %s",
+}
+
+// AttendanceAutoCloseEmail tells an employee their session was closed by the auto-close job and how it is paid
+type AttendanceAutoCloseEmail struct {
+	CompanyName  string
+	EmployeeName string
+	Date         string
+	ClosedAt     string
+	Policy       entity.AttendanceAutoClosePolicy
+	Locale       entity.Locale
+}
+
+// RenderAttendanceAutoCloseEmail returns the subject and the HTML body of an auto-closed attendance email
+func RenderAttendanceAutoCloseEmail(email *AttendanceAutoCloseEmail) (string, string, error) {
+	locale := email.Locale
+	if !locale.IsValid() {
+		locale = entity.LocaleIndonesian
+	}
+
+	var body bytes.Buffer
+	if err := attendanceAutoCloseTemplates.ExecuteTemplate(&body, fmt.Sprintf("attendance_auto_close.%s.html", locale), email); err != nil {
+		return "", "", err
+	}
+
+	return fmt.Sprintf(attendanceAutoCloseSubjects[locale], email.Date, email.CompanyName), body.String(), nil
+}
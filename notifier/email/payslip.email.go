@@ -0,0 +1,43 @@
+package emailnotifier
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"embed"
+	"fmt"
+	"html/template"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var payslipTemplates = template.Must(template.ParseFS(templateFS, "templates/payslip.*.html"))
+
+var payslipSubjects = map[entity.Locale]string{
+	entity.LocaleIndonesian: "Slip Gaji %s - %s",
+	entity.LocaleEnglish:    "Payslip %s - %s",
+}
+
+// PayslipEmail holds what is printed on a payslip email, the payslip itself is attached as a PDF
+type PayslipEmail struct {
+	CompanyName  string
+	EmployeeName string
+	PayrollName  string
+	Period       string
+	Locale       entity.Locale
+}
+
+// RenderPayslipEmail returns the subject and the HTML body of a payslip email
+func RenderPayslipEmail(email *PayslipEmail) (string, string, error) {
+	locale := email.Locale
+	if !locale.IsValid() {
+		locale = entity.LocaleIndonesian
+	}
+
+	var body bytes.Buffer
+	if err := payslipTemplates.ExecuteTemplate(&body, fmt.Sprintf("payslip.%s.html", locale), email); err != nil {
+		return "", "", err
+	}
+
+	return fmt.Sprintf(payslipSubjects[locale], email.PayrollName, email.CompanyName), body.String(), nil
+}
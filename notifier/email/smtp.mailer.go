@@ -0,0 +1,128 @@
+package emailnotifier
+
+import (
+	"bytes"
+	"d-payroll/config"
+	"d-payroll/utils"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+type Message struct {
+	To          string
+	Subject     string
+	HTMLBody    string
+	Attachments []*Attachment
+}
+
+type Mailer interface {
+	Send(message *Message) error
+}
+
+type smtpMailer struct {
+	config *config.Config
+}
+
+// NewSmtpMailer sends through the configured SMTP server, the config is read on every send
+func NewSmtpMailer(config *config.Config) Mailer {
+	return &smtpMailer{config: config}
+}
+
+func (m *smtpMailer) Send(message *Message) error {
+	smtpConfig := m.config.Smtp
+	addr := net.JoinHostPort(smtpConfig.Host, fmt.Sprintf("%d", smtpConfig.Port))
+
+	var auth smtp.Auth
+	if smtpConfig.Username != "" {
+		auth = smtp.PlainAuth("", smtpConfig.Username, smtpConfig.Password, smtpConfig.Host)
+	}
+
+	body, err := m.buildMessage(message)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(addr, auth, smtpConfig.From, []string{message.To}, body)
+}
+
+func (m *smtpMailer) buildMessage(message *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.config.Smtp.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", utils.TimeNow().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	htmlPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBase64(htmlPart, []byte(message.HTMLBody)); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range message.Attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, attachment.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeBase64 wraps the encoded content at 76 characters per line as required by RFC 2045
+func writeBase64(w io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		if _, err := w.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+
+	_, err := w.Write([]byte(encoded + "\r\n"))
+	return err
+}
+
+// IsTransientError reports whether sending again may succeed, connection failures and 4xx SMTP replies are transient
+func IsTransientError(err error) bool {
+	var protocolErr *textproto.Error
+	if errors.As(err, &protocolErr) {
+		return protocolErr.Code >= 400 && protocolErr.Code < 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,134 @@
+package attendanceparser
+
+import (
+	"bufio"
+	"bytes"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	"io"
+	"strings"
+	"time"
+)
+
+// punchLayouts are the wall clock formats the devices and their software write the punch time in, the day comes
+// before the month as set up in Indonesia
+var punchLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02-01-2006 15:04:05",
+	"02-01-2006 15:04",
+}
+
+// punchStates maps the states a device records, as a code or as the label of the export, to the attendance punched.
+// The overtime states open and close a session like the regular ones
+var punchStates = map[string]entity.AttendanceType{
+	"0":           entity.AttendanceTypeCheckIn,
+	"i":           entity.AttendanceTypeCheckIn,
+	"in":          entity.AttendanceTypeCheckIn,
+	"cin":         entity.AttendanceTypeCheckIn,
+	"checkin":     entity.AttendanceTypeCheckIn,
+	"1":           entity.AttendanceTypeCheckOut,
+	"o":           entity.AttendanceTypeCheckOut,
+	"out":         entity.AttendanceTypeCheckOut,
+	"cout":        entity.AttendanceTypeCheckOut,
+	"checkout":    entity.AttendanceTypeCheckOut,
+	"2":           entity.AttendanceTypeBreakStart,
+	"breakout":    entity.AttendanceTypeBreakStart,
+	"3":           entity.AttendanceTypeBreakEnd,
+	"breakin":     entity.AttendanceTypeBreakEnd,
+	"4":           entity.AttendanceTypeCheckIn,
+	"otin":        entity.AttendanceTypeCheckIn,
+	"overtimein":  entity.AttendanceTypeCheckIn,
+	"5":           entity.AttendanceTypeCheckOut,
+	"otout":       entity.AttendanceTypeCheckOut,
+	"overtimeout": entity.AttendanceTypeCheckOut,
+}
+
+// ParseZKTecoLog reads the punches of a ZKTeco-style attendance log, either the attlog download of the device or the
+// CSV export of its software, told apart by the header of the export. The lines that cannot be read are returned as
+// anomalies, the log is only rejected when it has no punch to read
+func ParseZKTecoLog(r io.Reader) ([]*entity.AttendancePunch, []*entity.AttendanceImportAnomaly, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the exports of the Windows software start with a byte order mark
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	firstLine := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if firstLine = strings.TrimSpace(scanner.Text()); firstLine != "" {
+			break
+		}
+	}
+	if firstLine == "" {
+		return nil, nil, &internalerror.AttendanceLogInvalidError{Reason: "the log is empty"}
+	}
+
+	if delimiter, ok := csvDelimiter(firstLine); ok {
+		return parseCsv(content, delimiter)
+	}
+	return parseAttlog(content, attlogDownloadStateColumn)
+}
+
+// ParseDeviceRecords reads the records pushed by a device, the records that cannot be read are returned as anomalies
+// with their position in the push as the line
+func ParseDeviceRecords(deviceID uint, records []*entity.AttendanceDeviceRecord) ([]*entity.AttendancePunch, []*entity.AttendanceImportAnomaly) {
+	var punches []*entity.AttendancePunch
+	var anomalies []*entity.AttendanceImportAnomaly
+	for i, record := range records {
+		punchedAt, ok := parsePunchTime(record.PunchedAt)
+		if !ok {
+			anomalies = append(anomalies, invalidLine(i+1, record.Pin, "Invalid punch time "+record.PunchedAt))
+			continue
+		}
+
+		punches = append(punches, &entity.AttendancePunch{
+			Line:      i + 1,
+			Pin:       record.Pin,
+			PunchedAt: punchedAt,
+			Type:      parsePunchState(record.State),
+			DeviceID:  &deviceID,
+		})
+	}
+	return punches, anomalies
+}
+
+// parsePunchTime reads a wall clock time of the device, the zone is set once the user punching is known
+func parsePunchTime(value string) (time.Time, bool) {
+	value = strings.Join(strings.Fields(value), " ")
+	for _, layout := range punchLayouts {
+		if punchedAt, err := time.Parse(layout, value); err == nil {
+			return punchedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parsePunchState returns the attendance punched, nil when the state is blank or unknown
+func parsePunchState(value string) *entity.AttendanceType {
+	state := strings.ToLower(value)
+	for _, separator := range []string{" ", "/", "-", "_", "."} {
+		state = strings.ReplaceAll(state, separator, "")
+	}
+
+	attendanceType, ok := punchStates[state]
+	if !ok {
+		return nil
+	}
+	return &attendanceType
+}
+
+func invalidLine(line int, pin string, message string) *entity.AttendanceImportAnomaly {
+	return &entity.AttendanceImportAnomaly{
+		Line:    line,
+		Pin:     pin,
+		Type:    entity.AttendanceImportAnomalyTypeInvalidLine,
+		Message: message,
+	}
+}
@@ -0,0 +1,136 @@
+package attendanceparser
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// the headers the attendance software versions name the columns with, compared in lower case
+var (
+	csvPinHeaders      = []string{"pin", "ac-no.", "ac-no", "ac no", "user id", "userid", "user_id", "enroll number", "enrollnumber", "badgenumber"}
+	csvDateTimeHeaders = []string{"datetime", "date time", "date/time", "checktime", "check time", "punch time", "timestamp"}
+	csvDateHeaders     = []string{"date"}
+	csvTimeHeaders     = []string{"time"}
+	csvStateHeaders    = []string{"state", "status", "checktype", "check type", "in/out", "attendance state"}
+)
+
+// csvDelimiter tells whether the first line is the header of a CSV export and which delimiter it uses, the
+// software writes commas, semicolons or tabs depending on the regional settings of Windows
+func csvDelimiter(firstLine string) (rune, bool) {
+	for _, delimiter := range []rune{',', ';', '\t'} {
+		if csvColumn(splitHeader(firstLine, delimiter), csvPinHeaders) >= 0 {
+			return delimiter, true
+		}
+	}
+	return 0, false
+}
+
+func splitHeader(line string, delimiter rune) []string {
+	var header []string
+	for _, column := range strings.Split(line, string(delimiter)) {
+		header = append(header, strings.ToLower(strings.Trim(strings.TrimSpace(column), `"`)))
+	}
+	return header
+}
+
+// csvColumn returns the index of the first column named by one of the headers, -1 when there is none
+func csvColumn(header []string, names []string) int {
+	return slices.IndexFunc(header, func(column string) bool {
+		return slices.Contains(names, column)
+	})
+}
+
+// parseCsv reads the CSV export of the attendance software, the punch time is either in a single column or split
+// into a date and a time column
+func parseCsv(content []byte, delimiter rune) ([]*entity.AttendancePunch, []*entity.AttendanceImportAnomaly, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	headerRecord, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	header := make([]string, len(headerRecord))
+	for i, column := range headerRecord {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+
+	pinColumn := csvColumn(header, csvPinHeaders)
+	dateTimeColumn := csvColumn(header, csvDateTimeHeaders)
+	dateColumn := csvColumn(header, csvDateHeaders)
+	timeColumn := csvColumn(header, csvTimeHeaders)
+	stateColumn := csvColumn(header, csvStateHeaders)
+	if dateTimeColumn < 0 && dateColumn < 0 {
+		// a lone time column holds the whole punch time
+		dateTimeColumn, timeColumn = timeColumn, -1
+	}
+	if dateTimeColumn < 0 && (dateColumn < 0 || timeColumn < 0) {
+		return nil, nil, &internalerror.AttendanceLogInvalidError{Reason: "the header has no punch time column"}
+	}
+
+	var punches []*entity.AttendancePunch
+	var anomalies []*entity.AttendanceImportAnomaly
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			anomalies = append(anomalies, invalidLine(parseErr.StartLine, "", parseErr.Err.Error()))
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(column int) string {
+			if column < 0 || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+
+		pin := value(pinColumn)
+		punchTime := value(dateTimeColumn)
+		if dateTimeColumn < 0 {
+			punchTime = value(dateColumn) + " " + value(timeColumn)
+		}
+		if pin == "" && strings.TrimSpace(punchTime) == "" {
+			continue
+		}
+		if pin == "" {
+			anomalies = append(anomalies, invalidLine(line, "", "Expected a PIN"))
+			continue
+		}
+
+		punchedAt, ok := parsePunchTime(punchTime)
+		if !ok {
+			anomalies = append(anomalies, invalidLine(line, pin, "Invalid punch time "+punchTime))
+			continue
+		}
+
+		punches = append(punches, &entity.AttendancePunch{
+			Line:      line,
+			Pin:       pin,
+			PunchedAt: punchedAt,
+			Type:      parsePunchState(value(stateColumn)),
+		})
+	}
+
+	if len(punches) == 0 {
+		return nil, nil, &internalerror.AttendanceLogInvalidError{Reason: "no line has a PIN and a punch time"}
+	}
+
+	return punches, anomalies, nil
+}
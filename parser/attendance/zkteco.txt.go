@@ -0,0 +1,68 @@
+package attendanceparser
+
+import (
+	"bufio"
+	"bytes"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	"strings"
+)
+
+// attlogDownloadStateColumn is the column of the state in the attlog downloaded from the device over USB:
+//
+//	PIN, punch time, device number, state, verify mode, work code
+const attlogDownloadStateColumn = 3
+
+// parseAttlog reads a log with one punch per line and no header, the columns are separated by tabs. A log with the
+// tabs turned into spaces is read as well, the punch time then spans two columns
+func parseAttlog(content []byte, stateColumn int) ([]*entity.AttendancePunch, []*entity.AttendanceImportAnomaly, error) {
+	var punches []*entity.AttendancePunch
+	var anomalies []*entity.AttendanceImportAnomaly
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var columns []string
+		if strings.Contains(text, "\t") {
+			for _, column := range strings.Split(text, "\t") {
+				columns = append(columns, strings.TrimSpace(column))
+			}
+		} else if fields := strings.Fields(text); len(fields) >= 3 {
+			columns = append([]string{fields[0], fields[1] + " " + fields[2]}, fields[3:]...)
+		}
+
+		if len(columns) < 2 || columns[0] == "" {
+			anomalies = append(anomalies, invalidLine(line, "", "Expected a PIN and a punch time"))
+			continue
+		}
+
+		punchedAt, ok := parsePunchTime(columns[1])
+		if !ok {
+			anomalies = append(anomalies, invalidLine(line, columns[0], "Invalid punch time "+columns[1]))
+			continue
+		}
+
+		punch := &entity.AttendancePunch{
+			Line:      line,
+			Pin:       columns[0],
+			PunchedAt: punchedAt,
+		}
+		if len(columns) > stateColumn {
+			punch.Type = parsePunchState(columns[stateColumn])
+		}
+		punches = append(punches, punch)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(punches) == 0 {
+		return nil, nil, &internalerror.AttendanceLogInvalidError{Reason: "no line has a PIN and a punch time"}
+	}
+
+	return punches, anomalies, nil
+}
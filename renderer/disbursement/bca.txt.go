@@ -0,0 +1,73 @@
+package disbursementrenderer
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var bcaAccountNumber = regexp.MustCompile(`^\d{10}$`)
+
+// bcaRenderer renders the fixed width payroll upload of KlikBCA Bisnis, it only pays into BCA accounts.
+// The header record is followed by one detail record per transfer, the amounts carry two implied decimals:
+//
+//	header: "0", corporate ID (10), effective date DDMMYYYY (8), debit account (10), transfer count (5), total amount (17)
+//	detail: "1", credit account (10), amount (17), employee ID (10), account name (35)
+type bcaRenderer struct{}
+
+// bcaField fits a value into a field of the given width, the positions of the file are counted in bytes. The value is cut
+// between characters, a multi-byte character is never split, and padded with spaces
+func bcaField(value string, width int) string {
+	end := 0
+	for end < len(value) {
+		_, size := utf8.DecodeRuneInString(value[end:])
+		if end+size > width {
+			break
+		}
+		end += size
+	}
+
+	return value[:end] + strings.Repeat(" ", width-end)
+}
+
+func (r *bcaRenderer) Validate(batch *entity.DisbursementBatch, transfer *entity.DisbursementTransfer) string {
+	if transfer.BankCode != entity.BankCodeBca {
+		return "KlikBCA Bisnis payroll only pays into BCA accounts"
+	}
+
+	if !bcaAccountNumber.MatchString(transfer.AccountNumber) {
+		return "BCA account number must be 10 digits"
+	}
+
+	return ""
+}
+
+func (r *bcaRenderer) Render(batch *entity.DisbursementBatch) (*entity.DisbursementFile, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "0%s%s%s%05d%015d00\r\n",
+		bcaField(batch.CorporateID, 10),
+		batch.ExecutedAt.Format("02012006"),
+		bcaField(batch.DebitAccountNumber, 10),
+		len(batch.Transfers),
+		batch.TotalAmount,
+	)
+
+	for _, transfer := range batch.Transfers {
+		fmt.Fprintf(&buf, "1%s%015d00%010d%s\r\n",
+			bcaField(transfer.AccountNumber, 10),
+			transfer.Amount,
+			transfer.UserID,
+			bcaField(transfer.AccountName, 35),
+		)
+	}
+
+	return &entity.DisbursementFile{
+		Filename:    filename(batch, entity.DisbursementFormatBca, "txt"),
+		ContentType: "text/plain",
+		Content:     buf.Bytes(),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package disbursementrenderer
+
+import (
+	"d-payroll/entity"
+	"fmt"
+)
+
+// DisbursementRenderer renders a disbursement batch into the bulk transfer file of a bank
+type DisbursementRenderer interface {
+	// Validate returns why a transfer of the batch cannot be paid through the format, empty when it can
+	Validate(batch *entity.DisbursementBatch, transfer *entity.DisbursementTransfer) string
+	Render(batch *entity.DisbursementBatch) (*entity.DisbursementFile, error)
+}
+
+// renderers holds every supported format, a new bank format only needs an entry here
+var renderers = map[entity.DisbursementFormat]DisbursementRenderer{
+	entity.DisbursementFormatCsv:     &csvRenderer{},
+	entity.DisbursementFormatBca:     &bcaRenderer{},
+	entity.DisbursementFormatMandiri: &mandiriRenderer{},
+	entity.DisbursementFormatPain001: &pain001Renderer{},
+}
+
+func GetDisbursementRenderer(format entity.DisbursementFormat) (DisbursementRenderer, bool) {
+	renderer, ok := renderers[format]
+	return renderer, ok
+}
+
+func filename(batch *entity.DisbursementBatch, format entity.DisbursementFormat, extension string) string {
+	return fmt.Sprintf("disbursement-%d-%s.%s", *batch.Payroll.ID, format, extension)
+}
+
+func remark(batch *entity.DisbursementBatch) string {
+	return fmt.Sprintf("Payroll %s", batch.Payroll.Name)
+}
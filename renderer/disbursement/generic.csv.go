@@ -0,0 +1,45 @@
+package disbursementrenderer
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"encoding/csv"
+	"strconv"
+)
+
+// csvRenderer is a bank agnostic CSV, one row per transfer
+type csvRenderer struct{}
+
+func (r *csvRenderer) Validate(batch *entity.DisbursementBatch, transfer *entity.DisbursementTransfer) string {
+	return ""
+}
+
+func (r *csvRenderer) Render(batch *entity.DisbursementBatch) (*entity.DisbursementFile, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"user_id", "username", "bank_code", "bank_name", "account_number", "account_name", "amount", "remark"})
+	for _, transfer := range batch.Transfers {
+		writer.Write([]string{
+			strconv.FormatUint(uint64(transfer.UserID), 10),
+			transfer.Username,
+			string(transfer.BankCode),
+			transfer.BankCode.Name(),
+			transfer.AccountNumber,
+			transfer.AccountName,
+			strconv.Itoa(transfer.Amount),
+			remark(batch),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return &entity.DisbursementFile{
+		Filename:    filename(batch, entity.DisbursementFormatCsv, "csv"),
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+	}, nil
+}
@@ -0,0 +1,70 @@
+package disbursementrenderer
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"encoding/csv"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	mandiriInHouseTransfer   = "IBU"
+	mandiriInterbankTransfer = "LBU"
+)
+
+// mandiriRenderer renders the bulk transfer upload of Mandiri Cash Management, a "P" header row followed by one
+// row per transfer, transfers into Mandiri accounts go in-house and the others through the interbank clearing
+type mandiriRenderer struct{}
+
+func (r *mandiriRenderer) Validate(batch *entity.DisbursementBatch, transfer *entity.DisbursementTransfer) string {
+	if utf8.RuneCountInString(transfer.AccountName) > 70 {
+		return "Mandiri account name must be at most 70 characters"
+	}
+
+	return ""
+}
+
+func (r *mandiriRenderer) Render(batch *entity.DisbursementBatch) (*entity.DisbursementFile, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.UseCRLF = true
+
+	writer.Write([]string{
+		"P",
+		batch.ExecutedAt.Format("20060102"),
+		batch.DebitAccountNumber,
+		strconv.Itoa(len(batch.Transfers)),
+		strconv.Itoa(batch.TotalAmount),
+	})
+
+	for _, transfer := range batch.Transfers {
+		transferType := mandiriInterbankTransfer
+		if transfer.BankCode == entity.BankCodeMandiri {
+			transferType = mandiriInHouseTransfer
+		}
+
+		writer.Write([]string{
+			transfer.AccountNumber,
+			transfer.AccountName,
+			"IDR",
+			strconv.Itoa(transfer.Amount),
+			remark(batch),
+			transferType,
+			transfer.BankCode.ClearingCode(),
+			transfer.BankCode.Name(),
+			strconv.FormatUint(uint64(transfer.UserID), 10),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return &entity.DisbursementFile{
+		Filename:    filename(batch, entity.DisbursementFormatMandiri, "csv"),
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+	}, nil
+}
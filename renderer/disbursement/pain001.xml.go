@@ -0,0 +1,160 @@
+package disbursementrenderer
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"encoding/xml"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+	// pain001MaxIDLength bounds the identifications of the message, Max35Text in the schema
+	pain001MaxIDLength = 35
+)
+
+// pain001Renderer renders an ISO 20022 customer credit transfer initiation (pain.001.001.03), a single payment
+// information block debiting the company account with one credit transfer per employee
+type pain001Renderer struct{}
+
+type pain001Document struct {
+	XMLName    xml.Name          `xml:"Document"`
+	Xmlns      string            `xml:"xmlns,attr"`
+	Initiation pain001Initiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiation struct {
+	GroupHeader pain001GroupHeader `xml:"GrpHdr"`
+	Payment     pain001Payment     `xml:"PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MessageID        string       `xml:"MsgId"`
+	CreatedAt        string       `xml:"CreDtTm"`
+	TransactionCount int          `xml:"NbOfTxs"`
+	ControlSum       string       `xml:"CtrlSum"`
+	InitiatingParty  pain001Party `xml:"InitgPty"`
+}
+
+type pain001Party struct {
+	Name string `xml:"Nm"`
+}
+
+type pain001Account struct {
+	ID string `xml:"Id>Othr>Id"`
+}
+
+type pain001Agent struct {
+	Bic string `xml:"FinInstnId>BIC"`
+}
+
+type pain001Payment struct {
+	PaymentInformationID string                  `xml:"PmtInfId"`
+	PaymentMethod        string                  `xml:"PmtMtd"`
+	TransactionCount     int                     `xml:"NbOfTxs"`
+	ControlSum           string                  `xml:"CtrlSum"`
+	ExecutionDate        string                  `xml:"ReqdExctnDt"`
+	Debtor               pain001Party            `xml:"Dbtr"`
+	DebtorAccount        pain001Account          `xml:"DbtrAcct"`
+	DebtorAgent          pain001Agent            `xml:"DbtrAgt"`
+	CreditTransfers      []pain001CreditTransfer `xml:"CdtTrfTxInf"`
+}
+
+type pain001Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type pain001CreditTransfer struct {
+	EndToEndID      string         `xml:"PmtId>EndToEndId"`
+	Amount          pain001Amount  `xml:"Amt>InstdAmt"`
+	CreditorAgent   pain001Agent   `xml:"CdtrAgt"`
+	Creditor        pain001Party   `xml:"Cdtr"`
+	CreditorAccount pain001Account `xml:"CdtrAcct"`
+	Remittance      string         `xml:"RmtInf>Ustrd"`
+}
+
+func pain001Decimal(amount int) string {
+	return fmt.Sprintf("%d.00", amount)
+}
+
+func pain001MessageID(batch *entity.DisbursementBatch) string {
+	return fmt.Sprintf("PAYROLL-%d-%s", *batch.Payroll.ID, batch.CreatedAt.Format("20060102150405"))
+}
+
+// pain001EndToEndID identifies the transfer of an employee, it is unique within the payroll
+func pain001EndToEndID(batch *entity.DisbursementBatch, transfer *entity.DisbursementTransfer) string {
+	return fmt.Sprintf("PAYROLL-%d-%d", *batch.Payroll.ID, transfer.UserID)
+}
+
+func (r *pain001Renderer) Validate(batch *entity.DisbursementBatch, transfer *entity.DisbursementTransfer) string {
+	if utf8.RuneCountInString(transfer.AccountName) > 70 {
+		return "ISO 20022 creditor name must be at most 70 characters"
+	}
+
+	if len(pain001MessageID(batch)) > pain001MaxIDLength {
+		return fmt.Sprintf("ISO 20022 message ID must be at most %d characters", pain001MaxIDLength)
+	}
+
+	if len(pain001EndToEndID(batch, transfer)) > pain001MaxIDLength {
+		return fmt.Sprintf("ISO 20022 end to end ID must be at most %d characters", pain001MaxIDLength)
+	}
+
+	return ""
+}
+
+func (r *pain001Renderer) Render(batch *entity.DisbursementBatch) (*entity.DisbursementFile, error) {
+	messageID := pain001MessageID(batch)
+
+	payment := pain001Payment{
+		PaymentInformationID: messageID,
+		PaymentMethod:        "TRF",
+		TransactionCount:     len(batch.Transfers),
+		ControlSum:           pain001Decimal(batch.TotalAmount),
+		ExecutionDate:        batch.ExecutedAt.Format("2006-01-02"),
+		Debtor:               pain001Party{Name: batch.DebitAccountName},
+		DebtorAccount:        pain001Account{ID: batch.DebitAccountNumber},
+		DebtorAgent:          pain001Agent{Bic: batch.DebitBankCode.Bic()},
+	}
+
+	for _, transfer := range batch.Transfers {
+		payment.CreditTransfers = append(payment.CreditTransfers, pain001CreditTransfer{
+			EndToEndID:      pain001EndToEndID(batch, transfer),
+			Amount:          pain001Amount{Currency: "IDR", Value: pain001Decimal(transfer.Amount)},
+			CreditorAgent:   pain001Agent{Bic: transfer.BankCode.Bic()},
+			Creditor:        pain001Party{Name: transfer.AccountName},
+			CreditorAccount: pain001Account{ID: transfer.AccountNumber},
+			Remittance:      remark(batch),
+		})
+	}
+
+	document := pain001Document{
+		Xmlns: pain001Namespace,
+		Initiation: pain001Initiation{
+			GroupHeader: pain001GroupHeader{
+				MessageID:        messageID,
+				CreatedAt:        batch.CreatedAt.Format("2006-01-02T15:04:05"),
+				TransactionCount: len(batch.Transfers),
+				ControlSum:       pain001Decimal(batch.TotalAmount),
+				InitiatingParty:  pain001Party{Name: batch.DebitAccountName},
+			},
+			Payment: payment,
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+
+	return &entity.DisbursementFile{
+		Filename:    filename(batch, entity.DisbursementFormatPain001, "xml"),
+		ContentType: "application/xml",
+		Content:     buf.Bytes(),
+	}, nil
+}
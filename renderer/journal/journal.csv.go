@@ -0,0 +1,42 @@
+package journalrenderer
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"encoding/csv"
+	"strconv"
+)
+
+// csvRenderer writes one row per journal line, the journal header is repeated on every row
+type csvRenderer struct{}
+
+func (r *csvRenderer) Render(journal *entity.Journal) (*entity.JournalFile, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"reference", "date", "currency", "account_code", "account_name", "cost_center", "description", "debit", "credit"})
+	for _, line := range journal.Lines {
+		writer.Write([]string{
+			journal.Reference,
+			journal.Date.Format("2006-01-02"),
+			journal.Currency,
+			line.AccountCode,
+			line.AccountName,
+			line.CostCenter,
+			line.Description,
+			strconv.Itoa(line.Debit),
+			strconv.Itoa(line.Credit),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return &entity.JournalFile{
+		Filename:    filename(journal, "csv"),
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+	}, nil
+}
@@ -0,0 +1,70 @@
+package journalrenderer
+
+import (
+	"d-payroll/entity"
+	"encoding/json"
+	"time"
+)
+
+type jsonJournalLine struct {
+	Account     string `json:"account"`
+	AccountCode string `json:"account_code"`
+	AccountName string `json:"account_name"`
+	CostCenter  string `json:"cost_center,omitempty"`
+	Description string `json:"description"`
+	Debit       int    `json:"debit"`
+	Credit      int    `json:"credit"`
+}
+
+type jsonJournal struct {
+	Reference   string             `json:"reference"`
+	Date        string             `json:"date"`
+	Description string             `json:"description"`
+	Currency    string             `json:"currency"`
+	Source      string             `json:"source"`
+	PayrollID   uint               `json:"payroll_id"`
+	TotalDebit  int                `json:"total_debit"`
+	TotalCredit int                `json:"total_credit"`
+	Lines       []*jsonJournalLine `json:"lines"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// jsonRenderer writes the generic journal schema, amounts are whole units of the currency
+type jsonRenderer struct{}
+
+func (r *jsonRenderer) Render(journal *entity.Journal) (*entity.JournalFile, error) {
+	document := &jsonJournal{
+		Reference:   journal.Reference,
+		Date:        journal.Date.Format("2006-01-02"),
+		Description: journal.Payroll.Name,
+		Currency:    journal.Currency,
+		Source:      "d-payroll",
+		PayrollID:   *journal.Payroll.ID,
+		TotalDebit:  journal.TotalDebit,
+		TotalCredit: journal.TotalCredit,
+		Lines:       make([]*jsonJournalLine, len(journal.Lines)),
+		CreatedAt:   journal.CreatedAt,
+	}
+	for i, line := range journal.Lines {
+		document.Lines[i] = &jsonJournalLine{
+			Account:     string(line.Account),
+			AccountCode: line.AccountCode,
+			AccountName: line.AccountName,
+			CostCenter:  line.CostCenter,
+			Description: line.Description,
+			Debit:       line.Debit,
+			Credit:      line.Credit,
+		}
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.JournalFile{
+		Filename:    filename(journal, "json"),
+		ContentType: "application/json",
+		Content:     content,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package journalrenderer
+
+import (
+	"d-payroll/entity"
+	"fmt"
+)
+
+// JournalRenderer renders a payroll journal into a file the accounting system imports
+type JournalRenderer interface {
+	Render(journal *entity.Journal) (*entity.JournalFile, error)
+}
+
+var renderers = map[entity.JournalFormat]JournalRenderer{
+	entity.JournalFormatCsv:  &csvRenderer{},
+	entity.JournalFormatJson: &jsonRenderer{},
+}
+
+func GetJournalRenderer(format entity.JournalFormat) (JournalRenderer, bool) {
+	renderer, ok := renderers[format]
+	return renderer, ok
+}
+
+func filename(journal *entity.Journal, extension string) string {
+	return fmt.Sprintf("journal-%d.%s", *journal.Payroll.ID, extension)
+}
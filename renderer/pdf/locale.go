@@ -0,0 +1,127 @@
+package pdfrenderer
+
+import (
+	"d-payroll/entity"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var monthNames = map[entity.Locale][]string{
+	entity.LocaleIndonesian: {"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+	entity.LocaleEnglish:    {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+}
+
+var labels = map[entity.Locale]map[string]string{
+	entity.LocaleIndonesian: {
+		"title":            "SLIP GAJI",
+		"employee":         "Karyawan",
+		"employee_id":      "ID Karyawan",
+		"payroll":          "Penggajian",
+		"period":           "Periode",
+		"monthly_salary":   "Gaji Bulanan",
+		"earnings":         "Pendapatan",
+		"deductions":       "Potongan",
+		"description":      "Keterangan",
+		"amount":           "Jumlah",
+		"attendance":       "Kehadiran",
+		"overtime":         "Lembur",
+		"reimbursement":    "Penggantian Biaya",
+		"gross_pay":        "Total Pendapatan",
+		"total_deductions": "Total Potongan",
+		"take_home_pay":    "Gaji Bersih",
+		"no_deductions":    "Tidak ada potongan",
+		"attendance_title": "Rincian Kehadiran",
+		"overtime_title":   "Rincian Lembur",
+		"date":             "Tanggal",
+		"checkin":          "Masuk",
+		"checkout":         "Pulang",
+		"break":            "Istirahat",
+		"auto_pay_zero":    "Otomatis (nol)",
+		"auto_scheduled":   "Otomatis (jadwal)",
+		"auto_correction":  "Otomatis (koreksi)",
+		"duration":         "Durasi",
+		"generated_at":     "Dicetak pada",
+	},
+	entity.LocaleEnglish: {
+		"title":            "PAYSLIP",
+		"employee":         "Employee",
+		"employee_id":      "Employee ID",
+		"payroll":          "Payroll",
+		"period":           "Period",
+		"monthly_salary":   "Monthly Salary",
+		"earnings":         "Earnings",
+		"deductions":       "Deductions",
+		"description":      "Description",
+		"amount":           "Amount",
+		"attendance":       "Attendance",
+		"overtime":         "Overtime",
+		"reimbursement":    "Reimbursement",
+		"gross_pay":        "Gross Pay",
+		"total_deductions": "Total Deductions",
+		"take_home_pay":    "Take Home Pay",
+		"no_deductions":    "No deductions",
+		"attendance_title": "Attendance Details",
+		"overtime_title":   "Overtime Details",
+		"date":             "Date",
+		"checkin":          "Check-in",
+		"checkout":         "Check-out",
+		"break":            "Break",
+		"auto_pay_zero":    "Auto (unpaid)",
+		"auto_scheduled":   "Auto (scheduled)",
+		"auto_correction":  "Auto (to correct)",
+		"duration":         "Duration",
+		"generated_at":     "Generated at",
+	},
+}
+
+func translate(locale entity.Locale, key string) string {
+	return labels[locale][key]
+}
+
+// formatMoney formats an amount in rupiah, Indonesian uses dots to group thousands and a comma for decimals
+func formatMoney(amount float32, locale entity.Locale) string {
+	thousandSeparator, decimalSeparator := ",", "."
+	if locale == entity.LocaleIndonesian {
+		thousandSeparator, decimalSeparator = ".", ","
+	}
+
+	rounded := math.Round(float64(amount)*100) / 100
+	sign := ""
+	if rounded < 0 {
+		sign = "-"
+		rounded = -rounded
+	}
+
+	integer := int64(rounded)
+	decimal := int64(math.Round((rounded - float64(integer)) * 100))
+
+	return fmt.Sprintf("%sRp %s%s%02d", sign, groupThousands(integer, thousandSeparator), decimalSeparator, decimal)
+}
+
+func groupThousands(integer int64, separator string) string {
+	digits := fmt.Sprintf("%d", integer)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteString(separator)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return grouped.String()
+}
+
+func formatDate(t time.Time, locale entity.Locale) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), monthNames[locale][t.Month()-1], t.Year())
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("15:04")
+}
+
+func formatDuration(durationMilis int) string {
+	duration := time.Duration(durationMilis) * time.Millisecond
+	return fmt.Sprintf("%02d:%02d", int(duration.Hours()), int(duration.Minutes())%60)
+}
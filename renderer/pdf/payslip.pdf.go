@@ -0,0 +1,321 @@
+package pdfrenderer
+
+import (
+	"bytes"
+	"d-payroll/config"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pageMargin  = 15.0
+	lineHeight  = 6.0
+	amountWidth = 50.0
+)
+
+// PayslipDocument holds what is printed on a payslip besides the payslip itself
+type PayslipDocument struct {
+	Payslip *entity.Payslip
+	Payroll *entity.Payroll
+	User    *entity.User
+	Locale  entity.Locale
+	// Password protects the document when set, the company owner password unlocks it when configured
+	Password string
+}
+
+type PayslipRenderer interface {
+	RenderPayslip(document *PayslipDocument) ([]byte, error)
+}
+
+type payslipRenderer struct {
+	config *config.Config
+}
+
+func NewPayslipRenderer(config *config.Config) PayslipRenderer {
+	return &payslipRenderer{config: config}
+}
+
+// payslipPdf wraps a single document rendering, the core fonts are cp1252 so every text goes through tr
+type payslipPdf struct {
+	pdf      *gofpdf.Fpdf
+	tr       func(string) string
+	locale   entity.Locale
+	document *PayslipDocument
+}
+
+func (r *payslipRenderer) RenderPayslip(document *PayslipDocument) ([]byte, error) {
+	locale := document.Locale
+	if !locale.IsValid() {
+		locale = entity.LocaleIndonesian
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle(fmt.Sprintf("%s - %s", translate(locale, "title"), document.Payroll.Name), true)
+	pdf.SetAuthor(r.config.Company.Name, true)
+	pdf.SetCreationDate(utils.TimeNow())
+
+	if document.Password != "" {
+		// without a company owner password the document is only opened by its employee
+		ownerPassword := r.config.Payslip.OwnerPassword
+		if ownerPassword == "" {
+			var err error
+			ownerPassword, err = utils.GenerateApiKey()
+			if err != nil {
+				return nil, err
+			}
+		}
+		pdf.SetProtection(gofpdf.CnProtectPrint, document.Password, ownerPassword)
+	}
+
+	p := &payslipPdf{
+		pdf:      pdf,
+		tr:       pdf.UnicodeTranslatorFromDescriptor(""),
+		locale:   locale,
+		document: document,
+	}
+
+	pdf.AddPage()
+	p.letterhead(r.config.Company)
+	p.summary()
+	p.earnings()
+	p.deductions()
+	p.takeHomePay()
+
+	if document.Payslip.Attendance != nil && len(document.Payslip.Attendance.Details) > 0 {
+		pdf.AddPage()
+		p.letterhead(r.config.Company)
+		p.attendanceDetails()
+	}
+
+	if document.Payslip.Overtime != nil && len(document.Payslip.Overtime.Details) > 0 {
+		pdf.AddPage()
+		p.letterhead(r.config.Company)
+		p.overtimeDetails()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (p *payslipPdf) letterhead(company *config.CompanyConfig) {
+	letterhead(p.pdf, p.tr, company)
+}
+
+// letterhead prints the company letterhead shared by every document at the top of the page
+func letterhead(pdf *gofpdf.Fpdf, tr func(string) string, company *config.CompanyConfig) {
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, tr(company.Name), "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	if company.Address != "" {
+		pdf.CellFormat(0, 5, tr(company.Address), "", 1, "L", false, 0, "")
+	}
+
+	contact := company.Phone
+	if company.Email != "" {
+		if contact != "" {
+			contact += " | "
+		}
+		contact += company.Email
+	}
+	if contact != "" {
+		pdf.CellFormat(0, 5, tr(contact), "", 1, "L", false, 0, "")
+	}
+
+	x, y := pdf.GetXY()
+	pageWidth, _ := pdf.GetPageSize()
+	pdf.SetLineWidth(0.5)
+	pdf.Line(x, y+2, pageWidth-pageMargin, y+2)
+	pdf.SetLineWidth(0.2)
+	pdf.Ln(6)
+}
+
+func (p *payslipPdf) heading(text string) {
+	p.pdf.SetFont("Helvetica", "B", 11)
+	p.pdf.CellFormat(0, lineHeight+1, p.tr(text), "", 1, "L", false, 0, "")
+}
+
+func (p *payslipPdf) row(label string, value string) {
+	p.pdf.SetFont("Helvetica", "", 10)
+	p.pdf.CellFormat(45, lineHeight, p.tr(label), "", 0, "L", false, 0, "")
+	p.pdf.CellFormat(0, lineHeight, p.tr(": "+value), "", 1, "L", false, 0, "")
+}
+
+func (p *payslipPdf) summary() {
+	document := p.document
+
+	p.pdf.SetFont("Helvetica", "B", 14)
+	p.pdf.CellFormat(0, 10, p.tr(translate(p.locale, "title")), "", 1, "C", false, 0, "")
+	p.pdf.Ln(2)
+
+	p.row(translate(p.locale, "employee"), document.User.Username)
+	p.row(translate(p.locale, "employee_id"), fmt.Sprintf("%d", document.Payslip.UserID))
+	p.row(translate(p.locale, "payroll"), fmt.Sprintf("%s (%s)", document.Payroll.Name, document.Payslip.PayrollType))
+	p.row(translate(p.locale, "period"), fmt.Sprintf("%s - %s", formatDate(document.Payroll.StartedAt, p.locale), formatDate(document.Payroll.EndedAt, p.locale)))
+	if !document.Payslip.PayrollType.IsOffCycle() {
+		p.row(translate(p.locale, "monthly_salary"), formatMoney(float32(document.Payslip.Salary), p.locale))
+	}
+	p.pdf.Ln(4)
+}
+
+func (p *payslipPdf) tableHeader(columns []string, widths []float64) {
+	p.pdf.SetFont("Helvetica", "B", 10)
+	p.pdf.SetFillColor(230, 230, 230)
+	for i, column := range columns {
+		align := "L"
+		if i == len(columns)-1 {
+			align = "R"
+		}
+		p.pdf.CellFormat(widths[i], lineHeight+1, p.tr(column), "1", 0, align, true, 0, "")
+	}
+	p.pdf.Ln(-1)
+}
+
+func (p *payslipPdf) amountRow(description string, amount float32) {
+	p.pdf.SetFont("Helvetica", "", 10)
+	p.pdf.CellFormat(p.descriptionWidth(), lineHeight, p.tr(description), "1", 0, "L", false, 0, "")
+	p.pdf.CellFormat(amountWidth, lineHeight, p.tr(formatMoney(amount, p.locale)), "1", 1, "R", false, 0, "")
+}
+
+func (p *payslipPdf) totalRow(description string, amount float32) {
+	p.pdf.SetFont("Helvetica", "B", 10)
+	p.pdf.CellFormat(p.descriptionWidth(), lineHeight, p.tr(description), "1", 0, "L", false, 0, "")
+	p.pdf.CellFormat(amountWidth, lineHeight, p.tr(formatMoney(amount, p.locale)), "1", 1, "R", false, 0, "")
+}
+
+func (p *payslipPdf) descriptionWidth() float64 {
+	pageWidth, _ := p.pdf.GetPageSize()
+	return pageWidth - 2*pageMargin - amountWidth
+}
+
+func (p *payslipPdf) earnings() {
+	payslip := p.document.Payslip
+
+	p.heading(translate(p.locale, "earnings"))
+	p.tableHeader([]string{translate(p.locale, "description"), translate(p.locale, "amount")}, []float64{p.descriptionWidth(), amountWidth})
+
+	var grossPay float32
+	if payslip.Attendance != nil {
+		p.amountRow(fmt.Sprintf("%s (%s)", translate(p.locale, "attendance"), formatDuration(payslip.Attendance.TotalDurationMilis)), payslip.Attendance.TotalAmount)
+		grossPay += payslip.Attendance.TotalAmount
+	}
+	if payslip.Overtime != nil {
+		p.amountRow(fmt.Sprintf("%s (%s)", translate(p.locale, "overtime"), formatDuration(payslip.Overtime.TotalDurationMilis)), payslip.Overtime.TotalAmount)
+		grossPay += payslip.Overtime.TotalAmount
+	}
+	if payslip.Reimburse != nil {
+		p.amountRow(translate(p.locale, "reimbursement"), payslip.Reimburse.TotalAmount)
+		grossPay += payslip.Reimburse.TotalAmount
+	}
+	if payslip.Earning != nil {
+		for _, earning := range payslip.Earning.Details {
+			p.amountRow(earning.Description, float32(earning.Amount))
+		}
+		grossPay += payslip.Earning.TotalAmount
+	}
+	if payslip.RetroAdjustment != nil {
+		for _, adjustment := range payslip.RetroAdjustment.Details {
+			p.amountRow(adjustment.Description, float32(adjustment.Amount))
+		}
+		grossPay += payslip.RetroAdjustment.TotalAmount
+	}
+
+	p.totalRow(translate(p.locale, "gross_pay"), grossPay)
+	p.pdf.Ln(4)
+}
+
+func (p *payslipPdf) deductions() {
+	payslip := p.document.Payslip
+
+	p.heading(translate(p.locale, "deductions"))
+	p.tableHeader([]string{translate(p.locale, "description"), translate(p.locale, "amount")}, []float64{p.descriptionWidth(), amountWidth})
+
+	var totalDeductions float32
+	if payslip.Deduction != nil && len(payslip.Deduction.Details) > 0 {
+		for _, deduction := range payslip.Deduction.Details {
+			p.amountRow(deduction.Description, float32(deduction.Amount))
+		}
+		totalDeductions = payslip.Deduction.TotalAmount
+	} else {
+		p.pdf.SetFont("Helvetica", "I", 10)
+		p.pdf.CellFormat(0, lineHeight, p.tr(translate(p.locale, "no_deductions")), "1", 1, "L", false, 0, "")
+	}
+
+	p.totalRow(translate(p.locale, "total_deductions"), totalDeductions)
+	p.pdf.Ln(4)
+}
+
+func (p *payslipPdf) takeHomePay() {
+	p.pdf.SetFont("Helvetica", "B", 12)
+	p.pdf.SetFillColor(230, 230, 230)
+	p.pdf.CellFormat(p.descriptionWidth(), lineHeight+3, p.tr(translate(p.locale, "take_home_pay")), "1", 0, "L", true, 0, "")
+	p.pdf.CellFormat(amountWidth, lineHeight+3, p.tr(formatMoney(p.document.Payslip.TakeHomePay, p.locale)), "1", 1, "R", true, 0, "")
+	p.pdf.Ln(6)
+
+	p.pdf.SetFont("Helvetica", "I", 8)
+	generatedAt := utils.TimeNow()
+	p.pdf.CellFormat(0, 5, p.tr(fmt.Sprintf("%s %s %s", translate(p.locale, "generated_at"), formatDate(generatedAt, p.locale), formatTime(generatedAt))), "", 1, "R", false, 0, "")
+}
+
+var autoClosePolicyLabels = map[entity.AttendanceAutoClosePolicy]string{
+	entity.AttendanceAutoClosePolicyPayZero:           "auto_pay_zero",
+	entity.AttendanceAutoClosePolicyPayScheduled:      "auto_scheduled",
+	entity.AttendanceAutoClosePolicyRequireCorrection: "auto_correction",
+}
+
+func (p *payslipPdf) attendanceDetails() {
+	attendance := p.document.Payslip.Attendance
+
+	p.heading(translate(p.locale, "attendance_title"))
+	widths := []float64{50, 35, 35, 30, 30}
+	p.tableHeader([]string{translate(p.locale, "date"), translate(p.locale, "checkin"), translate(p.locale, "checkout"), translate(p.locale, "break"), translate(p.locale, "duration")}, widths)
+
+	p.pdf.SetFont("Helvetica", "", 10)
+	for _, detail := range attendance.Details {
+		checkoutAt := "-"
+		if detail.AutoClosePolicy != nil {
+			// the session was never checked out, the policy it is paid by is shown instead
+			checkoutAt = translate(p.locale, autoClosePolicyLabels[*detail.AutoClosePolicy])
+		} else if detail.CheckoutAt != nil {
+			checkoutAt = formatTime(*detail.CheckoutAt)
+		}
+
+		p.pdf.CellFormat(widths[0], lineHeight, p.tr(formatDate(detail.CheckinAt, p.locale)), "1", 0, "L", false, 0, "")
+		p.pdf.CellFormat(widths[1], lineHeight, formatTime(detail.CheckinAt), "1", 0, "L", false, 0, "")
+		p.pdf.CellFormat(widths[2], lineHeight, p.tr(checkoutAt), "1", 0, "L", false, 0, "")
+		p.pdf.CellFormat(widths[3], lineHeight, formatDuration(detail.BreakDurationMilis), "1", 0, "R", false, 0, "")
+		p.pdf.CellFormat(widths[4], lineHeight, formatDuration(detail.DurationMilis), "1", 1, "R", false, 0, "")
+	}
+
+	p.pdf.SetFont("Helvetica", "B", 10)
+	p.pdf.CellFormat(widths[0]+widths[1]+widths[2]+widths[3], lineHeight, "", "1", 0, "L", false, 0, "")
+	p.pdf.CellFormat(widths[4], lineHeight, formatDuration(attendance.TotalDurationMilis), "1", 1, "R", false, 0, "")
+}
+
+func (p *payslipPdf) overtimeDetails() {
+	overtime := p.document.Payslip.Overtime
+
+	p.heading(translate(p.locale, "overtime_title"))
+	widths := []float64{50, 90, 40}
+	p.tableHeader([]string{translate(p.locale, "date"), translate(p.locale, "description"), translate(p.locale, "duration")}, widths)
+
+	p.pdf.SetFont("Helvetica", "", 10)
+	for _, detail := range overtime.Details {
+		p.pdf.CellFormat(widths[0], lineHeight, p.tr(formatDate(detail.OvertimeAt, p.locale)), "1", 0, "L", false, 0, "")
+		p.pdf.CellFormat(widths[1], lineHeight, p.tr(detail.Description), "1", 0, "L", false, 0, "")
+		p.pdf.CellFormat(widths[2], lineHeight, formatDuration(detail.DurationMilis), "1", 1, "R", false, 0, "")
+	}
+
+	p.pdf.SetFont("Helvetica", "B", 10)
+	p.pdf.CellFormat(widths[0]+widths[1], lineHeight, "", "1", 0, "L", false, 0, "")
+	p.pdf.CellFormat(widths[2], lineHeight, formatDuration(overtime.TotalDurationMilis), "1", 1, "R", false, 0, "")
+}
@@ -0,0 +1,171 @@
+package pdfrenderer
+
+import (
+	"bytes"
+	"d-payroll/config"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// taxCertificateLines are the descriptions of the lines 1 to 20 of the Form 1721-A1, the form is only issued in Indonesian
+var taxCertificateLines = []string{
+	"Gaji/Pensiun atau THT/JHT",
+	"Tunjangan PPh",
+	"Tunjangan Lainnya, Uang Lembur dan sebagainya",
+	"Honorarium dan Imbalan Lain Sejenisnya",
+	"Premi Asuransi yang Dibayar Pemberi Kerja",
+	"Penerimaan dalam Bentuk Natura dan Kenikmatan Lainnya",
+	"Tantiem, Bonus, Gratifikasi, Jasa Produksi dan THR",
+	"Jumlah Penghasilan Bruto (1 s.d. 7)",
+	"Biaya Jabatan/Biaya Pensiun",
+	"Iuran Pensiun atau Iuran THT/JHT",
+	"Jumlah Pengurangan (9 s.d. 10)",
+	"Jumlah Penghasilan Neto (8 - 11)",
+	"Penghasilan Neto Masa Sebelumnya",
+	"Jumlah Penghasilan Neto untuk Penghitungan PPh Pasal 21",
+	"Penghasilan Tidak Kena Pajak (PTKP)",
+	"Penghasilan Kena Pajak Setahun/Disetahunkan (14 - 15)",
+	"PPh Pasal 21 atas Penghasilan Kena Pajak Setahun/Disetahunkan",
+	"PPh Pasal 21 yang Telah Dipotong Masa Sebelumnya",
+	"PPh Pasal 21 Terutang",
+	"PPh Pasal 21 dan PPh Pasal 26 yang Telah Dipotong dan Dilunasi",
+}
+
+const lineNumberWidth = 10.0
+
+type TaxCertificateRenderer interface {
+	RenderTaxCertificate(certificate *entity.TaxCertificate) ([]byte, error)
+}
+
+type taxCertificateRenderer struct {
+	config *config.Config
+}
+
+func NewTaxCertificateRenderer(config *config.Config) TaxCertificateRenderer {
+	return &taxCertificateRenderer{config: config}
+}
+
+// taxCertificatePdf wraps a single certificate rendering, see payslipPdf
+type taxCertificatePdf struct {
+	pdf         *gofpdf.Fpdf
+	tr          func(string) string
+	certificate *entity.TaxCertificate
+}
+
+func (r *taxCertificateRenderer) RenderTaxCertificate(certificate *entity.TaxCertificate) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle(fmt.Sprintf("1721-A1 %d - %s", certificate.Year, certificate.Name), true)
+	pdf.SetAuthor(r.config.Company.Name, true)
+	pdf.SetCreationDate(utils.TimeNow())
+
+	p := &taxCertificatePdf{
+		pdf:         pdf,
+		tr:          pdf.UnicodeTranslatorFromDescriptor(""),
+		certificate: certificate,
+	}
+
+	pdf.AddPage()
+	letterhead(pdf, p.tr, r.config.Company)
+	p.title(r.config)
+	p.recipient()
+	p.amounts()
+	p.signer(r.config.Tax)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (p *taxCertificatePdf) heading(text string) {
+	p.pdf.SetFont("Helvetica", "B", 10)
+	p.pdf.SetFillColor(230, 230, 230)
+	p.pdf.CellFormat(0, lineHeight+1, p.tr(text), "1", 1, "L", true, 0, "")
+}
+
+func (p *taxCertificatePdf) row(label string, value string) {
+	p.pdf.SetFont("Helvetica", "", 9)
+	p.pdf.CellFormat(45, lineHeight, p.tr(label), "", 0, "L", false, 0, "")
+	p.pdf.CellFormat(0, lineHeight, p.tr(": "+value), "", 1, "L", false, 0, "")
+}
+
+func (p *taxCertificatePdf) title(config *config.Config) {
+	certificate := p.certificate
+
+	p.pdf.SetFont("Helvetica", "B", 12)
+	p.pdf.CellFormat(0, 7, p.tr("BUKTI PEMOTONGAN PAJAK PENGHASILAN PASAL 21"), "", 1, "C", false, 0, "")
+	p.pdf.CellFormat(0, 7, p.tr("BAGI PEGAWAI TETAP ATAU PENERIMA PENSIUN ATAU THT/JHT BERKALA"), "", 1, "C", false, 0, "")
+	p.pdf.SetFont("Helvetica", "", 10)
+	p.pdf.CellFormat(0, 6, p.tr("FORMULIR 1721 - A1"), "", 1, "C", false, 0, "")
+	p.pdf.Ln(2)
+
+	p.row("Nomor", certificate.Number)
+	p.row("Masa Perolehan Penghasilan", fmt.Sprintf("%02d - %02d %d", certificate.FirstMonth, certificate.LastMonth, certificate.Year))
+	p.row("NPWP Pemotong", valueOrDash(config.Tax.Npwp))
+	p.row("Nama Pemotong", config.Company.Name)
+	p.pdf.Ln(2)
+}
+
+func (p *taxCertificatePdf) recipient() {
+	certificate := p.certificate
+
+	gender := "-"
+	switch certificate.Gender {
+	case entity.GenderMale:
+		gender = "Laki-laki"
+	case entity.GenderFemale:
+		gender = "Perempuan"
+	}
+
+	p.heading("A. IDENTITAS PENERIMA PENGHASILAN YANG DIPOTONG")
+	p.row("NPWP", valueOrDash(certificate.Npwp))
+	p.row("NIK", valueOrDash(certificate.Nik))
+	p.row("Nama", certificate.Name)
+	p.row("Alamat", valueOrDash(certificate.Address))
+	p.row("Jenis Kelamin", gender)
+	p.row("Status PTKP", valueOrDash(string(certificate.TaxStatus)))
+	p.row("Nama Jabatan", valueOrDash(certificate.Position))
+	p.pdf.Ln(2)
+}
+
+func (p *taxCertificatePdf) amounts() {
+	pageWidth, _ := p.pdf.GetPageSize()
+	descriptionWidth := pageWidth - 2*pageMargin - lineNumberWidth - amountWidth
+
+	p.heading("B. RINCIAN PENGHASILAN DAN PENGHITUNGAN PPh PASAL 21")
+	for i, amount := range p.certificate.Amounts() {
+		style := ""
+		// the totals of the gross income, the net income and the tax due are printed in bold
+		if i == 7 || i == 11 || i == 18 {
+			style = "B"
+		}
+
+		p.pdf.SetFont("Helvetica", style, 9)
+		p.pdf.CellFormat(lineNumberWidth, lineHeight, fmt.Sprintf("%d.", i+1), "1", 0, "R", false, 0, "")
+		p.pdf.CellFormat(descriptionWidth, lineHeight, p.tr(taxCertificateLines[i]), "1", 0, "L", false, 0, "")
+		p.pdf.CellFormat(amountWidth, lineHeight, groupThousands(int64(amount), "."), "1", 1, "R", false, 0, "")
+	}
+	p.pdf.Ln(2)
+}
+
+// signer prints who signs the certificate on behalf of the company
+func (p *taxCertificatePdf) signer(tax *config.TaxConfig) {
+	p.heading("C. IDENTITAS PEMOTONG")
+	p.row("NPWP", valueOrDash(tax.SignerNpwp))
+	p.row("Nama", valueOrDash(tax.SignerName))
+	p.row("Tanggal", formatDate(p.certificate.Date, entity.LocaleIndonesian))
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package reportrenderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter writes an array of objects keyed by the header columns, in column order
+type jsonWriter struct {
+	w        io.Writer
+	columns  []string
+	rowCount int
+}
+
+func newJsonWriter(w io.Writer, title string) TableWriter {
+	return &jsonWriter{w: w}
+}
+
+func (j *jsonWriter) WriteHeader(columns []string) error {
+	j.columns = columns
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *jsonWriter) WriteRow(values []any) error {
+	var buf bytes.Buffer
+	if j.rowCount > 0 {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\n  {")
+	for i, value := range values {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		key, err := json.Marshal(j.columns[i])
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(key)
+		buf.WriteString(": ")
+		buf.Write(encoded)
+	}
+	buf.WriteString("}")
+	j.rowCount++
+
+	_, err := j.w.Write(buf.Bytes())
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	closing := "\n]\n"
+	if j.rowCount == 0 {
+		closing = "]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
@@ -0,0 +1,62 @@
+package reportrenderer
+
+import (
+	"d-payroll/entity"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// TableWriter streams a tabular report one row at a time, values are strings, ints or float64s
+type TableWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []any) error
+	// Close writes whatever the format needs after the last row, it does not close the underlying writer
+	Close() error
+}
+
+type reportFormat struct {
+	contentType string
+	newWriter   func(w io.Writer, title string) TableWriter
+}
+
+var formats = map[entity.ReportFormat]reportFormat{
+	entity.ReportFormatJson: {contentType: "application/json", newWriter: newJsonWriter},
+	entity.ReportFormatCsv:  {contentType: "text/csv", newWriter: newCsvWriter},
+	entity.ReportFormatXlsx: {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", newWriter: newXlsxWriter},
+}
+
+func IsFormatSupported(format entity.ReportFormat) bool {
+	_, ok := formats[format]
+	return ok
+}
+
+func ContentType(format entity.ReportFormat) string {
+	return formats[format].contentType
+}
+
+func Filename(name string, format entity.ReportFormat) string {
+	return fmt.Sprintf("%s.%s", name, format)
+}
+
+func NewTableWriter(format entity.ReportFormat, w io.Writer, title string) (TableWriter, bool) {
+	reportFormat, ok := formats[format]
+	if !ok {
+		return nil, false
+	}
+	return reportFormat.newWriter(w, title), true
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,100 @@
+package taxrenderer
+
+import (
+	"d-payroll/config"
+	"d-payroll/entity"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ebupotTaxCode is the tax object code of the regular income of permanent employees
+	ebupotTaxCode = "21-100-01"
+	// ebupotEmptyNpwp is filled in for the employees without a NPWP, they are identified by their NIK
+	ebupotEmptyNpwp = "000000000000000"
+)
+
+var ebupotColumns = []string{
+	"Masa Pajak", "Tahun Pajak", "Pembetulan", "Nomor Bukti Potong", "Masa Perolehan Awal", "Masa Perolehan Akhir",
+	"NPWP", "NIK", "Nama", "Alamat", "Jenis Kelamin", "Status PTKP", "Jumlah Tanggungan", "Nama Jabatan",
+	"WP Luar Negeri", "Kode Negara", "Kode Pajak",
+	"Jumlah 1", "Jumlah 2", "Jumlah 3", "Jumlah 4", "Jumlah 5", "Jumlah 6", "Jumlah 7", "Jumlah 8", "Jumlah 9", "Jumlah 10",
+	"Jumlah 11", "Jumlah 12", "Jumlah 13", "Jumlah 14", "Jumlah 15", "Jumlah 16", "Jumlah 17", "Jumlah 18", "Jumlah 19", "Jumlah 20",
+	"Status Pindah", "NPWP Pemotong", "Nama Pemotong", "Tanggal Bukti Potong",
+}
+
+// EbupotWriter streams the 1721-A1 certificates of a year in the DJP e-Bupot bulk upload layout,
+// a semicolon separated file with a certificate per row
+type EbupotWriter struct {
+	writer *csv.Writer
+	config *config.TaxConfig
+}
+
+func NewEbupotWriter(w io.Writer, config *config.TaxConfig) *EbupotWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	return &EbupotWriter{writer: writer, config: config}
+}
+
+func (e *EbupotWriter) WriteHeader() error {
+	return e.writer.Write(ebupotColumns)
+}
+
+func (e *EbupotWriter) WriteCertificate(certificate *entity.TaxCertificate) error {
+	npwp := certificate.Npwp
+	if npwp == "" {
+		npwp = ebupotEmptyNpwp
+	}
+
+	gender := ""
+	switch certificate.Gender {
+	case entity.GenderMale:
+		gender = "M"
+	case entity.GenderFemale:
+		gender = "F"
+	}
+
+	ptkpStatus, dependents := "", ""
+	if certificate.TaxStatus.IsValid() {
+		ptkpStatus, _, _ = strings.Cut(string(certificate.TaxStatus), "/")
+		dependents = strconv.Itoa(certificate.TaxStatus.Dependents())
+	}
+
+	record := []string{
+		strconv.Itoa(certificate.LastMonth),
+		strconv.Itoa(certificate.Year),
+		"0",
+		certificate.Number,
+		strconv.Itoa(certificate.FirstMonth),
+		strconv.Itoa(certificate.LastMonth),
+		npwp,
+		certificate.Nik,
+		certificate.Name,
+		certificate.Address,
+		gender,
+		ptkpStatus,
+		dependents,
+		certificate.Position,
+		"N",
+		"",
+		ebupotTaxCode,
+	}
+	for _, amount := range certificate.Amounts() {
+		record = append(record, strconv.Itoa(amount))
+	}
+	record = append(record,
+		"",
+		e.config.SignerNpwp,
+		e.config.SignerName,
+		certificate.Date.Format("02/01/2006"),
+	)
+
+	return e.writer.Write(record)
+}
+
+func (e *EbupotWriter) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
@@ -4,18 +4,28 @@ import (
 	"context"
 	internalerror "d-payroll/internal-error"
 	"d-payroll/repository/db/models"
-	"d-payroll/utils"
 	"errors"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AttendanceDB interface {
 	CreateAttendance(ctx context.Context, attendance *models.UserAttendance) error
-	GetThisDayAttendanceByUserID(ctx context.Context, userID uint, attenanceType models.AttendanceType) (*models.UserAttendance, error)
+	CreateAttendances(ctx context.Context, attendances []*models.UserAttendance) ([]*models.UserAttendance, error)
+	GetLatestAttendanceByUserID(ctx context.Context, userID uint, since time.Time) (*models.UserAttendance, error)
 	GetAttendancesByUserID(ctx context.Context, userID uint) ([]*models.UserAttendance, error)
 	GetAttendancesByUserIDAndDateBetween(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*models.UserAttendance, error)
+	GetRecordedAttendancesByUserIDAndDateBetween(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*models.UserAttendance, error)
+	GetAttendanceByID(ctx context.Context, attendanceID uint) (*models.UserAttendance, error)
+	GetOpenCheckins(ctx context.Context, startedAt time.Time, endedAt time.Time, maxSession time.Duration) ([]*models.UserAttendance, error)
+
+	CreateCorrection(ctx context.Context, correction *models.AttendanceCorrection) error
+	GetCorrectionByID(ctx context.Context, correctionID uint) (*models.AttendanceCorrection, error)
+	GetCorrections(ctx context.Context, userID *uint, status *models.AttendanceCorrectionStatus) ([]*models.AttendanceCorrection, error)
+	UpdateCorrection(ctx context.Context, correction *models.AttendanceCorrection) error
+	ApplyCorrection(ctx context.Context, correction *models.AttendanceCorrection, attendance *models.UserAttendance) error
 }
 
 type attendanceDB struct {
@@ -30,9 +40,33 @@ func (e *attendanceDB) CreateAttendance(ctx context.Context, attendance *models.
 	return e.DB.WithContext(ctx).Create(attendance).Error
 }
 
-func (e *attendanceDB) GetThisDayAttendanceByUserID(ctx context.Context, userID uint, attendanceType models.AttendanceType) (*models.UserAttendance, error) {
+// CreateAttendances adds the attendances in one transaction, none is added when one of them fails. An attendance
+// already recorded by its device at the same time is skipped, the attendances added are returned
+func (e *attendanceDB) CreateAttendances(ctx context.Context, attendances []*models.UserAttendance) ([]*models.UserAttendance, error) {
+	var created []*models.UserAttendance
+	err := e.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, attendance := range attendances {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(attendance)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				created = append(created, attendance)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetLatestAttendanceByUserID returns the last attendance of the user recorded since the given time, the superseded
+// attendances are skipped
+func (e *attendanceDB) GetLatestAttendanceByUserID(ctx context.Context, userID uint, since time.Time) (*models.UserAttendance, error) {
 	var attendance *models.UserAttendance
-	result := e.DB.WithContext(ctx).Where("user_id = ? AND type = ? AND created_at BETWEEN ? AND ?", userID, attendanceType, utils.GetStartOfDay(), utils.GetEndOfDay()).First(&attendance)
+	result := e.DB.WithContext(ctx).Where("user_id = ? AND created_at >= ? AND superseded_by_correction_id IS NULL", userID, since).Order("created_at DESC, id DESC").First(&attendance)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, &internalerror.NotFoundError{}
@@ -44,6 +78,7 @@ func (e *attendanceDB) GetThisDayAttendanceByUserID(ctx context.Context, userID
 	return attendance, nil
 }
 
+// GetAttendancesByUserID returns every attendance of the user, the superseded ones included for the audit
 func (e *attendanceDB) GetAttendancesByUserID(ctx context.Context, userID uint) ([]*models.UserAttendance, error) {
 	var attendances []*models.UserAttendance
 	result := e.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&attendances)
@@ -53,11 +88,136 @@ func (e *attendanceDB) GetAttendancesByUserID(ctx context.Context, userID uint)
 	return attendances, nil
 }
 
+// GetAttendancesByUserIDAndDateBetween returns the attendances of the user counted between the given times, the
+// superseded attendances are skipped
 func (e *attendanceDB) GetAttendancesByUserIDAndDateBetween(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*models.UserAttendance, error) {
 	var attendances []*models.UserAttendance
-	result := e.DB.WithContext(ctx).Where("user_id = ? AND created_at BETWEEN ? AND ?", userID, startedAt, endedAt).Find(&attendances)
+	result := e.DB.WithContext(ctx).Where("user_id = ? AND created_at BETWEEN ? AND ? AND superseded_by_correction_id IS NULL", userID, startedAt, endedAt).Order("created_at, id").Find(&attendances)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return attendances, nil
 }
+
+// GetRecordedAttendancesByUserIDAndDateBetween returns every attendance of the user recorded between the given times,
+// the superseded ones included
+func (e *attendanceDB) GetRecordedAttendancesByUserIDAndDateBetween(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*models.UserAttendance, error) {
+	var attendances []*models.UserAttendance
+	result := e.DB.WithContext(ctx).Where("user_id = ? AND created_at BETWEEN ? AND ?", userID, startedAt, endedAt).Order("created_at, id").Find(&attendances)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return attendances, nil
+}
+
+func (e *attendanceDB) GetAttendanceByID(ctx context.Context, attendanceID uint) (*models.UserAttendance, error) {
+	var attendance models.UserAttendance
+	result := e.DB.WithContext(ctx).First(&attendance, attendanceID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &attendance, nil
+}
+
+// GetOpenCheckins returns the check-ins of every user between the given times that no checkout pairs with, the next
+// check-in or checkout of the user is another check-in, a checkout past the max session length or none
+func (e *attendanceDB) GetOpenCheckins(ctx context.Context, startedAt time.Time, endedAt time.Time, maxSession time.Duration) ([]*models.UserAttendance, error) {
+	var attendances []*models.UserAttendance
+	result := e.DB.WithContext(ctx).
+		Select("user_attendances.*").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT following.type, following.created_at FROM user_attendances following
+			WHERE following.user_id = user_attendances.user_id AND following.type IN ? AND following.superseded_by_correction_id IS NULL AND following.deleted_at IS NULL
+				AND (following.created_at, following.id) > (user_attendances.created_at, user_attendances.id)
+			ORDER BY following.created_at, following.id LIMIT 1
+		) AS next_attendance ON TRUE`, []models.AttendanceType{models.AttendanceTypeCheckIn, models.AttendanceTypeCheckOut}).
+		Where("user_attendances.type = ? AND user_attendances.superseded_by_correction_id IS NULL", models.AttendanceTypeCheckIn).
+		Where("user_attendances.created_at BETWEEN ? AND ?", startedAt, endedAt).
+		Where("next_attendance.type IS NULL OR next_attendance.type = ? OR next_attendance.created_at > user_attendances.created_at + ? * INTERVAL '1 second'", models.AttendanceTypeCheckIn, int(maxSession.Seconds())).
+		Order("user_attendances.created_at, user_attendances.id").
+		Find(&attendances)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return attendances, nil
+}
+
+func (e *attendanceDB) CreateCorrection(ctx context.Context, correction *models.AttendanceCorrection) error {
+	return e.DB.WithContext(ctx).Create(correction).Error
+}
+
+func (e *attendanceDB) GetCorrectionByID(ctx context.Context, correctionID uint) (*models.AttendanceCorrection, error) {
+	var correction models.AttendanceCorrection
+	result := e.DB.WithContext(ctx).First(&correction, correctionID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &correction, nil
+}
+
+func (e *attendanceDB) GetCorrections(ctx context.Context, userID *uint, status *models.AttendanceCorrectionStatus) ([]*models.AttendanceCorrection, error) {
+	query := e.DB.WithContext(ctx)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var corrections []*models.AttendanceCorrection
+	result := query.Order("created_at, id").Find(&corrections)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return corrections, nil
+}
+
+// reviewCorrection stores the review of a correction still pending, a correction reviewed concurrently fails the review
+func reviewCorrection(tx *gorm.DB, correction *models.AttendanceCorrection) error {
+	result := tx.Model(correction).
+		Where("status = ?", models.AttendanceCorrectionStatusPending).
+		Select("AttendanceID", "Status", "ReviewedByUserID", "ReviewedAt", "ReviewNote", "UpdatedAt").
+		Updates(correction)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &internalerror.AttendanceCorrectionReviewedError{}
+	}
+	return nil
+}
+
+func (e *attendanceDB) UpdateCorrection(ctx context.Context, correction *models.AttendanceCorrection) error {
+	return reviewCorrection(e.DB.WithContext(ctx), correction)
+}
+
+// ApplyCorrection stores the approved correction, marks the attendance it supersedes and adds the corrected
+// attendance in one transaction. A correction is only applied while pending and an attendance is only superseded once
+func (e *attendanceDB) ApplyCorrection(ctx context.Context, correction *models.AttendanceCorrection, attendance *models.UserAttendance) error {
+	return e.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := reviewCorrection(tx, correction); err != nil {
+			return err
+		}
+
+		if correction.AttendanceID != nil {
+			result := tx.Model(&models.UserAttendance{}).
+				Where("id = ? AND superseded_by_correction_id IS NULL", *correction.AttendanceID).
+				Update("superseded_by_correction_id", correction.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return &internalerror.AttendanceCorrectionSupersededError{}
+			}
+		}
+
+		attendance.CorrectionID = &correction.ID
+		return tx.Create(attendance).Error
+	})
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	internalerror "d-payroll/internal-error"
+	"d-payroll/repository/db/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AttendanceDeviceDB interface {
+	CreateDevice(ctx context.Context, device *models.AttendanceDevice) error
+	GetDevices(ctx context.Context) ([]*models.AttendanceDevice, error)
+	GetDeviceByID(ctx context.Context, deviceID uint) (*models.AttendanceDevice, error)
+	GetDeviceBySerialNumber(ctx context.Context, serialNumber string) (*models.AttendanceDevice, error)
+	GetDeviceByApiKeyHash(ctx context.Context, apiKeyHash string) (*models.AttendanceDevice, error)
+	UpdateApiKeyHash(ctx context.Context, deviceID uint, apiKeyHash string) error
+	UpdateLastPushAt(ctx context.Context, deviceID uint, lastPushAt time.Time) error
+}
+
+type attendanceDeviceDB struct {
+	DB *gorm.DB
+}
+
+func NewAttendanceDeviceDB(db *gorm.DB) AttendanceDeviceDB {
+	return &attendanceDeviceDB{DB: db}
+}
+
+func (a *attendanceDeviceDB) CreateDevice(ctx context.Context, device *models.AttendanceDevice) error {
+	return a.DB.WithContext(ctx).Create(device).Error
+}
+
+func (a *attendanceDeviceDB) GetDevices(ctx context.Context) ([]*models.AttendanceDevice, error) {
+	var devices []*models.AttendanceDevice
+	result := a.DB.WithContext(ctx).Order("id").Find(&devices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return devices, nil
+}
+
+func (a *attendanceDeviceDB) GetDeviceByID(ctx context.Context, deviceID uint) (*models.AttendanceDevice, error) {
+	return a.getDevice(ctx, "id = ?", deviceID)
+}
+
+func (a *attendanceDeviceDB) GetDeviceBySerialNumber(ctx context.Context, serialNumber string) (*models.AttendanceDevice, error) {
+	return a.getDevice(ctx, "serial_number = ?", serialNumber)
+}
+
+func (a *attendanceDeviceDB) GetDeviceByApiKeyHash(ctx context.Context, apiKeyHash string) (*models.AttendanceDevice, error) {
+	return a.getDevice(ctx, "api_key_hash = ?", apiKeyHash)
+}
+
+func (a *attendanceDeviceDB) getDevice(ctx context.Context, query string, value any) (*models.AttendanceDevice, error) {
+	var device models.AttendanceDevice
+	result := a.DB.WithContext(ctx).Where(query, value).First(&device)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &device, nil
+}
+
+func (a *attendanceDeviceDB) UpdateApiKeyHash(ctx context.Context, deviceID uint, apiKeyHash string) error {
+	return a.DB.WithContext(ctx).Model(&models.AttendanceDevice{}).Where("id = ?", deviceID).Update("api_key_hash", apiKeyHash).Error
+}
+
+func (a *attendanceDeviceDB) UpdateLastPushAt(ctx context.Context, deviceID uint, lastPushAt time.Time) error {
+	return a.DB.WithContext(ctx).Model(&models.AttendanceDevice{}).Where("id = ?", deviceID).Update("last_push_at", lastPushAt).Error
+}
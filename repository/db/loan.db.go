@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	internalerror "d-payroll/internal-error"
+	"d-payroll/repository/db/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoanDB interface {
+	CreateLoan(ctx context.Context, loan *models.UserLoan) error
+	GetLoanByID(ctx context.Context, loanID uint) (*models.UserLoan, error)
+	GetLoansByUserID(ctx context.Context, userID uint) ([]*models.UserLoan, error)
+	GetOutstandingLoansByUserID(ctx context.Context, userID uint, startedBefore time.Time) ([]*models.UserLoan, error)
+
+	GetInstallmentsByLoanID(ctx context.Context, loanID uint) ([]*models.LoanInstallment, error)
+	GetInstallmentsByUserIDAndPayrollID(ctx context.Context, userID uint, payrollID uint) ([]*models.LoanInstallment, error)
+}
+
+type loanDB struct {
+	DB *gorm.DB
+}
+
+func NewLoanDB(db *gorm.DB) LoanDB {
+	return &loanDB{DB: db}
+}
+
+func (l *loanDB) CreateLoan(ctx context.Context, loan *models.UserLoan) error {
+	return l.DB.WithContext(ctx).Create(loan).Error
+}
+
+func (l *loanDB) GetLoanByID(ctx context.Context, loanID uint) (*models.UserLoan, error) {
+	var loan *models.UserLoan
+
+	result := l.DB.WithContext(ctx).Where("id = ?", loanID).First(&loan)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+
+	return loan, nil
+}
+
+func (l *loanDB) GetLoansByUserID(ctx context.Context, userID uint) ([]*models.UserLoan, error) {
+	var loans []*models.UserLoan
+	result := l.DB.WithContext(ctx).Where("user_id = ?", userID).Order("started_at, id").Find(&loans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return loans, nil
+}
+
+func (l *loanDB) GetOutstandingLoansByUserID(ctx context.Context, userID uint, startedBefore time.Time) ([]*models.UserLoan, error) {
+	var loans []*models.UserLoan
+	result := l.DB.WithContext(ctx).
+		Where("user_id = ? AND remaining_balance > 0 AND started_at <= ?", userID, startedBefore).
+		Order("started_at, id").
+		Find(&loans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return loans, nil
+}
+
+func (l *loanDB) GetInstallmentsByLoanID(ctx context.Context, loanID uint) ([]*models.LoanInstallment, error) {
+	var installments []*models.LoanInstallment
+	result := l.DB.WithContext(ctx).Where("loan_id = ?", loanID).Order("paid_at, id").Find(&installments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return installments, nil
+}
+
+func (l *loanDB) GetInstallmentsByUserIDAndPayrollID(ctx context.Context, userID uint, payrollID uint) ([]*models.LoanInstallment, error) {
+	var installments []*models.LoanInstallment
+	result := l.DB.WithContext(ctx).
+		Joins("JOIN user_loans ON user_loans.id = loan_installments.loan_id").
+		Where("user_loans.user_id = ? AND loan_installments.payroll_id = ?", userID, payrollID).
+		Find(&installments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return installments, nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"d-payroll/entity"
 	"d-payroll/utils"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,6 +13,9 @@ type AttendanceType string
 const (
 	AttendanceTypeCheckIn  AttendanceType = "CHECKIN"
 	AttendanceTypeCheckOut AttendanceType = "CHECKOUT"
+	// a break is unpaid, it starts and ends within a session
+	AttendanceTypeBreakStart AttendanceType = "BREAK_START"
+	AttendanceTypeBreakEnd   AttendanceType = "BREAK_END"
 )
 
 type UserAttendance struct {
@@ -20,10 +24,27 @@ type UserAttendance struct {
 	UserID uint
 	User   *User          `gorm:"foreignKey:UserID"`
 	Type   AttendanceType `gorm:"type:attendance"`
+
+	Latitude       *float64
+	Longitude      *float64
+	WifiBSSID      *string `gorm:"column:wifi_bssid"`
+	IPAddress      *string `gorm:"column:ip_address"`
+	WorkLocationID *uint
+	IsOutOfArea    bool
+	IsRemote       bool
+
+	CorrectionID             *uint
+	SupersededByCorrectionID *uint
+	AutoClosePolicy          *string
+	DeviceID                 *uint
 }
 
+// BeforeCreate stamps the attendance now, an attendance added by a correction or imported from a device keeps the time
+// it took place
 func (u *UserAttendance) BeforeCreate(tx *gorm.DB) (err error) {
-	u.CreatedAt = utils.TimeNow()
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = utils.TimeNow()
+	}
 	u.UpdatedAt = utils.TimeNow()
 	return
 }
@@ -34,18 +55,55 @@ func (u *UserAttendance) BeforeUpdate(tx *gorm.DB) (err error) {
 }
 
 func (a *UserAttendance) ToAttendanceEntity() *entity.UserAttendance {
-	return &entity.UserAttendance{
-		ID:        &a.ID,
-		UserID:    a.UserID,
-		Type:      entity.AttendanceType(a.Type),
-		CreatedAt: &a.CreatedAt,
-		UpdatedAt: &a.UpdatedAt,
+	attendance := &entity.UserAttendance{
+		ID:                       &a.ID,
+		UserID:                   a.UserID,
+		Type:                     entity.AttendanceType(a.Type),
+		WorkLocationID:           a.WorkLocationID,
+		IsOutOfArea:              a.IsOutOfArea,
+		IsRemote:                 a.IsRemote,
+		CorrectionID:             a.CorrectionID,
+		SupersededByCorrectionID: a.SupersededByCorrectionID,
+		DeviceID:                 a.DeviceID,
+		CreatedAt:                &a.CreatedAt,
+		UpdatedAt:                &a.UpdatedAt,
+	}
+	if a.AutoClosePolicy != nil {
+		policy := entity.AttendanceAutoClosePolicy(*a.AutoClosePolicy)
+		attendance.AutoClosePolicy = &policy
 	}
+	if a.Latitude != nil || a.Longitude != nil || a.WifiBSSID != nil || a.IPAddress != nil {
+		attendance.Evidence = &entity.AttendanceEvidence{
+			Latitude:  a.Latitude,
+			Longitude: a.Longitude,
+			WifiBSSID: a.WifiBSSID,
+			IPAddress: a.IPAddress,
+		}
+	}
+	return attendance
 }
 
 func (a *UserAttendance) FromAttendanceEntity(attendance *entity.UserAttendance) {
 	a.UserID = attendance.UserID
 	a.Type = AttendanceType(attendance.Type)
+	a.WorkLocationID = attendance.WorkLocationID
+	a.IsOutOfArea = attendance.IsOutOfArea
+	a.IsRemote = attendance.IsRemote
+	a.CorrectionID = attendance.CorrectionID
+	a.SupersededByCorrectionID = attendance.SupersededByCorrectionID
+	a.DeviceID = attendance.DeviceID
+	a.AutoClosePolicy = nil
+	if attendance.AutoClosePolicy != nil {
+		policy := string(*attendance.AutoClosePolicy)
+		a.AutoClosePolicy = &policy
+	}
+
+	if attendance.Evidence != nil {
+		a.Latitude = attendance.Evidence.Latitude
+		a.Longitude = attendance.Evidence.Longitude
+		a.WifiBSSID = attendance.Evidence.WifiBSSID
+		a.IPAddress = attendance.Evidence.IPAddress
+	}
 
 	if attendance.CreatedAt != nil {
 		a.CreatedAt = *attendance.CreatedAt
@@ -56,3 +114,76 @@ func (a *UserAttendance) FromAttendanceEntity(attendance *entity.UserAttendance)
 	}
 
 }
+
+type AttendanceCorrectionType string
+
+const (
+	AttendanceCorrectionTypeMissingCheckIn  AttendanceCorrectionType = "MISSING_CHECKIN"
+	AttendanceCorrectionTypeMissingCheckOut AttendanceCorrectionType = "MISSING_CHECKOUT"
+	AttendanceCorrectionTypeWrongTime       AttendanceCorrectionType = "WRONG_TIME"
+)
+
+type AttendanceCorrectionStatus string
+
+const (
+	AttendanceCorrectionStatusPending  AttendanceCorrectionStatus = "PENDING"
+	AttendanceCorrectionStatusApproved AttendanceCorrectionStatus = "APPROVED"
+	AttendanceCorrectionStatusRejected AttendanceCorrectionStatus = "REJECTED"
+)
+
+type AttendanceCorrection struct {
+	gorm.Model
+
+	UserID           uint
+	User             *User                    `gorm:"foreignKey:UserID"`
+	Type             AttendanceCorrectionType `gorm:"type:attendance_correction_type"`
+	AttendanceID     *uint
+	Attendance       *UserAttendance `gorm:"foreignKey:AttendanceID"`
+	CorrectedAt      time.Time
+	Reason           string
+	Status           AttendanceCorrectionStatus `gorm:"type:attendance_correction_status;default:PENDING"`
+	ReviewedByUserID *uint
+	ReviewedByUser   *User `gorm:"foreignKey:ReviewedByUserID"`
+	ReviewedAt       *time.Time
+	ReviewNote       *string
+}
+
+func (a *AttendanceCorrection) BeforeCreate(tx *gorm.DB) (err error) {
+	a.CreatedAt = utils.TimeNow()
+	a.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (a *AttendanceCorrection) BeforeUpdate(tx *gorm.DB) (err error) {
+	a.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (a *AttendanceCorrection) ToAttendanceCorrectionEntity() *entity.AttendanceCorrection {
+	return &entity.AttendanceCorrection{
+		ID:               &a.ID,
+		UserID:           a.UserID,
+		Type:             entity.AttendanceCorrectionType(a.Type),
+		AttendanceID:     a.AttendanceID,
+		CorrectedAt:      a.CorrectedAt,
+		Reason:           a.Reason,
+		Status:           entity.AttendanceCorrectionStatus(a.Status),
+		ReviewedByUserID: a.ReviewedByUserID,
+		ReviewedAt:       a.ReviewedAt,
+		ReviewNote:       a.ReviewNote,
+		CreatedAt:        &a.CreatedAt,
+		UpdatedAt:        &a.UpdatedAt,
+	}
+}
+
+func (a *AttendanceCorrection) FromAttendanceCorrectionEntity(correction *entity.AttendanceCorrection) {
+	a.UserID = correction.UserID
+	a.Type = AttendanceCorrectionType(correction.Type)
+	a.AttendanceID = correction.AttendanceID
+	a.CorrectedAt = correction.CorrectedAt
+	a.Reason = correction.Reason
+	a.Status = AttendanceCorrectionStatus(correction.Status)
+	a.ReviewedByUserID = correction.ReviewedByUserID
+	a.ReviewedAt = correction.ReviewedAt
+	a.ReviewNote = correction.ReviewNote
+}
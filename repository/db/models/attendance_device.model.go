@@ -0,0 +1,46 @@
+package models
+
+import (
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AttendanceDevice struct {
+	gorm.Model
+
+	SerialNumber string
+	Name         string
+	ApiKeyHash   string
+	LastPushAt   *time.Time
+}
+
+func (a *AttendanceDevice) BeforeCreate(tx *gorm.DB) (err error) {
+	a.CreatedAt = utils.TimeNow()
+	a.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (a *AttendanceDevice) BeforeUpdate(tx *gorm.DB) (err error) {
+	a.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (a *AttendanceDevice) ToAttendanceDeviceEntity() *entity.AttendanceDevice {
+	return &entity.AttendanceDevice{
+		ID:           &a.ID,
+		SerialNumber: a.SerialNumber,
+		Name:         a.Name,
+		LastPushAt:   a.LastPushAt,
+		CreatedAt:    &a.CreatedAt,
+		UpdatedAt:    &a.UpdatedAt,
+	}
+}
+
+func (a *AttendanceDevice) FromAttendanceDeviceEntity(device *entity.AttendanceDevice) {
+	a.SerialNumber = device.SerialNumber
+	a.Name = device.Name
+	a.LastPushAt = device.LastPushAt
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoanType string
+
+const (
+	LoanTypeLoan    LoanType = "LOAN"
+	LoanTypeAdvance LoanType = "ADVANCE"
+)
+
+type UserLoan struct {
+	gorm.Model
+
+	UserID            uint
+	User              *User    `gorm:"foreignKey:UserID"`
+	Type              LoanType `gorm:"type:loan_type"`
+	Description       string
+	Principal         int
+	InstallmentCount  int
+	InstallmentAmount int
+	RemainingBalance  int
+	StartedAt         time.Time
+	CreatedByUserID   *uint
+	CreatedByUser     *User `gorm:"foreignKey:CreatedByUserID"`
+}
+
+func (u *UserLoan) BeforeCreate(tx *gorm.DB) (err error) {
+	u.CreatedAt = utils.TimeNow()
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserLoan) BeforeUpdate(tx *gorm.DB) (err error) {
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserLoan) ToUserLoanEntity() *entity.UserLoan {
+	return &entity.UserLoan{
+		ID:                &u.ID,
+		UserID:            u.UserID,
+		Type:              entity.LoanType(u.Type),
+		Description:       u.Description,
+		Principal:         u.Principal,
+		InstallmentCount:  u.InstallmentCount,
+		InstallmentAmount: u.InstallmentAmount,
+		RemainingBalance:  u.RemainingBalance,
+		StartedAt:         u.StartedAt,
+		CreatedByUserID:   u.CreatedByUserID,
+		CreatedAt:         &u.CreatedAt,
+		UpdatedAt:         &u.UpdatedAt,
+	}
+}
+
+func (u *UserLoan) FromUserLoanEntity(loan *entity.UserLoan) {
+	u.UserID = loan.UserID
+	u.Type = LoanType(loan.Type)
+	u.Description = loan.Description
+	u.Principal = loan.Principal
+	u.InstallmentCount = loan.InstallmentCount
+	u.InstallmentAmount = loan.InstallmentAmount
+	u.RemainingBalance = loan.RemainingBalance
+	u.StartedAt = loan.StartedAt
+	u.CreatedByUserID = loan.CreatedByUserID
+
+	if loan.CreatedAt != nil {
+		u.CreatedAt = *loan.CreatedAt
+	}
+
+	if loan.UpdatedAt != nil {
+		u.UpdatedAt = *loan.UpdatedAt
+	}
+}
+
+type LoanInstallment struct {
+	gorm.Model
+
+	LoanID    uint
+	Loan      *UserLoan `gorm:"foreignKey:LoanID"`
+	PayrollID uint
+	Payroll   *Payroll `gorm:"foreignKey:PayrollID"`
+	Amount    int
+	PaidAt    time.Time
+}
+
+func (l *LoanInstallment) BeforeCreate(tx *gorm.DB) (err error) {
+	l.CreatedAt = utils.TimeNow()
+	l.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (l *LoanInstallment) BeforeUpdate(tx *gorm.DB) (err error) {
+	l.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (l *LoanInstallment) ToLoanInstallmentEntity() *entity.LoanInstallment {
+	return &entity.LoanInstallment{
+		ID:        &l.ID,
+		LoanID:    l.LoanID,
+		PayrollID: l.PayrollID,
+		Amount:    l.Amount,
+		PaidAt:    l.PaidAt,
+		CreatedAt: &l.CreatedAt,
+		UpdatedAt: &l.UpdatedAt,
+	}
+}
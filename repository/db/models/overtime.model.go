@@ -13,6 +13,7 @@ type UserOvertime struct {
 
 	UserID          uint
 	User            *User `gorm:"foreignKey:UserID"`
+	OvertimePlanID  *uint
 	Description     string
 	OvertimeAt      time.Time
 	DurationMilis   int
@@ -36,6 +37,7 @@ func (o *UserOvertime) ToOvertimeEntity() *entity.UserOvertime {
 	return &entity.UserOvertime{
 		ID:              &o.ID,
 		UserID:          o.UserID,
+		OvertimePlanID:  o.OvertimePlanID,
 		Description:     o.Description,
 		OvertimeAt:      o.OvertimeAt,
 		DurationMilis:   o.DurationMilis,
@@ -48,6 +50,7 @@ func (o *UserOvertime) ToOvertimeEntity() *entity.UserOvertime {
 
 func (o *UserOvertime) FromOvertimeEntity(overtime *entity.UserOvertime) {
 	o.UserID = overtime.UserID
+	o.OvertimePlanID = overtime.OvertimePlanID
 	o.Description = overtime.Description
 	o.OvertimeAt = overtime.OvertimeAt
 	o.DurationMilis = overtime.DurationMilis
@@ -62,3 +65,51 @@ func (o *UserOvertime) FromOvertimeEntity(overtime *entity.UserOvertime) {
 		o.UpdatedAt = *overtime.UpdatedAt
 	}
 }
+
+type OvertimePlan struct {
+	gorm.Model
+
+	UserID          uint
+	User            *User `gorm:"foreignKey:UserID"`
+	Description     string
+	PlannedAt       time.Time
+	DurationMilis   int
+	CreatedByUserID uint
+	Overtime        *UserOvertime `gorm:"foreignKey:OvertimePlanID"`
+}
+
+func (o *OvertimePlan) BeforeCreate(tx *gorm.DB) (err error) {
+	o.CreatedAt = utils.TimeNow()
+	o.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (o *OvertimePlan) BeforeUpdate(tx *gorm.DB) (err error) {
+	o.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (o *OvertimePlan) ToOvertimePlanEntity() *entity.OvertimePlan {
+	plan := &entity.OvertimePlan{
+		ID:              &o.ID,
+		UserID:          o.UserID,
+		Description:     o.Description,
+		PlannedAt:       o.PlannedAt,
+		DurationMilis:   o.DurationMilis,
+		CreatedByUserID: o.CreatedByUserID,
+		CreatedAt:       &o.CreatedAt,
+		UpdatedAt:       &o.UpdatedAt,
+	}
+	if o.Overtime != nil {
+		plan.OvertimeID = &o.Overtime.ID
+	}
+	return plan
+}
+
+func (o *OvertimePlan) FromOvertimePlanEntity(plan *entity.OvertimePlan) {
+	o.UserID = plan.UserID
+	o.Description = plan.Description
+	o.PlannedAt = plan.PlannedAt
+	o.DurationMilis = plan.DurationMilis
+	o.CreatedByUserID = plan.CreatedByUserID
+}
@@ -8,10 +8,20 @@ import (
 	"gorm.io/gorm"
 )
 
+type PayrollType string
+
+const (
+	PayrollTypeRegular    PayrollType = "REGULAR"
+	PayrollTypeBonus      PayrollType = "BONUS"
+	PayrollTypeThr        PayrollType = "THR"
+	PayrollTypeCorrection PayrollType = "CORRECTION"
+)
+
 type Payroll struct {
 	gorm.Model
 
 	Name            string
+	Type            PayrollType `gorm:"type:payroll_type;default:REGULAR"`
 	StartedAt       time.Time
 	EndedAt         time.Time
 	IsRolled        *bool `gorm:"default:false"`
@@ -36,6 +46,7 @@ func (p *Payroll) ToPayrollEntity() *entity.Payroll {
 	return &entity.Payroll{
 		ID:              &p.ID,
 		Name:            p.Name,
+		Type:            entity.PayrollType(p.Type),
 		StartedAt:       p.StartedAt,
 		EndedAt:         p.EndedAt,
 		IsRolled:        p.IsRolled,
@@ -48,6 +59,7 @@ func (p *Payroll) ToPayrollEntity() *entity.Payroll {
 
 func (p *Payroll) FromPayrollEntity(payroll *entity.Payroll) {
 	p.Name = payroll.Name
+	p.Type = PayrollType(payroll.Type)
 	p.StartedAt = payroll.StartedAt
 	p.EndedAt = payroll.EndedAt
 	p.IsRolled = payroll.IsRolled
@@ -63,6 +75,116 @@ func (p *Payroll) FromPayrollEntity(payroll *entity.Payroll) {
 	}
 }
 
+type PayrollUser struct {
+	gorm.Model
+
+	PayrollID uint
+	Payroll   *Payroll `gorm:"foreignKey:PayrollID"`
+	UserID    uint
+	User      *User `gorm:"foreignKey:UserID"`
+}
+
+func (p *PayrollUser) BeforeCreate(tx *gorm.DB) (err error) {
+	p.CreatedAt = utils.TimeNow()
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayrollUser) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+type PayrollEarning struct {
+	gorm.Model
+
+	PayrollID       uint
+	Payroll         *Payroll `gorm:"foreignKey:PayrollID"`
+	UserID          uint
+	User            *User `gorm:"foreignKey:UserID"`
+	Description     string
+	Amount          int
+	CreatedByUserID *uint
+	CreatedByUser   *User `gorm:"foreignKey:CreatedByUserID"`
+}
+
+func (p *PayrollEarning) BeforeCreate(tx *gorm.DB) (err error) {
+	p.CreatedAt = utils.TimeNow()
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayrollEarning) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayrollEarning) ToPayrollEarningEntity() *entity.PayrollEarning {
+	return &entity.PayrollEarning{
+		ID:              &p.ID,
+		PayrollID:       p.PayrollID,
+		UserID:          p.UserID,
+		Description:     p.Description,
+		Amount:          p.Amount,
+		CreatedByUserID: p.CreatedByUserID,
+		CreatedAt:       &p.CreatedAt,
+		UpdatedAt:       &p.UpdatedAt,
+	}
+}
+
+func (p *PayrollEarning) FromPayrollEarningEntity(earning *entity.PayrollEarning) {
+	p.PayrollID = earning.PayrollID
+	p.UserID = earning.UserID
+	p.Description = earning.Description
+	p.Amount = earning.Amount
+	p.CreatedByUserID = earning.CreatedByUserID
+
+	if earning.CreatedAt != nil {
+		p.CreatedAt = *earning.CreatedAt
+	}
+
+	if earning.UpdatedAt != nil {
+		p.UpdatedAt = *earning.UpdatedAt
+	}
+}
+
+type PayrollRetroAdjustment struct {
+	gorm.Model
+
+	PayrollID       uint
+	Payroll         *Payroll `gorm:"foreignKey:PayrollID"`
+	SourcePayrollID uint
+	SourcePayroll   *Payroll `gorm:"foreignKey:SourcePayrollID"`
+	UserID          uint
+	User            *User `gorm:"foreignKey:UserID"`
+	Description     string
+	Amount          int
+}
+
+func (p *PayrollRetroAdjustment) BeforeCreate(tx *gorm.DB) (err error) {
+	p.CreatedAt = utils.TimeNow()
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayrollRetroAdjustment) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayrollRetroAdjustment) ToPayrollRetroAdjustmentEntity() *entity.PayrollRetroAdjustment {
+	return &entity.PayrollRetroAdjustment{
+		ID:              &p.ID,
+		PayrollID:       p.PayrollID,
+		SourcePayrollID: p.SourcePayrollID,
+		UserID:          p.UserID,
+		Description:     p.Description,
+		Amount:          p.Amount,
+		CreatedAt:       &p.CreatedAt,
+		UpdatedAt:       &p.UpdatedAt,
+	}
+}
+
 type UserPayslipSummary struct {
 	gorm.Model
 
@@ -71,6 +193,8 @@ type UserPayslipSummary struct {
 	UserID           uint
 	User             *User `gorm:"foreignKey:UserID"`
 	TotalTakeHomePay int
+	// Payslip is the payslip frozen at roll time, encoded as JSON
+	Payslip *string `gorm:"type:jsonb"`
 }
 
 func (u *UserPayslipSummary) BeforeCreate(tx *gorm.DB) (err error) {
@@ -112,3 +236,32 @@ func (u *UserPayslipSummary) FromUserPayslipSummaryEntity(summary *entity.UserPa
 func (UserPayslipSummary) TableName() string {
 	return "user_payslip_summaries"
 }
+
+// YearPayslipRow is not a table, it joins a rolled payslip summary with the payroll it belongs to
+type YearPayslipRow struct {
+	PayrollID        uint
+	PayrollType      PayrollType
+	PayrollEndedAt   time.Time
+	UserID           uint
+	TotalTakeHomePay int
+	Payslip          *string
+}
+
+// RetroChange is not a table, it is an input of the payslips changed at ChangedAt for the user. It affects the payrolls
+// overlapping ChangedFrom to ChangedTo, a nil ChangedTo affects every payroll from ChangedFrom on
+type RetroChange struct {
+	UserID      uint
+	ChangedAt   time.Time
+	ChangedFrom time.Time
+	ChangedTo   *time.Time
+}
+
+// PayslipRegisterRow is not a table, it joins a payslip summary with the employee it belongs to
+type PayslipRegisterRow struct {
+	UserID           uint
+	Username         string
+	Department       *string
+	CostCenter       *string
+	TotalTakeHomePay int
+	Payslip          *string
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PayslipArchiveStatus string
+
+const (
+	PayslipArchiveStatusPending PayslipArchiveStatus = "PENDING"
+	PayslipArchiveStatusRunning PayslipArchiveStatus = "RUNNING"
+	PayslipArchiveStatusDone    PayslipArchiveStatus = "DONE"
+	PayslipArchiveStatusFailed  PayslipArchiveStatus = "FAILED"
+)
+
+type PayslipArchiveJob struct {
+	gorm.Model
+
+	PayrollID       uint
+	Payroll         *Payroll `gorm:"foreignKey:PayrollID"`
+	Locale          string
+	Status          PayslipArchiveStatus `gorm:"type:payslip_archive_status;default:PENDING"`
+	TotalCount      int
+	ProcessedCount  int
+	FilePath        *string
+	ErrorMessage    *string
+	CreatedByUserID *uint
+	CreatedByUser   *User `gorm:"foreignKey:CreatedByUserID"`
+	FinishedAt      *time.Time
+}
+
+func (p *PayslipArchiveJob) BeforeCreate(tx *gorm.DB) (err error) {
+	p.CreatedAt = utils.TimeNow()
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayslipArchiveJob) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayslipArchiveJob) ToPayslipArchiveJobEntity() *entity.PayslipArchiveJob {
+	return &entity.PayslipArchiveJob{
+		ID:              &p.ID,
+		PayrollID:       p.PayrollID,
+		Locale:          entity.Locale(p.Locale),
+		Status:          entity.PayslipArchiveStatus(p.Status),
+		TotalCount:      p.TotalCount,
+		ProcessedCount:  p.ProcessedCount,
+		FilePath:        p.FilePath,
+		ErrorMessage:    p.ErrorMessage,
+		CreatedByUserID: p.CreatedByUserID,
+		FinishedAt:      p.FinishedAt,
+		CreatedAt:       &p.CreatedAt,
+		UpdatedAt:       &p.UpdatedAt,
+	}
+}
+
+func (p *PayslipArchiveJob) FromPayslipArchiveJobEntity(job *entity.PayslipArchiveJob) {
+	p.PayrollID = job.PayrollID
+	p.Locale = string(job.Locale)
+	p.Status = PayslipArchiveStatus(job.Status)
+	p.TotalCount = job.TotalCount
+	p.ProcessedCount = job.ProcessedCount
+	p.FilePath = job.FilePath
+	p.ErrorMessage = job.ErrorMessage
+	p.CreatedByUserID = job.CreatedByUserID
+	p.FinishedAt = job.FinishedAt
+
+	if job.CreatedAt != nil {
+		p.CreatedAt = *job.CreatedAt
+	}
+
+	if job.UpdatedAt != nil {
+		p.UpdatedAt = *job.UpdatedAt
+	}
+}
+
+type PayslipDeliveryStatus string
+
+const (
+	PayslipDeliveryStatusPending PayslipDeliveryStatus = "PENDING"
+	PayslipDeliveryStatusSent    PayslipDeliveryStatus = "SENT"
+	PayslipDeliveryStatusFailed  PayslipDeliveryStatus = "FAILED"
+	PayslipDeliveryStatusSkipped PayslipDeliveryStatus = "SKIPPED"
+)
+
+type PayslipDelivery struct {
+	gorm.Model
+
+	PayrollID uint
+	Payroll   *Payroll `gorm:"foreignKey:PayrollID"`
+	UserID    uint
+	User      *User `gorm:"foreignKey:UserID"`
+	Email     *string
+	Status    PayslipDeliveryStatus `gorm:"type:payslip_delivery_status;default:PENDING"`
+	Attempts  int
+	LastError *string
+	SentAt    *time.Time
+}
+
+func (p *PayslipDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	p.CreatedAt = utils.TimeNow()
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayslipDelivery) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (p *PayslipDelivery) ToPayslipDeliveryEntity() *entity.PayslipDelivery {
+	return &entity.PayslipDelivery{
+		ID:        &p.ID,
+		PayrollID: p.PayrollID,
+		UserID:    p.UserID,
+		Email:     p.Email,
+		Status:    entity.PayslipDeliveryStatus(p.Status),
+		Attempts:  p.Attempts,
+		LastError: p.LastError,
+		SentAt:    p.SentAt,
+		CreatedAt: &p.CreatedAt,
+		UpdatedAt: &p.UpdatedAt,
+	}
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Shift struct {
+	gorm.Model
+
+	Name         string
+	StartTime    string
+	EndTime      string
+	BreakMinutes int
+	GraceMinutes int
+}
+
+func (s *Shift) BeforeCreate(tx *gorm.DB) (err error) {
+	s.CreatedAt = utils.TimeNow()
+	s.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (s *Shift) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (s *Shift) ToShiftEntity() *entity.Shift {
+	return &entity.Shift{
+		ID:           &s.ID,
+		Name:         s.Name,
+		StartTime:    s.StartTime,
+		EndTime:      s.EndTime,
+		BreakMinutes: s.BreakMinutes,
+		GraceMinutes: s.GraceMinutes,
+		CreatedAt:    &s.CreatedAt,
+		UpdatedAt:    &s.UpdatedAt,
+	}
+}
+
+func (s *Shift) FromShiftEntity(shift *entity.Shift) {
+	s.Name = shift.Name
+	s.StartTime = shift.StartTime
+	s.EndTime = shift.EndTime
+	s.BreakMinutes = shift.BreakMinutes
+	s.GraceMinutes = shift.GraceMinutes
+
+	if shift.CreatedAt != nil {
+		s.CreatedAt = *shift.CreatedAt
+	}
+
+	if shift.UpdatedAt != nil {
+		s.UpdatedAt = *shift.UpdatedAt
+	}
+}
+
+type ShiftRotation struct {
+	gorm.Model
+
+	Name string
+	Days []*ShiftRotationDay `gorm:"foreignKey:RotationID"`
+}
+
+func (s *ShiftRotation) BeforeCreate(tx *gorm.DB) (err error) {
+	s.CreatedAt = utils.TimeNow()
+	s.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (s *ShiftRotation) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (s *ShiftRotation) ToShiftRotationEntity() *entity.ShiftRotation {
+	days := make([]*entity.ShiftRotationDay, len(s.Days))
+	for i, day := range s.Days {
+		days[i] = day.ToShiftRotationDayEntity()
+	}
+
+	return &entity.ShiftRotation{
+		ID:        &s.ID,
+		Name:      s.Name,
+		Days:      days,
+		CreatedAt: &s.CreatedAt,
+		UpdatedAt: &s.UpdatedAt,
+	}
+}
+
+func (s *ShiftRotation) FromShiftRotationEntity(rotation *entity.ShiftRotation) {
+	s.Name = rotation.Name
+
+	s.Days = make([]*ShiftRotationDay, len(rotation.Days))
+	for i, day := range rotation.Days {
+		s.Days[i] = &ShiftRotationDay{}
+		s.Days[i].FromShiftRotationDayEntity(day)
+	}
+
+	if rotation.CreatedAt != nil {
+		s.CreatedAt = *rotation.CreatedAt
+	}
+
+	if rotation.UpdatedAt != nil {
+		s.UpdatedAt = *rotation.UpdatedAt
+	}
+}
+
+type ShiftRotationDay struct {
+	gorm.Model
+
+	RotationID uint
+	DayIndex   int
+	ShiftID    *uint
+	Shift      *Shift `gorm:"foreignKey:ShiftID"`
+}
+
+func (s *ShiftRotationDay) BeforeCreate(tx *gorm.DB) (err error) {
+	s.CreatedAt = utils.TimeNow()
+	s.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (s *ShiftRotationDay) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (s *ShiftRotationDay) ToShiftRotationDayEntity() *entity.ShiftRotationDay {
+	day := &entity.ShiftRotationDay{
+		DayIndex: s.DayIndex,
+		ShiftID:  s.ShiftID,
+	}
+
+	if s.Shift != nil {
+		day.Shift = s.Shift.ToShiftEntity()
+	}
+
+	return day
+}
+
+func (s *ShiftRotationDay) FromShiftRotationDayEntity(day *entity.ShiftRotationDay) {
+	s.DayIndex = day.DayIndex
+	s.ShiftID = day.ShiftID
+}
+
+type UserShiftRotation struct {
+	gorm.Model
+
+	UserID          uint
+	User            *User `gorm:"foreignKey:UserID"`
+	RotationID      uint
+	Rotation        *ShiftRotation `gorm:"foreignKey:RotationID"`
+	EffectiveAt     time.Time
+	CreatedByUserID *uint
+	CreatedByUser   *User `gorm:"foreignKey:CreatedByUserID"`
+}
+
+func (u *UserShiftRotation) BeforeCreate(tx *gorm.DB) (err error) {
+	u.CreatedAt = utils.TimeNow()
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserShiftRotation) BeforeUpdate(tx *gorm.DB) (err error) {
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserShiftRotation) ToUserShiftRotationEntity() *entity.UserShiftRotation {
+	assignment := &entity.UserShiftRotation{
+		ID:              &u.ID,
+		UserID:          u.UserID,
+		RotationID:      u.RotationID,
+		EffectiveAt:     u.EffectiveAt,
+		CreatedByUserID: u.CreatedByUserID,
+		CreatedAt:       &u.CreatedAt,
+		UpdatedAt:       &u.UpdatedAt,
+	}
+
+	if u.Rotation != nil {
+		assignment.Rotation = u.Rotation.ToShiftRotationEntity()
+	}
+
+	return assignment
+}
+
+func (u *UserShiftRotation) FromUserShiftRotationEntity(assignment *entity.UserShiftRotation) {
+	u.UserID = assignment.UserID
+	u.RotationID = assignment.RotationID
+	u.EffectiveAt = assignment.EffectiveAt
+	u.CreatedByUserID = assignment.CreatedByUserID
+
+	if assignment.CreatedAt != nil {
+		u.CreatedAt = *assignment.CreatedAt
+	}
+
+	if assignment.UpdatedAt != nil {
+		u.UpdatedAt = *assignment.UpdatedAt
+	}
+}
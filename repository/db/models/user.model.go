@@ -3,6 +3,7 @@ package models
 import (
 	"d-payroll/entity"
 	"d-payroll/utils"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,6 +13,7 @@ type UserRole string
 const (
 	UserRoleAdmin    UserRole = "ADMIN"
 	UserRoleEmployee UserRole = "EMPLOYEE"
+	UserRoleFinance  UserRole = "FINANCE"
 )
 
 type User struct {
@@ -39,16 +41,108 @@ func (u *User) BeforeUpdate(tx *gorm.DB) (err error) {
 type UserInfo struct {
 	ID uint `gorm:"primarykey"`
 
-	UserId        uint
-	MonthlySalary *int
+	UserId         uint
+	MonthlySalary  *int
+	Religion       *string
+	ThrHoliday     *string
+	JoinedAt       *time.Time `gorm:"type:date"`
+	BirthDate      *time.Time `gorm:"type:date"`
+	Email          *string
+	Department     *string
+	CostCenter     *string
+	FullName       *string
+	Nik            *string
+	Npwp           *string
+	Address        *string
+	Gender         *string
+	TaxStatus      *string
+	Position       *string
+	Timezone       *string
+	WorkLocationID *uint
+	RemoteWork     bool
+	DevicePin      *string
+
+	AttendancePenaltyExempt bool
+}
+
+func (u *UserInfo) ToUserInfoEntity() *entity.UserInfo {
+	userInfo := &entity.UserInfo{
+		MonthlySalary:  u.MonthlySalary,
+		JoinedAt:       u.JoinedAt,
+		BirthDate:      u.BirthDate,
+		Email:          u.Email,
+		Department:     u.Department,
+		CostCenter:     u.CostCenter,
+		FullName:       u.FullName,
+		Nik:            u.Nik,
+		Npwp:           u.Npwp,
+		Address:        u.Address,
+		Position:       u.Position,
+		Timezone:       u.Timezone,
+		WorkLocationID: u.WorkLocationID,
+		RemoteWork:     u.RemoteWork,
+		DevicePin:      u.DevicePin,
+
+		AttendancePenaltyExempt: u.AttendancePenaltyExempt,
+	}
+	if u.Religion != nil {
+		religion := entity.Religion(*u.Religion)
+		userInfo.Religion = &religion
+	}
+	if u.ThrHoliday != nil {
+		thrHoliday := entity.ThrHoliday(*u.ThrHoliday)
+		userInfo.ThrHoliday = &thrHoliday
+	}
+	if u.Gender != nil {
+		gender := entity.Gender(*u.Gender)
+		userInfo.Gender = &gender
+	}
+	if u.TaxStatus != nil {
+		taxStatus := entity.TaxStatus(*u.TaxStatus)
+		userInfo.TaxStatus = &taxStatus
+	}
+	return userInfo
+}
+
+func (u *UserInfo) FromUserInfoEntity(userInfo *entity.UserInfo) {
+	u.MonthlySalary = userInfo.MonthlySalary
+	u.JoinedAt = userInfo.JoinedAt
+	u.BirthDate = userInfo.BirthDate
+	u.Email = userInfo.Email
+	u.Department = userInfo.Department
+	u.CostCenter = userInfo.CostCenter
+	u.FullName = userInfo.FullName
+	u.Nik = userInfo.Nik
+	u.Npwp = userInfo.Npwp
+	u.Address = userInfo.Address
+	u.Position = userInfo.Position
+	u.Timezone = userInfo.Timezone
+	u.WorkLocationID = userInfo.WorkLocationID
+	u.RemoteWork = userInfo.RemoteWork
+	u.DevicePin = userInfo.DevicePin
+	u.AttendancePenaltyExempt = userInfo.AttendancePenaltyExempt
+	if userInfo.Religion != nil {
+		religion := string(*userInfo.Religion)
+		u.Religion = &religion
+	}
+	if userInfo.ThrHoliday != nil {
+		thrHoliday := string(*userInfo.ThrHoliday)
+		u.ThrHoliday = &thrHoliday
+	}
+	if userInfo.Gender != nil {
+		gender := string(*userInfo.Gender)
+		u.Gender = &gender
+	}
+	if userInfo.TaxStatus != nil {
+		taxStatus := string(*userInfo.TaxStatus)
+		u.TaxStatus = &taxStatus
+	}
 }
 
 func (u *User) ToUserEntity() *entity.User {
 	var userInfo *entity.UserInfo
 	if u.UserInfo != nil {
-		userInfo = &entity.UserInfo{
-			MonthlySalary: u.UserInfo.MonthlySalary,
-		}
+		userInfo = u.UserInfo.ToUserInfoEntity()
 	}
 	return &entity.User{
 		Id:        &u.ID,
@@ -67,9 +161,8 @@ func (u *User) FromUserEntity(user *entity.User) {
 	u.Role = UserRole(user.Role)
 
 	if user.UserInfo != nil {
-		u.UserInfo = &UserInfo{
-			MonthlySalary: user.UserInfo.MonthlySalary,
-		}
+		u.UserInfo = &UserInfo{}
+		u.UserInfo.FromUserInfoEntity(user.UserInfo)
 	}
 
 	if user.CreatedAt != nil {
@@ -80,3 +173,185 @@ func (u *User) FromUserEntity(user *entity.User) {
 		u.UpdatedAt = *user.UpdatedAt
 	}
 }
+
+type UserSalary struct {
+	gorm.Model
+
+	UserID          uint
+	User            *User `gorm:"foreignKey:UserID"`
+	MonthlySalary   int
+	EffectiveAt     time.Time
+	CreatedByUserID *uint
+	CreatedByUser   *User `gorm:"foreignKey:CreatedByUserID"`
+}
+
+func (u *UserSalary) BeforeCreate(tx *gorm.DB) (err error) {
+	u.CreatedAt = utils.TimeNow()
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserSalary) BeforeUpdate(tx *gorm.DB) (err error) {
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserSalary) ToUserSalaryEntity() *entity.UserSalary {
+	return &entity.UserSalary{
+		ID:              &u.ID,
+		UserID:          u.UserID,
+		MonthlySalary:   u.MonthlySalary,
+		EffectiveAt:     u.EffectiveAt,
+		CreatedByUserID: u.CreatedByUserID,
+		CreatedAt:       &u.CreatedAt,
+		UpdatedAt:       &u.UpdatedAt,
+	}
+}
+
+func (u *UserSalary) FromUserSalaryEntity(salary *entity.UserSalary) {
+	u.UserID = salary.UserID
+	u.MonthlySalary = salary.MonthlySalary
+	u.EffectiveAt = salary.EffectiveAt
+	u.CreatedByUserID = salary.CreatedByUserID
+
+	if salary.CreatedAt != nil {
+		u.CreatedAt = *salary.CreatedAt
+	}
+
+	if salary.UpdatedAt != nil {
+		u.UpdatedAt = *salary.UpdatedAt
+	}
+}
+
+type PaymentMethod string
+
+const (
+	PaymentMethodTransfer PaymentMethod = "TRANSFER"
+	PaymentMethodCash     PaymentMethod = "CASH"
+	PaymentMethodEwallet  PaymentMethod = "EWALLET"
+)
+
+type UserBankAccount struct {
+	gorm.Model
+
+	UserID          uint
+	User            *User         `gorm:"foreignKey:UserID"`
+	PaymentMethod   PaymentMethod `gorm:"type:payment_method;default:TRANSFER"`
+	BankCode        string
+	AccountNumber   string
+	AccountName     string
+	UpdatedByUserID *uint
+	UpdatedByUser   *User `gorm:"foreignKey:UpdatedByUserID"`
+}
+
+func (u *UserBankAccount) BeforeCreate(tx *gorm.DB) (err error) {
+	u.CreatedAt = utils.TimeNow()
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserBankAccount) BeforeUpdate(tx *gorm.DB) (err error) {
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserBankAccount) ToUserBankAccountEntity() *entity.UserBankAccount {
+	return &entity.UserBankAccount{
+		ID:              &u.ID,
+		UserID:          u.UserID,
+		PaymentMethod:   entity.PaymentMethod(u.PaymentMethod),
+		BankCode:        entity.BankCode(u.BankCode),
+		AccountNumber:   u.AccountNumber,
+		AccountName:     u.AccountName,
+		UpdatedByUserID: u.UpdatedByUserID,
+		CreatedAt:       &u.CreatedAt,
+		UpdatedAt:       &u.UpdatedAt,
+	}
+}
+
+func (u *UserBankAccount) FromUserBankAccountEntity(account *entity.UserBankAccount) {
+	u.UserID = account.UserID
+	u.PaymentMethod = PaymentMethod(account.PaymentMethod)
+	u.BankCode = string(account.BankCode)
+	u.AccountNumber = account.AccountNumber
+	u.AccountName = account.AccountName
+	u.UpdatedByUserID = account.UpdatedByUserID
+}
+
+type BankAccountChangeStatus string
+
+const (
+	BankAccountChangeStatusPending  BankAccountChangeStatus = "PENDING"
+	BankAccountChangeStatusApproved BankAccountChangeStatus = "APPROVED"
+	BankAccountChangeStatusRejected BankAccountChangeStatus = "REJECTED"
+)
+
+type UserBankAccountChange struct {
+	gorm.Model
+
+	UserID            uint
+	User              *User         `gorm:"foreignKey:UserID"`
+	PaymentMethod     PaymentMethod `gorm:"type:payment_method"`
+	BankCode          string
+	AccountNumber     string
+	AccountName       string
+	Status            BankAccountChangeStatus `gorm:"type:bank_account_change_status;default:PENDING"`
+	RequestedByUserID uint
+	RequestedByUser   *User `gorm:"foreignKey:RequestedByUserID"`
+	ReviewedByUserID  *uint
+	ReviewedByUser    *User `gorm:"foreignKey:ReviewedByUserID"`
+	ReviewedAt        *time.Time
+	ReviewNote        *string
+}
+
+func (u *UserBankAccountChange) BeforeCreate(tx *gorm.DB) (err error) {
+	u.CreatedAt = utils.TimeNow()
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserBankAccountChange) BeforeUpdate(tx *gorm.DB) (err error) {
+	u.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (u *UserBankAccountChange) ToUserBankAccountChangeEntity() *entity.UserBankAccountChange {
+	return &entity.UserBankAccountChange{
+		ID:                &u.ID,
+		UserID:            u.UserID,
+		PaymentMethod:     entity.PaymentMethod(u.PaymentMethod),
+		BankCode:          entity.BankCode(u.BankCode),
+		AccountNumber:     u.AccountNumber,
+		AccountName:       u.AccountName,
+		Status:            entity.BankAccountChangeStatus(u.Status),
+		RequestedByUserID: u.RequestedByUserID,
+		ReviewedByUserID:  u.ReviewedByUserID,
+		ReviewedAt:        u.ReviewedAt,
+		ReviewNote:        u.ReviewNote,
+		CreatedAt:         &u.CreatedAt,
+		UpdatedAt:         &u.UpdatedAt,
+	}
+}
+
+func (u *UserBankAccountChange) FromUserBankAccountChangeEntity(change *entity.UserBankAccountChange) {
+	u.UserID = change.UserID
+	u.PaymentMethod = PaymentMethod(change.PaymentMethod)
+	u.BankCode = string(change.BankCode)
+	u.AccountNumber = change.AccountNumber
+	u.AccountName = change.AccountName
+	u.Status = BankAccountChangeStatus(change.Status)
+	u.RequestedByUserID = change.RequestedByUserID
+	u.ReviewedByUserID = change.ReviewedByUserID
+	u.ReviewedAt = change.ReviewedAt
+	u.ReviewNote = change.ReviewNote
+}
+
+// ApplyTo overwrites the account with the approved change
+func (u *UserBankAccountChange) ApplyTo(account *UserBankAccount) {
+	account.UserID = u.UserID
+	account.PaymentMethod = u.PaymentMethod
+	account.BankCode = u.BankCode
+	account.AccountNumber = u.AccountNumber
+	account.AccountName = u.AccountName
+	account.UpdatedByUserID = u.ReviewedByUserID
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"d-payroll/entity"
+	"d-payroll/utils"
+
+	"gorm.io/gorm"
+)
+
+type WorkLocationNetworkType string
+
+const (
+	WorkLocationNetworkTypeBSSID WorkLocationNetworkType = "BSSID"
+	WorkLocationNetworkTypeCIDR  WorkLocationNetworkType = "CIDR"
+)
+
+type WorkLocation struct {
+	gorm.Model
+
+	Name         string
+	Latitude     *float64
+	Longitude    *float64
+	RadiusMeters *float64
+	Policy       string
+	Points       []*WorkLocationPoint   `gorm:"foreignKey:WorkLocationID"`
+	Networks     []*WorkLocationNetwork `gorm:"foreignKey:WorkLocationID"`
+}
+
+func (w *WorkLocation) BeforeCreate(tx *gorm.DB) (err error) {
+	w.CreatedAt = utils.TimeNow()
+	w.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (w *WorkLocation) BeforeUpdate(tx *gorm.DB) (err error) {
+	w.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (w *WorkLocation) ToWorkLocationEntity() *entity.WorkLocation {
+	polygon := make([]*entity.GeoPoint, len(w.Points))
+	for i, point := range w.Points {
+		polygon[i] = &entity.GeoPoint{Latitude: point.Latitude, Longitude: point.Longitude}
+	}
+
+	workLocation := &entity.WorkLocation{
+		ID:           &w.ID,
+		Name:         w.Name,
+		Latitude:     w.Latitude,
+		Longitude:    w.Longitude,
+		RadiusMeters: w.RadiusMeters,
+		Polygon:      polygon,
+		WifiBSSIDs:   []string{},
+		IPRanges:     []string{},
+		Policy:       entity.WorkLocationPolicy(w.Policy),
+		CreatedAt:    &w.CreatedAt,
+		UpdatedAt:    &w.UpdatedAt,
+	}
+
+	for _, network := range w.Networks {
+		switch network.Type {
+		case WorkLocationNetworkTypeBSSID:
+			workLocation.WifiBSSIDs = append(workLocation.WifiBSSIDs, network.Value)
+		case WorkLocationNetworkTypeCIDR:
+			workLocation.IPRanges = append(workLocation.IPRanges, network.Value)
+		}
+	}
+
+	return workLocation
+}
+
+func (w *WorkLocation) FromWorkLocationEntity(workLocation *entity.WorkLocation) {
+	w.Name = workLocation.Name
+	w.Latitude = workLocation.Latitude
+	w.Longitude = workLocation.Longitude
+	w.RadiusMeters = workLocation.RadiusMeters
+	w.Policy = string(workLocation.Policy)
+
+	w.Points = make([]*WorkLocationPoint, len(workLocation.Polygon))
+	for i, point := range workLocation.Polygon {
+		w.Points[i] = &WorkLocationPoint{
+			PointIndex: i,
+			Latitude:   point.Latitude,
+			Longitude:  point.Longitude,
+		}
+	}
+
+	w.Networks = make([]*WorkLocationNetwork, 0, len(workLocation.WifiBSSIDs)+len(workLocation.IPRanges))
+	for _, bssid := range workLocation.WifiBSSIDs {
+		w.Networks = append(w.Networks, &WorkLocationNetwork{Type: WorkLocationNetworkTypeBSSID, Value: bssid})
+	}
+	for _, ipRange := range workLocation.IPRanges {
+		w.Networks = append(w.Networks, &WorkLocationNetwork{Type: WorkLocationNetworkTypeCIDR, Value: ipRange})
+	}
+
+	if workLocation.CreatedAt != nil {
+		w.CreatedAt = *workLocation.CreatedAt
+	}
+
+	if workLocation.UpdatedAt != nil {
+		w.UpdatedAt = *workLocation.UpdatedAt
+	}
+}
+
+// WorkLocationPoint is a vertex of the polygon of a work location, in order
+type WorkLocationPoint struct {
+	gorm.Model
+
+	WorkLocationID uint
+	PointIndex     int
+	Latitude       float64
+	Longitude      float64
+}
+
+func (w *WorkLocationPoint) BeforeCreate(tx *gorm.DB) (err error) {
+	w.CreatedAt = utils.TimeNow()
+	w.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (w *WorkLocationPoint) BeforeUpdate(tx *gorm.DB) (err error) {
+	w.UpdatedAt = utils.TimeNow()
+	return
+}
+
+// WorkLocationNetwork is a Wi-Fi access point or an IP range of a work location
+type WorkLocationNetwork struct {
+	gorm.Model
+
+	WorkLocationID uint
+	Type           WorkLocationNetworkType
+	Value          string
+}
+
+func (w *WorkLocationNetwork) BeforeCreate(tx *gorm.DB) (err error) {
+	w.CreatedAt = utils.TimeNow()
+	w.UpdatedAt = utils.TimeNow()
+	return
+}
+
+func (w *WorkLocationNetwork) BeforeUpdate(tx *gorm.DB) (err error) {
+	w.UpdatedAt = utils.TimeNow()
+	return
+}
@@ -16,8 +16,12 @@ type OvertimeDB interface {
 	ApproveOvertime(ctx context.Context, overtimeID uint, approvedByUserID uint) error
 	GetOvertimesByUserID(ctx context.Context, userID uint) ([]*models.UserOvertime, error)
 	GetOvertimeByID(ctx context.Context, overtimeID uint) (*models.UserOvertime, error)
-	GetThisDayOvertimeByUserID(ctx context.Context, userID uint) ([]*models.UserOvertime, error)
+	GetThisDayOvertimeByUserID(ctx context.Context, userID uint, loc *time.Location) ([]*models.UserOvertime, error)
 	GetOvertimesByUserIDAndDateBetween(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*models.UserOvertime, error)
+
+	CreateOvertimePlan(ctx context.Context, plan *models.OvertimePlan) error
+	GetOvertimePlanByID(ctx context.Context, planID uint) (*models.OvertimePlan, error)
+	GetOvertimePlansByUserID(ctx context.Context, userID uint) ([]*models.OvertimePlan, error)
 }
 
 type overtimeDB struct {
@@ -65,13 +69,15 @@ func (o *overtimeDB) GetOvertimeByID(ctx context.Context, overtimeID uint) (*mod
 	return overtime, nil
 }
 
-func (o *overtimeDB) GetThisDayOvertimeByUserID(ctx context.Context, userID uint) ([]*models.UserOvertime, error) {
+// GetThisDayOvertimeByUserID returns the overtimes the user submitted on the current day of the given zone
+func (o *overtimeDB) GetThisDayOvertimeByUserID(ctx context.Context, userID uint, loc *time.Location) ([]*models.UserOvertime, error) {
+	now := utils.TimeNow().In(loc)
 	var overtimes []*models.UserOvertime
 	result := o.DB.WithContext(ctx).Where(
 		"user_id = ? AND created_at BETWEEN ? AND ?",
 		userID,
-		utils.GetStartOfDay(),
-		utils.GetEndOfDay(),
+		utils.StartOfDay(now),
+		utils.EndOfDay(now),
 	).Find(&overtimes)
 	if result.Error != nil {
 		return nil, result.Error
@@ -92,3 +98,31 @@ func (o *overtimeDB) GetOvertimesByUserIDAndDateBetween(ctx context.Context, use
 	}
 	return overtimes, nil
 }
+
+func (o *overtimeDB) CreateOvertimePlan(ctx context.Context, plan *models.OvertimePlan) error {
+	return o.DB.WithContext(ctx).Create(plan).Error
+}
+
+// GetOvertimePlanByID returns the plan with the overtime submitted against it
+func (o *overtimeDB) GetOvertimePlanByID(ctx context.Context, planID uint) (*models.OvertimePlan, error) {
+	var plan *models.OvertimePlan
+
+	result := o.DB.WithContext(ctx).Preload("Overtime").Where("id = ?", planID).First(&plan)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+
+	return plan, nil
+}
+
+func (o *overtimeDB) GetOvertimePlansByUserID(ctx context.Context, userID uint) ([]*models.OvertimePlan, error) {
+	var plans []*models.OvertimePlan
+	result := o.DB.WithContext(ctx).Preload("Overtime").Where("user_id = ?", userID).Order("planned_at").Find(&plans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return plans, nil
+}
@@ -2,24 +2,47 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	internalerror "d-payroll/internal-error"
 	"d-payroll/repository/db/models"
 	"d-payroll/utils"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PayrollDB interface {
 	CreatePayroll(ctx context.Context, payroll *models.Payroll) error
+	CreateOffCyclePayroll(ctx context.Context, payroll *models.Payroll, earnings []*models.PayrollEarning) error
 	GetPayrollByID(ctx context.Context, payrollID uint) (*models.Payroll, error)
 	GetPayrolls(ctx context.Context) ([]*models.Payroll, error)
-	RollPayroll(ctx context.Context, payrollID uint, userID uint) error
+	RollPayroll(ctx context.Context, payrollID uint, userID uint, adjustments []*models.PayrollRetroAdjustment, summaries []*models.UserPayslipSummary, installments []*models.LoanInstallment) error
 
-	CreatePayslipSummary(ctx context.Context, summary *models.UserPayslipSummary) error
+	CreatePayrollUsers(ctx context.Context, payrollUsers []*models.PayrollUser) error
+	GetPayrollUserIds(ctx context.Context, payrollID uint) ([]uint, error)
+	IsPayrollUser(ctx context.Context, payrollID uint, userID uint) (bool, error)
+
+	CreatePayrollEarning(ctx context.Context, earning *models.PayrollEarning) error
+	GetPayrollEarnings(ctx context.Context, payrollID uint) ([]*models.PayrollEarning, error)
+	GetPayrollEarningsByUserID(ctx context.Context, payrollID uint, userID uint) ([]*models.PayrollEarning, error)
+
+	GetRolledPayrollsEndedBefore(ctx context.Context, payrollType models.PayrollType, endedAt time.Time) ([]*models.Payroll, error)
+	GetRolledPayrolls(ctx context.Context, payrollType models.PayrollType) ([]*models.Payroll, error)
+	GetRetroChangesSince(ctx context.Context, since time.Time) ([]*models.RetroChange, error)
+
+	CreateRetroAdjustment(ctx context.Context, adjustment *models.PayrollRetroAdjustment) error
+	GetRetroAdjustments(ctx context.Context, payrollID uint) ([]*models.PayrollRetroAdjustment, error)
+	GetRetroAdjustmentsByUserID(ctx context.Context, payrollID uint, userID uint) ([]*models.PayrollRetroAdjustment, error)
+	GetRetroAdjustedAmount(ctx context.Context, sourcePayrollID uint, userID uint) (int, error)
+
+	GetPayslipSummaryByUserID(ctx context.Context, payrollID uint, userID uint) (*models.UserPayslipSummary, error)
 	GetPayslipSummaries(ctx context.Context, payrollID uint) ([]*models.UserPayslipSummary, error)
+	StreamPayslipRegisterRows(ctx context.Context, payrollID uint, fn func(row *models.PayslipRegisterRow) error) error
 	GetTotalPayslipTakeHomePay(ctx context.Context, payrollID uint) (int, error)
+	StreamYearPayslipRows(ctx context.Context, year int, userID *uint, fn func(row *models.YearPayslipRow) error) error
 }
 
 type payrollDB struct {
@@ -34,6 +57,35 @@ func (p *payrollDB) CreatePayroll(ctx context.Context, payroll *models.Payroll)
 	return p.DB.WithContext(ctx).Create(payroll).Error
 }
 
+// CreateOffCyclePayroll creates the payroll with its earnings and selects their users in one transaction,
+// so a failure never leaves a half built payroll
+func (p *payrollDB) CreateOffCyclePayroll(ctx context.Context, payroll *models.Payroll, earnings []*models.PayrollEarning) error {
+	return p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payroll).Error; err != nil {
+			return err
+		}
+		if len(earnings) == 0 {
+			return nil
+		}
+
+		payrollUsers := []*models.PayrollUser{}
+		selected := map[uint]bool{}
+		for _, earning := range earnings {
+			earning.PayrollID = payroll.ID
+			if !selected[earning.UserID] {
+				selected[earning.UserID] = true
+				payrollUsers = append(payrollUsers, &models.PayrollUser{PayrollID: payroll.ID, UserID: earning.UserID})
+			}
+		}
+
+		if err := tx.Create(payrollUsers).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(earnings).Error
+	})
+}
+
 func (p *payrollDB) GetPayrollByID(ctx context.Context, payrollID uint) (*models.Payroll, error) {
 	var payroll *models.Payroll
 
@@ -57,18 +109,214 @@ func (p *payrollDB) GetPayrolls(ctx context.Context) ([]*models.Payroll, error)
 	return payrolls, nil
 }
 
-func (p *payrollDB) RollPayroll(ctx context.Context, payrollID uint, userID uint) error {
-	return p.DB.WithContext(ctx).Model(&models.Payroll{}).
-		Where("id = ?", payrollID).
-		Updates(map[string]interface{}{
-			"is_rolled":          true,
-			"updated_by_user_id": userID,
-			"updated_at":         utils.TimeNow(),
-		}).Error
+// RollPayroll marks the payroll rolled, stores the retro adjustments it carries and the payslip summaries and pays the loan
+// installments in one transaction, so a failure leaves the payroll open with nothing paid. A payroll rolled concurrently
+// fails with already rolled
+func (p *payrollDB) RollPayroll(ctx context.Context, payrollID uint, userID uint, adjustments []*models.PayrollRetroAdjustment, summaries []*models.UserPayslipSummary, installments []*models.LoanInstallment) error {
+	return p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Payroll{}).
+			Where("id = ? AND is_rolled IS NOT TRUE", payrollID).
+			Updates(map[string]interface{}{
+				"is_rolled":          true,
+				"updated_by_user_id": userID,
+				"updated_at":         utils.TimeNow(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &internalerror.PayrollAlreadyRolledError{}
+		}
+
+		for _, adjustment := range adjustments {
+			if err := tx.Create(adjustment).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, summary := range summaries {
+			if err := tx.Create(summary).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, installment := range installments {
+			if err := tx.Create(installment).Error; err != nil {
+				return err
+			}
+
+			err := tx.Model(&models.UserLoan{}).
+				Where("id = ?", installment.LoanID).
+				Update("remaining_balance", gorm.Expr("remaining_balance - ?", installment.Amount)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (p *payrollDB) CreatePayrollUsers(ctx context.Context, payrollUsers []*models.PayrollUser) error {
+	return p.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(payrollUsers).Error
+}
+
+func (p *payrollDB) GetPayrollUserIds(ctx context.Context, payrollID uint) ([]uint, error) {
+	var userIds []uint
+	result := p.DB.WithContext(ctx).Model(&models.PayrollUser{}).
+		Where("payroll_id = ?", payrollID).
+		Order("user_id").
+		Pluck("user_id", &userIds)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return userIds, nil
+}
+
+func (p *payrollDB) IsPayrollUser(ctx context.Context, payrollID uint, userID uint) (bool, error) {
+	var count int64
+	err := p.DB.WithContext(ctx).Model(&models.PayrollUser{}).
+		Where("payroll_id = ? AND user_id = ?", payrollID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (p *payrollDB) CreatePayrollEarning(ctx context.Context, earning *models.PayrollEarning) error {
+	return p.DB.WithContext(ctx).Create(earning).Error
+}
+
+func (p *payrollDB) GetPayrollEarnings(ctx context.Context, payrollID uint) ([]*models.PayrollEarning, error) {
+	var earnings []*models.PayrollEarning
+	if err := p.DB.WithContext(ctx).
+		Where("payroll_id = ?", payrollID).
+		Find(&earnings).Error; err != nil {
+		return nil, err
+	}
+
+	return earnings, nil
+}
+
+func (p *payrollDB) GetPayrollEarningsByUserID(ctx context.Context, payrollID uint, userID uint) ([]*models.PayrollEarning, error) {
+	var earnings []*models.PayrollEarning
+	if err := p.DB.WithContext(ctx).
+		Where("payroll_id = ? AND user_id = ?", payrollID, userID).
+		Find(&earnings).Error; err != nil {
+		return nil, err
+	}
+
+	return earnings, nil
+}
+
+func (p *payrollDB) GetRolledPayrollsEndedBefore(ctx context.Context, payrollType models.PayrollType, endedAt time.Time) ([]*models.Payroll, error) {
+	var payrolls []*models.Payroll
+	if err := p.DB.WithContext(ctx).
+		Where("type = ? AND is_rolled = ? AND ended_at < ?", payrollType, true, endedAt).
+		Order("ended_at, id").
+		Find(&payrolls).Error; err != nil {
+		return nil, err
+	}
+
+	return payrolls, nil
 }
 
-func (p *payrollDB) CreatePayslipSummary(ctx context.Context, summary *models.UserPayslipSummary) error {
-	return p.DB.WithContext(ctx).Create(summary).Error
+func (p *payrollDB) GetRolledPayrolls(ctx context.Context, payrollType models.PayrollType) ([]*models.Payroll, error) {
+	var payrolls []*models.Payroll
+	if err := p.DB.WithContext(ctx).
+		Where("type = ? AND is_rolled = ?", payrollType, true).
+		Order("ended_at, id").
+		Find(&payrolls).Error; err != nil {
+		return nil, err
+	}
+
+	return payrolls, nil
+}
+
+// GetRetroChangesSince returns the payslip inputs created, updated or deleted since the given time. An attendance, overtime
+// or reimbursement changes the payroll of its date, a salary or shift rotation every payroll from the date it is effective
+func (p *payrollDB) GetRetroChangesSince(ctx context.Context, since time.Time) ([]*models.RetroChange, error) {
+	var changes []*models.RetroChange
+	err := p.DB.WithContext(ctx).Raw(`
+		SELECT user_id, GREATEST(updated_at, deleted_at) AS changed_at, created_at AS changed_from, created_at AS changed_to
+		FROM user_attendances WHERE updated_at >= @since OR deleted_at >= @since
+		UNION ALL
+		SELECT user_id, GREATEST(updated_at, deleted_at), created_at, created_at
+		FROM user_overtimes WHERE updated_at >= @since OR deleted_at >= @since
+		UNION ALL
+		SELECT user_id, GREATEST(updated_at, deleted_at), created_at, created_at
+		FROM user_reimbursements WHERE updated_at >= @since OR deleted_at >= @since
+		UNION ALL
+		SELECT user_id, GREATEST(updated_at, deleted_at), effective_at, NULL
+		FROM user_salaries WHERE updated_at >= @since OR deleted_at >= @since
+		UNION ALL
+		SELECT user_id, GREATEST(updated_at, deleted_at), effective_at, NULL
+		FROM user_shift_rotations WHERE updated_at >= @since OR deleted_at >= @since`,
+		sql.Named("since", since)).
+		Scan(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (p *payrollDB) CreateRetroAdjustment(ctx context.Context, adjustment *models.PayrollRetroAdjustment) error {
+	return p.DB.WithContext(ctx).Create(adjustment).Error
+}
+
+func (p *payrollDB) GetRetroAdjustments(ctx context.Context, payrollID uint) ([]*models.PayrollRetroAdjustment, error) {
+	var adjustments []*models.PayrollRetroAdjustment
+	if err := p.DB.WithContext(ctx).
+		Where("payroll_id = ?", payrollID).
+		Order("id").
+		Find(&adjustments).Error; err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+func (p *payrollDB) GetRetroAdjustmentsByUserID(ctx context.Context, payrollID uint, userID uint) ([]*models.PayrollRetroAdjustment, error) {
+	var adjustments []*models.PayrollRetroAdjustment
+	if err := p.DB.WithContext(ctx).
+		Where("payroll_id = ? AND user_id = ?", payrollID, userID).
+		Order("id").
+		Find(&adjustments).Error; err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+// GetRetroAdjustedAmount sums the adjustments already carried for a rolled payroll, pending ones included
+func (p *payrollDB) GetRetroAdjustedAmount(ctx context.Context, sourcePayrollID uint, userID uint) (int, error) {
+	var total int
+	err := p.DB.WithContext(ctx).
+		Model(&models.PayrollRetroAdjustment{}).
+		Where("source_payroll_id = ? AND user_id = ?", sourcePayrollID, userID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (p *payrollDB) GetPayslipSummaryByUserID(ctx context.Context, payrollID uint, userID uint) (*models.UserPayslipSummary, error) {
+	var summary models.UserPayslipSummary
+	result := p.DB.WithContext(ctx).Where("payroll_id = ? AND user_id = ?", payrollID, userID).First(&summary)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+
+	return &summary, nil
 }
 
 func (p *payrollDB) GetPayslipSummaries(ctx context.Context, payrollID uint) ([]*models.UserPayslipSummary, error) {
@@ -82,6 +330,36 @@ func (p *payrollDB) GetPayslipSummaries(ctx context.Context, payrollID uint) ([]
 	return summaries, nil
 }
 
+// StreamPayslipRegisterRows walks the payslip summaries of a payroll with their employees one row at a time,
+// so a large payroll is never loaded in memory at once
+func (p *payrollDB) StreamPayslipRegisterRows(ctx context.Context, payrollID uint, fn func(row *models.PayslipRegisterRow) error) error {
+	rows, err := p.DB.WithContext(ctx).
+		Model(&models.UserPayslipSummary{}).
+		Joins("JOIN users ON users.id = user_payslip_summaries.user_id").
+		Joins("LEFT JOIN user_infos ON user_infos.user_id = users.id").
+		Where("user_payslip_summaries.payroll_id = ?", payrollID).
+		Select("user_payslip_summaries.user_id, users.username, user_infos.department, user_infos.cost_center, user_payslip_summaries.total_take_home_pay, user_payslip_summaries.payslip").
+		Order("user_payslip_summaries.user_id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.PayslipRegisterRow
+		if err := p.DB.ScanRows(rows, &row); err != nil {
+			return err
+		}
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (p *payrollDB) GetTotalPayslipTakeHomePay(ctx context.Context, payrollID uint) (int, error) {
 	var total int
 	err := p.DB.WithContext(ctx).
@@ -94,3 +372,38 @@ func (p *payrollDB) GetTotalPayslipTakeHomePay(ctx context.Context, payrollID ui
 	}
 	return total, nil
 }
+
+// StreamYearPayslipRows walks the payslips of every rolled payroll ending in the year regardless of its type,
+// so off-cycle runs count toward the same totals as the regular ones. The rows of an employee are consecutive
+// and in payroll order, userID narrows the rows down to a single employee
+func (p *payrollDB) StreamYearPayslipRows(ctx context.Context, year int, userID *uint, fn func(row *models.YearPayslipRow) error) error {
+	query := p.DB.WithContext(ctx).
+		Model(&models.UserPayslipSummary{}).
+		Joins("JOIN payrolls ON payrolls.id = user_payslip_summaries.payroll_id AND payrolls.deleted_at IS NULL").
+		Where("payrolls.is_rolled = ? AND EXTRACT(YEAR FROM payrolls.ended_at) = ?", true, year)
+	if userID != nil {
+		query = query.Where("user_payslip_summaries.user_id = ?", *userID)
+	}
+
+	rows, err := query.
+		Select("user_payslip_summaries.payroll_id, payrolls.type AS payroll_type, payrolls.ended_at AS payroll_ended_at, user_payslip_summaries.user_id, user_payslip_summaries.total_take_home_pay, user_payslip_summaries.payslip").
+		Order("user_payslip_summaries.user_id, payrolls.ended_at, payrolls.id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.YearPayslipRow
+		if err := p.DB.ScanRows(rows, &row); err != nil {
+			return err
+		}
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	internalerror "d-payroll/internal-error"
+	"d-payroll/repository/db/models"
+	"d-payroll/utils"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PayslipDB interface {
+	CreateArchiveJob(ctx context.Context, job *models.PayslipArchiveJob) error
+	GetArchiveJobByID(ctx context.Context, jobID uint) (*models.PayslipArchiveJob, error)
+	UpdateArchiveJob(ctx context.Context, job *models.PayslipArchiveJob) error
+	FailUnfinishedArchiveJobs(ctx context.Context, message string) (int64, error)
+
+	CreateDelivery(ctx context.Context, delivery *models.PayslipDelivery) error
+	GetDelivery(ctx context.Context, payrollID uint, userID uint) (*models.PayslipDelivery, error)
+	GetDeliveries(ctx context.Context, payrollID uint) ([]*models.PayslipDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.PayslipDelivery) error
+}
+
+type payslipDB struct {
+	DB *gorm.DB
+}
+
+func NewPayslipDB(db *gorm.DB) PayslipDB {
+	return &payslipDB{DB: db}
+}
+
+func (p *payslipDB) CreateArchiveJob(ctx context.Context, job *models.PayslipArchiveJob) error {
+	return p.DB.WithContext(ctx).Create(job).Error
+}
+
+func (p *payslipDB) GetArchiveJobByID(ctx context.Context, jobID uint) (*models.PayslipArchiveJob, error) {
+	var job models.PayslipArchiveJob
+	result := p.DB.WithContext(ctx).First(&job, jobID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+
+	return &job, nil
+}
+
+func (p *payslipDB) UpdateArchiveJob(ctx context.Context, job *models.PayslipArchiveJob) error {
+	return p.DB.WithContext(ctx).Save(job).Error
+}
+
+// FailUnfinishedArchiveJobs marks every pending or running archive job as failed
+func (p *payslipDB) FailUnfinishedArchiveJobs(ctx context.Context, message string) (int64, error) {
+	result := p.DB.WithContext(ctx).
+		Model(&models.PayslipArchiveJob{}).
+		Where("status IN ?", []models.PayslipArchiveStatus{models.PayslipArchiveStatusPending, models.PayslipArchiveStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":        models.PayslipArchiveStatusFailed,
+			"error_message": message,
+			"finished_at":   utils.TimeNow(),
+			"updated_at":    utils.TimeNow(),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (p *payslipDB) CreateDelivery(ctx context.Context, delivery *models.PayslipDelivery) error {
+	return p.DB.WithContext(ctx).Create(delivery).Error
+}
+
+func (p *payslipDB) GetDelivery(ctx context.Context, payrollID uint, userID uint) (*models.PayslipDelivery, error) {
+	var delivery models.PayslipDelivery
+	result := p.DB.WithContext(ctx).Where("payroll_id = ? AND user_id = ?", payrollID, userID).First(&delivery)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+
+	return &delivery, nil
+}
+
+func (p *payslipDB) GetDeliveries(ctx context.Context, payrollID uint) ([]*models.PayslipDelivery, error) {
+	var deliveries []*models.PayslipDelivery
+	result := p.DB.WithContext(ctx).Where("payroll_id = ?", payrollID).Order("user_id").Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return deliveries, nil
+}
+
+func (p *payslipDB) UpdateDelivery(ctx context.Context, delivery *models.PayslipDelivery) error {
+	return p.DB.WithContext(ctx).Save(delivery).Error
+}
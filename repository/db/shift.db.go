@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	internalerror "d-payroll/internal-error"
+	"d-payroll/repository/db/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ShiftDB interface {
+	CreateShift(ctx context.Context, shift *models.Shift) error
+	GetShifts(ctx context.Context) ([]*models.Shift, error)
+	GetShiftByID(ctx context.Context, shiftID uint) (*models.Shift, error)
+
+	CreateShiftRotation(ctx context.Context, rotation *models.ShiftRotation) error
+	GetShiftRotations(ctx context.Context) ([]*models.ShiftRotation, error)
+	GetShiftRotationByID(ctx context.Context, rotationID uint) (*models.ShiftRotation, error)
+
+	CreateUserShiftRotation(ctx context.Context, assignment *models.UserShiftRotation) error
+	GetUserShiftRotations(ctx context.Context, userID uint) ([]*models.UserShiftRotation, error)
+	GetUserShiftRotationAt(ctx context.Context, userID uint, at time.Time) (*models.UserShiftRotation, error)
+}
+
+type shiftDB struct {
+	DB *gorm.DB
+}
+
+func NewShiftDB(db *gorm.DB) ShiftDB {
+	return &shiftDB{DB: db}
+}
+
+// preloadRotationDays loads the days of the rotations in order with their shifts
+func preloadRotationDays(db *gorm.DB, prefix string) *gorm.DB {
+	return db.
+		Preload(prefix+"Days", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("day_index")
+		}).
+		Preload(prefix + "Days.Shift")
+}
+
+func (s *shiftDB) CreateShift(ctx context.Context, shift *models.Shift) error {
+	return s.DB.WithContext(ctx).Create(shift).Error
+}
+
+func (s *shiftDB) GetShifts(ctx context.Context) ([]*models.Shift, error) {
+	var shifts []*models.Shift
+	result := s.DB.WithContext(ctx).Order("id").Find(&shifts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return shifts, nil
+}
+
+func (s *shiftDB) GetShiftByID(ctx context.Context, shiftID uint) (*models.Shift, error) {
+	var shift models.Shift
+	result := s.DB.WithContext(ctx).Where("id = ?", shiftID).First(&shift)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &shift, nil
+}
+
+// CreateShiftRotation creates the rotation together with its days
+func (s *shiftDB) CreateShiftRotation(ctx context.Context, rotation *models.ShiftRotation) error {
+	return s.DB.WithContext(ctx).Create(rotation).Error
+}
+
+func (s *shiftDB) GetShiftRotations(ctx context.Context) ([]*models.ShiftRotation, error) {
+	var rotations []*models.ShiftRotation
+	result := preloadRotationDays(s.DB.WithContext(ctx), "").Order("id").Find(&rotations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rotations, nil
+}
+
+func (s *shiftDB) GetShiftRotationByID(ctx context.Context, rotationID uint) (*models.ShiftRotation, error) {
+	var rotation models.ShiftRotation
+	result := preloadRotationDays(s.DB.WithContext(ctx), "").Where("id = ?", rotationID).First(&rotation)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &rotation, nil
+}
+
+func (s *shiftDB) CreateUserShiftRotation(ctx context.Context, assignment *models.UserShiftRotation) error {
+	return s.DB.WithContext(ctx).Omit("Rotation").Create(assignment).Error
+}
+
+func (s *shiftDB) GetUserShiftRotations(ctx context.Context, userID uint) ([]*models.UserShiftRotation, error) {
+	var assignments []*models.UserShiftRotation
+	result := preloadRotationDays(s.DB.WithContext(ctx).Preload("Rotation"), "Rotation.").
+		Where("user_id = ?", userID).
+		Order("effective_at, id").
+		Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+// GetUserShiftRotationAt returns the latest rotation assigned to the user effective at the given time
+func (s *shiftDB) GetUserShiftRotationAt(ctx context.Context, userID uint, at time.Time) (*models.UserShiftRotation, error) {
+	var assignment models.UserShiftRotation
+	result := preloadRotationDays(s.DB.WithContext(ctx).Preload("Rotation"), "Rotation.").
+		Where("user_id = ? AND effective_at <= ?", userID, at).
+		Order("effective_at DESC, id DESC").
+		First(&assignment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &assignment, nil
+}
@@ -5,6 +5,7 @@ import (
 	internalerror "d-payroll/internal-error"
 	"d-payroll/repository/db/models"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,6 +17,26 @@ type UserDB interface {
 	GetuserById(ctx context.Context, id uint) (*models.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
 	GetUserIds(ctx context.Context) ([]uint, error)
+	GetUsersByRole(ctx context.Context, role models.UserRole) ([]*models.User, error)
+	UpdateMonthlySalary(ctx context.Context, userID uint, monthlySalary int) error
+	UpdateWorkLocation(ctx context.Context, userID uint, workLocationID *uint, remoteWork bool) error
+	UpdateDevicePin(ctx context.Context, userID uint, devicePin *string) error
+	UpdateAttendancePenaltyExempt(ctx context.Context, userID uint, exempt bool) error
+	GetUsersByDevicePins(ctx context.Context, devicePins []string) ([]*models.User, error)
+
+	CreateUserSalary(ctx context.Context, salary *models.UserSalary) error
+	GetUserSalaries(ctx context.Context, userID uint) ([]*models.UserSalary, error)
+	GetUserSalaryAt(ctx context.Context, userID uint, at time.Time) (*models.UserSalary, error)
+
+	GetUserBankAccount(ctx context.Context, userID uint) (*models.UserBankAccount, error)
+	GetUserBankAccounts(ctx context.Context, userIDs []uint) ([]*models.UserBankAccount, error)
+
+	CreateBankAccountChange(ctx context.Context, change *models.UserBankAccountChange) error
+	GetBankAccountChangeByID(ctx context.Context, changeID uint) (*models.UserBankAccountChange, error)
+	GetBankAccountChanges(ctx context.Context, userID *uint, status *models.BankAccountChangeStatus) ([]*models.UserBankAccountChange, error)
+	HasPendingBankAccountChange(ctx context.Context, userID uint) (bool, error)
+	UpdateBankAccountChange(ctx context.Context, change *models.UserBankAccountChange) error
+	ApplyBankAccountChange(ctx context.Context, change *models.UserBankAccountChange, account *models.UserBankAccount) error
 }
 
 type userDB struct {
@@ -76,3 +97,153 @@ func (e *userDB) GetUserIds(ctx context.Context) ([]uint, error) {
 	}
 	return userIds, nil
 }
+
+func (e *userDB) GetUsersByRole(ctx context.Context, role models.UserRole) ([]*models.User, error) {
+	var users []*models.User
+	result := e.DB.WithContext(ctx).Preload("UserInfo").Where("role = ?", role).Order("id").Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+func (e *userDB) UpdateMonthlySalary(ctx context.Context, userID uint, monthlySalary int) error {
+	return e.DB.WithContext(ctx).Model(&models.UserInfo{}).Where("user_id = ?", userID).Update("monthly_salary", monthlySalary).Error
+}
+
+func (e *userDB) UpdateWorkLocation(ctx context.Context, userID uint, workLocationID *uint, remoteWork bool) error {
+	return e.DB.WithContext(ctx).Model(&models.UserInfo{}).Where("user_id = ?", userID).Updates(map[string]any{
+		"work_location_id": workLocationID,
+		"remote_work":      remoteWork,
+	}).Error
+}
+
+func (e *userDB) UpdateDevicePin(ctx context.Context, userID uint, devicePin *string) error {
+	return e.DB.WithContext(ctx).Model(&models.UserInfo{}).Where("user_id = ?", userID).Update("device_pin", devicePin).Error
+}
+
+func (e *userDB) UpdateAttendancePenaltyExempt(ctx context.Context, userID uint, exempt bool) error {
+	return e.DB.WithContext(ctx).Model(&models.UserInfo{}).Where("user_id = ?", userID).Update("attendance_penalty_exempt", exempt).Error
+}
+
+func (e *userDB) GetUsersByDevicePins(ctx context.Context, devicePins []string) ([]*models.User, error) {
+	var users []*models.User
+	result := e.DB.WithContext(ctx).Preload("UserInfo").
+		Joins("JOIN user_infos ON user_infos.user_id = users.id").
+		Where("user_infos.device_pin IN ?", devicePins).
+		Order("users.id").
+		Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+func (e *userDB) CreateUserSalary(ctx context.Context, salary *models.UserSalary) error {
+	return e.DB.WithContext(ctx).Create(salary).Error
+}
+
+func (e *userDB) GetUserSalaries(ctx context.Context, userID uint) ([]*models.UserSalary, error) {
+	var salaries []*models.UserSalary
+	result := e.DB.WithContext(ctx).Where("user_id = ?", userID).Order("effective_at, id").Find(&salaries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return salaries, nil
+}
+
+// GetUserSalaryAt returns the latest salary history entry effective at the given time
+func (e *userDB) GetUserSalaryAt(ctx context.Context, userID uint, at time.Time) (*models.UserSalary, error) {
+	var salary models.UserSalary
+	result := e.DB.WithContext(ctx).
+		Where("user_id = ? AND effective_at <= ?", userID, at).
+		Order("effective_at DESC, id DESC").
+		First(&salary)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &salary, nil
+}
+
+func (e *userDB) GetUserBankAccount(ctx context.Context, userID uint) (*models.UserBankAccount, error) {
+	var account models.UserBankAccount
+	result := e.DB.WithContext(ctx).Where("user_id = ?", userID).First(&account)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+func (e *userDB) GetUserBankAccounts(ctx context.Context, userIDs []uint) ([]*models.UserBankAccount, error) {
+	var accounts []*models.UserBankAccount
+	result := e.DB.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&accounts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return accounts, nil
+}
+
+func (e *userDB) CreateBankAccountChange(ctx context.Context, change *models.UserBankAccountChange) error {
+	return e.DB.WithContext(ctx).Create(change).Error
+}
+
+func (e *userDB) GetBankAccountChangeByID(ctx context.Context, changeID uint) (*models.UserBankAccountChange, error) {
+	var change models.UserBankAccountChange
+	result := e.DB.WithContext(ctx).First(&change, changeID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &change, nil
+}
+
+func (e *userDB) GetBankAccountChanges(ctx context.Context, userID *uint, status *models.BankAccountChangeStatus) ([]*models.UserBankAccountChange, error) {
+	query := e.DB.WithContext(ctx)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var changes []*models.UserBankAccountChange
+	result := query.Order("created_at, id").Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
+
+func (e *userDB) HasPendingBankAccountChange(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	result := e.DB.WithContext(ctx).Model(&models.UserBankAccountChange{}).
+		Where("user_id = ? AND status = ?", userID, models.BankAccountChangeStatusPending).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+func (e *userDB) UpdateBankAccountChange(ctx context.Context, change *models.UserBankAccountChange) error {
+	return e.DB.WithContext(ctx).Save(change).Error
+}
+
+// ApplyBankAccountChange stores the reviewed change and the account it results in in one transaction
+func (e *userDB) ApplyBankAccountChange(ctx context.Context, change *models.UserBankAccountChange, account *models.UserBankAccount) error {
+	return e.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(change).Error; err != nil {
+			return err
+		}
+
+		return tx.Save(account).Error
+	})
+}
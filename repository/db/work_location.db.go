@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	internalerror "d-payroll/internal-error"
+	"d-payroll/repository/db/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type WorkLocationDB interface {
+	CreateWorkLocation(ctx context.Context, workLocation *models.WorkLocation) error
+	GetWorkLocations(ctx context.Context) ([]*models.WorkLocation, error)
+	GetWorkLocationByID(ctx context.Context, workLocationID uint) (*models.WorkLocation, error)
+}
+
+type workLocationDB struct {
+	DB *gorm.DB
+}
+
+func NewWorkLocationDB(db *gorm.DB) WorkLocationDB {
+	return &workLocationDB{DB: db}
+}
+
+// preloadWorkLocationAreas loads the polygon in order and the networks of the work locations
+func preloadWorkLocationAreas(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Points", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("point_index")
+		}).
+		Preload("Networks", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("id")
+		})
+}
+
+// CreateWorkLocation creates the work location together with its polygon and networks
+func (w *workLocationDB) CreateWorkLocation(ctx context.Context, workLocation *models.WorkLocation) error {
+	return w.DB.WithContext(ctx).Create(workLocation).Error
+}
+
+func (w *workLocationDB) GetWorkLocations(ctx context.Context) ([]*models.WorkLocation, error) {
+	var workLocations []*models.WorkLocation
+	result := preloadWorkLocationAreas(w.DB.WithContext(ctx)).Order("id").Find(&workLocations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return workLocations, nil
+}
+
+func (w *workLocationDB) GetWorkLocationByID(ctx context.Context, workLocationID uint) (*models.WorkLocation, error) {
+	var workLocation models.WorkLocation
+	result := preloadWorkLocationAreas(w.DB.WithContext(ctx)).Where("id = ?", workLocationID).First(&workLocation)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, &internalerror.NotFoundError{}
+		}
+		return nil, result.Error
+	}
+	return &workLocation, nil
+}
@@ -2,57 +2,115 @@ package attendanceservice
 
 import (
 	"context"
+	"d-payroll/config"
 	"d-payroll/entity"
 	internalerror "d-payroll/internal-error"
+	attendanceparser "d-payroll/parser/attendance"
 	repository "d-payroll/repository/db"
 	"d-payroll/repository/db/models"
+	shiftservice "d-payroll/service/shift"
+	userservice "d-payroll/service/user"
+	worklocationservice "d-payroll/service/worklocation"
 	"d-payroll/utils"
 	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
 	"time"
 )
 
+// doublePunchWindow is how long after a punch the same finger is taken as punched twice by mistake
+const doublePunchWindow = time.Minute
+
 // TODO:
 // - Possible race condition, checkin and checkout at the same time
 // - Fix using transaction or mutex lock
 
 type AttendanceService interface {
-	Checkin(ctx context.Context, userID uint) (*entity.UserAttendance, error)
+	Checkin(ctx context.Context, userID uint, evidence *entity.AttendanceEvidence) (*entity.UserAttendance, error)
 	Checkout(ctx context.Context, userID uint) (*entity.UserAttendance, error)
+	StartBreak(ctx context.Context, userID uint) (*entity.UserAttendance, error)
+	EndBreak(ctx context.Context, userID uint) (*entity.UserAttendance, error)
 	IsCheckedOut(ctx context.Context, userID uint) (bool, error)
+	GetLastCheckoutAt(ctx context.Context, userID uint) (*time.Time, error)
+	AutoCloseMissingCheckouts(ctx context.Context) ([]*entity.UserAttendance, error)
+	ImportDeviceLog(ctx context.Context, log io.Reader, dryRun bool) (*entity.AttendanceImport, error)
+	ImportDevicePunches(ctx context.Context, punches []*entity.AttendancePunch, anomalies []*entity.AttendanceImportAnomaly, dryRun bool) (*entity.AttendanceImport, error)
 
 	GetAttendancesByUserID(ctx context.Context, userID uint) ([]*entity.UserAttendance, error)
 	GetAttendancesByUserIDAndDateBetween(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*entity.UserAttendance, error)
 	GetAttendancesByUserIDAndDateBetweenGroupByDate(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*entity.UserAttendanceGroupedByDate, error)
+
+	GetUserLocation(ctx context.Context, userID uint) (*time.Location, error)
+	GetWorkSchedule(ctx context.Context, userID uint, day time.Time) (*entity.WorkSchedule, error)
+	GetAttendanceSummary(ctx context.Context, userID uint, startDate time.Time, endDate time.Time) (*entity.AttendanceSummary, error)
+
+	RequestCorrection(ctx context.Context, correction *entity.AttendanceCorrection) (*entity.AttendanceCorrection, error)
+	ReviewCorrection(ctx context.Context, correctionID uint, reviewerID uint, approved bool, note *string) (*entity.AttendanceCorrection, error)
+	GetCorrections(ctx context.Context, userID *uint, status *entity.AttendanceCorrectionStatus) ([]*entity.AttendanceCorrection, error)
 }
 
 type attendanceService struct {
-	attendanceDB repository.AttendanceDB
+	config          *config.Config
+	attendanceDB    repository.AttendanceDB
+	shiftSvc        shiftservice.ShiftService
+	workLocationSvc worklocationservice.WorkLocationService
+	userSvc         userservice.UserService
 }
 
-func NewAttendanceService(attendanceDB repository.AttendanceDB) AttendanceService {
-	return &attendanceService{attendanceDB: attendanceDB}
+func NewAttendanceService(config *config.Config, attendanceDB repository.AttendanceDB, shiftSvc shiftservice.ShiftService, workLocationSvc worklocationservice.WorkLocationService, userSvc userservice.UserService) AttendanceService {
+	return &attendanceService{config: config, attendanceDB: attendanceDB, shiftSvc: shiftSvc, workLocationSvc: workLocationSvc, userSvc: userSvc}
 }
 
-func (s *attendanceService) Checkin(ctx context.Context, userID uint) (*entity.UserAttendance, error) {
-	if utils.IsWeekend() {
-		return nil, &internalerror.AttendanceWeekendError{}
+// Checkin starts a session, the day and the weekend are judged in the zone of the user. A day can have several
+// sessions, as long as the previous one is checked out. The evidence is verified against the work location of the
+// user, a check-in out of its area is rejected or flagged depending on the policy of the location
+func (s *attendanceService) Checkin(ctx context.Context, userID uint, evidence *entity.AttendanceEvidence) (*entity.UserAttendance, error) {
+	loc, err := s.GetUserLocation(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	attendanceModel := &models.UserAttendance{
-		UserID: userID,
-		Type:   models.AttendanceTypeCheckIn,
+	now := utils.TimeNow().In(loc)
+	schedule, err := s.GetWorkSchedule(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		if utils.IsWeekendAt(now) {
+			return nil, &internalerror.AttendanceWeekendError{}
+		}
+		return nil, &internalerror.AttendanceDayOffError{}
 	}
 
-	thisDayCheckin, err := s.attendanceDB.GetThisDayAttendanceByUserID(ctx, userID, models.AttendanceTypeCheckIn)
+	// a session started yesterday evening is still open after midnight
+	latest, err := s.getLatestSessionAttendance(ctx, userID)
 	if err != nil {
-		if !errors.Is(err, &internalerror.NotFoundError{}) {
-			return nil, err
-		}
+		return nil, err
 	}
-	if thisDayCheckin != nil {
+	if latest != nil && latest.Type != models.AttendanceTypeCheckOut {
 		return nil, &internalerror.AttendanceAlreadyCheckedInError{}
 	}
 
+	check, err := s.workLocationSvc.CheckAttendanceEvidence(ctx, userID, evidence)
+	if err != nil {
+		return nil, err
+	}
+	if check.IsOutOfArea && check.Policy == entity.WorkLocationPolicyReject {
+		return nil, &internalerror.AttendanceOutOfAreaError{}
+	}
+
+	attendanceModel := &models.UserAttendance{}
+	attendanceModel.FromAttendanceEntity(&entity.UserAttendance{
+		UserID:         userID,
+		Type:           entity.AttendanceTypeCheckIn,
+		Evidence:       evidence,
+		WorkLocationID: check.WorkLocationID,
+		IsOutOfArea:    check.IsOutOfArea,
+		IsRemote:       check.IsRemote,
+	})
+
 	err = s.attendanceDB.CreateAttendance(ctx, attendanceModel)
 	if err != nil {
 		return nil, err
@@ -61,29 +119,72 @@ func (s *attendanceService) Checkin(ctx context.Context, userID uint) (*entity.U
 	return attendanceModel.ToAttendanceEntity(), nil
 }
 
+// Checkout closes the session of the latest check-in, as long as it is within the max session length. A break still
+// running ends with the session
 func (s *attendanceService) Checkout(ctx context.Context, userID uint) (*entity.UserAttendance, error) {
-	_, err := s.attendanceDB.GetThisDayAttendanceByUserID(ctx, userID, models.AttendanceTypeCheckIn)
+	latest, err := s.getLatestSessionAttendance(ctx, userID)
 	if err != nil {
-		if errors.Is(err, &internalerror.NotFoundError{}) {
-			return nil, &internalerror.AttendanceCannotCheckedOutError{}
-		}
+		return nil, err
+	}
+	if latest == nil {
+		return nil, &internalerror.AttendanceCannotCheckedOutError{}
+	}
+	if latest.Type == models.AttendanceTypeCheckOut {
+		return nil, &internalerror.AttendanceAlreadyCheckedOutError{}
+	}
+
+	attendanceModel := &models.UserAttendance{
+		UserID: userID,
+		Type:   models.AttendanceTypeCheckOut,
+	}
 
+	err = s.attendanceDB.CreateAttendance(ctx, attendanceModel)
+	if err != nil {
 		return nil, err
 	}
 
-	thisDayCheckout, err := s.attendanceDB.GetThisDayAttendanceByUserID(ctx, userID, models.AttendanceTypeCheckOut)
+	return attendanceModel.ToAttendanceEntity(), nil
+}
+
+// StartBreak starts an unpaid break within the open session
+func (s *attendanceService) StartBreak(ctx context.Context, userID uint) (*entity.UserAttendance, error) {
+	latest, err := s.getLatestSessionAttendance(ctx, userID)
 	if err != nil {
-		if !errors.Is(err, &internalerror.NotFoundError{}) {
-			return nil, err
-		}
+		return nil, err
 	}
-	if thisDayCheckout != nil {
-		return nil, &internalerror.AttendanceAlreadyCheckedOutError{}
+	if latest == nil || latest.Type == models.AttendanceTypeCheckOut {
+		return nil, &internalerror.AttendanceNotCheckedInError{}
+	}
+	if latest.Type == models.AttendanceTypeBreakStart {
+		return nil, &internalerror.AttendanceAlreadyOnBreakError{}
 	}
 
 	attendanceModel := &models.UserAttendance{
 		UserID: userID,
-		Type:   models.AttendanceTypeCheckOut,
+		Type:   models.AttendanceTypeBreakStart,
+	}
+
+	err = s.attendanceDB.CreateAttendance(ctx, attendanceModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return attendanceModel.ToAttendanceEntity(), nil
+}
+
+// EndBreak ends the running break, the session goes on
+func (s *attendanceService) EndBreak(ctx context.Context, userID uint) (*entity.UserAttendance, error) {
+	latest, err := s.getLatestSessionAttendance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil || latest.Type != models.AttendanceTypeBreakStart {
+		return nil, &internalerror.AttendanceNotOnBreakError{}
+	}
+
+	attendanceModel := &models.UserAttendance{
+		UserID: userID,
+		Type:   models.AttendanceTypeBreakEnd,
 	}
 
 	err = s.attendanceDB.CreateAttendance(ctx, attendanceModel)
@@ -95,13 +196,294 @@ func (s *attendanceService) Checkout(ctx context.Context, userID uint) (*entity.
 }
 
 func (s *attendanceService) IsCheckedOut(ctx context.Context, userID uint) (bool, error) {
-	thisDayCheckout, err := s.attendanceDB.GetThisDayAttendanceByUserID(ctx, userID, models.AttendanceTypeCheckOut)
+	latest, err := s.getLatestSessionAttendance(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return latest != nil && latest.Type == models.AttendanceTypeCheckOut, nil
+}
+
+// GetLastCheckoutAt returns the time of the checkout closing the last session, nil when the last session is still open
+// or is older than the max session length
+func (s *attendanceService) GetLastCheckoutAt(ctx context.Context, userID uint) (*time.Time, error) {
+	latest, err := s.getLatestSessionAttendance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil || latest.Type != models.AttendanceTypeCheckOut {
+		return nil, nil
+	}
+	return &latest.CreatedAt, nil
+}
+
+// AutoCloseMissingCheckouts closes the sessions still open the cutoff after the end of their shift with a checkout at
+// that end, the checkout records the configured policy the session is paid by. A session started on a day without a
+// shift is closed at its check-in once the max session length is over. The checkouts added are returned
+func (s *attendanceService) AutoCloseMissingCheckouts(ctx context.Context) ([]*entity.UserAttendance, error) {
+	policy := entity.AttendanceAutoClosePolicy(s.config.Attendance.AutoClosePolicy)
+	if !policy.IsValid() {
+		return nil, fmt.Errorf("invalid attendance auto close policy %q", s.config.Attendance.AutoClosePolicy)
+	}
+
+	now := utils.TimeNow()
+	cutoff := time.Duration(s.config.Attendance.AutoCloseCutoffMinutes) * time.Minute
+	lookback := time.Duration(s.config.Attendance.AutoCloseLookbackDays) * 24 * time.Hour
+
+	checkins, err := s.attendanceDB.GetOpenCheckins(ctx, now.Add(-lookback), now.Add(-cutoff), s.maxSessionDuration())
+	if err != nil {
+		return nil, err
+	}
+
+	var closed []*entity.UserAttendance
+	for _, checkin := range checkins {
+		loc, err := s.GetUserLocation(ctx, checkin.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		checkinAt := checkin.CreatedAt.In(loc)
+		schedule, err := s.GetWorkSchedule(ctx, checkin.UserID, utils.StartOfDay(checkinAt))
+		if err != nil {
+			return nil, err
+		}
+
+		closedAt := checkinAt
+		closeBy := checkinAt.Add(s.maxSessionDuration())
+		if schedule != nil {
+			// a check-in after the end of its shift, e.g. a second session, is closed right away
+			closedAt = schedule.EndAt(checkinAt)
+			if closedAt.Before(checkinAt) {
+				closedAt = checkinAt
+			}
+			closeBy = closedAt.Add(cutoff)
+		}
+		if now.Before(closeBy) {
+			continue
+		}
+
+		attendanceModel := &models.UserAttendance{}
+		attendanceModel.FromAttendanceEntity(&entity.UserAttendance{
+			UserID:          checkin.UserID,
+			Type:            entity.AttendanceTypeCheckOut,
+			AutoClosePolicy: &policy,
+			CreatedAt:       &closedAt,
+		})
+
+		if err := s.attendanceDB.CreateAttendance(ctx, attendanceModel); err != nil {
+			return nil, err
+		}
+		closed = append(closed, attendanceModel.ToAttendanceEntity())
+	}
+
+	return closed, nil
+}
+
+// ImportDeviceLog adds the punches of a biometric device log as the attendances of the users enrolled with their PIN
+func (s *attendanceService) ImportDeviceLog(ctx context.Context, log io.Reader, dryRun bool) (*entity.AttendanceImport, error) {
+	punches, anomalies, err := attendanceparser.ParseZKTecoLog(log)
 	if err != nil {
-		if !errors.Is(err, &internalerror.NotFoundError{}) {
-			return false, err
+		return nil, err
+	}
+
+	return s.ImportDevicePunches(ctx, punches, anomalies, dryRun)
+}
+
+// ImportDevicePunches adds the punches as the attendances of the users enrolled with their PIN, the wall clock times of
+// the device are read in the zone of the user. A punch already recorded is skipped, so the same punches can be
+// imported again. A punch without a state opens a session, or closes the open one. The punches of unknown PINs and the
+// anomalies are reported and left out, the others are added in one transaction unless it is a dry run. The anomalies
+// found reading the punches are reported along
+func (s *attendanceService) ImportDevicePunches(ctx context.Context, punches []*entity.AttendancePunch, anomalies []*entity.AttendanceImportAnomaly, dryRun bool) (*entity.AttendanceImport, error) {
+	report := &entity.AttendanceImport{
+		DryRun:    dryRun,
+		Punches:   len(punches),
+		Anomalies: anomalies,
+	}
+
+	var pins []string
+	punchesByPin := make(map[string][]*entity.AttendancePunch)
+	for _, punch := range punches {
+		if _, ok := punchesByPin[punch.Pin]; !ok {
+			pins = append(pins, punch.Pin)
 		}
+		punchesByPin[punch.Pin] = append(punchesByPin[punch.Pin], punch)
 	}
-	return thisDayCheckout != nil, nil
+
+	userIDByPin := make(map[string]uint, len(pins))
+	if len(pins) > 0 {
+		users, err := s.userSvc.GetUsersByDevicePins(ctx, pins)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			userIDByPin[*user.UserInfo.DevicePin] = *user.Id
+		}
+	}
+
+	var attendanceModels []*models.UserAttendance
+	for _, pin := range pins {
+		userID, ok := userIDByPin[pin]
+		if !ok {
+			report.UnmatchedPins = append(report.UnmatchedPins, &entity.AttendanceImportUnmatchedPin{
+				Pin:     pin,
+				Punches: len(punchesByPin[pin]),
+			})
+			continue
+		}
+
+		planned, err := s.planDeviceImport(ctx, userID, punchesByPin[pin], report)
+		if err != nil {
+			return nil, err
+		}
+		attendanceModels = append(attendanceModels, planned...)
+	}
+
+	if !dryRun && len(attendanceModels) > 0 {
+		created, err := s.attendanceDB.CreateAttendances(ctx, attendanceModels)
+		if err != nil {
+			return nil, err
+		}
+		// a punch pushed again while its first push is still in flight is recorded once
+		report.Duplicates += len(attendanceModels) - len(created)
+		attendanceModels = created
+	}
+
+	for _, attendanceModel := range attendanceModels {
+		report.Attendances = append(report.Attendances, attendanceModel.ToAttendanceEntity())
+	}
+	sort.SliceStable(report.Anomalies, func(i, j int) bool {
+		return report.Anomalies[i].Line < report.Anomalies[j].Line
+	})
+
+	return report, nil
+}
+
+// planDeviceImport walks the punches of the user along the attendances already recorded around them and returns the
+// attendances to add, the duplicates and the anomalies are counted on the report
+func (s *attendanceService) planDeviceImport(ctx context.Context, userID uint, punches []*entity.AttendancePunch, report *entity.AttendanceImport) ([]*models.UserAttendance, error) {
+	loc, err := s.GetUserLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	punchedAt := make(map[*entity.AttendancePunch]time.Time, len(punches))
+	for _, punch := range punches {
+		at := punch.PunchedAt
+		punchedAt[punch] = time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, loc)
+	}
+	punches = slices.Clone(punches)
+	sort.SliceStable(punches, func(i, j int) bool {
+		return punchedAt[punches[i]].Before(punchedAt[punches[j]])
+	})
+
+	maxSession := s.maxSessionDuration()
+	recorded, err := s.attendanceDB.GetRecordedAttendancesByUserIDAndDateBetween(ctx, userID, punchedAt[punches[0]].Add(-maxSession), punchedAt[punches[len(punches)-1]].Add(maxSession))
+	if err != nil {
+		return nil, err
+	}
+
+	// a superseded attendance is not counted anymore but its punch is still recorded, it is not imported again
+	recordedAt := make(map[int64]bool, len(recorded))
+	var counted []*models.UserAttendance
+	for _, attendance := range recorded {
+		recordedAt[attendance.CreatedAt.Unix()] = true
+		if attendance.SupersededByCorrectionID == nil {
+			counted = append(counted, attendance)
+		}
+	}
+
+	now := utils.TimeNow()
+	var latest *models.UserAttendance
+	var planned []*models.UserAttendance
+	for _, punch := range punches {
+		at := punchedAt[punch]
+		for len(counted) > 0 && !counted[0].CreatedAt.After(at) {
+			latest, counted = counted[0], counted[1:]
+		}
+
+		anomaly := &entity.AttendanceImportAnomaly{
+			Line:      punch.Line,
+			Pin:       punch.Pin,
+			UserID:    &userID,
+			PunchedAt: &at,
+		}
+
+		if recordedAt[at.Unix()] {
+			report.Duplicates++
+			continue
+		}
+		if at.After(now) {
+			anomaly.Type = entity.AttendanceImportAnomalyTypeInFuture
+			report.Anomalies = append(report.Anomalies, anomaly)
+			continue
+		}
+		if latest != nil && at.Sub(latest.CreatedAt) < doublePunchWindow {
+			anomaly.Type = entity.AttendanceImportAnomalyTypeDoublePunch
+			report.Anomalies = append(report.Anomalies, anomaly)
+			continue
+		}
+
+		isOpen := latest != nil && latest.Type != models.AttendanceTypeCheckOut && at.Sub(latest.CreatedAt) <= maxSession
+		attendanceType := models.AttendanceTypeCheckIn
+		if punch.Type != nil {
+			attendanceType = models.AttendanceType(*punch.Type)
+		} else if isOpen {
+			attendanceType = models.AttendanceTypeCheckOut
+		}
+
+		switch {
+		case attendanceType == models.AttendanceTypeCheckIn && isOpen:
+			anomaly.Type = entity.AttendanceImportAnomalyTypeAlreadyCheckedIn
+		case attendanceType != models.AttendanceTypeCheckIn && !isOpen:
+			anomaly.Type = entity.AttendanceImportAnomalyTypeNotCheckedIn
+		case attendanceType == models.AttendanceTypeBreakStart && latest.Type == models.AttendanceTypeBreakStart:
+			anomaly.Type = entity.AttendanceImportAnomalyTypeAlreadyOnBreak
+		case attendanceType == models.AttendanceTypeBreakEnd && latest.Type != models.AttendanceTypeBreakStart:
+			anomaly.Type = entity.AttendanceImportAnomalyTypeNotOnBreak
+		case attendanceType == models.AttendanceTypeCheckIn:
+			schedule, err := s.GetWorkSchedule(ctx, userID, at)
+			if err != nil {
+				return nil, err
+			}
+			if schedule == nil {
+				anomaly.Type = entity.AttendanceImportAnomalyTypeDayOff
+			}
+		}
+		if anomaly.Type != "" {
+			report.Anomalies = append(report.Anomalies, anomaly)
+			continue
+		}
+
+		attendance := &models.UserAttendance{
+			UserID:   userID,
+			Type:     attendanceType,
+			DeviceID: punch.DeviceID,
+		}
+		attendance.CreatedAt = at
+		planned = append(planned, attendance)
+
+		recordedAt[at.Unix()] = true
+		latest = attendance
+	}
+
+	return planned, nil
+}
+
+func (s *attendanceService) maxSessionDuration() time.Duration {
+	return time.Duration(s.config.Attendance.MaxSessionHours) * time.Hour
+}
+
+// getLatestSessionAttendance returns the last attendance within the max session length, nil when there is none. A
+// check-in is an open session
+func (s *attendanceService) getLatestSessionAttendance(ctx context.Context, userID uint) (*models.UserAttendance, error) {
+	latest, err := s.attendanceDB.GetLatestAttendanceByUserID(ctx, userID, utils.TimeNow().Add(-s.maxSessionDuration()))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return latest, nil
 }
 
 func (s *attendanceService) GetAttendancesByUserID(ctx context.Context, userID uint) ([]*entity.UserAttendance, error) {
@@ -132,35 +514,437 @@ func (s *attendanceService) GetAttendancesByUserIDAndDateBetween(ctx context.Con
 	return userAttendances, nil
 }
 
+// GetAttendancesByUserIDAndDateBetweenGroupByDate pairs every check-in with the next checkout within the max session
+// length, so a night shift is a single session, and collects the breaks in between. The sessions started between startedAt and endedAt are returned, dated
+// in the zone of the user
 func (s *attendanceService) GetAttendancesByUserIDAndDateBetweenGroupByDate(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*entity.UserAttendanceGroupedByDate, error) {
-	attendances, err := s.GetAttendancesByUserIDAndDateBetween(ctx, userID, startedAt, endedAt)
+	loc, err := s.GetUserLocation(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	grouped := make(map[string]*entity.UserAttendanceGroupedByDate)
+	maxSession := s.maxSessionDuration()
+	// the sessions crossing the bounds are read whole
+	attendances, err := s.GetAttendancesByUserIDAndDateBetween(ctx, userID, startedAt.Add(-maxSession), endedAt.Add(maxSession))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*entity.UserAttendanceGroupedByDate
+	var open *entity.UserAttendanceGroupedByDate
 	for _, att := range attendances {
 		if att.CreatedAt == nil {
 			continue
 		}
-		day := att.CreatedAt.Format("2006-01-02")
-		if _, exists := grouped[day]; !exists {
-			grouped[day] = &entity.UserAttendanceGroupedByDate{
-				Date: time.Date(att.CreatedAt.Year(), att.CreatedAt.Month(), att.CreatedAt.Day(), 0, 0, 0, 0, att.CreatedAt.Location()),
-			}
-		}
+
 		switch att.Type {
 		case entity.AttendanceTypeCheckIn:
-			grouped[day].CheckIn = att
+			open = &entity.UserAttendanceGroupedByDate{
+				Date:    utils.StartOfDay(att.CreatedAt.In(loc)),
+				CheckIn: att,
+			}
+			result = append(result, open)
+		case entity.AttendanceTypeBreakStart:
+			if open != nil && att.CreatedAt.Sub(*open.CheckIn.CreatedAt) <= maxSession {
+				open.Breaks = append(open.Breaks, &entity.UserAttendanceBreak{Start: att})
+			}
+		case entity.AttendanceTypeBreakEnd:
+			if open != nil && len(open.Breaks) > 0 {
+				if lastBreak := open.Breaks[len(open.Breaks)-1]; lastBreak.End == nil {
+					lastBreak.End = att
+				}
+			}
 		case entity.AttendanceTypeCheckOut:
-			grouped[day].CheckOut = att
+			if open != nil && att.CreatedAt.Sub(*open.CheckIn.CreatedAt) <= maxSession {
+				open.CheckOut = att
+			} else {
+				result = append(result, &entity.UserAttendanceGroupedByDate{
+					Date:     utils.StartOfDay(att.CreatedAt.In(loc)),
+					CheckOut: att,
+				})
+			}
+			open = nil
 		}
 	}
 
-	var result []*entity.UserAttendanceGroupedByDate
-	for _, v := range grouped {
-		result = append(result, v)
+	sessions := make([]*entity.UserAttendanceGroupedByDate, 0, len(result))
+	for _, session := range result {
+		startAt := session.CheckOut
+		if session.CheckIn != nil {
+			startAt = session.CheckIn
+		}
+
+		if !startAt.CreatedAt.Before(startedAt) && !startAt.CreatedAt.After(endedAt) {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions, nil
+}
+
+// GetUserLocation returns the zone the attendances of the user are judged in
+func (s *attendanceService) GetUserLocation(ctx context.Context, userID uint) (*time.Location, error) {
+	return s.shiftSvc.GetUserLocation(ctx, userID)
+}
+
+// GetWorkSchedule returns the shift the user works on the day, nil on a day off
+func (s *attendanceService) GetWorkSchedule(ctx context.Context, userID uint, day time.Time) (*entity.WorkSchedule, error) {
+	return s.shiftSvc.GetWorkSchedule(ctx, userID, day)
+}
+
+// GetAttendanceSummary evaluates the attendances of the user against their shift on every working day from startDate
+// to endDate inclusive. The dates are calendar days in the zone of the user. The first check-in of a day is judged
+// late and its last checkout early
+func (s *attendanceService) GetAttendanceSummary(ctx context.Context, userID uint, startDate time.Time, endDate time.Time) (*entity.AttendanceSummary, error) {
+	summary := &entity.AttendanceSummary{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	loc, err := s.GetUserLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	firstDay := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
+	lastDay := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, loc)
+	attendancesGroup, err := s.GetAttendancesByUserIDAndDateBetweenGroupByDate(ctx, userID, firstDay, utils.EndOfDay(lastDay))
+	if err != nil {
+		return nil, err
+	}
+
+	sessionsByDate := make(map[string][]*entity.UserAttendanceGroupedByDate, len(attendancesGroup))
+	for _, session := range attendancesGroup {
+		// a checkout without its check-in is not a session worked
+		if session.CheckIn == nil {
+			continue
+		}
+
+		date := session.Date.Format("2006-01-02")
+		sessionsByDate[date] = append(sessionsByDate[date], session)
+	}
+
+	today := utils.StartOfDay(utils.TimeNow().In(loc))
+	for day := firstDay; !day.After(lastDay) && !day.After(today); day = day.AddDate(0, 0, 1) {
+		schedule, err := s.GetWorkSchedule(ctx, userID, day)
+		if err != nil {
+			return nil, err
+		}
+		if schedule == nil {
+			continue
+		}
+
+		sessions := sessionsByDate[day.Format("2006-01-02")]
+		isToday := day.Equal(today)
+		if len(sessions) == 0 {
+			// today is not over yet, the employee can still check in
+			if !isToday {
+				summary.ScheduledDays++
+				summary.AbsentDays++
+			}
+			continue
+		}
+
+		summary.ScheduledDays++
+		summary.PresentDays++
+
+		checkinAt := *sessions[0].CheckIn.CreatedAt
+		if late := checkinAt.Sub(schedule.StartAt(day)); late > schedule.Grace {
+			summary.LateArrivals++
+			summary.LateMinutes += int(late.Minutes())
+			summary.Latenesses = append(summary.Latenesses, &entity.AttendanceLateness{
+				Type:         entity.AttendanceLatenessTypeLateArrival,
+				Date:         day,
+				AttendanceID: *sessions[0].CheckIn.ID,
+				Minutes:      int(late.Minutes()),
+				GraceMinutes: int(schedule.Grace.Minutes()),
+			})
+		}
+
+		var worked time.Duration
+		missingCheckout := false
+		for _, session := range sessions {
+			if session.CheckIn.IsOutOfArea {
+				summary.OutOfAreaCheckins++
+			}
+			if session.CheckOut == nil || session.IsAutoClosed() {
+				missingCheckout = true
+				continue
+			}
+			worked += session.WorkedDuration()
+		}
+		if !entity.TracksBreaks(sessions) {
+			worked = max(worked-schedule.Break, 0)
+		}
+		summary.WorkedMilis += int(worked.Milliseconds())
+
+		if missingCheckout && !isToday {
+			summary.MissingCheckouts++
+		}
+
+		lastSession := sessions[len(sessions)-1]
+		if lastSession.CheckOut == nil || lastSession.IsAutoClosed() {
+			continue
+		}
+
+		checkoutAt := *lastSession.CheckOut.CreatedAt
+		if early := schedule.EndAt(day).Sub(checkoutAt); early > schedule.Grace {
+			summary.EarlyDepartures++
+			summary.EarlyDepartureMinutes += int(early.Minutes())
+			summary.Latenesses = append(summary.Latenesses, &entity.AttendanceLateness{
+				Type:         entity.AttendanceLatenessTypeEarlyDeparture,
+				Date:         day,
+				AttendanceID: *lastSession.CheckOut.ID,
+				Minutes:      int(early.Minutes()),
+				GraceMinutes: int(schedule.Grace.Minutes()),
+			})
+		}
+	}
+
+	return summary, nil
+}
+
+// getMissingCheckout finds the session a checkout at correctedAt closes, the last check-in of the user within the max
+// session length before it must still be open or closed by the auto-close job. The auto-close checkout the correction
+// supersedes is returned, nil when the session is still open
+func (s *attendanceService) getMissingCheckout(ctx context.Context, userID uint, correctedAt time.Time) (*models.UserAttendance, error) {
+	attendances, err := s.attendanceDB.GetAttendancesByUserIDAndDateBetween(ctx, userID, correctedAt.Add(-s.maxSessionDuration()), correctedAt.Add(s.maxSessionDuration()))
+	if err != nil {
+		return nil, err
+	}
+
+	var checkin, next *models.UserAttendance
+	for _, attendance := range attendances {
+		if attendance.Type != models.AttendanceTypeCheckIn && attendance.Type != models.AttendanceTypeCheckOut {
+			continue
+		}
+		if attendance.Type == models.AttendanceTypeCheckIn && !attendance.CreatedAt.After(correctedAt) {
+			checkin, next = attendance, nil
+			continue
+		}
+		if checkin != nil && next == nil {
+			next = attendance
+		}
+	}
+
+	if checkin == nil {
+		return nil, &internalerror.AttendanceCorrectionNoOpenSessionError{}
+	}
+
+	// the next check-in starts another session and a checkout past the max session length does not pair with it
+	if next == nil || next.Type == models.AttendanceTypeCheckIn || next.CreatedAt.After(checkin.CreatedAt.Add(s.maxSessionDuration())) {
+		return nil, nil
+	}
+	if next.AutoClosePolicy != nil {
+		return next, nil
+	}
+	return nil, &internalerror.AttendanceCorrectionNoOpenSessionError{}
+}
+
+// checkWrongTime rejects a wrong time correction moving the attendance past the attendances around it, e.g. a check-in
+// moved past its checkout
+func (s *attendanceService) checkWrongTime(ctx context.Context, attendance *models.UserAttendance, correctedAt time.Time) error {
+	startedAt, endedAt := attendance.CreatedAt, correctedAt
+	if correctedAt.Before(startedAt) {
+		startedAt, endedAt = correctedAt, attendance.CreatedAt
+	}
+
+	attendances, err := s.attendanceDB.GetAttendancesByUserIDAndDateBetween(ctx, attendance.UserID, startedAt.Add(-s.maxSessionDuration()), endedAt.Add(s.maxSessionDuration()))
+	if err != nil {
+		return err
+	}
+
+	var previous, next *models.UserAttendance
+	for _, neighbour := range attendances {
+		if neighbour.ID == attendance.ID {
+			continue
+		}
+		if !neighbour.CreatedAt.After(attendance.CreatedAt) {
+			previous = neighbour
+		} else if next == nil {
+			next = neighbour
+		}
+	}
+
+	if previous != nil && !correctedAt.After(previous.CreatedAt) {
+		return &internalerror.AttendanceCorrectionOverlapError{}
+	}
+	if next != nil && !correctedAt.Before(next.CreatedAt) {
+		return &internalerror.AttendanceCorrectionOverlapError{}
+	}
+	return nil
+}
+
+// checkMissingCheckin rejects a missing check-in within a session already checked in, the check-in would overlap it
+func (s *attendanceService) checkMissingCheckin(ctx context.Context, userID uint, correctedAt time.Time) error {
+	attendances, err := s.attendanceDB.GetAttendancesByUserIDAndDateBetween(ctx, userID, correctedAt.Add(-s.maxSessionDuration()), correctedAt)
+	if err != nil {
+		return err
+	}
+
+	var previous *models.UserAttendance
+	for _, attendance := range attendances {
+		if attendance.Type == models.AttendanceTypeCheckIn || attendance.Type == models.AttendanceTypeCheckOut {
+			previous = attendance
+		}
+	}
+
+	if previous != nil && previous.Type == models.AttendanceTypeCheckIn {
+		return &internalerror.AttendanceCorrectionOverlapError{}
+	}
+	return nil
+}
+
+// RequestCorrection submits a pending correction on the attendances of the user, the corrected time cannot be in the
+// future. A missing checkout closes a check-in still open or closed by the auto-close job, a wrong time corrects
+// an attendance of the user that is not superseded yet without moving it past the attendances around it, and a
+// missing check-in cannot fall within another session
+func (s *attendanceService) RequestCorrection(ctx context.Context, correction *entity.AttendanceCorrection) (*entity.AttendanceCorrection, error) {
+	if correction.CorrectedAt.After(utils.TimeNow()) {
+		return nil, &internalerror.AttendanceCorrectionInFutureError{}
+	}
+
+	switch correction.Type {
+	case entity.AttendanceCorrectionTypeMissingCheckOut:
+		autoClosed, err := s.getMissingCheckout(ctx, correction.UserID, correction.CorrectedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		correction.AttendanceID = nil
+		if autoClosed != nil {
+			correction.AttendanceID = &autoClosed.ID
+		}
+	case entity.AttendanceCorrectionTypeWrongTime:
+		attendance, err := s.attendanceDB.GetAttendanceByID(ctx, *correction.AttendanceID)
+		if err != nil {
+			return nil, err
+		}
+		if attendance.UserID != correction.UserID {
+			return nil, &internalerror.NotFoundError{}
+		}
+		if attendance.SupersededByCorrectionID != nil {
+			return nil, &internalerror.AttendanceCorrectionSupersededError{}
+		}
+		if err := s.checkWrongTime(ctx, attendance, correction.CorrectedAt); err != nil {
+			return nil, err
+		}
+	default:
+		correction.AttendanceID = nil
+		if err := s.checkMissingCheckin(ctx, correction.UserID, correction.CorrectedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	correction.Status = entity.AttendanceCorrectionStatusPending
+	correctionModel := &models.AttendanceCorrection{}
+	correctionModel.FromAttendanceCorrectionEntity(correction)
+
+	if err := s.attendanceDB.CreateCorrection(ctx, correctionModel); err != nil {
+		return nil, err
+	}
+
+	return correctionModel.ToAttendanceCorrectionEntity(), nil
+}
+
+// ReviewCorrection approves or rejects a pending correction, the employee cannot review their own correction. An
+// approved correction adds the attendance at the corrected time and supersedes the wrong one, the original attendances
+// are kept for the audit. The session of a missing checkout is looked up again as it may have been closed since, and
+// the attendances around a wrong time or a missing check-in are checked again as they may have changed since
+func (s *attendanceService) ReviewCorrection(ctx context.Context, correctionID uint, reviewerID uint, approved bool, note *string) (*entity.AttendanceCorrection, error) {
+	correction, err := s.attendanceDB.GetCorrectionByID(ctx, correctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if correction.Status != models.AttendanceCorrectionStatusPending {
+		return nil, &internalerror.AttendanceCorrectionReviewedError{}
+	}
+
+	if correction.UserID == reviewerID {
+		return nil, &internalerror.AttendanceCorrectionSelfReviewError{}
+	}
+
+	reviewedAt := utils.TimeNow()
+	correction.ReviewedByUserID = &reviewerID
+	correction.ReviewedAt = &reviewedAt
+	correction.ReviewNote = note
+
+	if !approved {
+		correction.Status = models.AttendanceCorrectionStatusRejected
+		if err := s.attendanceDB.UpdateCorrection(ctx, correction); err != nil {
+			return nil, err
+		}
+		return correction.ToAttendanceCorrectionEntity(), nil
+	}
+
+	attendance := &models.UserAttendance{
+		UserID: correction.UserID,
+		Type:   models.AttendanceTypeCheckIn,
+	}
+	attendance.CreatedAt = correction.CorrectedAt
+
+	switch correction.Type {
+	case models.AttendanceCorrectionTypeMissingCheckOut:
+		autoClosed, err := s.getMissingCheckout(ctx, correction.UserID, correction.CorrectedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		attendance.Type = models.AttendanceTypeCheckOut
+		correction.AttendanceID = nil
+		if autoClosed != nil {
+			correction.AttendanceID = &autoClosed.ID
+		}
+	case models.AttendanceCorrectionTypeWrongTime:
+		superseded, err := s.attendanceDB.GetAttendanceByID(ctx, *correction.AttendanceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkWrongTime(ctx, superseded, correction.CorrectedAt); err != nil {
+			return nil, err
+		}
+
+		// the wrong attendance keeps its type, its evidence still tells where it was made from
+		attendance.Type = superseded.Type
+		attendance.Latitude = superseded.Latitude
+		attendance.Longitude = superseded.Longitude
+		attendance.WifiBSSID = superseded.WifiBSSID
+		attendance.IPAddress = superseded.IPAddress
+		attendance.WorkLocationID = superseded.WorkLocationID
+		attendance.IsOutOfArea = superseded.IsOutOfArea
+		attendance.IsRemote = superseded.IsRemote
+	case models.AttendanceCorrectionTypeMissingCheckIn:
+		if err := s.checkMissingCheckin(ctx, correction.UserID, correction.CorrectedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	correction.Status = models.AttendanceCorrectionStatusApproved
+	if err := s.attendanceDB.ApplyCorrection(ctx, correction, attendance); err != nil {
+		return nil, err
+	}
+
+	return correction.ToAttendanceCorrectionEntity(), nil
+}
+
+func (s *attendanceService) GetCorrections(ctx context.Context, userID *uint, status *entity.AttendanceCorrectionStatus) ([]*entity.AttendanceCorrection, error) {
+	var statusModel *models.AttendanceCorrectionStatus
+	if status != nil {
+		converted := models.AttendanceCorrectionStatus(*status)
+		statusModel = &converted
+	}
+
+	correctionModels, err := s.attendanceDB.GetCorrections(ctx, userID, statusModel)
+	if err != nil {
+		return nil, err
+	}
+
+	corrections := make([]*entity.AttendanceCorrection, len(correctionModels))
+	for i, model := range correctionModels {
+		corrections[i] = model.ToAttendanceCorrectionEntity()
 	}
 
-	return result, nil
+	return corrections, nil
 }
@@ -0,0 +1,134 @@
+package attendancedeviceservice
+
+import (
+	"context"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	attendanceparser "d-payroll/parser/attendance"
+	repository "d-payroll/repository/db"
+	"d-payroll/repository/db/models"
+	attendanceservice "d-payroll/service/attendance"
+	"d-payroll/utils"
+	"errors"
+)
+
+type AttendanceDeviceService interface {
+	RegisterDevice(ctx context.Context, device *entity.AttendanceDevice) (*entity.AttendanceDevice, error)
+	GetDevices(ctx context.Context) ([]*entity.AttendanceDevice, error)
+	RegenerateApiKey(ctx context.Context, deviceID uint) (*entity.AttendanceDevice, error)
+
+	AuthenticateDevice(ctx context.Context, apiKey string) (*entity.AttendanceDevice, error)
+	PushRecords(ctx context.Context, device *entity.AttendanceDevice, serialNumber string, records []*entity.AttendanceDeviceRecord) (*entity.AttendanceImport, error)
+}
+
+type attendanceDeviceService struct {
+	attendanceDeviceDB repository.AttendanceDeviceDB
+	attendanceSvc      attendanceservice.AttendanceService
+}
+
+func NewAttendanceDeviceService(attendanceDeviceDB repository.AttendanceDeviceDB, attendanceSvc attendanceservice.AttendanceService) AttendanceDeviceService {
+	return &attendanceDeviceService{
+		attendanceDeviceDB: attendanceDeviceDB,
+		attendanceSvc:      attendanceSvc,
+	}
+}
+
+// RegisterDevice registers a time clock by its serial number and generates its API key, the key is only returned here
+// and when regenerated
+func (s *attendanceDeviceService) RegisterDevice(ctx context.Context, device *entity.AttendanceDevice) (*entity.AttendanceDevice, error) {
+	_, err := s.attendanceDeviceDB.GetDeviceBySerialNumber(ctx, device.SerialNumber)
+	if err == nil {
+		return nil, &internalerror.AttendanceDeviceSerialTakenError{}
+	}
+	if !errors.Is(err, &internalerror.NotFoundError{}) {
+		return nil, err
+	}
+
+	apiKey, err := utils.GenerateApiKey()
+	if err != nil {
+		return nil, err
+	}
+
+	deviceModel := &models.AttendanceDevice{ApiKeyHash: utils.HashApiKey(apiKey)}
+	deviceModel.FromAttendanceDeviceEntity(device)
+
+	if err := s.attendanceDeviceDB.CreateDevice(ctx, deviceModel); err != nil {
+		return nil, err
+	}
+
+	createdDevice := deviceModel.ToAttendanceDeviceEntity()
+	createdDevice.ApiKey = &apiKey
+	return createdDevice, nil
+}
+
+func (s *attendanceDeviceService) GetDevices(ctx context.Context) ([]*entity.AttendanceDevice, error) {
+	deviceModels, err := s.attendanceDeviceDB.GetDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]*entity.AttendanceDevice, len(deviceModels))
+	for i, deviceModel := range deviceModels {
+		devices[i] = deviceModel.ToAttendanceDeviceEntity()
+	}
+	return devices, nil
+}
+
+// RegenerateApiKey replaces the API key of the device, the previous key stops authenticating right away
+func (s *attendanceDeviceService) RegenerateApiKey(ctx context.Context, deviceID uint) (*entity.AttendanceDevice, error) {
+	deviceModel, err := s.attendanceDeviceDB.GetDeviceByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := utils.GenerateApiKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attendanceDeviceDB.UpdateApiKeyHash(ctx, deviceID, utils.HashApiKey(apiKey)); err != nil {
+		return nil, err
+	}
+
+	device := deviceModel.ToAttendanceDeviceEntity()
+	device.ApiKey = &apiKey
+	return device, nil
+}
+
+// AuthenticateDevice returns the device the API key belongs to
+func (s *attendanceDeviceService) AuthenticateDevice(ctx context.Context, apiKey string) (*entity.AttendanceDevice, error) {
+	if apiKey == "" {
+		return nil, &internalerror.AttendanceDeviceKeyInvalidError{}
+	}
+
+	deviceModel, err := s.attendanceDeviceDB.GetDeviceByApiKeyHash(ctx, utils.HashApiKey(apiKey))
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return nil, &internalerror.AttendanceDeviceKeyInvalidError{}
+		}
+		return nil, err
+	}
+
+	return deviceModel.ToAttendanceDeviceEntity(), nil
+}
+
+// PushRecords imports the punches pushed by the device as they are imported from a device log, the serial number
+// pushed must be the one of the device. A device retransmitting a push it got no answer to adds nothing twice, the
+// punches already recorded are counted as duplicates
+func (s *attendanceDeviceService) PushRecords(ctx context.Context, device *entity.AttendanceDevice, serialNumber string, records []*entity.AttendanceDeviceRecord) (*entity.AttendanceImport, error) {
+	if serialNumber != device.SerialNumber {
+		return nil, &internalerror.AttendanceDeviceSerialMismatchError{}
+	}
+
+	punches, anomalies := attendanceparser.ParseDeviceRecords(*device.ID, records)
+	report, err := s.attendanceSvc.ImportDevicePunches(ctx, punches, anomalies, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attendanceDeviceDB.UpdateLastPushAt(ctx, *device.ID, utils.TimeNow()); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
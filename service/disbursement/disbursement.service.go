@@ -0,0 +1,136 @@
+package disbursementservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	disbursementrenderer "d-payroll/renderer/disbursement"
+	payrollservice "d-payroll/service/payroll"
+	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"sort"
+	"time"
+)
+
+type DisbursementService interface {
+	RenderDisbursement(ctx context.Context, payrollID uint, format entity.DisbursementFormat, executedAt *time.Time) (*entity.DisbursementFile, error)
+}
+
+type disbursementService struct {
+	config *config.Config
+
+	payrollService payrollservice.PayrollService
+	userService    userservice.UserService
+}
+
+func NewDisbursementService(config *config.Config, payrollService payrollservice.PayrollService, userService userservice.UserService) DisbursementService {
+	return &disbursementService{
+		config: config,
+
+		payrollService: payrollService,
+		userService:    userService,
+	}
+}
+
+// RenderDisbursement renders the bulk transfer file paying the take home pay of every employee of a rolled payroll,
+// nothing is rendered while an employee lacks bank details or cannot be paid through the format.
+// The transfers are executed today unless executedAt is given
+func (s *disbursementService) RenderDisbursement(ctx context.Context, payrollID uint, format entity.DisbursementFormat, executedAt *time.Time) (*entity.DisbursementFile, error) {
+	renderer, ok := disbursementrenderer.GetDisbursementRenderer(format)
+	if !ok {
+		return nil, &internalerror.DisbursementFormatNotSupportedError{}
+	}
+
+	payroll, err := s.payrollService.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payroll.IsRolled == nil || !*payroll.IsRolled {
+		return nil, &internalerror.PayrollNotRolledError{}
+	}
+
+	summaries, err := s.payrollService.GetPayslipSummaries(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	// nothing is transferred to the employees without a positive take home pay
+	takeHomePays := map[uint]int{}
+	var userIDs []uint
+	for _, summary := range summaries {
+		if summary.TotalTakeHomePay > 0 {
+			takeHomePays[summary.UserID] = summary.TotalTakeHomePay
+			userIDs = append(userIDs, summary.UserID)
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	accounts, err := s.userService.GetBankAccounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	accountByUserID := map[uint]*entity.UserBankAccount{}
+	for _, account := range accounts {
+		accountByUserID[account.UserID] = account
+	}
+
+	now := utils.TimeNow()
+	batch := &entity.DisbursementBatch{
+		Payroll:            payroll,
+		DebitBankCode:      entity.BankCode(s.config.Disbursement.DebitBankCode),
+		DebitAccountNumber: s.config.Disbursement.DebitAccountNumber,
+		DebitAccountName:   s.config.Disbursement.DebitAccountName,
+		CorporateID:        s.config.Disbursement.CorporateID,
+		ExecutedAt:         now,
+		CreatedAt:          now,
+	}
+
+	if executedAt != nil {
+		batch.ExecutedAt = *executedAt
+	}
+
+	var issues []*entity.DisbursementIssue
+	for _, userID := range userIDs {
+		user, err := s.userService.GetUserById(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		account, ok := accountByUserID[userID]
+		if !ok {
+			issues = append(issues, &entity.DisbursementIssue{UserID: userID, Username: user.Username, Reason: "Missing bank account"})
+			continue
+		}
+
+		// cash and e-wallet payments are paid outside of the bank file
+		if account.PaymentMethod != entity.PaymentMethodTransfer {
+			continue
+		}
+
+		transfer := &entity.DisbursementTransfer{
+			UserID:        userID,
+			Username:      user.Username,
+			BankCode:      account.BankCode,
+			AccountNumber: account.AccountNumber,
+			AccountName:   account.AccountName,
+			Amount:        takeHomePays[userID],
+		}
+
+		if reason := renderer.Validate(batch, transfer); reason != "" {
+			issues = append(issues, &entity.DisbursementIssue{UserID: userID, Username: user.Username, Reason: reason})
+			continue
+		}
+
+		batch.Transfers = append(batch.Transfers, transfer)
+		batch.TotalAmount += transfer.Amount
+	}
+
+	if len(issues) > 0 {
+		return nil, &internalerror.DisbursementIssuesError{Issues: issues}
+	}
+
+	return renderer.Render(batch)
+}
@@ -0,0 +1,207 @@
+package journalservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	journalrenderer "d-payroll/renderer/journal"
+	payrollservice "d-payroll/service/payroll"
+	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"fmt"
+	"math"
+	"sort"
+)
+
+type JournalService interface {
+	GetPayrollJournal(ctx context.Context, payrollID uint) (*entity.Journal, error)
+	RenderPayrollJournal(ctx context.Context, payrollID uint, format entity.JournalFormat) (*entity.JournalFile, error)
+}
+
+type journalService struct {
+	config *config.Config
+
+	payrollService payrollservice.PayrollService
+	userService    userservice.UserService
+}
+
+func NewJournalService(config *config.Config, payrollService payrollservice.PayrollService, userService userservice.UserService) JournalService {
+	return &journalService{
+		config: config,
+
+		payrollService: payrollService,
+		userService:    userService,
+	}
+}
+
+// accountOrder is the order the journal lines are listed in, debits first
+var accountOrder = []entity.JournalAccount{
+	entity.JournalAccountSalaryExpense,
+	entity.JournalAccountOvertimeExpense,
+	entity.JournalAccountReimbursementExpense,
+	entity.JournalAccountLoanReceivable,
+	entity.JournalAccountNetSalaryPayable,
+}
+
+func (s *journalService) accountCode(account entity.JournalAccount) string {
+	switch account {
+	case entity.JournalAccountSalaryExpense:
+		return s.config.Journal.SalaryExpenseAccount
+	case entity.JournalAccountOvertimeExpense:
+		return s.config.Journal.OvertimeExpenseAccount
+	case entity.JournalAccountReimbursementExpense:
+		return s.config.Journal.ReimbursementExpenseAccount
+	case entity.JournalAccountLoanReceivable:
+		return s.config.Journal.LoanReceivableAccount
+	case entity.JournalAccountNetSalaryPayable:
+		return s.config.Journal.NetSalaryPayableAccount
+	}
+	return ""
+}
+
+type journalKey struct {
+	account    entity.JournalAccount
+	costCenter string
+}
+
+// GetPayrollJournal books the frozen payslips of a rolled payroll. The expenses are booked per cost center and
+// the liabilities company wide, the salary expense absorbs the rounding so the journal always balances
+func (s *journalService) GetPayrollJournal(ctx context.Context, payrollID uint) (*entity.Journal, error) {
+	payroll, err := s.payrollService.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payroll.IsRolled == nil || !*payroll.IsRolled {
+		return nil, &internalerror.PayrollNotRolledError{}
+	}
+
+	summaries, err := s.payrollService.GetPayslipSummaries(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	// amounts are signed, positive is a debit and negative a credit
+	amounts := map[journalKey]int{}
+	book := func(account entity.JournalAccount, costCenter string, amount int) {
+		if !account.IsExpense() {
+			costCenter = ""
+		}
+		amounts[journalKey{account, costCenter}] += amount
+	}
+
+	for _, summary := range summaries {
+		user, err := s.userService.GetUserById(ctx, summary.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		costCenter := s.config.Journal.DefaultCostCenter
+		if user.UserInfo != nil && user.UserInfo.CostCenter != nil && *user.UserInfo.CostCenter != "" {
+			costCenter = *user.UserInfo.CostCenter
+		}
+
+		payslip, err := s.payrollService.GeneratePayslip(ctx, payrollID, summary.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		overtime := 0
+		if payslip.Overtime != nil {
+			overtime = int(math.Round(float64(payslip.Overtime.TotalAmount)))
+		}
+
+		reimbursement := 0
+		if payslip.Reimburse != nil {
+			reimbursement = int(math.Round(float64(payslip.Reimburse.TotalAmount)))
+		}
+
+		deductions := map[entity.JournalAccount]int{}
+		totalDeduction := 0
+		if payslip.Deduction != nil {
+			for _, deduction := range payslip.Deduction.Details {
+				account := entity.JournalAccountLoanReceivable
+				switch deduction.Type {
+				case entity.PayslipDeductionTypeLateDay:
+					// the attendance penalties are not owed to anyone, they reduce the salary expense
+					account = entity.JournalAccountSalaryExpense
+				}
+				deductions[account] += deduction.Amount
+				totalDeduction += deduction.Amount
+			}
+		}
+
+		salary := summary.TotalTakeHomePay + totalDeduction - overtime - reimbursement
+
+		book(entity.JournalAccountSalaryExpense, costCenter, salary)
+		book(entity.JournalAccountOvertimeExpense, costCenter, overtime)
+		book(entity.JournalAccountReimbursementExpense, costCenter, reimbursement)
+		for account, amount := range deductions {
+			book(account, costCenter, -amount)
+		}
+		book(entity.JournalAccountNetSalaryPayable, costCenter, -summary.TotalTakeHomePay)
+	}
+
+	keys := make([]journalKey, 0, len(amounts))
+	for key, amount := range amounts {
+		if amount != 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	position := map[entity.JournalAccount]int{}
+	for i, account := range accountOrder {
+		position[account] = i
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].account != keys[j].account {
+			return position[keys[i].account] < position[keys[j].account]
+		}
+		return keys[i].costCenter < keys[j].costCenter
+	})
+
+	journal := &entity.Journal{
+		Payroll:   payroll,
+		Reference: fmt.Sprintf("PAYROLL-%d", *payroll.ID),
+		Date:      payroll.EndedAt,
+		Currency:  s.config.Journal.Currency,
+		CreatedAt: utils.TimeNow(),
+	}
+
+	for _, key := range keys {
+		amount := amounts[key]
+		line := &entity.JournalLine{
+			Account:     key.account,
+			AccountCode: s.accountCode(key.account),
+			AccountName: key.account.Name(),
+			CostCenter:  key.costCenter,
+			Description: fmt.Sprintf("%s %s", key.account.Name(), payroll.Name),
+		}
+		if amount > 0 {
+			line.Debit = amount
+		} else {
+			line.Credit = -amount
+		}
+
+		journal.Lines = append(journal.Lines, line)
+		journal.TotalDebit += line.Debit
+		journal.TotalCredit += line.Credit
+	}
+
+	return journal, nil
+}
+
+func (s *journalService) RenderPayrollJournal(ctx context.Context, payrollID uint, format entity.JournalFormat) (*entity.JournalFile, error) {
+	renderer, ok := journalrenderer.GetJournalRenderer(format)
+	if !ok {
+		return nil, &internalerror.JournalFormatNotSupportedError{}
+	}
+
+	journal, err := s.GetPayrollJournal(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderer.Render(journal)
+}
@@ -0,0 +1,156 @@
+package loanservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	repository "d-payroll/repository/db"
+	"d-payroll/repository/db/models"
+	userservice "d-payroll/service/user"
+	"fmt"
+	"time"
+)
+
+type LoanService interface {
+	CreateLoan(ctx context.Context, loan *entity.UserLoan) (*entity.UserLoan, error)
+	GetLoanByID(ctx context.Context, loanID uint) (*entity.UserLoan, error)
+	GetLoansByUserID(ctx context.Context, userID uint) ([]*entity.UserLoan, error)
+	GetLoanStatement(ctx context.Context, loanID uint) (*entity.LoanStatement, error)
+
+	GetPayslipDeductions(ctx context.Context, userID uint, payrollID uint, payrollEndedAt time.Time, takeHomePay float32) ([]*entity.PayslipDeductionDetail, error)
+}
+
+type loanService struct {
+	config  *config.Config
+	loanDB  repository.LoanDB
+	userSvc userservice.UserService
+}
+
+func NewLoanService(config *config.Config, loanDB repository.LoanDB, userSvc userservice.UserService) LoanService {
+	return &loanService{
+		config:  config,
+		loanDB:  loanDB,
+		userSvc: userSvc,
+	}
+}
+
+func (s *loanService) CreateLoan(ctx context.Context, loan *entity.UserLoan) (*entity.UserLoan, error) {
+	if _, err := s.userSvc.GetUserById(ctx, loan.UserID); err != nil {
+		return nil, err
+	}
+
+	// round up, so the last installment settles whatever is left
+	loan.InstallmentAmount = (loan.Principal + loan.InstallmentCount - 1) / loan.InstallmentCount
+	loan.RemainingBalance = loan.Principal
+
+	loanModel := &models.UserLoan{}
+	loanModel.FromUserLoanEntity(loan)
+
+	err := s.loanDB.CreateLoan(ctx, loanModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return loanModel.ToUserLoanEntity(), nil
+}
+
+func (s *loanService) GetLoanByID(ctx context.Context, loanID uint) (*entity.UserLoan, error) {
+	loanModel, err := s.loanDB.GetLoanByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loanModel.ToUserLoanEntity(), nil
+}
+
+func (s *loanService) GetLoansByUserID(ctx context.Context, userID uint) ([]*entity.UserLoan, error) {
+	loanModels, err := s.loanDB.GetLoansByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loans := make([]*entity.UserLoan, len(loanModels))
+	for i, model := range loanModels {
+		loans[i] = model.ToUserLoanEntity()
+	}
+
+	return loans, nil
+}
+
+func (s *loanService) GetLoanStatement(ctx context.Context, loanID uint) (*entity.LoanStatement, error) {
+	loanModel, err := s.loanDB.GetLoanByID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	installmentModels, err := s.loanDB.GetInstallmentsByLoanID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &entity.LoanStatement{
+		Loan:         loanModel.ToUserLoanEntity(),
+		Installments: make([]*entity.LoanInstallment, len(installmentModels)),
+	}
+	for i, model := range installmentModels {
+		statement.Installments[i] = model.ToLoanInstallmentEntity()
+		statement.PaidAmount += model.Amount
+	}
+
+	if loanModel.InstallmentAmount > 0 {
+		statement.RemainingInstallments = (loanModel.RemainingBalance + loanModel.InstallmentAmount - 1) / loanModel.InstallmentAmount
+	}
+
+	return statement, nil
+}
+
+// GetPayslipDeductions returns the installments to deduct on a payslip, the installments already paid by the payroll
+// are returned as is, the others are capped so the take home pay never goes below the configured floor
+func (s *loanService) GetPayslipDeductions(ctx context.Context, userID uint, payrollID uint, payrollEndedAt time.Time, takeHomePay float32) ([]*entity.PayslipDeductionDetail, error) {
+	paidInstallments, err := s.loanDB.GetInstallmentsByUserIDAndPayrollID(ctx, userID, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	deductions := []*entity.PayslipDeductionDetail{}
+	if len(paidInstallments) > 0 {
+		for _, installment := range paidInstallments {
+			loan, err := s.loanDB.GetLoanByID(ctx, installment.LoanID)
+			if err != nil {
+				return nil, err
+			}
+
+			deductions = append(deductions, toPayslipDeductionDetail(loan, installment.Amount))
+		}
+
+		return deductions, nil
+	}
+
+	loans, err := s.loanDB.GetOutstandingLoansByUserID(ctx, userID, payrollEndedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	available := int(takeHomePay) - s.config.Loan.MinTakeHomePay
+	for _, loan := range loans {
+		amount := min(loan.InstallmentAmount, loan.RemainingBalance, available)
+		if amount <= 0 {
+			continue
+		}
+
+		deductions = append(deductions, toPayslipDeductionDetail(loan, amount))
+		available -= amount
+	}
+
+	return deductions, nil
+}
+
+func toPayslipDeductionDetail(loan *models.UserLoan, amount int) *entity.PayslipDeductionDetail {
+	loanID := loan.ID
+	return &entity.PayslipDeductionDetail{
+		Type:        entity.PayslipDeductionTypeLoan,
+		ReferenceID: &loanID,
+		Description: fmt.Sprintf("%s installment: %s", loan.Type, loan.Description),
+		Amount:      amount,
+	}
+}
@@ -0,0 +1,264 @@
+package notificationservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	emailnotifier "d-payroll/notifier/email"
+	repository "d-payroll/repository/db"
+	"d-payroll/repository/db/models"
+	payrollservice "d-payroll/service/payroll"
+	payslipservice "d-payroll/service/payslip"
+	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type NotificationService interface {
+	SendPayslips(ctx context.Context, payrollID uint) ([]*entity.PayslipDelivery, error)
+	ResendPayslip(ctx context.Context, payrollID uint, userID uint) (*entity.PayslipDelivery, error)
+	GetPayslipDeliveries(ctx context.Context, payrollID uint) ([]*entity.PayslipDelivery, error)
+	NotifyAttendanceAutoClosed(ctx context.Context, checkout *entity.UserAttendance) error
+}
+
+type notificationService struct {
+	config    *config.Config
+	mailer    emailnotifier.Mailer
+	payslipDB repository.PayslipDB
+
+	payslipService payslipservice.PayslipService
+	payrollService payrollservice.PayrollService
+	userService    userservice.UserService
+}
+
+func NewNotificationService(config *config.Config, mailer emailnotifier.Mailer, payslipDB repository.PayslipDB, payslipService payslipservice.PayslipService, payrollService payrollservice.PayrollService, userService userservice.UserService) NotificationService {
+	return &notificationService{
+		config:    config,
+		mailer:    mailer,
+		payslipDB: payslipDB,
+
+		payslipService: payslipService,
+		payrollService: payrollService,
+		userService:    userService,
+	}
+}
+
+// SendPayslips queues a payslip email for every employee of a rolled payroll and sends them in the background,
+// the employees already sent to are left alone
+func (s *notificationService) SendPayslips(ctx context.Context, payrollID uint) ([]*entity.PayslipDelivery, error) {
+	userIDs, err := s.payslipService.GetPayslipUserIds(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*models.PayslipDelivery
+	for _, userID := range userIDs {
+		delivery, err := s.getOrCreateDelivery(ctx, payrollID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if delivery.Status != models.PayslipDeliveryStatusSent {
+			pending = append(pending, delivery)
+		}
+	}
+
+	// TODO: move to a proper queue/worker, a restart leaves the pending deliveries behind
+	go func() {
+		for _, delivery := range pending {
+			s.deliver(context.Background(), delivery)
+		}
+	}()
+
+	return s.GetPayslipDeliveries(ctx, payrollID)
+}
+
+// ResendPayslip sends the payslip email of a user again and waits for the outcome
+func (s *notificationService) ResendPayslip(ctx context.Context, payrollID uint, userID uint) (*entity.PayslipDelivery, error) {
+	userIDs, err := s.payslipService.GetPayslipUserIds(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(userIDs, userID) {
+		return nil, &internalerror.PayrollUserNotSelectedError{}
+	}
+
+	delivery, err := s.getOrCreateDelivery(ctx, payrollID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deliver(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery.ToPayslipDeliveryEntity(), nil
+}
+
+func (s *notificationService) GetPayslipDeliveries(ctx context.Context, payrollID uint) ([]*entity.PayslipDelivery, error) {
+	deliveriesModel, err := s.payslipDB.GetDeliveries(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*entity.PayslipDelivery, len(deliveriesModel))
+	for i, deliveryModel := range deliveriesModel {
+		deliveries[i] = deliveryModel.ToPayslipDeliveryEntity()
+	}
+
+	return deliveries, nil
+}
+
+func (s *notificationService) getOrCreateDelivery(ctx context.Context, payrollID uint, userID uint) (*models.PayslipDelivery, error) {
+	delivery, err := s.payslipDB.GetDelivery(ctx, payrollID, userID)
+	if err == nil {
+		return delivery, nil
+	}
+
+	if !errors.Is(err, &internalerror.NotFoundError{}) {
+		return nil, err
+	}
+
+	delivery = &models.PayslipDelivery{
+		PayrollID: payrollID,
+		UserID:    userID,
+		Status:    models.PayslipDeliveryStatusPending,
+	}
+
+	if err := s.payslipDB.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// deliver sends the payslip email retrying the transient failures, the outcome is stored on the delivery,
+// the returned error is only about storing it
+func (s *notificationService) deliver(ctx context.Context, delivery *models.PayslipDelivery) error {
+	message, err := s.buildPayslipMessage(ctx, delivery)
+	if err != nil {
+		return s.fail(ctx, delivery, models.PayslipDeliveryStatusFailed, err)
+	}
+
+	if message == nil {
+		return s.fail(ctx, delivery, models.PayslipDeliveryStatusSkipped, errors.New("user has no email address"))
+	}
+
+	delivery.Email = &message.To
+
+	for attempt := 1; ; attempt++ {
+		delivery.Attempts++
+
+		err = s.mailer.Send(message)
+		if err == nil {
+			sentAt := utils.TimeNow()
+			delivery.Status = models.PayslipDeliveryStatusSent
+			delivery.SentAt = &sentAt
+			delivery.LastError = nil
+			return s.payslipDB.UpdateDelivery(ctx, delivery)
+		}
+
+		if attempt >= s.config.Smtp.MaxAttempts || !emailnotifier.IsTransientError(err) {
+			return s.fail(ctx, delivery, models.PayslipDeliveryStatusFailed, err)
+		}
+
+		time.Sleep(time.Duration(s.config.Smtp.RetryDelayMilis*attempt) * time.Millisecond)
+	}
+}
+
+func (s *notificationService) fail(ctx context.Context, delivery *models.PayslipDelivery, status models.PayslipDeliveryStatus, cause error) error {
+	message := cause.Error()
+	delivery.Status = status
+	delivery.LastError = &message
+
+	return s.payslipDB.UpdateDelivery(ctx, delivery)
+}
+
+// buildPayslipMessage renders the protected payslip and the email of a delivery, nil when the user has no email address
+func (s *notificationService) buildPayslipMessage(ctx context.Context, delivery *models.PayslipDelivery) (*emailnotifier.Message, error) {
+	user, err := s.userService.GetUserById(ctx, delivery.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.UserInfo == nil || user.UserInfo.Email == nil || *user.UserInfo.Email == "" {
+		return nil, nil
+	}
+
+	payroll, err := s.payrollService.GetPayrollByID(ctx, delivery.PayrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	locale := entity.Locale(s.config.Company.Locale)
+
+	pdf, err := s.payslipService.RenderPayslipPdf(ctx, delivery.PayrollID, delivery.UserID, locale, true)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, body, err := emailnotifier.RenderPayslipEmail(&emailnotifier.PayslipEmail{
+		CompanyName:  s.config.Company.Name,
+		EmployeeName: user.Username,
+		PayrollName:  payroll.Name,
+		Period:       fmt.Sprintf("%s - %s", payroll.StartedAt.Format("02/01/2006"), payroll.EndedAt.Format("02/01/2006")),
+		Locale:       locale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &emailnotifier.Message{
+		To:       *user.UserInfo.Email,
+		Subject:  subject,
+		HTMLBody: body,
+		Attachments: []*emailnotifier.Attachment{
+			{
+				Filename:    fmt.Sprintf("payslip-%d-%s.pdf", delivery.PayrollID, user.Username),
+				ContentType: "application/pdf",
+				Content:     pdf,
+			},
+		},
+	}, nil
+}
+
+// NotifyAttendanceAutoClosed emails the employee a checkout added by the auto-close job and how the session is paid,
+// the employees without an email address are skipped
+func (s *notificationService) NotifyAttendanceAutoClosed(ctx context.Context, checkout *entity.UserAttendance) error {
+	if checkout.AutoClosePolicy == nil || checkout.CreatedAt == nil {
+		return nil
+	}
+
+	user, err := s.userService.GetUserById(ctx, checkout.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.UserInfo == nil || user.UserInfo.Email == nil || *user.UserInfo.Email == "" {
+		return nil
+	}
+
+	// the checkout is dated in the zone of the employee
+	subject, body, err := emailnotifier.RenderAttendanceAutoCloseEmail(&emailnotifier.AttendanceAutoCloseEmail{
+		CompanyName:  s.config.Company.Name,
+		EmployeeName: user.Username,
+		Date:         checkout.CreatedAt.Format("02/01/2006"),
+		ClosedAt:     checkout.CreatedAt.Format("15:04"),
+		Policy:       *checkout.AutoClosePolicy,
+		Locale:       entity.Locale(s.config.Company.Locale),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(&emailnotifier.Message{
+		To:       *user.UserInfo.Email,
+		Subject:  subject,
+		HTMLBody: body,
+	})
+}
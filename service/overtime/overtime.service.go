@@ -17,6 +17,9 @@ type OvertimeService interface {
 	ApproveOvertime(ctx context.Context, overtimeID uint, approvedByUserID uint) error
 	GetOvertimesByUserID(ctx context.Context, userID uint) ([]*entity.UserOvertime, error)
 	GetOvertimesByUserIDAndDateBetween(ctx context.Context, userID uint, startedAt time.Time, endedAt time.Time) ([]*entity.UserOvertime, error)
+
+	CreateOvertimePlan(ctx context.Context, plan *entity.OvertimePlan) (*entity.OvertimePlan, error)
+	GetOvertimePlansByUserID(ctx context.Context, userID uint) ([]*entity.OvertimePlan, error)
 }
 
 type overtimeService struct {
@@ -33,8 +36,16 @@ func NewOvertimeService(config *config.Config, overtimeDB repository.OvertimeDB,
 	}
 }
 
+// CreateOvertime submits an overtime, the day and the weekend are judged in the zone of the user. An overtime submitted
+// against its plan is submitted once the plan is worked, see checkPlanDue. With a duration within the tolerance of the
+// plan it is approved right away, on behalf of who planned it
 func (s *overtimeService) CreateOvertime(ctx context.Context, overtime *entity.UserOvertime) (*entity.UserOvertime, error) {
-	if !utils.IsWeekend() {
+	loc, err := s.attendanceSvc.GetUserLocation(ctx, overtime.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !utils.IsWeekendAt(utils.TimeNow().In(loc)) {
 
 		isCheckedOut, err := s.attendanceSvc.IsCheckedOut(ctx, overtime.UserID)
 		if err != nil {
@@ -46,7 +57,25 @@ func (s *overtimeService) CreateOvertime(ctx context.Context, overtime *entity.U
 		}
 	}
 
-	thisDayOvertimes, err := s.overtimeDB.GetThisDayOvertimeByUserID(ctx, overtime.UserID)
+	var plan *models.OvertimePlan
+	if overtime.OvertimePlanID != nil {
+		plan, err = s.overtimeDB.GetOvertimePlanByID(ctx, *overtime.OvertimePlanID)
+		if err != nil {
+			return nil, err
+		}
+		if plan.UserID != overtime.UserID {
+			return nil, &internalerror.NotFoundError{}
+		}
+		if plan.Overtime != nil {
+			return nil, &internalerror.OvertimePlanAlreadySubmittedError{}
+		}
+
+		if err := s.checkPlanDue(ctx, plan, overtime.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	thisDayOvertimes, err := s.overtimeDB.GetThisDayOvertimeByUserID(ctx, overtime.UserID, loc)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +88,17 @@ func (s *overtimeService) CreateOvertime(ctx context.Context, overtime *entity.U
 		return nil, &internalerror.OvertimeExceedsLimitError{}
 	}
 
+	err = s.checkPeriodLimits(ctx, overtime, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// an overtime worked as planned needs no review
+	if plan != nil && max(overtime.DurationMilis-plan.DurationMilis, plan.DurationMilis-overtime.DurationMilis) <= s.config.Overtime.PlanToleranceMilis {
+		overtime.IsApproved = true
+		overtime.UpdatedByUserID = &plan.CreatedByUserID
+	}
+
 	overtimeModel := &models.UserOvertime{}
 	overtimeModel.FromOvertimeEntity(overtime)
 
@@ -70,6 +110,68 @@ func (s *overtimeService) CreateOvertime(ctx context.Context, overtime *entity.U
 	return overtimeModel.ToOvertimeEntity(), nil
 }
 
+// checkPlanDue fails unless the plan is claimed once worked: the checkout closing the last session, or the submission
+// when there is none, falls from the planned time to the end of the plan with the tolerance. A plan crossing midnight is
+// claimed after its checkout on the next day
+func (s *overtimeService) checkPlanDue(ctx context.Context, plan *models.OvertimePlan, userID uint) error {
+	workedAt, err := s.attendanceSvc.GetLastCheckoutAt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if workedAt == nil {
+		now := utils.TimeNow()
+		workedAt = &now
+	}
+
+	endedAt := plan.PlannedAt.Add(time.Duration(plan.DurationMilis+s.config.Overtime.PlanToleranceMilis) * time.Millisecond)
+	if workedAt.Before(plan.PlannedAt) || workedAt.After(endedAt) {
+		return &internalerror.OvertimePlanNotDueError{}
+	}
+	return nil
+}
+
+// checkPeriodLimits caps the overtimes submitted in the calendar week, from Monday, and in the calendar month of the
+// submission, as the daily limit does on the day
+func (s *overtimeService) checkPeriodLimits(ctx context.Context, overtime *entity.UserOvertime, loc *time.Location) error {
+	maxWeekMilis := s.config.Overtime.MaxDurationPerWeekMilis
+	maxMonthMilis := s.config.Overtime.MaxDurationPerMonthMilis
+	if maxWeekMilis <= 0 && maxMonthMilis <= 0 {
+		return nil
+	}
+
+	now := utils.TimeNow().In(loc)
+	weekStartedAt := utils.StartOfDay(now).AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+	monthStartedAt := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+
+	startedAt := monthStartedAt
+	if weekStartedAt.Before(startedAt) {
+		startedAt = weekStartedAt
+	}
+	overtimes, err := s.overtimeDB.GetOvertimesByUserIDAndDateBetween(ctx, overtime.UserID, startedAt, utils.EndOfDay(now))
+	if err != nil {
+		return err
+	}
+
+	weekMilis := overtime.DurationMilis
+	monthMilis := overtime.DurationMilis
+	for _, submitted := range overtimes {
+		if !submitted.CreatedAt.Before(weekStartedAt) {
+			weekMilis += submitted.DurationMilis
+		}
+		if !submitted.CreatedAt.Before(monthStartedAt) {
+			monthMilis += submitted.DurationMilis
+		}
+	}
+
+	if maxWeekMilis > 0 && weekMilis > maxWeekMilis {
+		return &internalerror.OvertimeExceedsWeeklyLimitError{}
+	}
+	if maxMonthMilis > 0 && monthMilis > maxMonthMilis {
+		return &internalerror.OvertimeExceedsMonthlyLimitError{}
+	}
+	return nil
+}
+
 func (s *overtimeService) ApproveOvertime(ctx context.Context, overtimeID uint, approvedByUserID uint) error {
 	overtime, err := s.overtimeDB.GetOvertimeByID(ctx, overtimeID)
 	if err != nil {
@@ -109,3 +211,44 @@ func (s *overtimeService) GetOvertimesByUserIDAndDateBetween(ctx context.Context
 
 	return overtimes, nil
 }
+
+// CreateOvertimePlan assigns an overtime to the user in advance, the day is judged in the zone of the user. A plan
+// cannot start on a day already over nor be longer than the limit of a day
+func (s *overtimeService) CreateOvertimePlan(ctx context.Context, plan *entity.OvertimePlan) (*entity.OvertimePlan, error) {
+	loc, err := s.attendanceSvc.GetUserLocation(ctx, plan.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.PlannedAt.In(loc).Before(utils.StartOfDay(utils.TimeNow().In(loc))) {
+		return nil, &internalerror.OvertimePlanInPastError{}
+	}
+
+	if plan.DurationMilis > s.config.Overtime.MaxDurationPerDayMilis {
+		return nil, &internalerror.OvertimeExceedsLimitError{}
+	}
+
+	planModel := &models.OvertimePlan{}
+	planModel.FromOvertimePlanEntity(plan)
+
+	err = s.overtimeDB.CreateOvertimePlan(ctx, planModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return planModel.ToOvertimePlanEntity(), nil
+}
+
+func (s *overtimeService) GetOvertimePlansByUserID(ctx context.Context, userID uint) ([]*entity.OvertimePlan, error) {
+	planModels, err := s.overtimeDB.GetOvertimePlansByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*entity.OvertimePlan, len(planModels))
+	for i, model := range planModels {
+		plans[i] = model.ToOvertimePlanEntity()
+	}
+
+	return plans, nil
+}
@@ -8,21 +8,40 @@ import (
 	repository "d-payroll/repository/db"
 	"d-payroll/repository/db/models"
 	attendanceservice "d-payroll/service/attendance"
+	loanservice "d-payroll/service/loan"
 	overtimeservice "d-payroll/service/overtime"
 	reimbursementservice "d-payroll/service/reimbursement"
 	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 )
 
 type PayrollService interface {
 	CreatePayroll(ctx context.Context, payroll *entity.Payroll) (*entity.Payroll, error)
+	CreateOffCyclePayroll(ctx context.Context, payroll *entity.Payroll, earnings []*entity.PayrollEarning) (*entity.Payroll, error)
 	GetPayrolls(ctx context.Context) ([]*entity.Payroll, error)
+	GetPayrollByID(ctx context.Context, payrollID uint) (*entity.Payroll, error)
 	RollPayroll(ctx context.Context, payrollID uint, userID uint) error
 
+	AddPayrollUsers(ctx context.Context, payrollID uint, userIDs []uint) error
+	AddPayrollEarning(ctx context.Context, earning *entity.PayrollEarning) (*entity.PayrollEarning, error)
+	GetPayrollEarnings(ctx context.Context, payrollID uint) ([]*entity.PayrollEarning, error)
+
+	DetectRetroAdjustments(ctx context.Context, payrollID uint) ([]*entity.PayrollRetroAdjustment, error)
+	GetRetroAdjustments(ctx context.Context, payrollID uint) ([]*entity.PayrollRetroAdjustment, error)
+
+	PreviewPayroll(ctx context.Context, payrollID uint, thresholdPercent float64) (*entity.PayrollPreview, error)
 	GeneratePayslip(ctx context.Context, payrollID uint, userID uint) (*entity.Payslip, error)
 	GetPayslipSummaries(ctx context.Context, payrollID uint) ([]*entity.UserPayslipSummary, error)
+	StreamPayslips(ctx context.Context, payrollID uint, fn func(record *entity.PayslipRecord) error) error
 	GetTotalTakeHomePay(ctx context.Context, payrollID uint) (int, error)
+	GetYearToDate(ctx context.Context, userID uint, year int) (*entity.UserYearToDate, error)
+	StreamYearToDates(ctx context.Context, year int, fn func(yearToDate *entity.UserYearToDate) error) error
 }
 
 type payrollService struct {
@@ -33,9 +52,10 @@ type payrollService struct {
 	attendanceService    attendanceservice.AttendanceService
 	reimbursementService reimbursementservice.ReimbursementService
 	overtimeService      overtimeservice.OvertimeService
+	loanService          loanservice.LoanService
 }
 
-func NewPayrollService(config *config.Config, payrollDB repository.PayrollDB, userservice userservice.UserService, attendanceService attendanceservice.AttendanceService, reimbursementService reimbursementservice.ReimbursementService, overtimeService overtimeservice.OvertimeService) PayrollService {
+func NewPayrollService(config *config.Config, payrollDB repository.PayrollDB, userservice userservice.UserService, attendanceService attendanceservice.AttendanceService, reimbursementService reimbursementservice.ReimbursementService, overtimeService overtimeservice.OvertimeService, loanService loanservice.LoanService) PayrollService {
 	return &payrollService{
 		config:    config,
 		payrollDB: payrollDB,
@@ -44,10 +64,15 @@ func NewPayrollService(config *config.Config, payrollDB repository.PayrollDB, us
 		attendanceService:    attendanceService,
 		reimbursementService: reimbursementService,
 		overtimeService:      overtimeService,
+		loanService:          loanService,
 	}
 }
 
 func (s *payrollService) CreatePayroll(ctx context.Context, payroll *entity.Payroll) (*entity.Payroll, error) {
+	if payroll.Type == "" {
+		payroll.Type = entity.PayrollTypeRegular
+	}
+
 	payrollModel := &models.Payroll{}
 	payrollModel.FromPayrollEntity(payroll)
 
@@ -59,6 +84,29 @@ func (s *payrollService) CreatePayroll(ctx context.Context, payroll *entity.Payr
 	return payrollModel.ToPayrollEntity(), nil
 }
 
+// CreateOffCyclePayroll creates an off-cycle payroll already paying the given earnings, their users are selected with them
+func (s *payrollService) CreateOffCyclePayroll(ctx context.Context, payroll *entity.Payroll, earnings []*entity.PayrollEarning) (*entity.Payroll, error) {
+	if !payroll.Type.IsOffCycle() {
+		return nil, &internalerror.PayrollNotOffCycleError{}
+	}
+
+	payrollModel := &models.Payroll{}
+	payrollModel.FromPayrollEntity(payroll)
+
+	earningModels := make([]*models.PayrollEarning, len(earnings))
+	for i, earning := range earnings {
+		earningModels[i] = &models.PayrollEarning{}
+		earningModels[i].FromPayrollEarningEntity(earning)
+	}
+
+	err := s.payrollDB.CreateOffCyclePayroll(ctx, payrollModel, earningModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return payrollModel.ToPayrollEntity(), nil
+}
+
 func (s *payrollService) GetPayrolls(ctx context.Context) ([]*entity.Payroll, error) {
 	payrollModels, err := s.payrollDB.GetPayrolls(ctx)
 	if err != nil {
@@ -73,34 +121,73 @@ func (s *payrollService) GetPayrolls(ctx context.Context) ([]*entity.Payroll, er
 	return payrolls, nil
 }
 
+func (s *payrollService) GetPayrollByID(ctx context.Context, payrollID uint) (*entity.Payroll, error) {
+	payrollModel, err := s.payrollDB.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payrollModel.ToPayrollEntity(), nil
+}
+
+// getPayrollUserIds returns every user for a regular payroll, off-cycle payrolls only pay the selected users
+func (s *payrollService) getPayrollUserIds(ctx context.Context, payroll *models.Payroll) ([]uint, error) {
+	if entity.PayrollType(payroll.Type).IsOffCycle() {
+		return s.payrollDB.GetPayrollUserIds(ctx, payroll.ID)
+	}
+
+	return s.userservice.GetUserIds(ctx)
+}
+
+// freezePayslips calculates the payslip of every payroll user to freeze on roll, with the loan installments they deduct,
+// the retro adjustments detected by the roll are paid though not stored yet
 // TODO: ideally this should be run in the background, use queue, worker or something, just for now to make it simple and usable
-func (s *payrollService) processPayslipSummary(ctx context.Context, payrollID uint, userID uint) error {
-	userIds, err := s.userservice.GetUserIds(ctx)
+func (s *payrollService) freezePayslips(ctx context.Context, payroll *models.Payroll, pendingAdjustments map[uint][]*models.PayrollRetroAdjustment) ([]*models.UserPayslipSummary, []*models.LoanInstallment, error) {
+	payrollID := payroll.ID
+	userIds, err := s.getPayrollUserIds(ctx, payroll)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
+	summaries := make([]*models.UserPayslipSummary, 0, len(userIds))
+	installments := []*models.LoanInstallment{}
 	for _, userId := range userIds {
-		payslip, err := s.GeneratePayslip(ctx, payrollID, userId)
+		payslip, err := s.calculateOpenPayslip(ctx, payroll, userId, pendingAdjustments[userId])
 		if err != nil {
-			// TODO: this shouldbe ignored, use retry mechanism
-			return err
+			return nil, nil, err
 		}
 
-		payrollSummary := &models.UserPayslipSummary{
+		frozenPayslip, err := json.Marshal(payslip)
+		if err != nil {
+			return nil, nil, err
+		}
+		frozenPayslipStr := string(frozenPayslip)
+
+		summaries = append(summaries, &models.UserPayslipSummary{
 			PayrollID:        payrollID,
 			UserID:           userId,
 			TotalTakeHomePay: int(payslip.TakeHomePay),
+			Payslip:          &frozenPayslipStr,
+		})
+
+		if payslip.Deduction == nil {
+			continue
 		}
+		for _, deduction := range payslip.Deduction.Details {
+			if deduction.Type != entity.PayslipDeductionTypeLoan {
+				continue
+			}
 
-		err = s.payrollDB.CreatePayslipSummary(ctx, payrollSummary)
-		if err != nil {
-			// TODO: this shouldbe ignored, use retry mechanism
-			return err
+			installments = append(installments, &models.LoanInstallment{
+				LoanID:    *deduction.ReferenceID,
+				PayrollID: payrollID,
+				Amount:    deduction.Amount,
+				PaidAt:    utils.TimeNow(),
+			})
 		}
 	}
 
-	return nil
+	return summaries, installments, nil
 }
 
 func (s *payrollService) RollPayroll(ctx context.Context, payrollID uint, userID uint) error {
@@ -113,9 +200,390 @@ func (s *payrollService) RollPayroll(ctx context.Context, payrollID uint, userID
 		return &internalerror.PayrollAlreadyRolledError{}
 	}
 
-	s.processPayslipSummary(ctx, payrollID, userID)
+	// the retro adjustments are stored with the roll, a failed roll leaves none behind
+	adjustments := []*models.PayrollRetroAdjustment{}
+	if !entity.PayrollType(payroll.Type).IsOffCycle() {
+		adjustments, err = s.calculateRetroAdjustments(ctx, payroll)
+		if err != nil {
+			return err
+		}
+	}
+
+	summaries, installments, err := s.freezePayslips(ctx, payroll, groupRetroAdjustmentsByUser(adjustments))
+	if err != nil {
+		return err
+	}
+
+	return s.payrollDB.RollPayroll(ctx, payrollID, userID, adjustments, summaries, installments)
+}
+
+func groupRetroAdjustmentsByUser(adjustments []*models.PayrollRetroAdjustment) map[uint][]*models.PayrollRetroAdjustment {
+	grouped := map[uint][]*models.PayrollRetroAdjustment{}
+	for _, adjustment := range adjustments {
+		grouped[adjustment.UserID] = append(grouped[adjustment.UserID], adjustment)
+	}
+	return grouped
+}
+
+func (s *payrollService) getOpenPayroll(ctx context.Context, payrollID uint) (*models.Payroll, error) {
+	payroll, err := s.payrollDB.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payroll.IsRolled != nil && *payroll.IsRolled {
+		return nil, &internalerror.PayrollAlreadyRolledError{}
+	}
+
+	return payroll, nil
+}
+
+func (s *payrollService) AddPayrollUsers(ctx context.Context, payrollID uint, userIDs []uint) error {
+	payroll, err := s.getOpenPayroll(ctx, payrollID)
+	if err != nil {
+		return err
+	}
+
+	if !entity.PayrollType(payroll.Type).IsOffCycle() {
+		return &internalerror.PayrollNotOffCycleError{}
+	}
+
+	payrollUsers := make([]*models.PayrollUser, len(userIDs))
+	for i, userID := range userIDs {
+		// make sure the user exists before selecting it
+		if _, err := s.userservice.GetUserById(ctx, userID); err != nil {
+			return err
+		}
+
+		payrollUsers[i] = &models.PayrollUser{
+			PayrollID: payrollID,
+			UserID:    userID,
+		}
+	}
+
+	return s.payrollDB.CreatePayrollUsers(ctx, payrollUsers)
+}
+
+func (s *payrollService) AddPayrollEarning(ctx context.Context, earning *entity.PayrollEarning) (*entity.PayrollEarning, error) {
+	payroll, err := s.getOpenPayroll(ctx, earning.PayrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.PayrollType(payroll.Type).IsOffCycle() {
+		isSelected, err := s.payrollDB.IsPayrollUser(ctx, earning.PayrollID, earning.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isSelected {
+			return nil, &internalerror.PayrollUserNotSelectedError{}
+		}
+	} else {
+		if _, err := s.userservice.GetUserById(ctx, earning.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	earningModel := &models.PayrollEarning{}
+	earningModel.FromPayrollEarningEntity(earning)
+
+	err = s.payrollDB.CreatePayrollEarning(ctx, earningModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return earningModel.ToPayrollEarningEntity(), nil
+}
+
+func (s *payrollService) GetPayrollEarnings(ctx context.Context, payrollID uint) ([]*entity.PayrollEarning, error) {
+	earningModels, err := s.payrollDB.GetPayrollEarnings(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	earnings := make([]*entity.PayrollEarning, len(earningModels))
+	for i, earningModel := range earningModels {
+		earnings[i] = earningModel.ToPayrollEarningEntity()
+	}
+
+	return earnings, nil
+}
+
+func (s *payrollService) getPayslipEarning(ctx context.Context, payrollID uint, userID uint) (*entity.PayslipEarning, error) {
+	earnings, err := s.payrollDB.GetPayrollEarningsByUserID(ctx, payrollID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	earningDetails := []*entity.PayslipEarningDetail{}
+	var earningTotalAmount float32
+	for _, earning := range earnings {
+		earningDetails = append(earningDetails, &entity.PayslipEarningDetail{
+			Description: earning.Description,
+			Amount:      earning.Amount,
+			CreatedAt:   earning.CreatedAt,
+		})
+		earningTotalAmount += float32(earning.Amount)
+	}
+
+	return &entity.PayslipEarning{
+		Details:     earningDetails,
+		TotalAmount: earningTotalAmount,
+	}, nil
+}
+
+// generateOffCyclePayslip only pays the explicitly added earnings, attendance, overtime and reimbursement
+// are already paid by the regular payroll of the period
+func (s *payrollService) generateOffCyclePayslip(ctx context.Context, payroll *models.Payroll, user *entity.User) (*entity.Payslip, error) {
+	isSelected, err := s.payrollDB.IsPayrollUser(ctx, payroll.ID, *user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSelected {
+		return nil, &internalerror.PayrollUserNotSelectedError{}
+	}
+
+	earning, err := s.getPayslipEarning(ctx, payroll.ID, *user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	salary := 0
+	if user.UserInfo != nil && user.UserInfo.MonthlySalary != nil {
+		salary = *user.UserInfo.MonthlySalary
+	}
+
+	return &entity.Payslip{
+		PayrollID:   payroll.ID,
+		PayrollType: entity.PayrollType(payroll.Type),
+		UserID:      *user.Id,
+		Salary:      salary,
+		Earning:     earning,
+		TakeHomePay: earning.TotalAmount,
+	}, nil
+}
+
+// getPayslipRetroAdjustment lists the retro adjustments carried by the payroll, followed by the pending ones not stored yet
+func (s *payrollService) getPayslipRetroAdjustment(ctx context.Context, payrollID uint, userID uint, pendingAdjustments []*models.PayrollRetroAdjustment) (*entity.PayslipRetroAdjustment, error) {
+	adjustments, err := s.payrollDB.GetRetroAdjustmentsByUserID(ctx, payrollID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := []*entity.PayslipRetroAdjustmentDetail{}
+	var totalAmount float32
+	for _, adjustment := range adjustments {
+		details = append(details, &entity.PayslipRetroAdjustmentDetail{
+			SourcePayrollID: adjustment.SourcePayrollID,
+			Description:     adjustment.Description,
+			Amount:          adjustment.Amount,
+			CreatedAt:       adjustment.CreatedAt,
+		})
+		totalAmount += float32(adjustment.Amount)
+	}
+	for _, adjustment := range pendingAdjustments {
+		details = append(details, &entity.PayslipRetroAdjustmentDetail{
+			SourcePayrollID: adjustment.SourcePayrollID,
+			Description:     adjustment.Description,
+			Amount:          adjustment.Amount,
+			CreatedAt:       utils.TimeNow(),
+		})
+		totalAmount += float32(adjustment.Amount)
+	}
+
+	return &entity.PayslipRetroAdjustment{
+		Details:     details,
+		TotalAmount: totalAmount,
+	}, nil
+}
+
+// getRetroAdjustableAmount sums the parts of a payslip that may change after the roll,
+// a late salary change, overtime or reimbursement approval, or an attendance correction moving the late days
+func getRetroAdjustableAmount(payslip *entity.Payslip) float32 {
+	var amount float32
+	if payslip.Attendance != nil {
+		amount += payslip.Attendance.TotalAmount
+	}
+	if payslip.Overtime != nil {
+		amount += payslip.Overtime.TotalAmount
+	}
+	if payslip.Reimburse != nil {
+		amount += payslip.Reimburse.TotalAmount
+	}
+	if payslip.Deduction != nil {
+		for _, deduction := range payslip.Deduction.Details {
+			if deduction.Type == entity.PayslipDeductionTypeLateDay {
+				amount -= float32(deduction.Amount)
+			}
+		}
+	}
+	return amount
+}
+
+// DetectRetroAdjustments recalculates the rolled regular payrolls ended before the given open payroll and compares
+// them with the frozen payslips, the delta not carried yet is added to the open payroll as a retro adjustment
+func (s *payrollService) DetectRetroAdjustments(ctx context.Context, payrollID uint) ([]*entity.PayrollRetroAdjustment, error) {
+	payroll, err := s.getOpenPayroll(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.PayrollType(payroll.Type).IsOffCycle() {
+		return nil, &internalerror.PayrollNotRegularError{}
+	}
+
+	adjustmentModels, err := s.calculateRetroAdjustments(ctx, payroll)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := make([]*entity.PayrollRetroAdjustment, len(adjustmentModels))
+	for i, adjustmentModel := range adjustmentModels {
+		err = s.payrollDB.CreateRetroAdjustment(ctx, adjustmentModel)
+		if err != nil {
+			return nil, err
+		}
+
+		adjustments[i] = adjustmentModel.ToPayrollRetroAdjustmentEntity()
+	}
+
+	return adjustments, nil
+}
+
+// getRetroAffectedUsers returns the users of each source payroll whose payslip inputs changed since the changes were last
+// carried: the source payroll is detected again by every later roll of a regular payroll ending after it, so only the changes
+// since the last of those rolls, or its own roll, can move it
+func (s *payrollService) getRetroAffectedUsers(ctx context.Context, sourcePayrolls []*models.Payroll) (map[uint]map[uint]bool, error) {
+	rolledPayrolls, err := s.payrollDB.GetRolledPayrolls(ctx, models.PayrollTypeRegular)
+	if err != nil {
+		return nil, err
+	}
+
+	// a rolled payroll is only updated by its roll
+	carriedAt := make(map[uint]time.Time, len(sourcePayrolls))
+	var since time.Time
+	for i, sourcePayroll := range sourcePayrolls {
+		sourceCarriedAt := sourcePayroll.UpdatedAt
+		for _, rolledPayroll := range rolledPayrolls {
+			if rolledPayroll.EndedAt.After(sourcePayroll.EndedAt) && rolledPayroll.UpdatedAt.After(sourceCarriedAt) {
+				sourceCarriedAt = rolledPayroll.UpdatedAt
+			}
+		}
+
+		carriedAt[sourcePayroll.ID] = sourceCarriedAt
+		if i == 0 || sourceCarriedAt.Before(since) {
+			since = sourceCarriedAt
+		}
+	}
+
+	affectedUsers := make(map[uint]map[uint]bool, len(sourcePayrolls))
+	if len(sourcePayrolls) == 0 {
+		return affectedUsers, nil
+	}
+
+	changes, err := s.payrollDB.GetRetroChangesSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sourcePayroll := range sourcePayrolls {
+		users := map[uint]bool{}
+		for _, change := range changes {
+			if change.ChangedAt.Before(carriedAt[sourcePayroll.ID]) || change.ChangedFrom.After(sourcePayroll.EndedAt) {
+				continue
+			}
+			if change.ChangedTo != nil && change.ChangedTo.Before(sourcePayroll.StartedAt) {
+				continue
+			}
+			users[change.UserID] = true
+		}
+		affectedUsers[sourcePayroll.ID] = users
+	}
+
+	return affectedUsers, nil
+}
+
+// calculateRetroAdjustments returns the retro adjustments not carried yet for the given open payroll without persisting them,
+// only the payslips whose inputs changed since they were last carried are recalculated
+func (s *payrollService) calculateRetroAdjustments(ctx context.Context, payroll *models.Payroll) ([]*models.PayrollRetroAdjustment, error) {
+	sourcePayrolls, err := s.payrollDB.GetRolledPayrollsEndedBefore(ctx, models.PayrollTypeRegular, payroll.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	affectedUsers, err := s.getRetroAffectedUsers(ctx, sourcePayrolls)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := []*models.PayrollRetroAdjustment{}
+	for _, sourcePayroll := range sourcePayrolls {
+		userIds := make([]uint, 0, len(affectedUsers[sourcePayroll.ID]))
+		for userId := range affectedUsers[sourcePayroll.ID] {
+			userIds = append(userIds, userId)
+		}
+		sort.Slice(userIds, func(i, j int) bool { return userIds[i] < userIds[j] })
+
+		for _, userId := range userIds {
+			frozenPayslip, err := s.getFrozenPayslip(ctx, sourcePayroll.ID, userId)
+			if err != nil {
+				return nil, err
+			}
+			if frozenPayslip == nil {
+				continue
+			}
+
+			user, err := s.userservice.GetUserById(ctx, userId)
+			if err != nil {
+				return nil, err
+			}
+
+			payslip, err := s.calculatePayslip(ctx, sourcePayroll, user, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			adjustedAmount, err := s.payrollDB.GetRetroAdjustedAmount(ctx, sourcePayroll.ID, userId)
+			if err != nil {
+				return nil, err
+			}
+
+			delta := int(math.Round(float64(getRetroAdjustableAmount(payslip)-getRetroAdjustableAmount(frozenPayslip)))) - adjustedAmount
+			if delta == 0 {
+				continue
+			}
+
+			adjustments = append(adjustments, &models.PayrollRetroAdjustment{
+				PayrollID:       payroll.ID,
+				SourcePayrollID: sourcePayroll.ID,
+				UserID:          userId,
+				Description:     fmt.Sprintf("Retro adjustment for %s", sourcePayroll.Name),
+				Amount:          delta,
+			})
+		}
+	}
+
+	return adjustments, nil
+}
+
+func (s *payrollService) GetRetroAdjustments(ctx context.Context, payrollID uint) ([]*entity.PayrollRetroAdjustment, error) {
+	if _, err := s.payrollDB.GetPayrollByID(ctx, payrollID); err != nil {
+		return nil, err
+	}
+
+	adjustmentModels, err := s.payrollDB.GetRetroAdjustments(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.payrollDB.RollPayroll(ctx, payrollID, userID)
+	adjustments := make([]*entity.PayrollRetroAdjustment, len(adjustmentModels))
+	for i, model := range adjustmentModels {
+		adjustments[i] = model.ToPayrollRetroAdjustmentEntity()
+	}
+
+	return adjustments, nil
 }
 
 // TODO: this should be cached, not ideal, shoud lock the database (maybe SHARE restriction is enough)
@@ -129,18 +597,70 @@ func (s *payrollService) GeneratePayslip(ctx context.Context, payrollID uint, us
 		return nil, &internalerror.PayrollNotRolledError{}
 	}
 
+	// rolled payslips are frozen, later changes are carried by retro adjustments instead
+	if *payroll.IsRolled {
+		payslip, err := s.getFrozenPayslip(ctx, payrollID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if payslip != nil {
+			return payslip, nil
+		}
+	}
+
+	return s.calculateOpenPayslip(ctx, payroll, userID, nil)
+}
+
+// calculateOpenPayslip calculates the payslip of a payroll not frozen yet, paying the given pending retro adjustments
+// on top of the stored ones
+func (s *payrollService) calculateOpenPayslip(ctx context.Context, payroll *models.Payroll, userID uint, pendingAdjustments []*models.PayrollRetroAdjustment) (*entity.Payslip, error) {
 	user, err := s.userservice.GetUserById(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	attendancesGroup, err := s.attendanceService.GetAttendancesByUserIDAndDateBetweenGroupByDate(ctx, userID, payroll.StartedAt, payroll.UpdatedAt)
+	if entity.PayrollType(payroll.Type).IsOffCycle() {
+		return s.generateOffCyclePayslip(ctx, payroll, user)
+	}
+
+	return s.calculatePayslip(ctx, payroll, user, pendingAdjustments)
+}
+
+// getFrozenPayslip returns the payslip stored at roll time, nil if the payroll was rolled before payslips were frozen
+func (s *payrollService) getFrozenPayslip(ctx context.Context, payrollID uint, userID uint) (*entity.Payslip, error) {
+	summary, err := s.payrollDB.GetPayslipSummaryByUserID(ctx, payrollID, userID)
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if summary.Payslip == nil {
+		return nil, nil
+	}
+
+	var payslip entity.Payslip
+	err = json.Unmarshal([]byte(*summary.Payslip), &payslip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payslip, nil
+}
+
+// calculatePayslip calculates a regular payslip from the current attendances, overtimes and reimbursements
+func (s *payrollService) calculatePayslip(ctx context.Context, payroll *models.Payroll, user *entity.User, pendingAdjustments []*models.PayrollRetroAdjustment) (*entity.Payslip, error) {
+	userID := *user.Id
+
+	attendancesGroup, err := s.attendanceService.GetAttendancesByUserIDAndDateBetweenGroupByDate(ctx, userID, payroll.StartedAt, payroll.EndedAt)
 	if err != nil {
 		return nil, err
 	}
 
 	var reimbursementDetails []*entity.PayslipReimburseDetail
-	reimbursements, err := s.reimbursementService.GetReimbursementsByUserIDAndDateBetween(ctx, userID, payroll.StartedAt, payroll.UpdatedAt)
+	var reimburseTotalAmount float32
+	reimbursements, err := s.reimbursementService.GetReimbursementsByUserIDAndDateBetween(ctx, userID, payroll.StartedAt, payroll.EndedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -151,11 +671,13 @@ func (s *payrollService) GeneratePayslip(ctx context.Context, payrollID uint, us
 				Amount:      reimbursement.Amount,
 				CreatedAt:   *reimbursement.CreatedAt,
 			})
+			reimburseTotalAmount += float32(reimbursement.Amount)
 		}
 	}
 
 	var overtimeDetails []*entity.PayslipOvertimeDetail
-	overtimes, err := s.overtimeService.GetOvertimesByUserIDAndDateBetween(ctx, userID, payroll.StartedAt, payroll.UpdatedAt)
+	overtimeTotalDurationMilis := 0
+	overtimes, err := s.overtimeService.GetOvertimesByUserIDAndDateBetween(ctx, userID, payroll.StartedAt, payroll.EndedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -167,45 +689,100 @@ func (s *payrollService) GeneratePayslip(ctx context.Context, payrollID uint, us
 				DurationMilis: overtime.DurationMilis,
 				CreatedAt:     *overtime.CreatedAt,
 			})
-
+			overtimeTotalDurationMilis += overtime.DurationMilis
 		}
 	}
 
 	if user.UserInfo == nil {
 		return nil, fmt.Errorf("User info not found")
 	}
-	salary := user.UserInfo.MonthlySalary
+	salary, err := s.userservice.GetMonthlySalaryAt(ctx, userID, payroll.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+	if salary == nil {
+		return nil, fmt.Errorf("User salary not found")
+	}
 	// TODO: precision issuee heree..
 	proRateMilis := float32(*salary) / float32(s.config.Payroll.DayPerMonthProrate*s.config.Payroll.MaxWorkingMilisPerDay)
 
+	// the sessions of a day share its working duration, the scheduled break is only unpaid on the days without breaks
+	sessionsByDate := make(map[string][]*entity.UserAttendanceGroupedByDate)
+	for _, attendance := range attendancesGroup {
+		date := attendance.Date.Format("2006-01-02")
+		sessionsByDate[date] = append(sessionsByDate[date], attendance)
+	}
+
+	type attendanceDay struct {
+		remainingMilis      int
+		unpaidBreakDuration time.Duration
+	}
+	attendanceDays := make(map[string]*attendanceDay, len(sessionsByDate))
+
 	attendanceDetails := []*entity.PayslipAttendanceDetail{}
 	for _, attendance := range attendancesGroup {
 		// checkinAt is the time of checkin or the end of the payroll if checkin is nil
 		// it's possible the started at of the payroll is after the checkin (edge case)
-		checkinAt := payroll.UpdatedAt
+		checkinAt := payroll.EndedAt
 		if attendance.CheckIn != nil {
 			checkinAt = *attendance.CheckIn.CreatedAt
 		}
 
-		// if the checkout is nil, it means the user forgot to checkout, so we use max working milis per day
-		durationMilis := s.config.Payroll.MaxWorkingMilisPerDay
+		// the day is paid up to the working duration of the shift, the break unpaid. A day without shift, when the
+		// rotation changed after the attendance, is capped to max working milis per day
+		date := attendance.Date.Format("2006-01-02")
+		day, ok := attendanceDays[date]
+		if !ok {
+			schedule, err := s.attendanceService.GetWorkSchedule(ctx, userID, attendance.Date)
+			if err != nil {
+				return nil, err
+			}
+
+			day = &attendanceDay{remainingMilis: s.config.Payroll.MaxWorkingMilisPerDay}
+			if schedule != nil {
+				day.remainingMilis = int(schedule.WorkingDuration().Milliseconds())
+				if !entity.TracksBreaks(sessionsByDate[date]) {
+					day.unpaidBreakDuration = schedule.Break
+				}
+			}
+			attendanceDays[date] = day
+		}
+
+		// a session is only paid once it is closed. If the checkout is nil, the session is still running or the user
+		// forgot to checkout and the auto-close job did not close it yet, it is not paid until the job or an approved
+		// attendance correction adds the checkout. The detail is flagged meanwhile. A session closed by the job is paid
+		// by the policy recorded on its checkout, and a checkout without its check-in is not a session worked
+		durationMilis := 0
 		var checkoutAt *time.Time
+		var autoClosePolicy *entity.AttendanceAutoClosePolicy
 		if attendance.CheckOut != nil {
 			checkoutAt = attendance.CheckOut.CreatedAt
-			if attendance.CheckIn != nil && attendance.CheckOut != nil && attendance.CheckOut.CreatedAt != nil {
-				durationMilis = int(attendance.CheckOut.CreatedAt.Sub(checkinAt).Milliseconds())
+			autoClosePolicy = attendance.CheckOut.AutoClosePolicy
+			if attendance.CheckIn != nil && autoClosePolicy != nil {
+				if *autoClosePolicy == entity.AttendanceAutoClosePolicyPayScheduled {
+					durationMilis = day.remainingMilis
+				}
+			} else if attendance.CheckIn != nil && attendance.CheckOut.CreatedAt != nil {
+				worked := attendance.WorkedDuration()
+				unpaidBreak := min(worked, day.unpaidBreakDuration)
+				day.unpaidBreakDuration -= unpaidBreak
+				durationMilis = int((worked - unpaidBreak).Milliseconds())
 			}
 		}
 
-		// cap the duration to max working milis per day
-		if durationMilis > s.config.Payroll.MaxWorkingMilisPerDay {
-			durationMilis = s.config.Payroll.MaxWorkingMilisPerDay
+		// cap the duration to the rest of the working duration of the day
+		if durationMilis > day.remainingMilis {
+			durationMilis = day.remainingMilis
 		}
+		day.remainingMilis -= durationMilis
 
 		attendanceDetails = append(attendanceDetails, &entity.PayslipAttendanceDetail{
-			CheckinAt:     checkinAt,
-			CheckoutAt:    checkoutAt,
-			DurationMilis: durationMilis,
+			CheckinAt:          checkinAt,
+			CheckoutAt:         checkoutAt,
+			BreakDurationMilis: int(attendance.BreakDuration().Milliseconds()),
+			DurationMilis:      durationMilis,
+			MissingCheckout:    attendance.CheckIn != nil && attendance.CheckOut == nil,
+			AutoClosePolicy:    autoClosePolicy,
 		})
 	}
 
@@ -221,41 +798,230 @@ func (s *payrollService) GeneratePayslip(ctx context.Context, payrollID uint, us
 		TotalAmount:        attendanceTotalAmount,
 	}
 
-	var reimburseTotalAmount float32
-	for _, reimbursement := range reimbursements {
-		reimburseTotalAmount += float32(reimbursement.Amount)
-	}
 	reimburse := &entity.PayslipReimburse{
 		Details:     reimbursementDetails,
 		TotalAmount: reimburseTotalAmount,
 	}
 
-	overtimeTotalDurationMilis := 0
-	var overtimeTotalAmount float32
-	for _, overtime := range overtimes {
-		overtimeTotalDurationMilis += overtime.DurationMilis
-		overtimeTotalAmount += float32(overtime.DurationMilis) * proRateMilis
-	}
 	overtime := &entity.PayslipOvertime{
 		Details:            overtimeDetails,
 		TotalDurationMilis: overtimeTotalDurationMilis,
-		TotalAmount:        overtimeTotalAmount,
+		TotalAmount:        float32(overtimeTotalDurationMilis) * proRateMilis,
+	}
+
+	earning, err := s.getPayslipEarning(ctx, payroll.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	retroAdjustment, err := s.getPayslipRetroAdjustment(ctx, payroll.ID, userID, pendingAdjustments)
+	if err != nil {
+		return nil, err
+	}
+
+	grossPay := attendance.TotalAmount + overtime.TotalAmount + reimburse.TotalAmount + earning.TotalAmount + retroAdjustment.TotalAmount
+
+	deductionDetails, err := s.getAttendancePenalties(ctx, payroll, user)
+	if err != nil {
+		return nil, err
+	}
+	// the penalties never take more than the gross pay, the lines past it are cut to what is left
+	var penaltyTotalAmount float32
+	penaltyDetails := []*entity.PayslipDeductionDetail{}
+	for _, deduction := range deductionDetails {
+		amount := min(deduction.Amount, int(grossPay-penaltyTotalAmount))
+		if amount <= 0 {
+			break
+		}
+		deduction.Amount = amount
+		penaltyTotalAmount += float32(amount)
+		penaltyDetails = append(penaltyDetails, deduction)
+	}
+	deductionDetails = penaltyDetails
+
+	// the loan installments are capped on what is left once the penalties are deducted
+	loanDeductions, err := s.loanService.GetPayslipDeductions(ctx, userID, payroll.ID, payroll.EndedAt, grossPay-penaltyTotalAmount)
+	if err != nil {
+		return nil, err
+	}
+	deductionDetails = append(deductionDetails, loanDeductions...)
+
+	var deductionTotalAmount float32
+	for _, deduction := range deductionDetails {
+		deductionTotalAmount += float32(deduction.Amount)
+	}
+	deduction := &entity.PayslipDeduction{
+		Details:     deductionDetails,
+		TotalAmount: deductionTotalAmount,
 	}
 
 	payslip := &entity.Payslip{
-		PayrollID:   payroll.ID,
-		UserID:      userID,
-		Salary:      *salary,
-		ProRate:     proRateMilis,
-		Attendance:  attendance,
-		Overtime:    overtime,
-		Reimburse:   reimburse,
-		TakeHomePay: attendanceTotalAmount + overtimeTotalAmount + reimburseTotalAmount,
+		PayrollID:       payroll.ID,
+		PayrollType:     entity.PayrollType(payroll.Type),
+		UserID:          userID,
+		Salary:          *salary,
+		ProRate:         proRateMilis,
+		Attendance:      attendance,
+		Overtime:        overtime,
+		Reimburse:       reimburse,
+		Earning:         earning,
+		RetroAdjustment: retroAdjustment,
+		Deduction:       deduction,
+		TakeHomePay:     grossPay - deductionTotalAmount,
 	}
 
 	return payslip, nil
 }
 
+// getAttendancePenalties deducts the late days of the payroll period, judged by the first check-in of the day past the
+// grace period. Once the employee is late on the threshold of days each late day from then on deducts the fixed amount,
+// a line per day. The minutes late are not deducted, the attendance already leaves them unpaid
+func (s *payrollService) getAttendancePenalties(ctx context.Context, payroll *models.Payroll, user *entity.User) ([]*entity.PayslipDeductionDetail, error) {
+	rules := s.config.Attendance
+	if user.UserInfo.AttendancePenaltyExempt || rules.PenaltyLateDaysThreshold <= 0 || rules.PenaltyLateDayAmount <= 0 {
+		return []*entity.PayslipDeductionDetail{}, nil
+	}
+
+	summary, err := s.attendanceService.GetAttendanceSummary(ctx, *user.Id, payroll.StartedAt, payroll.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	deductions := []*entity.PayslipDeductionDetail{}
+	lateDays := 0
+	for _, lateness := range summary.Latenesses {
+		if lateness.Type != entity.AttendanceLatenessTypeLateArrival {
+			continue
+		}
+
+		lateDays++
+		if lateDays < rules.PenaltyLateDaysThreshold {
+			continue
+		}
+
+		attendanceID := lateness.AttendanceID
+		date := lateness.Date
+		deductions = append(deductions, &entity.PayslipDeductionDetail{
+			Type:           entity.PayslipDeductionTypeLateDay,
+			ReferenceID:    &attendanceID,
+			AttendanceDate: &date,
+			Description:    fmt.Sprintf("Late day %d of the period on %s", lateDays, date.Format("2006-01-02")),
+			Amount:         rules.PenaltyLateDayAmount,
+		})
+	}
+
+	return deductions, nil
+}
+
+// PreviewPayroll calculates every payslip of an open payroll without persisting anything, including the retro
+// adjustments the roll would detect, and compares the take home pay with the previous rolled payroll of the same type
+func (s *payrollService) PreviewPayroll(ctx context.Context, payrollID uint, thresholdPercent float64) (*entity.PayrollPreview, error) {
+	payroll, err := s.getOpenPayroll(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIds, err := s.getPayrollUserIds(ctx, payroll)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingAdjustments := map[uint][]*models.PayrollRetroAdjustment{}
+	if !entity.PayrollType(payroll.Type).IsOffCycle() {
+		adjustments, err := s.calculateRetroAdjustments(ctx, payroll)
+		if err != nil {
+			return nil, err
+		}
+		pendingAdjustments = groupRetroAdjustmentsByUser(adjustments)
+	}
+
+	preview := &entity.PayrollPreview{
+		Payroll:          payroll.ToPayrollEntity(),
+		ThresholdPercent: thresholdPercent,
+		Entries:          []*entity.PayrollPreviewEntry{},
+	}
+
+	previousTakeHomePays := map[uint]int{}
+	previousPayrolls, err := s.payrollDB.GetRolledPayrollsEndedBefore(ctx, payroll.Type, payroll.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(previousPayrolls) > 0 {
+		previousPayroll := previousPayrolls[len(previousPayrolls)-1]
+		preview.PreviousPayroll = previousPayroll.ToPayrollEntity()
+
+		summaries, err := s.payrollDB.GetPayslipSummaries(ctx, previousPayroll.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range summaries {
+			previousTakeHomePays[summary.UserID] = summary.TotalTakeHomePay
+			preview.PreviousTotalTakeHomePay += summary.TotalTakeHomePay
+		}
+	}
+
+	for _, userId := range userIds {
+		payslip, err := s.calculateOpenPayslip(ctx, payroll, userId, pendingAdjustments[userId])
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &entity.PayrollPreviewEntry{
+			UserID:      userId,
+			Payslip:     payslip,
+			TakeHomePay: int(payslip.TakeHomePay),
+			Flags:       []entity.PayrollPreviewFlag{},
+		}
+
+		if previousTakeHomePay, ok := previousTakeHomePays[userId]; ok {
+			entry.PreviousTakeHomePay = &previousTakeHomePay
+			delete(previousTakeHomePays, userId)
+
+			if previousTakeHomePay != 0 {
+				changePercent := float64(entry.TakeHomePay-previousTakeHomePay) / math.Abs(float64(previousTakeHomePay)) * 100
+				entry.ChangePercent = &changePercent
+				if math.Abs(changePercent) > thresholdPercent {
+					entry.Flags = append(entry.Flags, entity.PayrollPreviewFlagChangeAboveThreshold)
+				}
+			} else if entry.TakeHomePay != 0 {
+				entry.Flags = append(entry.Flags, entity.PayrollPreviewFlagChangeAboveThreshold)
+			}
+		} else if preview.PreviousPayroll != nil {
+			entry.Flags = append(entry.Flags, entity.PayrollPreviewFlagNewEmployee)
+		}
+
+		if entry.TakeHomePay == 0 {
+			entry.Flags = append(entry.Flags, entity.PayrollPreviewFlagZeroPay)
+		} else if entry.TakeHomePay < 0 {
+			entry.Flags = append(entry.Flags, entity.PayrollPreviewFlagNegativePay)
+		}
+
+		preview.Entries = append(preview.Entries, entry)
+		preview.TotalTakeHomePay += entry.TakeHomePay
+	}
+
+	// whoever is left was paid by the previous payroll but is not part of this one
+	for userId, previousTakeHomePay := range previousTakeHomePays {
+		preview.Entries = append(preview.Entries, &entity.PayrollPreviewEntry{
+			UserID:              userId,
+			PreviousTakeHomePay: &previousTakeHomePay,
+			Flags:               []entity.PayrollPreviewFlag{entity.PayrollPreviewFlagMissingEmployee},
+		})
+	}
+
+	sort.Slice(preview.Entries, func(i, j int) bool {
+		return preview.Entries[i].UserID < preview.Entries[j].UserID
+	})
+
+	for _, entry := range preview.Entries {
+		if len(entry.Flags) > 0 {
+			preview.FlaggedCount++
+		}
+	}
+
+	return preview, nil
+}
+
 func (s *payrollService) GetTotalTakeHomePay(ctx context.Context, payrollID uint) (int, error) {
 	return s.payrollDB.GetTotalPayslipTakeHomePay(ctx, payrollID)
 }
@@ -273,3 +1039,140 @@ func (s *payrollService) GetPayslipSummaries(ctx context.Context, payrollID uint
 
 	return summaries, nil
 }
+
+// StreamPayslips walks the payslips of a rolled payroll with their employees in user order,
+// payslips rolled before they were frozen are recalculated
+func (s *payrollService) StreamPayslips(ctx context.Context, payrollID uint, fn func(record *entity.PayslipRecord) error) error {
+	return s.payrollDB.StreamPayslipRegisterRows(ctx, payrollID, func(row *models.PayslipRegisterRow) error {
+		payslip, err := s.getRolledPayslip(ctx, payrollID, row.UserID, row.Payslip)
+		if err != nil {
+			return err
+		}
+
+		return fn(&entity.PayslipRecord{
+			UserID:      row.UserID,
+			Username:    row.Username,
+			Department:  row.Department,
+			CostCenter:  row.CostCenter,
+			TakeHomePay: row.TotalTakeHomePay,
+			Payslip:     payslip,
+		})
+	})
+}
+
+// getRolledPayslip decodes the payslip frozen at roll time, or recalculates it when it was rolled before payslips were frozen
+func (s *payrollService) getRolledPayslip(ctx context.Context, payrollID uint, userID uint, frozenPayslip *string) (*entity.Payslip, error) {
+	if frozenPayslip == nil {
+		return s.GeneratePayslip(ctx, payrollID, userID)
+	}
+
+	var payslip entity.Payslip
+	if err := json.Unmarshal([]byte(*frozenPayslip), &payslip); err != nil {
+		return nil, err
+	}
+
+	return &payslip, nil
+}
+
+func (s *payrollService) GetYearToDate(ctx context.Context, userID uint, year int) (*entity.UserYearToDate, error) {
+	yearToDate := &entity.UserYearToDate{UserID: userID, Year: year}
+	err := s.streamYearToDates(ctx, year, &userID, func(userYearToDate *entity.UserYearToDate) error {
+		yearToDate = userYearToDate
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return yearToDate, nil
+}
+
+// StreamYearToDates walks the year to date of every employee paid in the year in user order,
+// only the employee being summed is held in memory
+func (s *payrollService) StreamYearToDates(ctx context.Context, year int, fn func(yearToDate *entity.UserYearToDate) error) error {
+	return s.streamYearToDates(ctx, year, nil, fn)
+}
+
+func (s *payrollService) streamYearToDates(ctx context.Context, year int, userID *uint, fn func(yearToDate *entity.UserYearToDate) error) error {
+	var yearToDate *entity.UserYearToDate
+	err := s.payrollDB.StreamYearPayslipRows(ctx, year, userID, func(row *models.YearPayslipRow) error {
+		if yearToDate != nil && yearToDate.UserID != row.UserID {
+			if err := fn(yearToDate); err != nil {
+				return err
+			}
+			yearToDate = nil
+		}
+		if yearToDate == nil {
+			yearToDate = &entity.UserYearToDate{UserID: row.UserID, Year: year}
+		}
+
+		payslip, err := s.getRolledPayslip(ctx, row.PayrollID, row.UserID, row.Payslip)
+		if err != nil {
+			return err
+		}
+
+		addToYearToDate(yearToDate, entity.PayrollType(row.PayrollType), row.PayrollEndedAt, row.TotalTakeHomePay, payslip)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if yearToDate == nil {
+		return nil
+	}
+	return fn(yearToDate)
+}
+
+func roundAmount(amount float32) int {
+	return int(math.Round(float64(amount)))
+}
+
+// addToYearToDate adds a rolled payslip to the totals, the salary is what is left of the gross pay
+// once the other components are taken out so the totals always add up to the take home pay
+func addToYearToDate(yearToDate *entity.UserYearToDate, payrollType entity.PayrollType, endedAt time.Time, takeHomePay int, payslip *entity.Payslip) {
+	month := int(endedAt.Month())
+	if yearToDate.FirstMonth == 0 || month < yearToDate.FirstMonth {
+		yearToDate.FirstMonth = month
+	}
+	if month > yearToDate.LastMonth {
+		yearToDate.LastMonth = month
+	}
+	yearToDate.PayrollCount++
+
+	var overtime, reimbursement, earning, bonus, totalDeduction int
+	if payslip.Overtime != nil {
+		overtime = roundAmount(payslip.Overtime.TotalAmount)
+	}
+	if payslip.Reimburse != nil {
+		reimbursement = roundAmount(payslip.Reimburse.TotalAmount)
+	}
+	if payslip.Earning != nil {
+		if payrollType == entity.PayrollTypeBonus || payrollType == entity.PayrollTypeThr {
+			bonus = roundAmount(payslip.Earning.TotalAmount)
+		} else {
+			earning = roundAmount(payslip.Earning.TotalAmount)
+		}
+	}
+	if payslip.Deduction != nil {
+		for _, deduction := range payslip.Deduction.Details {
+			switch deduction.Type {
+			case entity.PayslipDeductionTypeLateDay:
+				yearToDate.PenaltyDeduction += deduction.Amount
+			default:
+				yearToDate.LoanDeduction += deduction.Amount
+			}
+			totalDeduction += deduction.Amount
+		}
+	}
+
+	grossPay := takeHomePay + totalDeduction
+	yearToDate.Salary += grossPay - overtime - reimbursement - earning - bonus
+	yearToDate.Overtime += overtime
+	yearToDate.Reimbursement += reimbursement
+	yearToDate.Earning += earning
+	yearToDate.Bonus += bonus
+	yearToDate.GrossPay += grossPay
+	yearToDate.TotalDeduction += totalDeduction
+	yearToDate.TotalTakeHomePay += takeHomePay
+}
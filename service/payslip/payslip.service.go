@@ -0,0 +1,304 @@
+package payslipservice
+
+import (
+	"archive/zip"
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	pdfrenderer "d-payroll/renderer/pdf"
+	repository "d-payroll/repository/db"
+	"d-payroll/repository/db/models"
+	payrollservice "d-payroll/service/payroll"
+	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+type PayslipService interface {
+	RenderPayslipPdf(ctx context.Context, payrollID uint, userID uint, locale entity.Locale, protected bool) ([]byte, error)
+
+	GetPayslipUserIds(ctx context.Context, payrollID uint) ([]uint, error)
+	CheckPayslipPasswords(ctx context.Context, userIDs []uint) error
+	WritePayslipArchive(ctx context.Context, payrollID uint, userIDs []uint, locale entity.Locale, w io.Writer) error
+	StartPayslipArchive(ctx context.Context, payrollID uint, userIDs []uint, locale entity.Locale, createdByUserID uint) (*entity.PayslipArchiveJob, error)
+	GetPayslipArchiveJob(ctx context.Context, jobID uint) (*entity.PayslipArchiveJob, error)
+	GetPayslipArchiveFilePath(ctx context.Context, jobID uint) (string, error)
+	FailInterruptedArchiveJobs(ctx context.Context) error
+}
+
+type payslipService struct {
+	config    *config.Config
+	renderer  pdfrenderer.PayslipRenderer
+	payslipDB repository.PayslipDB
+
+	payrollService payrollservice.PayrollService
+	userService    userservice.UserService
+}
+
+func NewPayslipService(config *config.Config, renderer pdfrenderer.PayslipRenderer, payslipDB repository.PayslipDB, payrollService payrollservice.PayrollService, userService userservice.UserService) PayslipService {
+	return &payslipService{
+		config:    config,
+		renderer:  renderer,
+		payslipDB: payslipDB,
+
+		payrollService: payrollService,
+		userService:    userService,
+	}
+}
+
+func (s *payslipService) localeOrDefault(locale entity.Locale) entity.Locale {
+	if locale == "" {
+		return entity.Locale(s.config.Company.Locale)
+	}
+
+	return locale
+}
+
+// RenderPayslipPdf renders the payslip of a user, an empty locale falls back to the company locale
+func (s *payslipService) RenderPayslipPdf(ctx context.Context, payrollID uint, userID uint, locale entity.Locale, protected bool) ([]byte, error) {
+	payroll, err := s.payrollService.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderPayslip(ctx, payroll, userID, s.localeOrDefault(locale), protected)
+}
+
+func (s *payslipService) renderPayslip(ctx context.Context, payroll *entity.Payroll, userID uint, locale entity.Locale, protected bool) ([]byte, error) {
+	user, err := s.userService.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var password string
+	if protected {
+		userPassword := user.GetPayslipPassword()
+		if userPassword == nil {
+			return nil, &internalerror.PayslipPasswordMissingError{UserIDs: []uint{userID}}
+		}
+		password = *userPassword
+	}
+
+	payslip, err := s.payrollService.GeneratePayslip(ctx, *payroll.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &pdfrenderer.PayslipDocument{
+		Payslip:  payslip,
+		Payroll:  payroll,
+		User:     user,
+		Locale:   locale,
+		Password: password,
+	}
+
+	return s.renderer.RenderPayslip(document)
+}
+
+// GetPayslipUserIds returns the employees paid by a rolled payroll
+func (s *payslipService) GetPayslipUserIds(ctx context.Context, payrollID uint) ([]uint, error) {
+	payroll, err := s.payrollService.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payroll.IsRolled == nil || !*payroll.IsRolled {
+		return nil, &internalerror.PayrollNotRolledError{}
+	}
+
+	summaries, err := s.payrollService.GetPayslipSummaries(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, len(summaries))
+	for i, summary := range summaries {
+		userIDs[i] = summary.UserID
+	}
+
+	return userIDs, nil
+}
+
+// CheckPayslipPasswords makes sure the payslips of every user can be protected before an archive of them is started
+func (s *payslipService) CheckPayslipPasswords(ctx context.Context, userIDs []uint) error {
+	missingUserIDs := []uint{}
+	for _, userID := range userIDs {
+		user, err := s.userService.GetUserById(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if user.GetPayslipPassword() == nil {
+			missingUserIDs = append(missingUserIDs, userID)
+		}
+	}
+
+	if len(missingUserIDs) > 0 {
+		return &internalerror.PayslipPasswordMissingError{UserIDs: missingUserIDs}
+	}
+
+	return nil
+}
+
+// WritePayslipArchive writes a ZIP of the protected payslips of the users into w
+func (s *payslipService) WritePayslipArchive(ctx context.Context, payrollID uint, userIDs []uint, locale entity.Locale, w io.Writer) error {
+	payroll, err := s.payrollService.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return err
+	}
+
+	return s.writeArchive(ctx, payroll, userIDs, s.localeOrDefault(locale), w, func(processed int) error { return nil })
+}
+
+func (s *payslipService) writeArchive(ctx context.Context, payroll *entity.Payroll, userIDs []uint, locale entity.Locale, w io.Writer, onProgress func(processed int) error) error {
+	archive := zip.NewWriter(w)
+
+	for i, userID := range userIDs {
+		user, err := s.userService.GetUserById(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		pdf, err := s.renderPayslip(ctx, payroll, userID, locale, true)
+		if err != nil {
+			return err
+		}
+
+		file, err := archive.Create(fmt.Sprintf("payslip-%d-%s.pdf", *payroll.ID, user.Username))
+		if err != nil {
+			return err
+		}
+
+		if _, err := file.Write(pdf); err != nil {
+			return err
+		}
+
+		if err := onProgress(i + 1); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// StartPayslipArchive creates an archive job and writes the archive into the archive dir in the background
+func (s *payslipService) StartPayslipArchive(ctx context.Context, payrollID uint, userIDs []uint, locale entity.Locale, createdByUserID uint) (*entity.PayslipArchiveJob, error) {
+	payroll, err := s.payrollService.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CheckPayslipPasswords(ctx, userIDs); err != nil {
+		return nil, err
+	}
+
+	job := &models.PayslipArchiveJob{
+		PayrollID:       payrollID,
+		Locale:          string(s.localeOrDefault(locale)),
+		Status:          models.PayslipArchiveStatusPending,
+		TotalCount:      len(userIDs),
+		CreatedByUserID: &createdByUserID,
+	}
+
+	if err := s.payslipDB.CreateArchiveJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// the job only lives in this process, FailInterruptedArchiveJobs fails it at the next startup if the process dies
+	go s.runArchiveJob(context.Background(), job, payroll, userIDs)
+
+	return job.ToPayslipArchiveJobEntity(), nil
+}
+
+func (s *payslipService) runArchiveJob(ctx context.Context, job *models.PayslipArchiveJob, payroll *entity.Payroll, userIDs []uint) {
+	job.Status = models.PayslipArchiveStatusRunning
+	if err := s.payslipDB.UpdateArchiveJob(ctx, job); err != nil {
+		log.Printf("failed to start payslip archive job %d: %v", job.ID, err)
+		return
+	}
+
+	err := s.writeArchiveFile(ctx, job, payroll, userIDs)
+
+	finishedAt := utils.TimeNow()
+	job.FinishedAt = &finishedAt
+
+	if err != nil {
+		message := err.Error()
+		job.Status = models.PayslipArchiveStatusFailed
+		job.ErrorMessage = &message
+	} else {
+		job.Status = models.PayslipArchiveStatusDone
+	}
+
+	if err := s.payslipDB.UpdateArchiveJob(ctx, job); err != nil {
+		log.Printf("failed to finish payslip archive job %d as %s: %v", job.ID, job.Status, err)
+	}
+}
+
+// FailInterruptedArchiveJobs fails the archive jobs left pending or running by a previous process
+func (s *payslipService) FailInterruptedArchiveJobs(ctx context.Context) error {
+	count, err := s.payslipDB.FailUnfinishedArchiveJobs(ctx, "archive job was interrupted by a restart")
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		log.Printf("failed %d payslip archive jobs interrupted by a restart", count)
+	}
+
+	return nil
+}
+
+func (s *payslipService) writeArchiveFile(ctx context.Context, job *models.PayslipArchiveJob, payroll *entity.Payroll, userIDs []uint) error {
+	if err := os.MkdirAll(s.config.Payslip.ArchiveDir, 0o755); err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(s.config.Payslip.ArchiveDir, fmt.Sprintf("payslip-archive-%d.zip", job.ID))
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = s.writeArchive(ctx, payroll, userIDs, entity.Locale(job.Locale), file, func(processed int) error {
+		job.ProcessedCount = processed
+		return s.payslipDB.UpdateArchiveJob(ctx, job)
+	})
+	if err != nil {
+		os.Remove(filePath)
+		return err
+	}
+
+	job.FilePath = &filePath
+
+	return nil
+}
+
+func (s *payslipService) GetPayslipArchiveJob(ctx context.Context, jobID uint) (*entity.PayslipArchiveJob, error) {
+	job, err := s.payslipDB.GetArchiveJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return job.ToPayslipArchiveJobEntity(), nil
+}
+
+// GetPayslipArchiveFilePath returns the archive file of a finished job
+func (s *payslipService) GetPayslipArchiveFilePath(ctx context.Context, jobID uint) (string, error) {
+	job, err := s.payslipDB.GetArchiveJobByID(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+
+	if job.Status != models.PayslipArchiveStatusDone || job.FilePath == nil {
+		return "", &internalerror.PayslipArchiveNotReadyError{}
+	}
+
+	return *job.FilePath, nil
+}
@@ -0,0 +1,276 @@
+package reportservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	reportrenderer "d-payroll/renderer/report"
+	attendanceservice "d-payroll/service/attendance"
+	payrollservice "d-payroll/service/payroll"
+	userservice "d-payroll/service/user"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+type ReportService interface {
+	GetRolledPayroll(ctx context.Context, payrollID uint) (*entity.Payroll, error)
+	WritePayrollRegister(ctx context.Context, payroll *entity.Payroll, format entity.ReportFormat, w io.Writer) error
+	WritePayrollCostReport(ctx context.Context, payroll *entity.Payroll, groupBy entity.ReportGroupBy, format entity.ReportFormat, w io.Writer) error
+
+	GetAttendanceReportUsers(ctx context.Context, userID *uint, department *string) ([]*entity.User, error)
+	WriteAttendanceReport(ctx context.Context, users []*entity.User, startDate time.Time, endDate time.Time, format entity.ReportFormat, w io.Writer) error
+}
+
+type reportService struct {
+	config *config.Config
+
+	payrollService    payrollservice.PayrollService
+	attendanceService attendanceservice.AttendanceService
+	userService       userservice.UserService
+}
+
+func NewReportService(config *config.Config, payrollService payrollservice.PayrollService, attendanceService attendanceservice.AttendanceService, userService userservice.UserService) ReportService {
+	return &reportService{
+		config: config,
+
+		payrollService:    payrollService,
+		attendanceService: attendanceService,
+		userService:       userService,
+	}
+}
+
+const unassignedDepartment = "UNASSIGNED"
+
+var registerColumns = []string{
+	"user_id", "username", "department", "cost_center",
+	"base_salary", "attendance", "overtime", "reimbursement", "earning", "retro_adjustment", "gross_pay",
+	"loan_deduction", "penalty_deduction", "total_deduction", "net_pay",
+}
+
+var costReportColumns = []string{
+	"group", "employee_count", "overtime", "reimbursement", "gross_pay", "total_deduction", "net_pay",
+}
+
+var attendanceReportColumns = []string{
+	"user_id", "username", "department", "start_date", "end_date",
+	"scheduled_days", "present_days", "absent_days", "late_arrivals", "late_minutes",
+	"early_departures", "early_departure_minutes", "missing_checkouts", "out_of_area_checkins", "total_hours",
+}
+
+// GetRolledPayroll returns the payroll a report is generated for, the reports only cover rolled payrolls
+func (s *reportService) GetRolledPayroll(ctx context.Context, payrollID uint) (*entity.Payroll, error) {
+	payroll, err := s.payrollService.GetPayrollByID(ctx, payrollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payroll.IsRolled == nil || !*payroll.IsRolled {
+		return nil, &internalerror.PayrollNotRolledError{}
+	}
+
+	return payroll, nil
+}
+
+func round(amount float32) int {
+	return int(math.Round(float64(amount)))
+}
+
+func (s *reportService) toRegisterEntry(record *entity.PayslipRecord) *entity.PayrollRegisterEntry {
+	entry := &entity.PayrollRegisterEntry{
+		UserID:     record.UserID,
+		Username:   record.Username,
+		Department: unassignedDepartment,
+		CostCenter: s.config.Journal.DefaultCostCenter,
+		NetPay:     record.TakeHomePay,
+	}
+	if record.Department != nil && *record.Department != "" {
+		entry.Department = *record.Department
+	}
+	if record.CostCenter != nil && *record.CostCenter != "" {
+		entry.CostCenter = *record.CostCenter
+	}
+
+	payslip := record.Payslip
+	entry.BaseSalary = payslip.Salary
+	if payslip.Attendance != nil {
+		entry.Attendance = round(payslip.Attendance.TotalAmount)
+	}
+	if payslip.Overtime != nil {
+		entry.Overtime = round(payslip.Overtime.TotalAmount)
+	}
+	if payslip.Reimburse != nil {
+		entry.Reimbursement = round(payslip.Reimburse.TotalAmount)
+	}
+	if payslip.Earning != nil {
+		entry.Earning = round(payslip.Earning.TotalAmount)
+	}
+	if payslip.RetroAdjustment != nil {
+		entry.RetroAdjustment = round(payslip.RetroAdjustment.TotalAmount)
+	}
+	if payslip.Deduction != nil {
+		for _, deduction := range payslip.Deduction.Details {
+			switch deduction.Type {
+			case entity.PayslipDeductionTypeLateDay:
+				entry.PenaltyDeduction += deduction.Amount
+			default:
+				entry.LoanDeduction += deduction.Amount
+			}
+			entry.TotalDeduction += deduction.Amount
+		}
+	}
+
+	entry.GrossPay = entry.NetPay + entry.TotalDeduction
+
+	return entry
+}
+
+// WritePayrollRegister streams a line per employee of the payroll into w
+func (s *reportService) WritePayrollRegister(ctx context.Context, payroll *entity.Payroll, format entity.ReportFormat, w io.Writer) error {
+	writer, ok := reportrenderer.NewTableWriter(format, w, payroll.Name)
+	if !ok {
+		return &internalerror.ReportFormatNotSupportedError{}
+	}
+
+	if err := writer.WriteHeader(registerColumns); err != nil {
+		return err
+	}
+
+	err := s.payrollService.StreamPayslips(ctx, *payroll.ID, func(record *entity.PayslipRecord) error {
+		entry := s.toRegisterEntry(record)
+		return writer.WriteRow([]any{
+			entry.UserID, entry.Username, entry.Department, entry.CostCenter,
+			entry.BaseSalary, entry.Attendance, entry.Overtime, entry.Reimbursement, entry.Earning, entry.RetroAdjustment, entry.GrossPay,
+			entry.LoanDeduction, entry.PenaltyDeduction, entry.TotalDeduction, entry.NetPay,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// WritePayrollCostReport streams the payslips and writes their totals per department or cost center into w,
+// only the groups are held in memory
+func (s *reportService) WritePayrollCostReport(ctx context.Context, payroll *entity.Payroll, groupBy entity.ReportGroupBy, format entity.ReportFormat, w io.Writer) error {
+	writer, ok := reportrenderer.NewTableWriter(format, w, payroll.Name)
+	if !ok {
+		return &internalerror.ReportFormatNotSupportedError{}
+	}
+
+	groups := map[string]*entity.PayrollCostGroup{}
+	err := s.payrollService.StreamPayslips(ctx, *payroll.ID, func(record *entity.PayslipRecord) error {
+		entry := s.toRegisterEntry(record)
+
+		key := entry.Department
+		if groupBy == entity.ReportGroupByCostCenter {
+			key = entry.CostCenter
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &entity.PayrollCostGroup{Group: key}
+			groups[key] = group
+		}
+
+		group.EmployeeCount++
+		group.Overtime += entry.Overtime
+		group.Reimbursement += entry.Reimbursement
+		group.GrossPay += entry.GrossPay
+		group.TotalDeduction += entry.TotalDeduction
+		group.NetPay += entry.NetPay
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := writer.WriteHeader(costReportColumns); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		group := groups[key]
+		err := writer.WriteRow([]any{
+			group.Group, group.EmployeeCount, group.Overtime, group.Reimbursement, group.GrossPay, group.TotalDeduction, group.NetPay,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// GetAttendanceReportUsers returns the employees an attendance report covers, a single user or the employees of
+// a department, every employee when neither is given
+func (s *reportService) GetAttendanceReportUsers(ctx context.Context, userID *uint, department *string) ([]*entity.User, error) {
+	if userID != nil {
+		user, err := s.userService.GetUserById(ctx, *userID)
+		if err != nil {
+			return nil, err
+		}
+		return []*entity.User{user}, nil
+	}
+
+	employees, err := s.userService.GetUsersByRole(ctx, entity.UserRoleEmployee)
+	if err != nil {
+		return nil, err
+	}
+
+	if department == nil {
+		return employees, nil
+	}
+
+	var users []*entity.User
+	for _, employee := range employees {
+		if employee.UserInfo != nil && employee.UserInfo.Department != nil && *employee.UserInfo.Department == *department {
+			users = append(users, employee)
+		}
+	}
+	return users, nil
+}
+
+// WriteAttendanceReport streams the attendance summary of every user from startDate to endDate inclusive into w
+func (s *reportService) WriteAttendanceReport(ctx context.Context, users []*entity.User, startDate time.Time, endDate time.Time, format entity.ReportFormat, w io.Writer) error {
+	writer, ok := reportrenderer.NewTableWriter(format, w, "Attendance")
+	if !ok {
+		return &internalerror.ReportFormatNotSupportedError{}
+	}
+
+	if err := writer.WriteHeader(attendanceReportColumns); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		summary, err := s.attendanceService.GetAttendanceSummary(ctx, *user.Id, startDate, endDate)
+		if err != nil {
+			return err
+		}
+
+		department := unassignedDepartment
+		if user.UserInfo != nil && user.UserInfo.Department != nil && *user.UserInfo.Department != "" {
+			department = *user.UserInfo.Department
+		}
+
+		totalHours := math.Round(float64(summary.WorkedMilis)/float64(time.Hour.Milliseconds())*100) / 100
+		err = writer.WriteRow([]any{
+			*user.Id, user.Username, department, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"),
+			summary.ScheduledDays, summary.PresentDays, summary.AbsentDays, summary.LateArrivals, summary.LateMinutes,
+			summary.EarlyDepartures, summary.EarlyDepartureMinutes, summary.MissingCheckouts, summary.OutOfAreaCheckins, totalHours,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
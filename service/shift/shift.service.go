@@ -0,0 +1,248 @@
+package shiftservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	repository "d-payroll/repository/db"
+	"d-payroll/repository/db/models"
+	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"errors"
+	"time"
+)
+
+type ShiftService interface {
+	CreateShift(ctx context.Context, shift *entity.Shift) (*entity.Shift, error)
+	GetShifts(ctx context.Context) ([]*entity.Shift, error)
+
+	CreateShiftRotation(ctx context.Context, rotation *entity.ShiftRotation) (*entity.ShiftRotation, error)
+	GetShiftRotations(ctx context.Context) ([]*entity.ShiftRotation, error)
+
+	AssignShiftRotation(ctx context.Context, assignment *entity.UserShiftRotation) (*entity.UserShiftRotation, error)
+	GetUserShiftRotations(ctx context.Context, userID uint) ([]*entity.UserShiftRotation, error)
+
+	GetUserLocation(ctx context.Context, userID uint) (*time.Location, error)
+	GetWorkSchedule(ctx context.Context, userID uint, day time.Time) (*entity.WorkSchedule, error)
+}
+
+type shiftService struct {
+	config  *config.Config
+	shiftDB repository.ShiftDB
+	userSvc userservice.UserService
+}
+
+func NewShiftService(config *config.Config, shiftDB repository.ShiftDB, userSvc userservice.UserService) ShiftService {
+	return &shiftService{
+		config:  config,
+		shiftDB: shiftDB,
+		userSvc: userSvc,
+	}
+}
+
+// newWorkSchedule evaluates the clock times of a shift, an end at or before the start is on the next day
+func newWorkSchedule(startTime string, endTime string, breakMinutes int, graceMinutes int) (*entity.WorkSchedule, error) {
+	start, err := utils.ParseTimeOfDay(startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := utils.ParseTimeOfDay(endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if end <= start {
+		end += 24 * time.Hour
+	}
+
+	return &entity.WorkSchedule{
+		Start: start,
+		End:   end,
+		Break: time.Duration(breakMinutes) * time.Minute,
+		Grace: time.Duration(graceMinutes) * time.Minute,
+	}, nil
+}
+
+func (s *shiftService) CreateShift(ctx context.Context, shift *entity.Shift) (*entity.Shift, error) {
+	schedule, err := newWorkSchedule(shift.StartTime, shift.EndTime, shift.BreakMinutes, shift.GraceMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.WorkingDuration() <= 0 {
+		return nil, &internalerror.ShiftBreakTooLongError{}
+	}
+
+	shiftModel := &models.Shift{}
+	shiftModel.FromShiftEntity(shift)
+
+	err = s.shiftDB.CreateShift(ctx, shiftModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return shiftModel.ToShiftEntity(), nil
+}
+
+func (s *shiftService) GetShifts(ctx context.Context) ([]*entity.Shift, error) {
+	shiftModels, err := s.shiftDB.GetShifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := make([]*entity.Shift, len(shiftModels))
+	for i, shiftModel := range shiftModels {
+		shifts[i] = shiftModel.ToShiftEntity()
+	}
+
+	return shifts, nil
+}
+
+func (s *shiftService) CreateShiftRotation(ctx context.Context, rotation *entity.ShiftRotation) (*entity.ShiftRotation, error) {
+	for i, day := range rotation.Days {
+		day.DayIndex = i
+		if day.ShiftID == nil {
+			continue
+		}
+
+		if _, err := s.shiftDB.GetShiftByID(ctx, *day.ShiftID); err != nil {
+			return nil, err
+		}
+	}
+
+	rotationModel := &models.ShiftRotation{}
+	rotationModel.FromShiftRotationEntity(rotation)
+
+	err := s.shiftDB.CreateShiftRotation(ctx, rotationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// read it back so the days carry their shift
+	createdRotation, err := s.shiftDB.GetShiftRotationByID(ctx, rotationModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return createdRotation.ToShiftRotationEntity(), nil
+}
+
+func (s *shiftService) GetShiftRotations(ctx context.Context) ([]*entity.ShiftRotation, error) {
+	rotationModels, err := s.shiftDB.GetShiftRotations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rotations := make([]*entity.ShiftRotation, len(rotationModels))
+	for i, rotationModel := range rotationModels {
+		rotations[i] = rotationModel.ToShiftRotationEntity()
+	}
+
+	return rotations, nil
+}
+
+// AssignShiftRotation starts the rotation on the day of EffectiveAt, the first day of the rotation falls on that day
+func (s *shiftService) AssignShiftRotation(ctx context.Context, assignment *entity.UserShiftRotation) (*entity.UserShiftRotation, error) {
+	if _, err := s.userSvc.GetUserById(ctx, assignment.UserID); err != nil {
+		return nil, err
+	}
+
+	rotationModel, err := s.shiftDB.GetShiftRotationByID(ctx, assignment.RotationID)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveAt := assignment.EffectiveAt
+	assignment.EffectiveAt = time.Date(effectiveAt.Year(), effectiveAt.Month(), effectiveAt.Day(), 0, 0, 0, 0, time.Local)
+
+	assignmentModel := &models.UserShiftRotation{}
+	assignmentModel.FromUserShiftRotationEntity(assignment)
+
+	err = s.shiftDB.CreateUserShiftRotation(ctx, assignmentModel)
+	if err != nil {
+		return nil, err
+	}
+
+	assignmentModel.Rotation = rotationModel
+	return assignmentModel.ToUserShiftRotationEntity(), nil
+}
+
+func (s *shiftService) GetUserShiftRotations(ctx context.Context, userID uint) ([]*entity.UserShiftRotation, error) {
+	assignmentModels, err := s.shiftDB.GetUserShiftRotations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*entity.UserShiftRotation, len(assignmentModels))
+	for i, assignmentModel := range assignmentModels {
+		assignments[i] = assignmentModel.ToUserShiftRotationEntity()
+	}
+
+	return assignments, nil
+}
+
+// GetUserLocation returns the zone the attendances of the user are judged in, the default zone of the company when the
+// user has none
+func (s *shiftService) GetUserLocation(ctx context.Context, userID uint) (*time.Location, error) {
+	user, err := s.userSvc.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone := s.config.Attendance.DefaultTimezone
+	if user.UserInfo != nil && user.UserInfo.Timezone != nil && *user.UserInfo.Timezone != "" {
+		timezone = *user.UserInfo.Timezone
+	}
+
+	return time.LoadLocation(timezone)
+}
+
+// GetWorkSchedule returns the shift the user works on the day, nil on a day off. The day is a calendar day in the zone
+// of the user. The employees without an assigned rotation work the company working day from Monday to Friday
+func (s *shiftService) GetWorkSchedule(ctx context.Context, userID uint, day time.Time) (*entity.WorkSchedule, error) {
+	// the rotations start on a server midnight, whatever the zone of the user
+	startOfDay := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+
+	assignment, err := s.shiftDB.GetUserShiftRotationAt(ctx, userID, startOfDay)
+	if err != nil {
+		if !errors.Is(err, &internalerror.NotFoundError{}) {
+			return nil, err
+		}
+
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			return nil, nil
+		}
+
+		attendanceConfig := s.config.Attendance
+		return newWorkSchedule(attendanceConfig.WorkStartTime, attendanceConfig.WorkEndTime, 0, attendanceConfig.GraceMinutes)
+	}
+
+	days := assignment.Rotation.Days
+	if len(days) == 0 {
+		return nil, nil
+	}
+
+	rotationDay := days[daysBetween(assignment.EffectiveAt, startOfDay)%len(days)]
+	if rotationDay.Shift == nil {
+		return nil, nil
+	}
+
+	shift := rotationDay.Shift
+	schedule, err := newWorkSchedule(shift.StartTime, shift.EndTime, shift.BreakMinutes, shift.GraceMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.ShiftID = &shift.ID
+	schedule.ShiftName = shift.Name
+	return schedule, nil
+}
+
+// daysBetween counts the calendar days from start to end, whatever the daylight saving changes in between
+func daysBetween(start time.Time, end time.Time) int {
+	startDate := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(endDate.Sub(startDate).Hours() / 24)
+}
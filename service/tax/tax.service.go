@@ -0,0 +1,227 @@
+package taxservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	pdfrenderer "d-payroll/renderer/pdf"
+	taxrenderer "d-payroll/renderer/tax"
+	payrollservice "d-payroll/service/payroll"
+	userservice "d-payroll/service/user"
+	"fmt"
+	"io"
+	"time"
+)
+
+type TaxService interface {
+	GetTaxCertificate(ctx context.Context, userID uint, year int) (*entity.TaxCertificate, error)
+	RenderTaxCertificatePdf(ctx context.Context, userID uint, year int) ([]byte, error)
+	CheckEbupot(ctx context.Context, year int) error
+	WriteEbupotCsv(ctx context.Context, year int, w io.Writer) error
+}
+
+type taxService struct {
+	config   *config.Config
+	renderer pdfrenderer.TaxCertificateRenderer
+
+	payrollService payrollservice.PayrollService
+	userService    userservice.UserService
+}
+
+func NewTaxService(config *config.Config, renderer pdfrenderer.TaxCertificateRenderer, payrollService payrollservice.PayrollService, userService userservice.UserService) TaxService {
+	return &taxService{
+		config:   config,
+		renderer: renderer,
+
+		payrollService: payrollService,
+		userService:    userService,
+	}
+}
+
+const (
+	// occupationalCostPercent of the gross income is deductible, up to occupationalCostMonthlyCap per month worked
+	occupationalCostPercent    = 5
+	occupationalCostMonthlyCap = 500000
+)
+
+// taxBrackets are the progressive PPh 21 rates of the yearly taxable income, the last bracket has no ceiling
+var taxBrackets = []struct {
+	ceiling int
+	percent int
+}{
+	{ceiling: 60000000, percent: 5},
+	{ceiling: 250000000, percent: 15},
+	{ceiling: 500000000, percent: 25},
+	{ceiling: 5000000000, percent: 30},
+	{ceiling: 0, percent: 35},
+}
+
+func calculateAnnualTax(taxableIncome int) int {
+	var tax, floor int
+	for _, bracket := range taxBrackets {
+		if taxableIncome <= floor {
+			break
+		}
+
+		taxed := taxableIncome - floor
+		if bracket.ceiling != 0 && taxableIncome > bracket.ceiling {
+			taxed = bracket.ceiling - floor
+		}
+		tax += taxed * bracket.percent / 100
+		floor = bracket.ceiling
+	}
+
+	return tax
+}
+
+// buildTaxCertificate fills the Form 1721-A1 from the year to date of the employee. The regular pay goes to line 1,
+// overtime and the other earnings to line 3 and the bonus and THR payrolls to line 7, the reimbursements are not income.
+// The payrolls withhold neither PPh 21 nor BPJS, the pension contribution and the tax withheld are left at zero
+func (s *taxService) buildTaxCertificate(user *entity.User, yearToDate *entity.UserYearToDate) *entity.TaxCertificate {
+	certificate := &entity.TaxCertificate{
+		Number:     fmt.Sprintf("1.1-%02d.%02d-%07d", yearToDate.LastMonth, yearToDate.Year%100, yearToDate.UserID),
+		Year:       yearToDate.Year,
+		FirstMonth: yearToDate.FirstMonth,
+		LastMonth:  yearToDate.LastMonth,
+		Date:       time.Date(yearToDate.Year, time.Month(yearToDate.LastMonth)+1, 0, 0, 0, 0, 0, time.Local),
+		UserID:     yearToDate.UserID,
+		Name:       user.Username,
+		TaxStatus:  entity.TaxStatusTk0,
+	}
+
+	if info := user.UserInfo; info != nil {
+		if info.FullName != nil && *info.FullName != "" {
+			certificate.Name = *info.FullName
+		}
+		if info.Nik != nil {
+			certificate.Nik = *info.Nik
+		}
+		if info.Npwp != nil {
+			certificate.Npwp = *info.Npwp
+		}
+		if info.Address != nil {
+			certificate.Address = *info.Address
+		}
+		if info.Gender != nil {
+			certificate.Gender = *info.Gender
+		}
+		if info.TaxStatus != nil && info.TaxStatus.IsValid() {
+			certificate.TaxStatus = *info.TaxStatus
+		}
+		if info.Position != nil {
+			certificate.Position = *info.Position
+		}
+	}
+
+	certificate.Salary = yearToDate.Salary
+	certificate.OtherAllowance = yearToDate.Overtime + yearToDate.Earning
+	certificate.Bonus = yearToDate.Bonus
+	certificate.GrossIncome = certificate.Salary + certificate.TaxAllowance + certificate.OtherAllowance +
+		certificate.Honorarium + certificate.InsurancePremium + certificate.BenefitInKind + certificate.Bonus
+
+	months := certificate.LastMonth - certificate.FirstMonth + 1
+	certificate.OccupationalCost = min(max(certificate.GrossIncome, 0)*occupationalCostPercent/100, occupationalCostMonthlyCap*months)
+	certificate.TotalDeduction = certificate.OccupationalCost + certificate.PensionContribution
+	certificate.NetIncome = certificate.GrossIncome - certificate.TotalDeduction
+
+	certificate.AnnualNetIncome = certificate.NetIncome + certificate.PreviousNetIncome
+	certificate.Ptkp = certificate.TaxStatus.Ptkp()
+	// the taxable income is rounded down to the thousand
+	certificate.TaxableIncome = max(certificate.AnnualNetIncome-certificate.Ptkp, 0) / 1000 * 1000
+	certificate.AnnualTax = calculateAnnualTax(certificate.TaxableIncome)
+	certificate.TaxDue = certificate.AnnualTax - certificate.PreviousTax
+
+	return certificate
+}
+
+// isWithheld tells whether the tax due on the certificate was withheld, a certificate reporting a tax due that nobody
+// withheld cannot be issued
+func isWithheld(certificate *entity.TaxCertificate) bool {
+	return certificate.TaxWithheld >= certificate.TaxDue
+}
+
+// GetTaxCertificate returns the certificate of an employee over the rolled payrolls ending in the year
+func (s *taxService) GetTaxCertificate(ctx context.Context, userID uint, year int) (*entity.TaxCertificate, error) {
+	user, err := s.userService.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	yearToDate, err := s.payrollService.GetYearToDate(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	if yearToDate.PayrollCount == 0 {
+		return nil, &internalerror.NotFoundError{}
+	}
+
+	certificate := s.buildTaxCertificate(user, yearToDate)
+	if !isWithheld(certificate) {
+		return nil, &internalerror.TaxNotWithheldError{UserIDs: []uint{userID}}
+	}
+
+	return certificate, nil
+}
+
+func (s *taxService) RenderTaxCertificatePdf(ctx context.Context, userID uint, year int) ([]byte, error) {
+	certificate, err := s.GetTaxCertificate(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderer.RenderTaxCertificate(certificate)
+}
+
+// CheckEbupot makes sure the certificate of every employee paid in the year can be issued before the file is streamed
+func (s *taxService) CheckEbupot(ctx context.Context, year int) error {
+	var userIDs []uint
+	err := s.payrollService.StreamYearToDates(ctx, year, func(yearToDate *entity.UserYearToDate) error {
+		user, err := s.userService.GetUserById(ctx, yearToDate.UserID)
+		if err != nil {
+			return err
+		}
+
+		if !isWithheld(s.buildTaxCertificate(user, yearToDate)) {
+			userIDs = append(userIDs, yearToDate.UserID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(userIDs) > 0 {
+		return &internalerror.TaxNotWithheldError{UserIDs: userIDs}
+	}
+	return nil
+}
+
+// WriteEbupotCsv streams the certificate of every employee paid in the year into w, it stops at the first certificate
+// that cannot be issued
+func (s *taxService) WriteEbupotCsv(ctx context.Context, year int, w io.Writer) error {
+	writer := taxrenderer.NewEbupotWriter(w, s.config.Tax)
+	if err := writer.WriteHeader(); err != nil {
+		return err
+	}
+
+	err := s.payrollService.StreamYearToDates(ctx, year, func(yearToDate *entity.UserYearToDate) error {
+		user, err := s.userService.GetUserById(ctx, yearToDate.UserID)
+		if err != nil {
+			return err
+		}
+
+		certificate := s.buildTaxCertificate(user, yearToDate)
+		if !isWithheld(certificate) {
+			return &internalerror.TaxNotWithheldError{UserIDs: []uint{yearToDate.UserID}}
+		}
+
+		return writer.WriteCertificate(certificate)
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
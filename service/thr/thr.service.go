@@ -0,0 +1,118 @@
+package thrservice
+
+import (
+	"context"
+	"d-payroll/entity"
+	payrollservice "d-payroll/service/payroll"
+	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"fmt"
+	"time"
+)
+
+type ThrService interface {
+	CalculateThr(ctx context.Context, calculation *entity.ThrCalculation) (*entity.ThrResult, error)
+}
+
+type thrService struct {
+	userSvc    userservice.UserService
+	payrollSvc payrollservice.PayrollService
+}
+
+func NewThrService(userSvc userservice.UserService, payrollSvc payrollservice.PayrollService) ThrService {
+	return &thrService{
+		userSvc:    userSvc,
+		payrollSvc: payrollSvc,
+	}
+}
+
+// calculateEntry follows the usual THR rule: a full monthly salary from 12 months of tenure,
+// prorated by month below that, and nothing for less than a month
+func calculateEntry(user *entity.User, salary *int, payoutAt time.Time) *entity.ThrEntry {
+	entry := &entity.ThrEntry{
+		UserID:   *user.Id,
+		JoinedAt: user.UserInfo.JoinedAt,
+	}
+
+	if salary == nil {
+		reason := entity.ThrExcludedReasonMissingSalary
+		entry.ExcludedReason = &reason
+		return entry
+	}
+	entry.MonthlySalary = *salary
+
+	if user.UserInfo.JoinedAt == nil {
+		reason := entity.ThrExcludedReasonMissingJoinedAt
+		entry.ExcludedReason = &reason
+		return entry
+	}
+
+	entry.TenureMonths = utils.GetFullMonthsBetween(*user.UserInfo.JoinedAt, payoutAt)
+	switch {
+	case entry.TenureMonths < 1:
+		reason := entity.ThrExcludedReasonTenureUnderOneMonth
+		entry.ExcludedReason = &reason
+	case entry.TenureMonths >= 12:
+		entry.Amount = entry.MonthlySalary
+	default:
+		entry.Amount = entry.MonthlySalary * entry.TenureMonths / 12
+	}
+
+	return entry
+}
+
+// CalculateThr creates a THR payroll with the eligible employees of the holiday, it is left unrolled so it can be reviewed first.
+// The THR is based on the salary effective at the payout
+func (s *thrService) CalculateThr(ctx context.Context, calculation *entity.ThrCalculation) (*entity.ThrResult, error) {
+	users, err := s.userSvc.GetUsersByRole(ctx, entity.UserRoleEmployee)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.ThrResult{
+		Entries: []*entity.ThrEntry{},
+	}
+	earnings := []*entity.PayrollEarning{}
+	for _, user := range users {
+		if user.UserInfo == nil {
+			continue
+		}
+
+		holiday := user.UserInfo.GetThrHoliday()
+		if holiday == nil || *holiday != calculation.Holiday {
+			continue
+		}
+
+		salary, err := s.userSvc.GetMonthlySalaryAt(ctx, *user.Id, calculation.PayoutAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := calculateEntry(user, salary, calculation.PayoutAt)
+		result.Entries = append(result.Entries, entry)
+		if entry.ExcludedReason != nil {
+			continue
+		}
+
+		earnings = append(earnings, &entity.PayrollEarning{
+			UserID:          entry.UserID,
+			Description:     fmt.Sprintf("THR %s (%d months tenure)", calculation.Holiday, entry.TenureMonths),
+			Amount:          entry.Amount,
+			CreatedByUserID: &calculation.CreatedByUserID,
+		})
+		result.TotalAmount += entry.Amount
+	}
+
+	result.Payroll, err = s.payrollSvc.CreateOffCyclePayroll(ctx, &entity.Payroll{
+		Name:            calculation.Name,
+		Type:            entity.PayrollTypeThr,
+		StartedAt:       calculation.PayoutAt,
+		EndedAt:         calculation.PayoutAt,
+		CreatedByUserID: &calculation.CreatedByUserID,
+	}, earnings)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
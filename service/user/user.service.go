@@ -3,8 +3,12 @@ package userservice
 import (
 	"context"
 	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
 	repository "d-payroll/repository/db"
 	"d-payroll/repository/db/models"
+	"d-payroll/utils"
+	"errors"
+	"time"
 )
 
 type UserService interface {
@@ -13,6 +17,21 @@ type UserService interface {
 	GetUserById(ctx context.Context, id uint) (*entity.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*entity.User, error)
 	GetUserIds(ctx context.Context) ([]uint, error)
+	GetUsersByRole(ctx context.Context, role entity.UserRole) ([]*entity.User, error)
+	UpdateWorkLocation(ctx context.Context, userID uint, workLocationID *uint, remoteWork bool) (*entity.User, error)
+	UpdateDevicePin(ctx context.Context, userID uint, devicePin *string) (*entity.User, error)
+	UpdateAttendancePenaltyExempt(ctx context.Context, userID uint, exempt bool) (*entity.User, error)
+	GetUsersByDevicePins(ctx context.Context, devicePins []string) ([]*entity.User, error)
+
+	ChangeSalary(ctx context.Context, salary *entity.UserSalary) (*entity.UserSalary, error)
+	GetSalaryHistory(ctx context.Context, userID uint) ([]*entity.UserSalary, error)
+	GetMonthlySalaryAt(ctx context.Context, userID uint, at time.Time) (*int, error)
+
+	GetBankAccount(ctx context.Context, userID uint) (*entity.UserBankAccount, error)
+	GetBankAccounts(ctx context.Context, userIDs []uint) ([]*entity.UserBankAccount, error)
+	RequestBankAccountChange(ctx context.Context, change *entity.UserBankAccountChange) (*entity.UserBankAccountChange, error)
+	ReviewBankAccountChange(ctx context.Context, changeID uint, reviewerID uint, approved bool, note *string) (*entity.UserBankAccountChange, error)
+	GetBankAccountChanges(ctx context.Context, userID *uint, status *entity.BankAccountChangeStatus) ([]*entity.UserBankAccountChange, error)
 }
 
 type userService struct {
@@ -24,6 +43,12 @@ func NewUserService(userDB repository.UserDB) UserService {
 }
 
 func (s *userService) CreateUser(ctx context.Context, user *entity.User) (*entity.User, error) {
+	if user.UserInfo != nil && user.UserInfo.DevicePin != nil {
+		if err := s.checkDevicePinAvailable(ctx, 0, *user.UserInfo.DevicePin); err != nil {
+			return nil, err
+		}
+	}
+
 	err := user.HashPassword()
 	if err != nil {
 		return nil, err
@@ -80,3 +105,328 @@ func (s *userService) GetUserByUsername(ctx context.Context, username string) (*
 func (s *userService) GetUserIds(ctx context.Context) ([]uint, error) {
 	return s.userDB.GetUserIds(ctx)
 }
+
+func (s *userService) GetUsersByRole(ctx context.Context, role entity.UserRole) ([]*entity.User, error) {
+	userModels, err := s.userDB.GetUsersByRole(ctx, models.UserRole(role))
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, len(userModels))
+	for i, model := range userModels {
+		users[i] = model.ToUserEntity()
+	}
+	return users, nil
+}
+
+// UpdateWorkLocation sets the site the user checks in at, remote work exempts the user from checking in there
+func (s *userService) UpdateWorkLocation(ctx context.Context, userID uint, workLocationID *uint, remoteWork bool) (*entity.User, error) {
+	if _, err := s.userDB.GetuserById(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.userDB.UpdateWorkLocation(ctx, userID, workLocationID, remoteWork); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserById(ctx, userID)
+}
+
+// UpdateDevicePin sets the PIN the user is enrolled with on the biometric devices, a PIN identifies a single user
+func (s *userService) UpdateDevicePin(ctx context.Context, userID uint, devicePin *string) (*entity.User, error) {
+	if _, err := s.userDB.GetuserById(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if devicePin != nil {
+		if err := s.checkDevicePinAvailable(ctx, userID, *devicePin); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userDB.UpdateDevicePin(ctx, userID, devicePin); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserById(ctx, userID)
+}
+
+// UpdateAttendancePenaltyExempt exempts the user from the lateness and early departure deductions, the payslips of
+// the payrolls already rolled are kept as is
+func (s *userService) UpdateAttendancePenaltyExempt(ctx context.Context, userID uint, exempt bool) (*entity.User, error) {
+	if _, err := s.userDB.GetuserById(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.userDB.UpdateAttendancePenaltyExempt(ctx, userID, exempt); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserById(ctx, userID)
+}
+
+// checkDevicePinAvailable fails when the PIN is enrolled by a user other than userID
+func (s *userService) checkDevicePinAvailable(ctx context.Context, userID uint, devicePin string) error {
+	userModels, err := s.userDB.GetUsersByDevicePins(ctx, []string{devicePin})
+	if err != nil {
+		return err
+	}
+
+	for _, model := range userModels {
+		if model.ID != userID {
+			return &internalerror.DevicePinTakenError{}
+		}
+	}
+	return nil
+}
+
+func (s *userService) GetUsersByDevicePins(ctx context.Context, devicePins []string) ([]*entity.User, error) {
+	userModels, err := s.userDB.GetUsersByDevicePins(ctx, devicePins)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*entity.User, len(userModels))
+	for i, model := range userModels {
+		users[i] = model.ToUserEntity()
+	}
+	return users, nil
+}
+
+func (s *userService) ChangeSalary(ctx context.Context, salary *entity.UserSalary) (*entity.UserSalary, error) {
+	user, err := s.userDB.GetuserById(ctx, salary.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	salaries, err := s.userDB.GetUserSalaries(ctx, salary.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the first change keeps the salary set at creation as the history baseline, otherwise
+	// payrolls before the change would be recalculated with the new salary
+	if len(salaries) == 0 && user.UserInfo != nil && user.UserInfo.MonthlySalary != nil && user.CreatedAt.Before(salary.EffectiveAt) {
+		err = s.userDB.CreateUserSalary(ctx, &models.UserSalary{
+			UserID:        salary.UserID,
+			MonthlySalary: *user.UserInfo.MonthlySalary,
+			EffectiveAt:   user.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	salaryModel := &models.UserSalary{}
+	salaryModel.FromUserSalaryEntity(salary)
+
+	err = s.userDB.CreateUserSalary(ctx, salaryModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the user info in sync with the salary effective today, future changes apply once they are due
+	currentSalary, err := s.userDB.GetUserSalaryAt(ctx, salary.UserID, utils.TimeNow())
+	if err != nil {
+		if errors.Is(err, &internalerror.NotFoundError{}) {
+			return salaryModel.ToUserSalaryEntity(), nil
+		}
+		return nil, err
+	}
+
+	err = s.userDB.UpdateMonthlySalary(ctx, salary.UserID, currentSalary.MonthlySalary)
+	if err != nil {
+		return nil, err
+	}
+
+	return salaryModel.ToUserSalaryEntity(), nil
+}
+
+func (s *userService) GetSalaryHistory(ctx context.Context, userID uint) ([]*entity.UserSalary, error) {
+	salaryModels, err := s.userDB.GetUserSalaries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	salaries := make([]*entity.UserSalary, len(salaryModels))
+	for i, model := range salaryModels {
+		salaries[i] = model.ToUserSalaryEntity()
+	}
+	return salaries, nil
+}
+
+// GetMonthlySalaryAt returns the salary effective at the given time, users without history fall back to the salary of the user info
+func (s *userService) GetMonthlySalaryAt(ctx context.Context, userID uint, at time.Time) (*int, error) {
+	salary, err := s.userDB.GetUserSalaryAt(ctx, userID, at)
+	if err == nil {
+		return &salary.MonthlySalary, nil
+	}
+	if !errors.Is(err, &internalerror.NotFoundError{}) {
+		return nil, err
+	}
+
+	user, err := s.userDB.GetuserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserInfo == nil {
+		return nil, nil
+	}
+	return user.UserInfo.MonthlySalary, nil
+}
+
+func (s *userService) GetBankAccount(ctx context.Context, userID uint) (*entity.UserBankAccount, error) {
+	account, err := s.userDB.GetUserBankAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.ToUserBankAccountEntity(), nil
+}
+
+func (s *userService) GetBankAccounts(ctx context.Context, userIDs []uint) ([]*entity.UserBankAccount, error) {
+	accountModels, err := s.userDB.GetUserBankAccounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*entity.UserBankAccount, len(accountModels))
+	for i, model := range accountModels {
+		accounts[i] = model.ToUserBankAccountEntity()
+	}
+	return accounts, nil
+}
+
+// RequestBankAccountChange records a bank account change waiting for the review of another user,
+// a user has at most one pending change
+func (s *userService) RequestBankAccountChange(ctx context.Context, change *entity.UserBankAccountChange) (*entity.UserBankAccountChange, error) {
+	switch change.PaymentMethod {
+	case entity.PaymentMethodTransfer:
+		if !change.BankCode.IsValid() {
+			return nil, &internalerror.BankAccountMethodMismatchError{}
+		}
+	case entity.PaymentMethodEwallet:
+		if !change.BankCode.IsEwallet() {
+			return nil, &internalerror.BankAccountMethodMismatchError{}
+		}
+	}
+
+	if _, err := s.userDB.GetuserById(ctx, change.UserID); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.userDB.HasPendingBankAccountChange(ctx, change.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending {
+		return nil, &internalerror.BankAccountChangePendingError{}
+	}
+
+	change.Status = entity.BankAccountChangeStatusPending
+
+	var changeModel models.UserBankAccountChange
+	changeModel.FromUserBankAccountChangeEntity(change)
+
+	if err := s.userDB.CreateBankAccountChange(ctx, &changeModel); err != nil {
+		return nil, err
+	}
+
+	return changeModel.ToUserBankAccountChangeEntity(), nil
+}
+
+// ReviewBankAccountChange approves or rejects a pending change, the requester cannot review their own change and the
+// reviewer has the other role than the requester. An approved change replaces the bank account of the user
+func (s *userService) ReviewBankAccountChange(ctx context.Context, changeID uint, reviewerID uint, approved bool, note *string) (*entity.UserBankAccountChange, error) {
+	change, err := s.userDB.GetBankAccountChangeByID(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if change.Status != models.BankAccountChangeStatusPending {
+		return nil, &internalerror.BankAccountChangeReviewedError{}
+	}
+
+	if change.RequestedByUserID == reviewerID {
+		return nil, &internalerror.BankAccountChangeSelfReviewError{}
+	}
+
+	if err := s.checkReviewerRole(ctx, change, reviewerID); err != nil {
+		return nil, err
+	}
+
+	reviewedAt := utils.TimeNow()
+	change.ReviewedByUserID = &reviewerID
+	change.ReviewedAt = &reviewedAt
+	change.ReviewNote = note
+
+	if !approved {
+		change.Status = models.BankAccountChangeStatusRejected
+		if err := s.userDB.UpdateBankAccountChange(ctx, change); err != nil {
+			return nil, err
+		}
+		return change.ToUserBankAccountChangeEntity(), nil
+	}
+
+	account, err := s.userDB.GetUserBankAccount(ctx, change.UserID)
+	if err != nil {
+		if !errors.Is(err, &internalerror.NotFoundError{}) {
+			return nil, err
+		}
+		account = &models.UserBankAccount{}
+	}
+
+	change.Status = models.BankAccountChangeStatusApproved
+	change.ApplyTo(account)
+
+	if err := s.userDB.ApplyBankAccountChange(ctx, change, account); err != nil {
+		return nil, err
+	}
+
+	return change.ToUserBankAccountChangeEntity(), nil
+}
+
+// checkReviewerRole fails unless the change requested by an admin is reviewed by finance and the change requested by
+// finance by an admin, the maker and the checker come from the two roles. Nobody reviews their own bank account
+func (s *userService) checkReviewerRole(ctx context.Context, change *models.UserBankAccountChange, reviewerID uint) error {
+	if change.UserID == reviewerID {
+		return &internalerror.BankAccountChangeOwnAccountReviewError{}
+	}
+
+	requester, err := s.userDB.GetuserById(ctx, change.RequestedByUserID)
+	if err != nil {
+		return err
+	}
+	reviewer, err := s.userDB.GetuserById(ctx, reviewerID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case requester.Role == models.UserRoleAdmin && reviewer.Role == models.UserRoleFinance:
+	case requester.Role == models.UserRoleFinance && reviewer.Role == models.UserRoleAdmin:
+	default:
+		return &internalerror.BankAccountChangeReviewerRoleError{}
+	}
+	return nil
+}
+
+func (s *userService) GetBankAccountChanges(ctx context.Context, userID *uint, status *entity.BankAccountChangeStatus) ([]*entity.UserBankAccountChange, error) {
+	var statusModel *models.BankAccountChangeStatus
+	if status != nil {
+		converted := models.BankAccountChangeStatus(*status)
+		statusModel = &converted
+	}
+
+	changeModels, err := s.userDB.GetBankAccountChanges(ctx, userID, statusModel)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*entity.UserBankAccountChange, len(changeModels))
+	for i, model := range changeModels {
+		changes[i] = model.ToUserBankAccountChangeEntity()
+	}
+	return changes, nil
+}
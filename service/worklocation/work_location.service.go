@@ -0,0 +1,157 @@
+package worklocationservice
+
+import (
+	"context"
+	"d-payroll/config"
+	"d-payroll/entity"
+	internalerror "d-payroll/internal-error"
+	repository "d-payroll/repository/db"
+	"d-payroll/repository/db/models"
+	userservice "d-payroll/service/user"
+	"d-payroll/utils"
+	"net"
+	"strings"
+)
+
+type WorkLocationService interface {
+	CreateWorkLocation(ctx context.Context, workLocation *entity.WorkLocation) (*entity.WorkLocation, error)
+	GetWorkLocations(ctx context.Context) ([]*entity.WorkLocation, error)
+
+	AssignWorkLocation(ctx context.Context, userID uint, workLocationID *uint, remoteWork bool) (*entity.User, error)
+	CheckAttendanceEvidence(ctx context.Context, userID uint, evidence *entity.AttendanceEvidence) (*entity.WorkLocationCheck, error)
+}
+
+type workLocationService struct {
+	config         *config.Config
+	workLocationDB repository.WorkLocationDB
+	userSvc        userservice.UserService
+}
+
+func NewWorkLocationService(config *config.Config, workLocationDB repository.WorkLocationDB, userSvc userservice.UserService) WorkLocationService {
+	return &workLocationService{
+		config:         config,
+		workLocationDB: workLocationDB,
+		userSvc:        userSvc,
+	}
+}
+
+// CreateWorkLocation creates a site, it needs an area, a radius around its center or a polygon, or a network to be
+// checked in from
+func (s *workLocationService) CreateWorkLocation(ctx context.Context, workLocation *entity.WorkLocation) (*entity.WorkLocation, error) {
+	hasRadius := workLocation.Latitude != nil && workLocation.Longitude != nil && workLocation.RadiusMeters != nil
+	if !hasRadius && len(workLocation.Polygon) == 0 && len(workLocation.WifiBSSIDs) == 0 && len(workLocation.IPRanges) == 0 {
+		return nil, &internalerror.WorkLocationAreaRequiredError{}
+	}
+
+	for i, bssid := range workLocation.WifiBSSIDs {
+		workLocation.WifiBSSIDs[i] = strings.ToLower(bssid)
+	}
+
+	workLocationModel := &models.WorkLocation{}
+	workLocationModel.FromWorkLocationEntity(workLocation)
+
+	err := s.workLocationDB.CreateWorkLocation(ctx, workLocationModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return workLocationModel.ToWorkLocationEntity(), nil
+}
+
+func (s *workLocationService) GetWorkLocations(ctx context.Context) ([]*entity.WorkLocation, error) {
+	workLocationModels, err := s.workLocationDB.GetWorkLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workLocations := make([]*entity.WorkLocation, len(workLocationModels))
+	for i, workLocationModel := range workLocationModels {
+		workLocations[i] = workLocationModel.ToWorkLocationEntity()
+	}
+
+	return workLocations, nil
+}
+
+// AssignWorkLocation sets the site the user checks in at, a nil work location stops verifying the check-ins of the user
+func (s *workLocationService) AssignWorkLocation(ctx context.Context, userID uint, workLocationID *uint, remoteWork bool) (*entity.User, error) {
+	if workLocationID != nil {
+		if _, err := s.workLocationDB.GetWorkLocationByID(ctx, *workLocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.userSvc.UpdateWorkLocation(ctx, userID, workLocationID, remoteWork)
+}
+
+// CheckAttendanceEvidence verifies a check-in was made within the work location of the user. The users without a
+// work location are not verified
+func (s *workLocationService) CheckAttendanceEvidence(ctx context.Context, userID uint, evidence *entity.AttendanceEvidence) (*entity.WorkLocationCheck, error) {
+	user, err := s.userSvc.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &entity.WorkLocationCheck{}
+	if user.UserInfo == nil || user.UserInfo.WorkLocationID == nil {
+		return check, nil
+	}
+
+	check.WorkLocationID = user.UserInfo.WorkLocationID
+	if user.UserInfo.RemoteWork {
+		check.IsRemote = true
+		return check, nil
+	}
+
+	workLocationModel, err := s.workLocationDB.GetWorkLocationByID(ctx, *user.UserInfo.WorkLocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	workLocation := workLocationModel.ToWorkLocationEntity()
+	check.Policy = workLocation.Policy
+	check.IsOutOfArea = evidence == nil || !isWithinWorkLocation(workLocation, evidence)
+
+	return check, nil
+}
+
+// isWithinWorkLocation tells whether the evidence places the attendance at the work location. The coordinates are
+// judged first when they are given and the location has an area, a Wi-Fi access point or an IP range is only checked
+// otherwise, as a BSSID is broadcast publicly and cannot outweigh coordinates out of the area
+func isWithinWorkLocation(workLocation *entity.WorkLocation, evidence *entity.AttendanceEvidence) bool {
+	if evidence.Latitude != nil && evidence.Longitude != nil {
+		if len(workLocation.Polygon) >= 3 {
+			latitudes := make([]float64, len(workLocation.Polygon))
+			longitudes := make([]float64, len(workLocation.Polygon))
+			for i, point := range workLocation.Polygon {
+				latitudes[i] = point.Latitude
+				longitudes[i] = point.Longitude
+			}
+			return utils.PolygonContains(latitudes, longitudes, *evidence.Latitude, *evidence.Longitude)
+		}
+
+		if workLocation.Latitude != nil && workLocation.Longitude != nil && workLocation.RadiusMeters != nil {
+			distance := utils.DistanceMeters(*workLocation.Latitude, *workLocation.Longitude, *evidence.Latitude, *evidence.Longitude)
+			return distance <= *workLocation.RadiusMeters
+		}
+	}
+
+	if evidence.WifiBSSID != nil {
+		for _, bssid := range workLocation.WifiBSSIDs {
+			if strings.EqualFold(bssid, *evidence.WifiBSSID) {
+				return true
+			}
+		}
+	}
+
+	if evidence.IPAddress != nil {
+		if ip := net.ParseIP(*evidence.IPAddress); ip != nil {
+			for _, ipRange := range workLocation.IPRanges {
+				if _, network, err := net.ParseCIDR(ipRange); err == nil && network.Contains(ip) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
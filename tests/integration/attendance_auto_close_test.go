@@ -0,0 +1,217 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceAutoClose(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	sink := startSmtpSink(t)
+	testApp.Config.Smtp.Port = sink.port()
+
+	salary := 5000000
+	email := "employee-auto-close@test.example"
+	employee, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+		Username: "employee-auto-close",
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+			Email:         &email,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+	employeeID := *employee.Id
+	employeeToken, err := utils.GenerateToken(testApp.Config.Auth.JwtSecret, &entity.AuthTokenPayload{
+		ID:   employeeID,
+		Role: entity.UserRoleEmployee,
+	})
+	require.NoError(t, err, "Failed to generate employee token")
+
+	noEmailID, noEmailToken := testApp.createEmployee(t, "employee-auto-close-no-mail", 5000000)
+
+	attend := func(t *testing.T, path string, at time.Time, token string) int {
+		now = at
+		status, _ := testApp.doJSONRequest(t, "POST", path, nil, token)
+		return status
+	}
+
+	autoClose := func(t *testing.T, at time.Time) []*entity.UserAttendance {
+		now = at
+		closed, err := testApp.AttendanceJob.AutoCloseMissingCheckouts(testApp.ctx)
+		require.NoError(t, err, "Expected the auto-close to succeed")
+		return closed
+	}
+
+	var mondayCheckout *entity.UserAttendance
+	t.Run("Close After Cutoff", func(t *testing.T) {
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local), employeeToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local), noEmailToken))
+
+		assert.Empty(t, autoClose(t, time.Date(2025, 6, 16, 18, 30, 0, 0, time.Local)), "The sessions should stay open until the cutoff")
+
+		closed := autoClose(t, time.Date(2025, 6, 16, 19, 30, 0, 0, time.Local))
+		require.Len(t, closed, 2, "Both open sessions should be closed")
+		for _, checkout := range closed {
+			assert.Equal(t, entity.AttendanceTypeCheckOut, checkout.Type, "A checkout should be added")
+			assert.True(t, checkout.CreatedAt.Equal(time.Date(2025, 6, 16, 17, 0, 0, 0, time.Local)), "The session should be closed at the end of the shift")
+			require.NotNil(t, checkout.AutoClosePolicy, "The policy should be recorded")
+			assert.Equal(t, entity.AttendanceAutoClosePolicyRequireCorrection, *checkout.AutoClosePolicy, "The configured policy should be recorded")
+			if checkout.UserID == employeeID {
+				mondayCheckout = checkout
+			}
+		}
+		require.NotNil(t, mondayCheckout, "The session of the employee should be closed")
+
+		messages := sink.received()
+		require.Len(t, messages, 1, "Only the employee with an email address should be notified")
+		assert.Contains(t, messages[0], "To: "+email, "Message should be addressed to the employee")
+
+		assert.Empty(t, autoClose(t, time.Date(2025, 6, 16, 20, 0, 0, 0, time.Local)), "A closed session should not be closed again")
+		assert.Equal(t, fiber.StatusConflict, attend(t, "/attendances/checkout", time.Date(2025, 6, 16, 20, 0, 0, 0, time.Local), noEmailToken), "An auto-closed session should be checked out")
+	})
+
+	t.Run("Close Before Next Checkin", func(t *testing.T) {
+		testApp.Config.Attendance.AutoClosePolicy = string(entity.AttendanceAutoClosePolicyPayScheduled)
+		defer func() {
+			testApp.Config.Attendance.AutoClosePolicy = string(entity.AttendanceAutoClosePolicyRequireCorrection)
+		}()
+
+		// both forget to check out on Tuesday, the job does not run until Wednesday morning
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 9, 0, 0, 0, time.Local), employeeToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 9, 0, 0, 0, time.Local), noEmailToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 18, 9, 0, 0, 0, time.Local), noEmailToken))
+
+		closed := autoClose(t, time.Date(2025, 6, 18, 10, 0, 0, 0, time.Local))
+		require.Len(t, closed, 2, "Only the Tuesday sessions should be closed")
+		for _, checkout := range closed {
+			assert.True(t, checkout.CreatedAt.Equal(time.Date(2025, 6, 17, 17, 0, 0, 0, time.Local)), "The session should be closed at the end of its shift")
+			assert.Equal(t, entity.AttendanceAutoClosePolicyPayScheduled, *checkout.AutoClosePolicy, "The configured policy should be recorded")
+		}
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/attendances/report?user_id=%d&start_date=2025-06-16&end_date=2025-06-17", employeeID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected attendance report")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+		require.Len(t, entries, 1, "Only the requested employee should be listed")
+		assert.Equal(t, float64(2), entries[0]["missing_checkouts"], "Auto-closed sessions should be missing checkouts")
+		assert.Equal(t, float64(0), entries[0]["early_departures"], "Auto-closed sessions should not be early")
+		assert.Equal(t, float64(0), entries[0]["total_hours"], "Auto-closed sessions should not be worked")
+	})
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+
+	payslip := func(t *testing.T, userID uint) dto.PayslipDto {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *payroll.ID, userID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		require.NotNil(t, payslip.Attendance, "Payslip should pay attendance")
+		return payslip
+	}
+
+	t.Run("Payslip", func(t *testing.T) {
+		details := payslip(t, noEmailID).Attendance.Details
+		require.Len(t, details, 3, "Every session should be listed")
+
+		require.NotNil(t, details[0].AutoClosePolicy, "The policy should be on the payslip line")
+		assert.Equal(t, "REQUIRE_CORRECTION", *details[0].AutoClosePolicy, "The policy should be on the payslip line")
+		assert.Equal(t, 0, details[0].DurationMilis, "A session to correct should not be paid")
+
+		require.NotNil(t, details[1].AutoClosePolicy, "The policy should be on the payslip line")
+		assert.Equal(t, "PAY_SCHEDULED", *details[1].AutoClosePolicy, "The policy should be on the payslip line")
+		assert.Equal(t, 8*60*60*1000, details[1].DurationMilis, "The scheduled day should be paid")
+
+		assert.Nil(t, details[2].AutoClosePolicy, "A session still open should not be auto-closed")
+		assert.True(t, details[2].MissingCheckout, "A session still open should be flagged")
+		assert.Equal(t, 0, details[2].DurationMilis, "A session still open should not be paid")
+	})
+
+	t.Run("Corrected", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/attendance-corrections", dto.RequestAttendanceCorrectionBodyDto{
+			Type:         "WRONG_TIME",
+			AttendanceID: mondayCheckout.ID,
+			CorrectedAt:  time.Date(2025, 6, 16, 17, 30, 0, 0, time.Local),
+			Reason:       "Forgot to check out",
+		}, employeeToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected the correction to be requested")
+
+		var correction dto.AttendanceCorrectionResponseDto
+		decodeData(t, response.Data, &correction)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/attendance-corrections/%d/approve", *correction.ID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the approval to succeed")
+
+		details := payslip(t, employeeID).Attendance.Details
+		require.Len(t, details, 2, "Every session should be listed")
+		assert.Nil(t, details[0].AutoClosePolicy, "The corrected checkout should replace the auto-closed one")
+		assert.Equal(t, 8*60*60*1000, details[0].DurationMilis, "The corrected session should be paid")
+	})
+
+	t.Run("Missing Checkout Corrected", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/attendance-corrections", dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "MISSING_CHECKOUT",
+			CorrectedAt: time.Date(2025, 6, 17, 18, 0, 0, 0, time.Local),
+			Reason:      "Forgot to check out",
+		}, employeeToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected the correction to be requested")
+
+		var correction dto.AttendanceCorrectionResponseDto
+		decodeData(t, response.Data, &correction)
+		assert.NotNil(t, correction.AttendanceID, "The auto-closed checkout should be named")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/attendance-corrections/%d/approve", *correction.ID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the approval to succeed")
+
+		details := payslip(t, employeeID).Attendance.Details
+		require.Len(t, details, 2, "Every session should be listed")
+		assert.Nil(t, details[1].AutoClosePolicy, "The checkout should replace the auto-closed one")
+		require.NotNil(t, details[1].CheckoutAt, "The session should be checked out")
+		assert.True(t, details[1].CheckoutAt.Equal(time.Date(2025, 6, 17, 18, 0, 0, 0, time.Local)), "The corrected checkout should close the session")
+		assert.Equal(t, 8*60*60*1000, details[1].DurationMilis, "The corrected session should be paid")
+	})
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceSessionsAndBreaks(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-attendance-break", 5000000)
+
+	attend := func(t *testing.T, path string, at time.Time) int {
+		now = at
+		status, _ := testApp.doJSONRequest(t, "POST", path, nil, employeeToken)
+		return status
+	}
+
+	t.Run("Breaks", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusUnprocessableEntity, attend(t, "/attendances/break-start", time.Date(2025, 6, 16, 8, 0, 0, 0, time.Local)), "A break should need a check-in")
+
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 8, 55, 0, 0, time.Local)))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/break-start", time.Date(2025, 6, 16, 12, 0, 0, 0, time.Local)))
+		assert.Equal(t, fiber.StatusConflict, attend(t, "/attendances/break-start", time.Date(2025, 6, 16, 12, 5, 0, 0, time.Local)), "A break should not start twice")
+		assert.Equal(t, fiber.StatusConflict, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 12, 10, 0, 0, time.Local)), "The session should still be open on break")
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/break-end", time.Date(2025, 6, 16, 12, 45, 0, 0, time.Local)))
+		assert.Equal(t, fiber.StatusUnprocessableEntity, attend(t, "/attendances/break-end", time.Date(2025, 6, 16, 12, 50, 0, 0, time.Local)), "A break should not end twice")
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 16, 14, 0, 0, 0, time.Local)))
+		assert.Equal(t, fiber.StatusUnprocessableEntity, attend(t, "/attendances/break-start", time.Date(2025, 6, 16, 14, 5, 0, 0, time.Local)), "A break should not start after the checkout")
+	})
+
+	t.Run("Multiple Sessions", func(t *testing.T) {
+		// back from a site visit on Monday
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 15, 0, 0, 0, time.Local)), "A second session should be allowed on the same day")
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 16, 17, 30, 0, 0, time.Local)))
+
+		// more sessions on Tuesday than its working duration
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 9, 0, 0, 0, time.Local)))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 17, 19, 0, 0, 0, time.Local)))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 20, 0, 0, 0, time.Local)))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 17, 21, 0, 0, 0, time.Local)))
+		now = time.Date(2025, 6, 18, 9, 0, 0, 0, time.Local)
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/attendances/report?user_id=%d&start_date=2025-06-16&end_date=2025-06-16", employeeID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected attendance report")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+		require.Len(t, entries, 1, "Only the requested employee should be listed")
+		assert.Equal(t, float64(1), entries[0]["present_days"], "Both sessions should be a single day")
+		assert.Equal(t, float64(0), entries[0]["late_arrivals"], "Lateness should follow the first check-in")
+		assert.Equal(t, float64(0), entries[0]["early_departures"], "Early departure should follow the last checkout")
+		assert.Equal(t, 6.83, entries[0]["total_hours"], "Both sessions should be worked less the break")
+	})
+
+	t.Run("Payslip", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "June 2025",
+			StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *payroll.ID, employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		require.NotNil(t, payslip.Attendance, "Payslip should pay attendance")
+		require.Len(t, payslip.Attendance.Details, 4, "Each session should be listed")
+
+		details := payslip.Attendance.Details
+		assert.Equal(t, 45*60*1000, details[0].BreakDurationMilis, "The break should be listed on its session")
+		assert.Equal(t, (4*60+20)*60*1000, details[0].DurationMilis, "The break should be unpaid")
+		assert.Equal(t, (2*60+30)*60*1000, details[1].DurationMilis, "The second session should be paid")
+		assert.Equal(t, 8*60*60*1000, details[2].DurationMilis, "A session should be paid up to the working duration")
+		assert.Equal(t, 0, details[3].DurationMilis, "The sessions past the working duration should not be paid")
+		assert.Equal(t, (14*60+50)*60*1000, payslip.Attendance.TotalDurationMilis, "Every session should be paid less the breaks")
+	})
+}
@@ -0,0 +1,312 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceCorrections(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-attendance-correction", 5000000)
+	otherID, otherToken := testApp.createEmployee(t, "employee-attendance-correction-other", 5000000)
+
+	attend := func(t *testing.T, path string, at time.Time, token string) dto.AttendanceResponseDto {
+		now = at
+		status, response := testApp.doJSONRequest(t, "POST", path, nil, token)
+		require.Equal(t, fiber.StatusOK, status, "Expected %s to succeed", path)
+
+		var attendance dto.AttendanceResponseDto
+		decodeData(t, response.Data, &attendance)
+		return attendance
+	}
+
+	getAttendances := func(t *testing.T, userID uint) []dto.AttendanceResponseDto {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/attendances?user_id=%d", userID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected attendances")
+
+		var attendances []dto.AttendanceResponseDto
+		decodeData(t, response.Data, &attendances)
+		return attendances
+	}
+
+	// forgot to check out on Monday, checked in at 09:30 instead of 09:00 on Tuesday
+	attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local), employeeToken)
+	wrongCheckin := attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 9, 30, 0, 0, time.Local), employeeToken)
+	attend(t, "/attendances/checkout", time.Date(2025, 6, 17, 17, 0, 0, 0, time.Local), employeeToken)
+	otherCheckin := attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 9, 0, 0, 0, time.Local), otherToken)
+	now = time.Date(2025, 6, 18, 10, 0, 0, 0, time.Local)
+
+	request := func(t *testing.T, body dto.RequestAttendanceCorrectionBodyDto) (int, dto.AttendanceCorrectionResponseDto) {
+		status, response := testApp.doJSONRequest(t, "POST", "/attendance-corrections", body, employeeToken)
+
+		var correction dto.AttendanceCorrectionResponseDto
+		if status == fiber.StatusAccepted {
+			decodeData(t, response.Data, &correction)
+		}
+		return status, correction
+	}
+
+	var missingCheckout, wrongTime, missingCheckin dto.AttendanceCorrectionResponseDto
+	t.Run("Request Corrections", func(t *testing.T) {
+		status, _ := request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "WRONG_TIME",
+			CorrectedAt: time.Date(2025, 6, 17, 9, 0, 0, 0, time.Local),
+			Reason:      "Checked in late by mistake",
+		})
+		assert.Equal(t, fiber.StatusBadRequest, status, "A wrong time should name its attendance")
+
+		status, _ = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "MISSING_CHECKOUT",
+			CorrectedAt: time.Date(2025, 6, 18, 17, 0, 0, 0, time.Local),
+			Reason:      "Leaving early",
+		})
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A correction should not be in the future")
+
+		status, _ = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:         "WRONG_TIME",
+			AttendanceID: otherCheckin.Id,
+			CorrectedAt:  time.Date(2025, 6, 17, 8, 0, 0, 0, time.Local),
+			Reason:       "Not mine",
+		})
+		assert.Equal(t, fiber.StatusNotFound, status, "The attendances of another employee should not be corrected")
+
+		status, _ = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "MISSING_CHECKOUT",
+			CorrectedAt: time.Date(2025, 6, 15, 17, 0, 0, 0, time.Local),
+			Reason:      "Worked on Sunday",
+		})
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A missing checkout should close a check-in")
+
+		status, _ = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "MISSING_CHECKOUT",
+			CorrectedAt: time.Date(2025, 6, 17, 18, 0, 0, 0, time.Local),
+			Reason:      "Stayed late",
+		})
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A session already checked out should not get another checkout")
+
+		status, _ = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:         "WRONG_TIME",
+			AttendanceID: wrongCheckin.Id,
+			CorrectedAt:  time.Date(2025, 6, 17, 18, 0, 0, 0, time.Local),
+			Reason:       "Checked in after leaving",
+		})
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A check-in should not move past its checkout")
+
+		status, _ = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "MISSING_CHECKIN",
+			CorrectedAt: time.Date(2025, 6, 17, 12, 0, 0, 0, time.Local),
+			Reason:      "Back from lunch",
+		})
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A missing check-in should not overlap another session")
+
+		status, missingCheckout = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "MISSING_CHECKOUT",
+			CorrectedAt: time.Date(2025, 6, 16, 17, 0, 0, 0, time.Local),
+			Reason:      "Forgot to check out",
+		})
+		require.Equal(t, fiber.StatusAccepted, status, "Expected the correction to be requested")
+		assert.Equal(t, "PENDING", missingCheckout.Status, "The correction should wait for a review")
+
+		status, wrongTime = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:         "WRONG_TIME",
+			AttendanceID: wrongCheckin.Id,
+			CorrectedAt:  time.Date(2025, 6, 17, 9, 0, 0, 0, time.Local),
+			Reason:       "The badge reader was down",
+		})
+		require.Equal(t, fiber.StatusAccepted, status, "Expected the correction to be requested")
+
+		status, missingCheckin = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:        "MISSING_CHECKIN",
+			CorrectedAt: time.Date(2025, 6, 13, 9, 0, 0, 0, time.Local),
+			Reason:      "Worked on Friday",
+		})
+		require.Equal(t, fiber.StatusAccepted, status, "Expected the correction to be requested")
+
+		// nothing is applied before the review
+		for _, attendance := range getAttendances(t, employeeID) {
+			assert.Nil(t, attendance.SupersededByCorrectionID, "A pending correction should not supersede")
+		}
+	})
+
+	review := func(t *testing.T, correctionID *uint, action string) (int, dto.AttendanceCorrectionResponseDto) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/attendance-corrections/%d/%s", *correctionID, action), map[string]string{"note": "Checked with the team lead"}, testApp.AdminToken)
+
+		var correction dto.AttendanceCorrectionResponseDto
+		if status == fiber.StatusOK {
+			decodeData(t, response.Data, &correction)
+		}
+		return status, correction
+	}
+
+	t.Run("Review Corrections", func(t *testing.T) {
+		status, correction := review(t, missingCheckin.ID, "reject")
+		require.Equal(t, fiber.StatusOK, status, "Expected the rejection to succeed")
+		assert.Equal(t, "REJECTED", correction.Status, "The correction should be rejected")
+
+		status, _ = review(t, missingCheckin.ID, "approve")
+		assert.Equal(t, fiber.StatusConflict, status, "A reviewed correction should not be reviewed again")
+
+		status, correction = review(t, missingCheckout.ID, "approve")
+		require.Equal(t, fiber.StatusOK, status, "Expected the approval to succeed")
+		assert.Equal(t, "APPROVED", correction.Status, "The correction should be approved")
+		require.NotNil(t, correction.ReviewNote, "The review note should be stored")
+
+		status, _ = review(t, wrongTime.ID, "approve")
+		require.Equal(t, fiber.StatusOK, status, "Expected the approval to succeed")
+
+		status, _ = request(t, dto.RequestAttendanceCorrectionBodyDto{
+			Type:         "WRONG_TIME",
+			AttendanceID: wrongCheckin.Id,
+			CorrectedAt:  time.Date(2025, 6, 17, 8, 0, 0, 0, time.Local),
+			Reason:       "Earlier still",
+		})
+		assert.Equal(t, fiber.StatusConflict, status, "A superseded attendance should not be corrected again")
+	})
+
+	t.Run("Audit Trail", func(t *testing.T) {
+		attendances := getAttendances(t, employeeID)
+		require.Len(t, attendances, 5, "The original attendances should be kept beside the corrected ones")
+
+		var corrected int
+		for _, attendance := range attendances {
+			if *attendance.Id == *wrongCheckin.Id {
+				require.NotNil(t, attendance.SupersededByCorrectionID, "The wrong check-in should be superseded")
+				assert.Equal(t, *wrongTime.ID, *attendance.SupersededByCorrectionID, "The wrong check-in should name its correction")
+				assert.True(t, attendance.CreatedAt.Equal(*wrongCheckin.CreatedAt), "The wrong check-in should not be overwritten")
+			}
+			if attendance.CorrectionID != nil {
+				corrected++
+			}
+		}
+		assert.Equal(t, 2, corrected, "The approved corrections should add an attendance each")
+
+		status, response := testApp.doJSONRequest(t, "GET", "/attendance-corrections", nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the corrections")
+
+		var corrections []dto.AttendanceCorrectionResponseDto
+		decodeData(t, response.Data, &corrections)
+		assert.Len(t, corrections, 3, "The employee should see their own corrections")
+
+		status, _ = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/attendance-corrections?user_id=%d", employeeID), nil, otherToken)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "An employee should not see the corrections of another")
+
+		status, response = testApp.doJSONRequest(t, "GET", "/attendance-corrections?status=PENDING", nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the corrections")
+		decodeData(t, response.Data, &corrections)
+		assert.Empty(t, corrections, "Every correction should be reviewed")
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/attendances/report?user_id=%d&start_date=2025-06-16&end_date=2025-06-17", employeeID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected attendance report")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+		require.Len(t, entries, 1, "Only the requested employee should be listed")
+		assert.Equal(t, float64(0), entries[0]["missing_checkouts"], "The missing checkout should be corrected")
+		assert.Equal(t, float64(0), entries[0]["late_arrivals"], "The corrected check-in should be on time")
+		assert.Equal(t, float64(16), entries[0]["total_hours"], "Both days should be worked in full")
+	})
+
+	t.Run("Payslip", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "June 2025",
+			StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		payslip := func(t *testing.T, userID uint) dto.PayslipDto {
+			status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *payroll.ID, userID), nil, testApp.AdminToken)
+			require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+			var payslip dto.PayslipDto
+			decodeData(t, response.Data, &payslip)
+			require.NotNil(t, payslip.Attendance, "Payslip should pay attendance")
+			return payslip
+		}
+
+		corrected := payslip(t, employeeID)
+		require.Len(t, corrected.Attendance.Details, 2, "The superseded check-in should not be paid")
+		assert.False(t, corrected.Attendance.Details[0].MissingCheckout, "The Monday session should be checked out")
+		assert.True(t, corrected.Attendance.Details[1].CheckinAt.Equal(time.Date(2025, 6, 17, 9, 0, 0, 0, time.Local)), "The corrected check-in should be paid")
+		assert.Equal(t, 2*8*60*60*1000, corrected.Attendance.TotalDurationMilis, "Both days should be paid in full")
+
+		uncorrected := payslip(t, otherID)
+		require.Len(t, uncorrected.Attendance.Details, 1, "The session should be paid")
+		assert.True(t, uncorrected.Attendance.Details[0].MissingCheckout, "The missing checkout should be flagged")
+	})
+
+	t.Run("Checkout Without Checkin", func(t *testing.T) {
+		orphanID, orphanToken := testApp.createEmployee(t, "employee-attendance-correction-orphan", 5000000)
+		checkin := attend(t, "/attendances/checkin", time.Date(2025, 6, 12, 9, 0, 0, 0, time.Local), orphanToken)
+		attend(t, "/attendances/checkout", time.Date(2025, 6, 12, 17, 0, 0, 0, time.Local), orphanToken)
+		now = time.Date(2025, 6, 18, 10, 0, 0, 0, time.Local)
+
+		// the check-in moved back past the max session length leaves the checkout on its own
+		status, response := testApp.doJSONRequest(t, "POST", "/attendance-corrections", dto.RequestAttendanceCorrectionBodyDto{
+			Type:         "WRONG_TIME",
+			AttendanceID: checkin.Id,
+			CorrectedAt:  time.Date(2025, 6, 11, 23, 0, 0, 0, time.Local),
+			Reason:       "Started the night before",
+		}, orphanToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected the correction to be requested")
+
+		var correction dto.AttendanceCorrectionResponseDto
+		decodeData(t, response.Data, &correction)
+		status, _ = review(t, correction.ID, "approve")
+		require.Equal(t, fiber.StatusOK, status, "Expected the approval to succeed")
+
+		status, response = testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "June 2025 Orphan",
+			StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *payroll.ID, orphanID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		require.Len(t, payslip.Attendance.Details, 2, "The check-in and the checkout should be apart")
+		require.NotNil(t, payslip.Attendance.Details[1].CheckoutAt, "The second session should be the checkout")
+		assert.Equal(t, 0, payslip.Attendance.Details[1].DurationMilis, "A checkout without its check-in should not be paid")
+	})
+}
@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceDevicePush(t *testing.T) {
+	// Mock time.Now to be Monday at 8pm
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 20, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 8:00 PM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	salary := 5000000
+	pin := "2001"
+	employee, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+		Username: "employee-device-push",
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+			DevicePin:     &pin,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+	employeeID := *employee.Id
+
+	push := func(t *testing.T, apiKey string, body dto.PushAttendanceDeviceRecordsBodyDto) (int, entity.HttpResponse) {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err, "Failed to marshal request body")
+
+		req, err := testApp.makeAuthenticatedRequest("POST", "/attendance-devices/push", payload, "")
+		require.NoError(t, err, "Failed to create request")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Device-Key", apiKey)
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var response entity.HttpResponse
+		require.NoError(t, json.Unmarshal(respBody, &response), "Response should be valid json")
+		return resp.StatusCode, response
+	}
+
+	var device dto.AttendanceDeviceResponseDto
+	t.Run("Register Device", func(t *testing.T) {
+		body := dto.RegisterAttendanceDeviceBodyDto{SerialNumber: "CKJY201560123", Name: "Lobby"}
+
+		status, _ := testApp.doJSONRequest(t, "POST", "/attendance-devices", body, "")
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Only an admin should register a device")
+
+		status, response := testApp.doJSONRequest(t, "POST", "/attendance-devices", body, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the device to be registered")
+		decodeData(t, response.Data, &device)
+		require.NotNil(t, device.ApiKey, "The API key should be returned once registered")
+
+		status, _ = testApp.doJSONRequest(t, "POST", "/attendance-devices", body, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusConflict, status, "A serial number should identify a single device")
+
+		status, response = testApp.doJSONRequest(t, "GET", "/attendance-devices", nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the devices")
+		var devices []dto.AttendanceDeviceResponseDto
+		decodeData(t, response.Data, &devices)
+		require.Len(t, devices, 1, "The registered device should be listed")
+		assert.Nil(t, devices[0].ApiKey, "The API key should not be listed")
+	})
+
+	records := dto.PushAttendanceDeviceRecordsBodyDto{
+		SerialNumber: "CKJY201560123",
+		Records: []*dto.AttendanceDeviceRecordDto{
+			{Pin: "2001", PunchedAt: "2025-06-16 08:58:00", State: "0"},
+			{Pin: "2001", PunchedAt: "2025-06-16 17:02:00", State: "1"},
+			{Pin: "2001", PunchedAt: "16 June", State: "1"},
+		},
+	}
+
+	t.Run("Reject Unknown Device", func(t *testing.T) {
+		status, _ := push(t, "", records)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "A push without key should be rejected")
+
+		status, _ = push(t, "not-a-device-key", records)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "A push with an unknown key should be rejected")
+
+		status, _ = push(t, *device.ApiKey, dto.PushAttendanceDeviceRecordsBodyDto{SerialNumber: "OTHER", Records: records.Records})
+		assert.Equal(t, fiber.StatusForbidden, status, "The key of a device should not push for another one")
+
+		status, _ = push(t, *device.ApiKey, dto.PushAttendanceDeviceRecordsBodyDto{SerialNumber: "CKJY201560123"})
+		assert.Equal(t, fiber.StatusBadRequest, status, "A push should carry records")
+	})
+
+	t.Run("Push Records", func(t *testing.T) {
+		status, response := push(t, *device.ApiKey, records)
+		require.Equal(t, fiber.StatusOK, status, "Expected the push to succeed")
+
+		var report dto.AttendanceImportDto
+		decodeData(t, response.Data, &report)
+		assert.Equal(t, 2, report.Imported, "The session punched should be imported")
+		require.Len(t, report.Anomalies, 1, "The invalid record should be reported")
+		assert.Equal(t, 3, report.Anomalies[0].Line, "The invalid record should be reported with its position")
+
+		status, response = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/attendances?user_id=%d", employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected attendances")
+		var attendances []dto.AttendanceResponseDto
+		decodeData(t, response.Data, &attendances)
+		require.Len(t, attendances, 2, "The session punched should be imported")
+		require.NotNil(t, attendances[0].DeviceID, "The attendance should keep the device punched on")
+		assert.Equal(t, *device.ID, *attendances[0].DeviceID, "The attendance should keep the device punched on")
+
+		// the device retransmits the push it got no answer to
+		status, response = push(t, *device.ApiKey, records)
+		require.Equal(t, fiber.StatusOK, status, "Expected the push to succeed")
+		decodeData(t, response.Data, &report)
+		assert.Equal(t, 0, report.Imported, "A retransmitted push should add nothing")
+		assert.Equal(t, 2, report.Duplicates, "The punches already recorded should be counted")
+
+		status, response = testApp.doJSONRequest(t, "GET", "/attendance-devices", nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the devices")
+		var devices []dto.AttendanceDeviceResponseDto
+		decodeData(t, response.Data, &devices)
+		require.NotNil(t, devices[0].LastPushAt, "The last push should be recorded")
+	})
+
+	t.Run("Regenerate Api Key", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", "/attendance-devices/999/api-key", nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusNotFound, status, "Unknown devices should not get a key")
+
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/attendance-devices/%d/api-key", *device.ID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the key to be regenerated")
+		var regenerated dto.AttendanceDeviceResponseDto
+		decodeData(t, response.Data, &regenerated)
+		require.NotNil(t, regenerated.ApiKey, "The new API key should be returned")
+
+		status, _ = push(t, *device.ApiKey, records)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "The previous key should stop authenticating")
+
+		status, _ = push(t, *regenerated.ApiKey, records)
+		assert.Equal(t, fiber.StatusOK, status, "The new key should authenticate the device")
+	})
+}
@@ -0,0 +1,192 @@
+package integration
+
+import (
+	"bytes"
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceImport(t *testing.T) {
+	// Mock time.Now to be Monday at 8pm
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 20, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 8:00 PM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	andiID, _ := testApp.createEmployee(t, "employee-import-andi", 5000000)
+
+	salary := 5000000
+	pin := "1002"
+	budi, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+		Username: "employee-import-budi",
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+			DevicePin:     &pin,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+	budiID := *budi.Id
+
+	t.Run("Assign Device Pin", func(t *testing.T) {
+		assign := func(t *testing.T, userID uint, pin string) int {
+			status, _ := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/device-pin", userID), dto.AssignDevicePinBodyDto{
+				DevicePin: &pin,
+			}, testApp.AdminToken)
+			return status
+		}
+
+		assert.Equal(t, fiber.StatusConflict, assign(t, andiID, "1002"), "A PIN should identify a single user")
+		assert.Equal(t, fiber.StatusNotFound, assign(t, 999, "1001"), "Unknown users should not be enrolled")
+		assert.Equal(t, fiber.StatusBadRequest, assign(t, andiID, "10-01"), "A PIN should be alphanumeric")
+		require.Equal(t, fiber.StatusOK, assign(t, andiID, "1001"))
+	})
+
+	importLog := func(t *testing.T, filename string, content string, dryRun bool) (int, entity.HttpResponse) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", filename)
+		require.NoError(t, err, "Failed to create form file")
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err, "Failed to write form file")
+		require.NoError(t, writer.Close(), "Failed to close form")
+
+		req, err := testApp.makeAuthenticatedRequest("POST", fmt.Sprintf("/attendances/import?dry_run=%t", dryRun), body.Bytes(), testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var response entity.HttpResponse
+		require.NoError(t, json.Unmarshal(respBody, &response), "Response should be valid json")
+		return resp.StatusCode, response
+	}
+
+	attendances := func(t *testing.T, userID uint) []dto.AttendanceResponseDto {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/attendances?user_id=%d", userID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected attendances")
+
+		var attendances []dto.AttendanceResponseDto
+		decodeData(t, response.Data, &attendances)
+		return attendances
+	}
+
+	csvLog := "AC-No.,Name,Time,State\n" +
+		"1001,Andi,16/06/2025 08:55:12,C/In\n" +
+		"1001,Andi,16/06/2025 08:55:40,C/In\n" +
+		"1001,Andi,16/06/2025 17:05:00,C/Out\n" +
+		"1002,Budi,16/06/2025 09:01:00,\n" +
+		"1002,Budi,16/06/2025 18:00:00,\n" +
+		"1002,Budi,14/06/2025 09:00:00,\n" +
+		"9999,Unknown,16/06/2025 09:00:00,C/In\n" +
+		"1001,Andi,yesterday,C/In\n" +
+		"1001,Andi,17/06/2025 09:00:00,C/Out\n" +
+		"1001,Andi,13/06/2025 17:00:00,C/Out\n"
+
+	t.Run("Dry Run", func(t *testing.T) {
+		status, response := importLog(t, "export.csv", csvLog, true)
+		require.Equal(t, fiber.StatusOK, status, "Expected the dry run to succeed")
+
+		var report dto.AttendanceImportDto
+		decodeData(t, response.Data, &report)
+		assert.True(t, report.DryRun, "The report should be a dry run")
+		assert.Equal(t, 9, report.Punches, "Every line read should be a punch")
+		assert.Equal(t, 4, report.Imported, "The sessions of both employees should be imported")
+		require.Len(t, report.Attendances, 4, "The attendances to add should be listed")
+		for _, attendance := range report.Attendances {
+			assert.Nil(t, attendance.Id, "Nothing should be added on a dry run")
+		}
+
+		require.Len(t, report.UnmatchedPins, 1, "The unknown PIN should be reported")
+		assert.Equal(t, "9999", report.UnmatchedPins[0].Pin, "The unknown PIN should be reported")
+
+		anomalies := make(map[int]string, len(report.Anomalies))
+		for _, anomaly := range report.Anomalies {
+			anomalies[anomaly.Line] = anomaly.Type
+		}
+		assert.Equal(t, map[int]string{
+			3:  "DOUBLE_PUNCH",
+			7:  "DAY_OFF",
+			9:  "INVALID_LINE",
+			10: "IN_FUTURE",
+			11: "NOT_CHECKED_IN",
+		}, anomalies, "Every anomaly should be reported with its line")
+
+		assert.Empty(t, attendances(t, andiID), "Nothing should be added on a dry run")
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		status, response := importLog(t, "export.csv", csvLog, false)
+		require.Equal(t, fiber.StatusOK, status, "Expected the import to succeed")
+
+		var report dto.AttendanceImportDto
+		decodeData(t, response.Data, &report)
+		assert.Equal(t, 4, report.Imported, "The sessions of both employees should be imported")
+
+		andiAttendances := attendances(t, andiID)
+		require.Len(t, andiAttendances, 2, "The session of the employee should be imported")
+		assert.Equal(t, "CHECKIN", andiAttendances[0].Type, "The state punched should be kept")
+		assert.True(t, andiAttendances[0].CreatedAt.Equal(time.Date(2025, 6, 16, 8, 55, 12, 0, time.Local)), "The punch time should be kept")
+		assert.Equal(t, "CHECKOUT", andiAttendances[1].Type, "The state punched should be kept")
+
+		budiAttendances := attendances(t, budiID)
+		require.Len(t, budiAttendances, 2, "The session of the employee should be imported")
+		assert.Equal(t, "CHECKIN", budiAttendances[0].Type, "A punch without state should open the session")
+		assert.Equal(t, "CHECKOUT", budiAttendances[1].Type, "A punch without state should close the open session")
+
+		status, response = importLog(t, "export.csv", csvLog, false)
+		require.Equal(t, fiber.StatusOK, status, "Expected the import to succeed")
+		decodeData(t, response.Data, &report)
+		assert.Equal(t, 0, report.Imported, "A log imported again should add nothing")
+		assert.Equal(t, 4, report.Duplicates, "The punches already recorded should be counted")
+		assert.Len(t, attendances(t, andiID), 2, "A log imported again should add nothing")
+	})
+
+	t.Run("Import Device Download", func(t *testing.T) {
+		now = time.Date(2025, 6, 17, 20, 0, 0, 0, time.Local)
+
+		status, response := importLog(t, "1_attlog.dat", "  1001\t2025-06-17 08:00:00\t1\t0\t1\t0\n  1001\t2025-06-17 17:00:00\t1\t1\t1\t0\n", false)
+		require.Equal(t, fiber.StatusOK, status, "Expected the import to succeed")
+
+		var report dto.AttendanceImportDto
+		decodeData(t, response.Data, &report)
+		assert.Equal(t, 2, report.Imported, "Every punch of the download should be imported")
+		assert.Empty(t, report.Anomalies, "The download should have no anomaly")
+	})
+
+	t.Run("Invalid Log", func(t *testing.T) {
+		status, _ := importLog(t, "empty.csv", "", false)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "An empty log should be rejected")
+
+		status, _ = importLog(t, "export.csv", "AC-No.,Name\n1001,Andi\n", false)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A log without punch time should be rejected")
+
+		status, _ = testApp.doJSONRequest(t, "POST", "/attendances/import", nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "The log should be uploaded as a file")
+	})
+}
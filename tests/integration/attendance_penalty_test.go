@@ -0,0 +1,205 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendancePenalty(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	testApp.Config.Attendance.PenaltyLateDaysThreshold = 2
+	testApp.Config.Attendance.PenaltyLateDayAmount = 50000
+
+	lateID, lateToken := testApp.createEmployee(t, "employee-penalty-late", 5000000)
+	exemptID, exemptToken := testApp.createEmployee(t, "employee-penalty-exempt", 5000000)
+	cappedID, cappedToken := testApp.createEmployee(t, "employee-penalty-capped", 220000)
+	onceLateID, onceLateToken := testApp.createEmployee(t, "employee-penalty-once-late", 5000000)
+
+	attend := func(t *testing.T, path string, at time.Time, token string) {
+		now = at
+		status, _ := testApp.doJSONRequest(t, "POST", path, nil, token)
+		require.Equal(t, fiber.StatusOK, status, "Expected %s to succeed", path)
+	}
+
+	// 40 minutes late on Monday, leaving 30 minutes early on Tuesday and 20 minutes late on Wednesday, the grace
+	// period is 15 minutes
+	for _, token := range []string{lateToken, exemptToken, cappedToken} {
+		attend(t, "/attendances/checkin", time.Date(2025, 6, 9, 9, 40, 0, 0, time.Local), token)
+		attend(t, "/attendances/checkout", time.Date(2025, 6, 9, 17, 0, 0, 0, time.Local), token)
+		attend(t, "/attendances/checkin", time.Date(2025, 6, 10, 9, 10, 0, 0, time.Local), token)
+		attend(t, "/attendances/checkout", time.Date(2025, 6, 10, 16, 30, 0, 0, time.Local), token)
+		attend(t, "/attendances/checkin", time.Date(2025, 6, 11, 9, 20, 0, 0, time.Local), token)
+		attend(t, "/attendances/checkout", time.Date(2025, 6, 11, 17, 0, 0, 0, time.Local), token)
+	}
+	// 30 minutes late on Thursday only
+	attend(t, "/attendances/checkin", time.Date(2025, 6, 12, 9, 30, 0, 0, time.Local), onceLateToken)
+	attend(t, "/attendances/checkout", time.Date(2025, 6, 12, 17, 0, 0, 0, time.Local), onceLateToken)
+	now = time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local)
+
+	t.Run("Exempt Employee", func(t *testing.T) {
+		exempt := true
+		status, _ := testApp.doJSONRequest(t, "PUT", "/users/999/attendance-penalty-exemption", dto.SetAttendancePenaltyExemptionBodyDto{
+			Exempt: &exempt,
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusNotFound, status, "Unknown users should not be exempted")
+
+		status, _ = testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/attendance-penalty-exemption", exemptID), dto.SetAttendancePenaltyExemptionBodyDto{}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "The exemption should be explicit")
+
+		status, response := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/attendance-penalty-exemption", exemptID), dto.SetAttendancePenaltyExemptionBodyDto{
+			Exempt: &exempt,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the exemption to succeed")
+
+		var user dto.GetUserByIdResponseDto
+		decodeData(t, response.Data, &user)
+		assert.True(t, user.UserInfo.AttendancePenaltyExempt, "The user should be exempted")
+	})
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	payrollID := *payroll.ID
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	getPayslip := func(t *testing.T, userID uint, token string) dto.PayslipDto {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", payrollID, userID), nil, token)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		return payslip
+	}
+
+	t.Run("Payslip Penalties", func(t *testing.T) {
+		payslip := getPayslip(t, lateID, lateToken)
+		require.NotNil(t, payslip.Deduction, "Payslip should carry deductions")
+
+		details := payslip.Deduction.Details
+		require.Len(t, details, 1, "Only the late day reaching the threshold should be deducted")
+		assert.Equal(t, "LATE_DAY", details[0].Type, "The second late day should reach the threshold")
+		assert.Equal(t, 50000, details[0].Amount, "The late day should deduct the fixed amount")
+		require.NotNil(t, details[0].AttendanceDate, "The deduction should reference the day")
+		assert.Equal(t, "2025-06-11", details[0].AttendanceDate.Format("2006-01-02"), "The deduction should reference the day")
+		assert.NotNil(t, details[0].ReferenceID, "The deduction should reference the check-in")
+
+		assert.Equal(t, float32(50000), payslip.Deduction.TotalAmount, "Deductions should sum the penalties")
+		assert.Equal(t, payslip.Attendance.TotalAmount-50000, payslip.TakeHomePay, "Only the late day should be deducted from the worked time")
+	})
+
+	t.Run("One Late Day", func(t *testing.T) {
+		payslip := getPayslip(t, onceLateID, onceLateToken)
+		require.NotNil(t, payslip.Attendance, "Payslip should pay attendance")
+		require.Len(t, payslip.Attendance.Details, 1, "The late day should be paid")
+
+		// the 30 minutes late are unpaid, and not deducted again below the threshold
+		workedMilis := (7*60 + 30) * 60 * 1000
+		assert.Equal(t, workedMilis, payslip.Attendance.Details[0].DurationMilis, "The day should be paid from the check-in")
+		assert.Empty(t, payslip.Deduction.Details, "A single late day should not be deducted")
+		assert.InDelta(t, float32(workedMilis)*payslip.ProRate, payslip.TakeHomePay, 0.01, "The net pay should be the time worked")
+	})
+
+	t.Run("Capped Penalties", func(t *testing.T) {
+		payslip := getPayslip(t, cappedID, cappedToken)
+		require.NotNil(t, payslip.Deduction, "Payslip should carry deductions")
+		require.NotEmpty(t, payslip.Deduction.Details, "The late employee should be penalized")
+
+		// three days of a 220.000 salary are paid less than the 50.000 of the late day
+		grossPay := payslip.Attendance.TotalAmount + payslip.Overtime.TotalAmount
+		assert.Less(t, grossPay, float32(50000), "The late day should exceed the gross pay")
+		assert.LessOrEqual(t, payslip.Deduction.TotalAmount, grossPay, "Penalties should be capped at the gross pay")
+		assert.GreaterOrEqual(t, payslip.TakeHomePay, float32(0), "Take home pay should not go below zero")
+		assert.Less(t, payslip.TakeHomePay, float32(1), "The penalties should take the whole gross pay")
+	})
+
+	t.Run("Exempted Payslip", func(t *testing.T) {
+		payslip := getPayslip(t, exemptID, exemptToken)
+		require.NotNil(t, payslip.Deduction, "Payslip should carry deductions")
+		assert.Empty(t, payslip.Deduction.Details, "An exempted employee should not be penalized")
+	})
+
+	t.Run("Retro Correction", func(t *testing.T) {
+		rolledPayslip := getPayslip(t, lateID, lateToken)
+
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/attendances?user_id=%d", lateID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected attendances")
+
+		var attendances []dto.AttendanceResponseDto
+		decodeData(t, response.Data, &attendances)
+
+		// the late Wednesday check-in reaching the threshold was on time
+		var lateCheckinID *uint
+		for _, attendance := range attendances {
+			if attendance.Type == "CHECKIN" && attendance.CreatedAt.Equal(time.Date(2025, 6, 11, 9, 20, 0, 0, time.Local)) {
+				lateCheckinID = attendance.Id
+			}
+		}
+		require.NotNil(t, lateCheckinID, "Expected the late check-in")
+
+		now = time.Date(2025, 6, 16, 10, 0, 0, 0, time.Local)
+		status, response = testApp.doJSONRequest(t, "POST", "/attendance-corrections", dto.RequestAttendanceCorrectionBodyDto{
+			Type:         "WRONG_TIME",
+			AttendanceID: lateCheckinID,
+			CorrectedAt:  time.Date(2025, 6, 11, 9, 0, 0, 0, time.Local),
+			Reason:       "The badge reader was down",
+		}, lateToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected the correction to be requested")
+
+		var correction dto.AttendanceCorrectionResponseDto
+		decodeData(t, response.Data, &correction)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/attendance-corrections/%d/approve", *correction.ID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the approval to succeed")
+
+		status, response = testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "July 2025",
+			StartedAt: time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 7, 31, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var july dto.PayrollResponseDto
+		decodeData(t, response.Data, &july)
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/retro-adjustments/detect", *july.ID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected detection to succeed")
+
+		var adjustments []dto.PayrollRetroAdjustmentResponseDto
+		decodeData(t, response.Data, &adjustments)
+		require.Len(t, adjustments, 1, "Only the corrected employee should be adjusted")
+		assert.Equal(t, lateID, adjustments[0].UserID, "Adjustment should belong to the corrected employee")
+
+		// one late day is left below the threshold, the late day penalty is given back with the 20 minutes now worked
+		workedMilis := 20 * 60 * 1000
+		assert.InDelta(t, 50000+float32(workedMilis)*rolledPayslip.ProRate, adjustments[0].Amount, 1, "Adjustment should give back the penalty")
+	})
+}
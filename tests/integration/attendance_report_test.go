@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"bytes"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceReport(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	salary := 5000000
+	department := "Engineering"
+	engineer, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+		Username: "employee-attendance-report",
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+			Department:    &department,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+	engineerID := *engineer.Id
+
+	engineerToken, err := utils.GenerateToken(testApp.Config.Auth.JwtSecret, &entity.AuthTokenPayload{
+		ID:   engineerID,
+		Role: engineer.Role,
+	})
+	require.NoError(t, err, "Failed to generate employee token")
+
+	_, otherToken := testApp.createEmployee(t, "employee-attendance-report-other", 5000000)
+
+	attend := func(t *testing.T, path string, at time.Time) {
+		now = at
+		status, _ := testApp.doJSONRequest(t, "POST", path, nil, engineerToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected %s to succeed", path)
+	}
+
+	// on time on Monday, 30 minutes late and leaving an hour early on Tuesday, never checking out on Wednesday
+	// and absent on Thursday and Friday
+	attend(t, "/attendances/checkin", time.Date(2025, 6, 9, 8, 55, 0, 0, time.Local))
+	attend(t, "/attendances/checkout", time.Date(2025, 6, 9, 17, 5, 0, 0, time.Local))
+	attend(t, "/attendances/checkin", time.Date(2025, 6, 10, 9, 30, 0, 0, time.Local))
+	attend(t, "/attendances/checkout", time.Date(2025, 6, 10, 16, 0, 0, 0, time.Local))
+	attend(t, "/attendances/checkin", time.Date(2025, 6, 11, 9, 0, 0, 0, time.Local))
+	now = time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local)
+
+	getReport := func(t *testing.T, query string, token string) (int, []byte) {
+		req, err := testApp.makeAuthenticatedRequest("GET", "/attendances/report?"+query, nil, token)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		return resp.StatusCode, body
+	}
+
+	t.Run("Json Report", func(t *testing.T) {
+		status, body := getReport(t, fmt.Sprintf("user_id=%d&start_date=2025-06-09&end_date=2025-06-15", engineerID), testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected json report")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+		require.Len(t, entries, 1, "Only the requested employee should be listed")
+
+		entry := entries[0]
+		assert.Equal(t, "Engineering", entry["department"], "Department should be listed")
+		assert.Equal(t, float64(5), entry["scheduled_days"], "Weekends should not be scheduled")
+		assert.Equal(t, float64(3), entry["present_days"], "Checked in days should be present")
+		assert.Equal(t, float64(2), entry["absent_days"], "Days without check in should be absent")
+		assert.Equal(t, float64(1), entry["late_arrivals"], "Check ins past the grace period should be late")
+		assert.Equal(t, float64(30), entry["late_minutes"], "Lateness should be measured from the scheduled start")
+		assert.Equal(t, float64(1), entry["early_departures"], "Checkouts before the grace period should be early")
+		assert.Equal(t, float64(60), entry["early_departure_minutes"], "Early departures should be measured to the scheduled end")
+		assert.Equal(t, float64(1), entry["missing_checkouts"], "Days never checked out should be missing a checkout")
+		assert.Equal(t, 14.67, entry["total_hours"], "Only checked out days should be worked")
+	})
+
+	t.Run("Csv Department Report", func(t *testing.T) {
+		status, body := getReport(t, "department=Engineering&start_date=2025-06-09&end_date=2025-06-15&format=csv", testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected csv report")
+
+		records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err, "Report should be valid csv")
+		require.Len(t, records, 2, "Only the employees of the department should be listed")
+		assert.Equal(t, "user_id", records[0][0], "Header should come first")
+		assert.Equal(t, "employee-attendance-report", records[1][1], "Employee should be listed")
+		assert.Equal(t, "14.67", records[1][len(records[1])-1], "Total hours should be the last column")
+	})
+
+	t.Run("Invalid Range", func(t *testing.T) {
+		status, _ := getReport(t, "start_date=2025-06-15&end_date=2025-06-09", testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "End date should not be before start date")
+
+		status, _ = getReport(t, "start_date=2024-01-01&end_date=2025-06-09", testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "Date range should be bounded")
+	})
+
+	t.Run("Employee Access", func(t *testing.T) {
+		status, _ := getReport(t, "start_date=2025-06-09&end_date=2025-06-15", engineerToken)
+		assert.Equal(t, fiber.StatusOK, status, "Employees should read their own report")
+
+		status, _ = getReport(t, fmt.Sprintf("user_id=%d&start_date=2025-06-09&end_date=2025-06-15", engineerID), otherToken)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Employees should not read other reports")
+
+		status, _ = getReport(t, "department=Engineering&start_date=2025-06-09&end_date=2025-06-15", otherToken)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Employees should not read department reports")
+	})
+}
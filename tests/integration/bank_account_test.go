@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBankAccountChange(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-bank-account", 5000000)
+	financeID, financeToken := testApp.createFinance(t, "finance-bank-account")
+	_, otherFinanceToken := testApp.createFinance(t, "finance-bank-account-other")
+
+	var changeID uint
+
+	t.Run("Request Change", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", employeeID), dto.RequestBankAccountChangeBodyDto{
+			PaymentMethod: "TRANSFER",
+			BankCode:      "BCA",
+			AccountNumber: "1234567890",
+			AccountName:   "Budi Santoso",
+		}, financeToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected bank account change to be requested")
+
+		var change dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &change)
+		assert.Equal(t, "PENDING", change.Status, "Change should wait for a review")
+		assert.Equal(t, "1234567890", change.AccountNumber, "Finance should see the full account number")
+		changeID = *change.ID
+
+		status, _ = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/bank-account", employeeID), nil, financeToken)
+		assert.Equal(t, fiber.StatusNotFound, status, "Pending changes should not be applied")
+
+		status, _ = testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", employeeID), dto.RequestBankAccountChangeBodyDto{
+			PaymentMethod: "TRANSFER",
+			BankCode:      "BNI",
+			AccountNumber: "0098765432",
+			AccountName:   "Budi Santoso",
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusConflict, status, "Only one change can be pending")
+
+		status, _ = testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", employeeID), dto.RequestBankAccountChangeBodyDto{
+			PaymentMethod: "TRANSFER",
+			BankCode:      "BCA",
+			AccountNumber: "1234567890",
+			AccountName:   "Budi Santoso",
+		}, employeeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not request changes")
+	})
+
+	t.Run("Review Change", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/approve", changeID), nil, financeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Requester should not approve their own change")
+
+		status, response := testApp.doJSONRequest(t, "GET", "/bank-account-changes?status=PENDING", nil, otherFinanceToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected pending changes to be listed")
+
+		var pending []dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &pending)
+		require.Len(t, pending, 1, "One change should be pending")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/approve", changeID), nil, otherFinanceToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "A change requested by finance should not be approved by finance")
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/approve", changeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected change to be approved")
+
+		var change dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &change)
+		assert.Equal(t, "APPROVED", change.Status, "Change should be approved")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/reject", changeID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusConflict, status, "Reviewed changes should not be reviewed again")
+	})
+
+	t.Run("Masked Account Number", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/bank-account", employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected bank account to be returned")
+
+		var account dto.UserBankAccountResponseDto
+		decodeData(t, response.Data, &account)
+		assert.Equal(t, "TRANSFER", account.PaymentMethod, "Payment method should be applied")
+		assert.Equal(t, "******7890", account.AccountNumber, "Admins should only see the last digits")
+
+		status, response = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/bank-account", employeeID), nil, financeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected bank account to be returned")
+
+		decodeData(t, response.Data, &account)
+		assert.Equal(t, "1234567890", account.AccountNumber, "Finance should see the full account number")
+	})
+
+	t.Run("Payment Methods", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", employeeID), dto.RequestBankAccountChangeBodyDto{
+			PaymentMethod: "EWALLET",
+			BankCode:      "BCA",
+			AccountNumber: "081234567890",
+			AccountName:   "Budi Santoso",
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "E-wallet payments need an e-wallet provider")
+
+		status, response := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", employeeID), dto.RequestBankAccountChangeBodyDto{
+			PaymentMethod: "CASH",
+			AccountName:   "Budi Santoso",
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected cash change to be requested")
+
+		var change dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &change)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/reject", *change.ID), dto.ReviewBankAccountChangeBodyDto{}, financeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected change to be rejected")
+
+		status, response = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/bank-account", employeeID), nil, financeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected bank account to be returned")
+
+		var account dto.UserBankAccountResponseDto
+		decodeData(t, response.Data, &account)
+		assert.Equal(t, "TRANSFER", account.PaymentMethod, "Rejected changes should not be applied")
+	})
+
+	t.Run("Reviewer Role", func(t *testing.T) {
+		otherAdmin, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+			Username: "admin-bank-account-other",
+			Password: "password123",
+			Role:     entity.UserRoleAdmin,
+			UserInfo: &entity.UserInfo{},
+		})
+		require.NoError(t, err, "Failed to create admin user")
+
+		otherAdminToken, err := utils.GenerateToken(testApp.Config.Auth.JwtSecret, &entity.AuthTokenPayload{
+			ID:   *otherAdmin.Id,
+			Role: entity.UserRoleAdmin,
+		})
+		require.NoError(t, err, "Failed to generate admin token")
+
+		status, response := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", employeeID), dto.RequestBankAccountChangeBodyDto{
+			PaymentMethod: "TRANSFER",
+			BankCode:      "MANDIRI",
+			AccountNumber: "1370012345678",
+			AccountName:   "Budi Santoso",
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected bank account change to be requested")
+
+		var change dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &change)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/approve", *change.ID), nil, otherAdminToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "A change requested by an admin should not be approved by another admin")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/reject", *change.ID), nil, financeToken)
+		require.Equal(t, fiber.StatusOK, status, "Finance should review the change requested by an admin")
+	})
+
+	t.Run("Own Account", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", financeID), dto.RequestBankAccountChangeBodyDto{
+			PaymentMethod: "TRANSFER",
+			BankCode:      "BRI",
+			AccountNumber: "0021010012345",
+			AccountName:   "Sari Dewi",
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected bank account change to be requested")
+
+		var change dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &change)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/approve", *change.ID), nil, financeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Nobody should approve a change to their own bank account")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/approve", *change.ID), nil, otherFinanceToken)
+		require.Equal(t, fiber.StatusOK, status, "Another finance user should approve the change")
+	})
+
+	t.Run("Change History", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/bank-account/changes", employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected change history to be returned")
+
+		var changes []dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &changes)
+		require.Len(t, changes, 3, "History should list every change")
+		assert.Equal(t, "APPROVED", changes[0].Status, "First change should be approved")
+		assert.Equal(t, "******7890", changes[0].AccountNumber, "History should be masked for admins")
+		assert.Equal(t, "REJECTED", changes[1].Status, "Second change should be rejected")
+		assert.Equal(t, "REJECTED", changes[2].Status, "Third change should be rejected")
+	})
+}
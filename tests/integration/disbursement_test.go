@@ -0,0 +1,164 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisbursement(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	bcaID, bcaToken := testApp.createEmployee(t, "employee-bca", 5000000)
+	mandiriID, _ := testApp.createEmployee(t, "employee-mandiri", 5000000)
+	missingID, _ := testApp.createEmployee(t, "employee-missing-bank", 5000000)
+
+	_, financeToken := testApp.createFinance(t, "finance-disbursement")
+
+	setBankAccount := func(t *testing.T, userID uint, body dto.RequestBankAccountChangeBodyDto) {
+		body.PaymentMethod = "TRANSFER"
+		status, response := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", userID), body, testApp.AdminToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected bank account change to be requested")
+
+		var change dto.UserBankAccountChangeResponseDto
+		decodeData(t, response.Data, &change)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/bank-account-changes/%d/approve", *change.ID), nil, financeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected bank account change to be approved")
+	}
+
+	t.Run("Set Bank Account", func(t *testing.T) {
+		setBankAccount(t, bcaID, dto.RequestBankAccountChangeBodyDto{BankCode: "BCA", AccountNumber: "1234567890", AccountName: "Budi Santoso"})
+		setBankAccount(t, mandiriID, dto.RequestBankAccountChangeBodyDto{BankCode: "MANDIRI", AccountNumber: "1370012345678", AccountName: "Sari Wulandari"})
+
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/bank-account", bcaID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected bank account to be returned")
+
+		var account dto.UserBankAccountResponseDto
+		decodeData(t, response.Data, &account)
+		assert.Equal(t, "Bank Central Asia", account.BankName, "Bank name should follow the bank code")
+
+		status, _ = testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", bcaID), dto.RequestBankAccountChangeBodyDto{PaymentMethod: "TRANSFER", BankCode: "UNKNOWN", AccountNumber: "1234567890", AccountName: "Budi"}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "Unknown banks should be rejected")
+
+		status, _ = testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/bank-account", bcaID), dto.RequestBankAccountChangeBodyDto{PaymentMethod: "TRANSFER", BankCode: "BCA", AccountNumber: "1234567890", AccountName: "Budi"}, bcaToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not change bank accounts")
+	})
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025 Bonus",
+		Type:      "BONUS",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	payrollID := *payroll.ID
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/users", payrollID), dto.AddPayrollUsersBodyDto{
+		UserIDs: []uint{bcaID, mandiriID, missingID},
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll users to be selected")
+
+	for _, userID := range []uint{bcaID, mandiriID, missingID} {
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", payrollID), dto.CreatePayrollEarningBodyDto{
+			UserID:      userID,
+			Description: "Performance bonus",
+			Amount:      1500000,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+	}
+
+	t.Run("Not Rolled Payroll", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/%d/disbursement", payrollID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Open payrolls cannot be disbursed")
+	})
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	getDisbursement := func(t *testing.T, format string) (int, string) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/payrolls/%d/disbursement?format=%s&executed_at=2025-06-25", payrollID, format), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("Missing Bank Account", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/%d/disbursement", payrollID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusUnprocessableEntity, status, "Employees without bank account should block the disbursement")
+
+		var issues []dto.DisbursementIssueDto
+		decodeData(t, response.Data, &issues)
+		require.Len(t, issues, 1, "Only the employee without bank account should be listed")
+		assert.Equal(t, missingID, issues[0].UserID, "Issue should name the employee")
+		assert.Equal(t, "Missing bank account", issues[0].Reason, "Issue should explain the reason")
+	})
+
+	setBankAccount(t, missingID, dto.RequestBankAccountChangeBodyDto{BankCode: "BNI", AccountNumber: "0098765432", AccountName: "Andi Wijaya"})
+
+	t.Run("Generic CSV", func(t *testing.T) {
+		status, body := getDisbursement(t, "csv")
+		require.Equal(t, fiber.StatusOK, status, "Expected csv disbursement")
+
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		require.Len(t, lines, 4, "Expected a header and one row per employee")
+		assert.Equal(t, fmt.Sprintf("%d,employee-bca,BCA,Bank Central Asia,1234567890,Budi Santoso,1500000,Payroll June 2025 Bonus", bcaID), lines[1])
+	})
+
+	t.Run("BCA Only Pays BCA Accounts", func(t *testing.T) {
+		status, body := getDisbursement(t, "bca")
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Other banks cannot be paid through KlikBCA payroll")
+		assert.Contains(t, body, "KlikBCA Bisnis payroll only pays into BCA accounts")
+	})
+
+	t.Run("Mandiri MCM", func(t *testing.T) {
+		status, body := getDisbursement(t, "mandiri")
+		require.Equal(t, fiber.StatusOK, status, "Expected mandiri disbursement")
+		assert.True(t, strings.HasPrefix(body, "P,20250625,0123456789,3,4500000\r\n"), "Header should carry the debit account and the totals")
+		assert.Contains(t, body, "1370012345678,Sari Wulandari,IDR,1500000,Payroll June 2025 Bonus,IBU,008,Bank Mandiri", "Mandiri accounts should be paid in-house")
+		assert.Contains(t, body, "1234567890,Budi Santoso,IDR,1500000,Payroll June 2025 Bonus,LBU,014,Bank Central Asia", "Other banks should be paid interbank")
+	})
+
+	t.Run("ISO 20022 pain.001", func(t *testing.T) {
+		status, body := getDisbursement(t, "pain001")
+		require.Equal(t, fiber.StatusOK, status, "Expected pain.001 disbursement")
+		assert.Contains(t, body, `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">`)
+		assert.Contains(t, body, "<CtrlSum>4500000.00</CtrlSum>")
+		assert.Contains(t, body, "<ReqdExctnDt>2025-06-25</ReqdExctnDt>")
+		assert.Contains(t, body, "<BIC>BNINIDJA</BIC>")
+		assert.Contains(t, body, fmt.Sprintf("<EndToEndId>PAYROLL-%d-%d</EndToEndId>", payrollID, bcaID), "End to end ID should fit in 35 characters")
+	})
+
+	t.Run("Unknown Format", func(t *testing.T) {
+		status, _ := getDisbursement(t, "swift")
+		assert.Equal(t, fiber.StatusBadRequest, status, "Unknown formats should be rejected")
+	})
+}
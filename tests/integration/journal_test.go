@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"bytes"
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayrollJournal(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	salary := 5000000
+	costCenter := "ENG"
+	status, response := testApp.doJSONRequest(t, "POST", "/users", dto.CreateUserBodyDto{
+		Username: "employee-journal-engineer",
+		Password: "password123",
+		Role:     "EMPLOYEE",
+		UserInfo: &dto.CreateUserInfoBodyDto{
+			MonthlySalary: &salary,
+			CostCenter:    &costCenter,
+		},
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected user creation to succeed")
+
+	var engineer dto.CreateUserResponseDto
+	decodeData(t, response.Data, &engineer)
+	require.NotNil(t, engineer.UserInfo, "User info should be returned")
+	assert.Equal(t, "ENG", *engineer.UserInfo.CostCenter, "Cost center should be stored")
+	engineerID := *engineer.Id
+
+	otherID, otherToken := testApp.createEmployee(t, "employee-journal-other", 5000000)
+
+	status, _ = testApp.doJSONRequest(t, "POST", "/loans", dto.CreateLoanBodyDto{
+		UserID:           engineerID,
+		Type:             "LOAN",
+		Description:      "Laptop loan",
+		Principal:        3000000,
+		InstallmentCount: 3,
+		StartedAt:        time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected loan creation to succeed")
+
+	status, response = testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	payrollID := *payroll.ID
+
+	for userID, amount := range map[uint]int{engineerID: 3000000, otherID: 1500000} {
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", payrollID), dto.CreatePayrollEarningBodyDto{
+			UserID:      userID,
+			Description: "Project allowance",
+			Amount:      amount,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+	}
+
+	t.Run("Not Rolled", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/%d/journal", payrollID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Open payrolls should not be journaled")
+	})
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	getJournal := func(t *testing.T, format string) (int, []byte) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/payrolls/%d/journal?format=%s", payrollID, format), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		return resp.StatusCode, body
+	}
+
+	t.Run("Json Journal", func(t *testing.T) {
+		status, body := getJournal(t, "json")
+		require.Equal(t, fiber.StatusOK, status, "Expected json journal")
+
+		var journal struct {
+			Reference   string `json:"reference"`
+			Date        string `json:"date"`
+			Currency    string `json:"currency"`
+			TotalDebit  int    `json:"total_debit"`
+			TotalCredit int    `json:"total_credit"`
+			Lines       []struct {
+				Account     string `json:"account"`
+				AccountCode string `json:"account_code"`
+				CostCenter  string `json:"cost_center"`
+				Debit       int    `json:"debit"`
+				Credit      int    `json:"credit"`
+			} `json:"lines"`
+		}
+		require.NoError(t, json.Unmarshal(body, &journal), "Journal should be valid json")
+
+		assert.Equal(t, fmt.Sprintf("PAYROLL-%d", payrollID), journal.Reference, "Reference should point to the payroll")
+		assert.Equal(t, "2025-06-30", journal.Date, "Journal should be dated at the end of the payroll")
+		assert.Equal(t, "IDR", journal.Currency, "Currency should follow the config")
+		assert.Equal(t, 4500000, journal.TotalDebit, "Debits should book the gross pay")
+		assert.Equal(t, journal.TotalDebit, journal.TotalCredit, "Journal should balance")
+
+		require.Len(t, journal.Lines, 4, "Journal should book the salaries, the loan and the net pay")
+		assert.Equal(t, "SALARY_EXPENSE", journal.Lines[0].Account, "Expenses should come first")
+		assert.Equal(t, "6101", journal.Lines[0].AccountCode, "Account code should follow the chart of accounts")
+		assert.Equal(t, "ENG", journal.Lines[0].CostCenter, "Expenses should be booked to the employee cost center")
+		assert.Equal(t, 3000000, journal.Lines[0].Debit, "Engineer salary expense should match")
+		assert.Equal(t, "GENERAL", journal.Lines[1].CostCenter, "Employees without a cost center use the default")
+		assert.Equal(t, 1500000, journal.Lines[1].Debit, "Other salary expense should match")
+		assert.Equal(t, "LOAN_RECEIVABLE", journal.Lines[2].Account, "Loan installments should reduce the receivable")
+		assert.Equal(t, 1000000, journal.Lines[2].Credit, "Loan credit should equal the installment")
+		assert.Equal(t, "NET_SALARY_PAYABLE", journal.Lines[3].Account, "Net pay should be booked last")
+		assert.Equal(t, "", journal.Lines[3].CostCenter, "Liabilities should be booked company wide")
+		assert.Equal(t, 3500000, journal.Lines[3].Credit, "Net payable should equal the take home pays")
+	})
+
+	t.Run("Csv Journal", func(t *testing.T) {
+		status, body := getJournal(t, "csv")
+		require.Equal(t, fiber.StatusOK, status, "Expected csv journal")
+
+		records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err, "Journal should be valid csv")
+		require.Len(t, records, 5, "Csv should hold the header and every line")
+		assert.Equal(t, []string{"reference", "date", "currency", "account_code", "account_name", "cost_center", "description", "debit", "credit"}, records[0], "Header should match")
+		assert.Equal(t, "2101", records[4][3], "Net payable account code should match")
+		assert.Equal(t, "3500000", records[4][8], "Net payable credit should match")
+	})
+
+	t.Run("Invalid Format", func(t *testing.T) {
+		status, _ := getJournal(t, "xml")
+		assert.Equal(t, fiber.StatusBadRequest, status, "Unsupported formats should be rejected")
+	})
+
+	t.Run("Employee Forbidden", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/%d/journal", payrollID), nil, otherToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not export the journal")
+	})
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoanDeduction(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	borrowerID, borrowerToken := testApp.createEmployee(t, "employee-loan-borrower", 5000000)
+	_, otherToken := testApp.createEmployee(t, "employee-loan-other", 5000000)
+
+	var loanID uint
+
+	t.Run("Create Loan", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/loans", dto.CreateLoanBodyDto{
+			UserID:           borrowerID,
+			Type:             "LOAN",
+			Description:      "Laptop loan",
+			Principal:        3000000,
+			InstallmentCount: 3,
+			StartedAt:        time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected loan creation to succeed")
+
+		var loan dto.LoanResponseDto
+		decodeData(t, response.Data, &loan)
+		assert.Equal(t, 1000000, loan.InstallmentAmount, "Installment should split the principal evenly")
+		assert.Equal(t, 3000000, loan.RemainingBalance, "Remaining balance should start at the principal")
+		loanID = *loan.ID
+
+		status, _ = testApp.doJSONRequest(t, "POST", "/loans", dto.CreateLoanBodyDto{
+			UserID:           borrowerID,
+			Type:             "LOAN",
+			Description:      "Laptop loan",
+			Principal:        3000000,
+			InstallmentCount: 3,
+			StartedAt:        time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		}, borrowerToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not create loans")
+	})
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	payrollID := *payroll.ID
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", payrollID), dto.CreatePayrollEarningBodyDto{
+		UserID:      borrowerID,
+		Description: "Project allowance",
+		Amount:      2000000,
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+
+	t.Run("Roll And Payslip Deduction", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", payrollID, borrowerID), nil, borrowerToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		require.NotNil(t, payslip.Deduction, "Payslip should carry deductions")
+		require.Len(t, payslip.Deduction.Details, 1, "Payslip should deduct one installment")
+		assert.Equal(t, "LOAN", payslip.Deduction.Details[0].Type, "Deduction type should match")
+		assert.Equal(t, loanID, *payslip.Deduction.Details[0].ReferenceID, "Deduction should reference the loan")
+		assert.Equal(t, float32(1000000), payslip.Deduction.TotalAmount, "Deduction should equal one installment")
+		assert.Equal(t, float32(1000000), payslip.TakeHomePay, "Take home pay should not drop below the configured minimum")
+	})
+
+	t.Run("Loan Statement", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/loans/%d/statement", loanID), nil, borrowerToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected loan statement to succeed")
+
+		var statement dto.LoanStatementDto
+		decodeData(t, response.Data, &statement)
+		require.Len(t, statement.Installments, 1, "Statement should list the paid installment")
+		assert.Equal(t, payrollID, statement.Installments[0].PayrollID, "Installment should reference the payroll")
+		assert.Equal(t, 1000000, statement.PaidAmount, "Paid amount should match")
+		assert.Equal(t, 2000000, statement.Loan.RemainingBalance, "Remaining balance should be reduced")
+		assert.Equal(t, 2, statement.RemainingInstallments, "Two installments should remain")
+
+		status, _ = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/loans/%d/statement", loanID), nil, otherToken)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Other employees should not see the loan")
+	})
+}
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffCyclePayroll(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	selectedID, selectedToken := testApp.createEmployee(t, "employee-bonus-selected", 5000000)
+	otherID, _ := testApp.createEmployee(t, "employee-bonus-other", 5000000)
+
+	// The selected employee works a day, which must not be paid again by the bonus run
+	status, _ := testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, selectedToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected checkin to succeed")
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025 Bonus",
+		Type:      "BONUS",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	assert.Equal(t, "BONUS", payroll.Type, "Payroll type should match")
+	payrollID := *payroll.ID
+
+	t.Run("Earning For Unselected User", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", payrollID), dto.CreatePayrollEarningBodyDto{
+			UserID:      otherID,
+			Description: "Performance bonus",
+			Amount:      1000000,
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Expected unselected user earning to be rejected")
+	})
+
+	t.Run("Select User And Add Earning", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/users", payrollID), dto.AddPayrollUsersBodyDto{
+			UserIDs: []uint{selectedID},
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected user selection to succeed")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", payrollID), dto.CreatePayrollEarningBodyDto{
+			UserID:      selectedID,
+			Description: "Performance bonus",
+			Amount:      1000000,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+	})
+
+	t.Run("Roll And Payslips", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", payrollID, selectedID), nil, selectedToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		assert.Nil(t, payslip.Attendance, "Bonus payslip should not pay attendance")
+		assert.Equal(t, float32(1000000), payslip.TakeHomePay, "Bonus payslip should only pay the earning")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", payrollID, otherID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusNotFound, status, "Unselected user should not have a payslip")
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslip-summaries", payrollID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip summaries to succeed")
+
+		var summaries []dto.UserPayslipSummaryDto
+		decodeData(t, response.Data, &summaries)
+		require.Len(t, summaries, 1, "Only the selected user should be summarized")
+		assert.Equal(t, selectedID, summaries[0].UserID, "Summary should belong to the selected user")
+	})
+
+	t.Run("Year To Date Includes Off-Cycle Run", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/year-to-date?user_id=%d&year=2025", selectedID), nil, selectedToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected year to date to succeed")
+
+		var yearToDate dto.UserYearToDateResponseDto
+		decodeData(t, response.Data, &yearToDate)
+		assert.Equal(t, 1, yearToDate.PayrollCount, "Off-cycle payroll should be counted")
+		assert.Equal(t, 1000000, yearToDate.TotalTakeHomePay, "Off-cycle payroll should feed the total")
+	})
+
+	t.Run("Year To Date Defaults To Caller", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", "/payrolls/year-to-date?year=2025", nil, selectedToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected year to date without user ID to succeed")
+
+		var yearToDate dto.UserYearToDateResponseDto
+		decodeData(t, response.Data, &yearToDate)
+		assert.Equal(t, selectedID, yearToDate.UserID, "Year to date should default to the caller")
+		assert.Equal(t, 1000000, yearToDate.TotalTakeHomePay, "Year to date should be the caller's")
+	})
+}
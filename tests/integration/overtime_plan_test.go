@@ -0,0 +1,198 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOvertimePlan(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-overtime-plan", 5000000)
+	otherID, otherToken := testApp.createEmployee(t, "employee-overtime-plan-other", 5000000)
+
+	hour := 60 * 60 * 1000
+
+	// work checks the employee in and out of a weekday, an overtime is submitted after the checkout
+	work := func(t *testing.T, day int) {
+		now = time.Date(2025, 6, day, 9, 0, 0, 0, time.Local)
+		status, _ := testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected check-in to succeed")
+
+		now = time.Date(2025, 6, day, 17, 0, 0, 0, time.Local)
+		status, _ = testApp.doJSONRequest(t, "POST", "/attendances/checkout", nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected checkout to succeed")
+	}
+
+	plan := func(t *testing.T, userID uint, plannedAt time.Time, durationMilis int) (int, dto.OvertimePlanResponseDto) {
+		status, response := testApp.doJSONRequest(t, "POST", "/overtime-plans", dto.CreateOvertimePlanBodyDto{
+			UserID:        userID,
+			Description:   "Release night",
+			PlannedAt:     plannedAt,
+			DurationMilis: durationMilis,
+		}, testApp.AdminToken)
+
+		var plan dto.OvertimePlanResponseDto
+		if status == fiber.StatusOK {
+			decodeData(t, response.Data, &plan)
+		}
+		return status, plan
+	}
+
+	submit := func(t *testing.T, durationMilis int, planID *uint) (int, dto.OvertimeResponseDto) {
+		status, response := testApp.doJSONRequest(t, "POST", "/overtimes", dto.CreateOvertimeBodyDto{
+			Description:    "Release night",
+			OvertimeAt:     now,
+			DurationMilis:  durationMilis,
+			OvertimePlanID: planID,
+		}, employeeToken)
+
+		var overtime dto.OvertimeResponseDto
+		if status == fiber.StatusOK {
+			decodeData(t, response.Data, &overtime)
+		}
+		return status, overtime
+	}
+
+	var mondayPlan dto.OvertimePlanResponseDto
+	t.Run("Plan Overtime", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", "/overtime-plans", dto.CreateOvertimePlanBodyDto{
+			UserID:        employeeID,
+			Description:   "Release night",
+			PlannedAt:     time.Date(2025, 6, 16, 18, 0, 0, 0, time.Local),
+			DurationMilis: 2 * hour,
+		}, employeeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not plan overtimes")
+
+		status, _ = plan(t, employeeID, time.Date(2025, 6, 13, 18, 0, 0, 0, time.Local), 2*hour)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "An overtime should not be planned on a past day")
+
+		status, _ = plan(t, employeeID, time.Date(2025, 6, 16, 18, 0, 0, 0, time.Local), 4*hour)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A plan should not exceed the limit of a day")
+
+		status, _ = plan(t, 999, time.Date(2025, 6, 16, 18, 0, 0, 0, time.Local), 2*hour)
+		assert.Equal(t, fiber.StatusNotFound, status, "Unknown users should not be planned")
+
+		status, mondayPlan = plan(t, employeeID, time.Date(2025, 6, 16, 17, 0, 0, 0, time.Local), 2*hour)
+		require.Equal(t, fiber.StatusOK, status, "Expected the plan to succeed")
+		assert.Nil(t, mondayPlan.OvertimeID, "Nothing should be submitted against the plan yet")
+
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/overtime-plans?user_id=%d", employeeID), nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected the plans")
+		var plans []dto.OvertimePlanResponseDto
+		decodeData(t, response.Data, &plans)
+		assert.Len(t, plans, 1, "The employee should see their plan")
+	})
+
+	t.Run("Submit Against Plan", func(t *testing.T) {
+		work(t, 16)
+
+		status, otherPlan := plan(t, otherID, time.Date(2025, 6, 16, 17, 0, 0, 0, time.Local), 2*hour)
+		require.Equal(t, fiber.StatusOK, status, "Expected the plan to succeed")
+		status, _ = submit(t, 2*hour, otherPlan.ID)
+		assert.Equal(t, fiber.StatusNotFound, status, "The plan of another employee should not be submitted against")
+
+		status, eveningPlan := plan(t, employeeID, time.Date(2025, 6, 16, 20, 0, 0, 0, time.Local), hour)
+		require.Equal(t, fiber.StatusOK, status, "Expected the plan to succeed")
+		status, _ = submit(t, hour, eveningPlan.ID)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A plan should not be submitted against before its planned time")
+
+		// 10 minutes more than planned is within the 15 minutes tolerance
+		status, overtime := submit(t, 2*hour+10*60*1000, mondayPlan.ID)
+		require.Equal(t, fiber.StatusOK, status, "Expected the overtime to succeed")
+		assert.True(t, overtime.IsApproved, "An overtime worked as planned should be approved")
+		assert.NotNil(t, overtime.UpdatedByUserID, "The overtime should be approved on behalf of the planner")
+		require.NotNil(t, overtime.OvertimePlanID, "The overtime should reference its plan")
+		assert.Equal(t, *mondayPlan.ID, *overtime.OvertimePlanID, "The overtime should reference its plan")
+
+		status, _ = submit(t, 30*60*1000, mondayPlan.ID)
+		assert.Equal(t, fiber.StatusConflict, status, "A plan should be submitted against once")
+
+		work(t, 17)
+		status, _ = submit(t, hour, eveningPlan.ID)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A plan should not be submitted against after its end")
+
+		status, tuesdayPlan := plan(t, employeeID, time.Date(2025, 6, 17, 17, 0, 0, 0, time.Local), hour)
+		require.Equal(t, fiber.StatusOK, status, "Expected the plan to succeed")
+
+		status, overtime = submit(t, 2*hour, tuesdayPlan.ID)
+		require.Equal(t, fiber.StatusOK, status, "Expected the overtime to succeed")
+		assert.False(t, overtime.IsApproved, "An overtime longer than planned should be reviewed")
+	})
+
+	t.Run("Weekly Limit", func(t *testing.T) {
+		for _, day := range []int{18, 19, 20} {
+			work(t, day)
+			status, _ := submit(t, 3*hour, nil)
+			require.Equal(t, fiber.StatusOK, status, "Expected the overtime to succeed")
+		}
+
+		// 13 hours 10 minutes were submitted in the week, the limit is 14 hours
+		now = time.Date(2025, 6, 21, 10, 0, 0, 0, time.Local)
+		status, _ := submit(t, hour, nil)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "The overtimes of a week should be capped")
+
+		status, _ = submit(t, 50*60*1000, nil)
+		assert.Equal(t, fiber.StatusOK, status, "The overtimes up to the weekly limit should be accepted")
+	})
+
+	t.Run("Monthly Limit", func(t *testing.T) {
+		testApp.Config.Overtime.MaxDurationPerMonthMilis = 15 * hour
+		defer func() { testApp.Config.Overtime.MaxDurationPerMonthMilis = 0 }()
+
+		// a new week, 14 hours were submitted in the month
+		work(t, 23)
+		status, _ := submit(t, hour, nil)
+		require.Equal(t, fiber.StatusOK, status, "The overtimes up to the monthly limit should be accepted")
+
+		status, _ = submit(t, 30*60*1000, nil)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "The overtimes of a month should be capped")
+	})
+
+	t.Run("Plan Across Midnight", func(t *testing.T) {
+		now = time.Date(2025, 6, 24, 13, 0, 0, 0, time.Local)
+		status, nightPlan := plan(t, otherID, time.Date(2025, 6, 24, 22, 0, 0, 0, time.Local), 3*hour)
+		require.Equal(t, fiber.StatusOK, status, "Expected the plan to succeed")
+
+		now = time.Date(2025, 6, 24, 14, 0, 0, 0, time.Local)
+		status, _ = testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, otherToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected check-in to succeed")
+
+		now = time.Date(2025, 6, 25, 1, 0, 0, 0, time.Local)
+		status, _ = testApp.doJSONRequest(t, "POST", "/attendances/checkout", nil, otherToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected checkout to succeed")
+
+		now = time.Date(2025, 6, 25, 1, 5, 0, 0, time.Local)
+		status, response := testApp.doJSONRequest(t, "POST", "/overtimes", dto.CreateOvertimeBodyDto{
+			Description:    "Release night",
+			OvertimeAt:     now,
+			DurationMilis:  3 * hour,
+			OvertimePlanID: nightPlan.ID,
+		}, otherToken)
+		require.Equal(t, fiber.StatusOK, status, "A plan should be submitted against after its checkout on the next day")
+
+		var overtime dto.OvertimeResponseDto
+		decodeData(t, response.Data, &overtime)
+		assert.True(t, overtime.IsApproved, "An overtime worked as planned should be approved")
+	})
+}
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayrollPreview(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	raisedID, _ := testApp.createEmployee(t, "employee-preview-raised", 5000000)
+	missingID, _ := testApp.createEmployee(t, "employee-preview-missing", 5000000)
+	newID, _ := testApp.createEmployee(t, "employee-preview-new", 5000000)
+
+	createBonusPayroll := func(t *testing.T, name string, endedAt time.Time, earnings map[uint]int, userIDs []uint) uint {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      name,
+			Type:      "BONUS",
+			StartedAt: endedAt.AddDate(0, -1, 0),
+			EndedAt:   endedAt,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/users", *payroll.ID), dto.AddPayrollUsersBodyDto{
+			UserIDs: userIDs,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected user selection to succeed")
+
+		for userID, amount := range earnings {
+			status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", *payroll.ID), dto.CreatePayrollEarningBodyDto{
+				UserID:      userID,
+				Description: "Performance bonus",
+				Amount:      amount,
+			}, testApp.AdminToken)
+			require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+		}
+
+		return *payroll.ID
+	}
+
+	juneID := createBonusPayroll(t, "June 2025 Bonus", time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local), map[uint]int{
+		raisedID:  1000000,
+		missingID: 1000000,
+	}, []uint{raisedID, missingID})
+	status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", juneID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	julyID := createBonusPayroll(t, "July 2025 Bonus", time.Date(2025, 7, 31, 23, 59, 59, 0, time.Local), map[uint]int{
+		raisedID: 1500000,
+	}, []uint{raisedID, newID})
+
+	t.Run("Preview Flags", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/preview", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected preview to succeed")
+
+		var preview dto.PayrollPreviewDto
+		decodeData(t, response.Data, &preview)
+		require.NotNil(t, preview.PreviousPayroll, "Preview should compare with the previous bonus run")
+		assert.Equal(t, juneID, *preview.PreviousPayroll.ID, "Previous payroll should be June")
+		assert.Equal(t, 1500000, preview.TotalTakeHomePay, "Total should match")
+		assert.Equal(t, 2000000, preview.PreviousTotalTakeHomePay, "Previous total should match")
+		assert.Equal(t, 3, preview.FlaggedCount, "Every employee should be flagged")
+
+		require.Len(t, preview.Entries, 3, "Preview should list current and missing employees")
+		assert.Equal(t, raisedID, preview.Entries[0].UserID)
+		assert.Equal(t, []string{"CHANGE_ABOVE_THRESHOLD"}, preview.Entries[0].Flags)
+		assert.InDelta(t, 50, *preview.Entries[0].ChangePercent, 0.01, "Change should be 50%")
+		require.NotNil(t, preview.Entries[0].Payslip, "Preview should include the payslip")
+
+		assert.Equal(t, missingID, preview.Entries[1].UserID)
+		assert.Equal(t, []string{"MISSING_EMPLOYEE"}, preview.Entries[1].Flags)
+		assert.Nil(t, preview.Entries[1].Payslip, "Missing employee has no payslip")
+
+		assert.Equal(t, newID, preview.Entries[2].UserID)
+		assert.Equal(t, []string{"NEW_EMPLOYEE", "ZERO_PAY"}, preview.Entries[2].Flags)
+	})
+
+	t.Run("Preview Threshold", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/preview?threshold_percent=60", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected preview to succeed")
+
+		var preview dto.PayrollPreviewDto
+		decodeData(t, response.Data, &preview)
+		assert.Equal(t, 2, preview.FlaggedCount, "Change below threshold should not be flagged")
+		assert.Empty(t, preview.Entries[0].Flags, "Raise should not be flagged")
+	})
+
+	t.Run("Preview Does Not Persist", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Payroll should still be open after previews")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/preview", julyID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusConflict, status, "Rolled payroll cannot be previewed")
+	})
+}
@@ -0,0 +1,192 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayslipArchive(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	salary := 5000000
+	birthDate := time.Date(1990, 3, 25, 0, 0, 0, 0, time.UTC)
+	employee, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+		Username: "employee-archive",
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+			BirthDate:     &birthDate,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+	require.NotNil(t, employee.GetPayslipPassword(), "Employee with a birth date should have a password")
+	assert.Equal(t, "25031990", *employee.GetPayslipPassword(), "Password should be the birth date")
+
+	// every archived payslip is protected, so everyone on the payroll needs a birth date
+	otherEmployee, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+		Username: "employee-archive-other",
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+			BirthDate:     &birthDate,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+	employeeToken, err := utils.GenerateToken(testApp.Config.Auth.JwtSecret, &entity.AuthTokenPayload{
+		ID:   *otherEmployee.Id,
+		Role: otherEmployee.Role,
+	})
+	require.NoError(t, err, "Failed to generate employee token")
+
+	createPayroll := func(name string) uint {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      name,
+			StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+		return *payroll.ID
+	}
+
+	payrollID := createPayroll("June 2025")
+
+	t.Run("Not Rolled Payroll", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/archive", payrollID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Only rolled payrolls can be archived")
+	})
+
+	status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	expectedFile := fmt.Sprintf("payslip-%d-employee-archive.pdf", payrollID)
+
+	assertArchive := func(t *testing.T, body []byte) {
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err, "Body should be a zip archive")
+
+		files := map[string][]byte{}
+		for _, file := range archive.File {
+			reader, err := file.Open()
+			require.NoError(t, err, "Failed to open archived file")
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err, "Failed to read archived file")
+			reader.Close()
+			files[file.Name] = content
+		}
+
+		require.Contains(t, files, expectedFile, "Archive should contain the employee payslip")
+		assert.True(t, bytes.HasPrefix(files[expectedFile], []byte("%PDF-")), "Archived payslip should be a pdf document")
+		assert.True(t, bytes.Contains(files[expectedFile], []byte("/Encrypt")), "Archived payslip should be protected")
+	}
+
+	t.Run("Protected Single Payslip", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("POST", fmt.Sprintf("/payrolls/%d/payslips/pdf?user_id=%d&protected=true", payrollID, *employee.Id), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected pdf to render")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+		assert.True(t, bytes.Contains(body, []byte("/Encrypt")), "Payslip should be protected")
+	})
+
+	t.Run("Streamed Archive", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("POST", fmt.Sprintf("/payrolls/%d/payslips/archive?locale=en", payrollID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected archive to stream")
+		assert.Equal(t, "application/zip", resp.Header.Get(fiber.HeaderContentType), "Expected a zip response")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+		assertArchive(t, body)
+	})
+
+	t.Run("Background Archive", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/archive?async=true", payrollID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusAccepted, status, "Expected archive job to be accepted")
+
+		var job dto.PayslipArchiveJobDto
+		decodeData(t, response.Data, &job)
+		require.NotNil(t, job.ID, "Job should have an ID")
+
+		require.Eventually(t, func() bool {
+			status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payslip-archives/%d", *job.ID), nil, testApp.AdminToken)
+			require.Equal(t, fiber.StatusOK, status, "Expected job status to be returned")
+			decodeData(t, response.Data, &job)
+			return job.Status == string(entity.PayslipArchiveStatusDone) || job.Status == string(entity.PayslipArchiveStatusFailed)
+		}, 10*time.Second, 100*time.Millisecond, "Archive job should finish")
+
+		require.Equal(t, string(entity.PayslipArchiveStatusDone), job.Status, "Archive job should succeed")
+		assert.Equal(t, job.TotalCount, job.ProcessedCount, "Every payslip should be processed")
+
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/payslip-archives/%d/download", *job.ID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected archive download")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+		assertArchive(t, body)
+	})
+
+	t.Run("Employee Cannot Archive", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/archive", payrollID), nil, employeeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not archive payslips")
+	})
+
+	t.Run("Missing Birth Date", func(t *testing.T) {
+		noBirthDateID, _ := testApp.createEmployee(t, "employee-archive-no-birth-date", 5000000)
+
+		julyID := createPayroll("July 2025")
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/pdf?user_id=%d&protected=true", julyID, noBirthDateID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Payslip without a birth date cannot be protected")
+
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/archive", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusUnprocessableEntity, status, "Archive should be refused before streaming")
+
+		var missingUserIDs []uint
+		decodeData(t, response.Data, &missingUserIDs)
+		assert.Equal(t, []uint{noBirthDateID}, missingUserIDs, "Only the employee without a birth date should be listed")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/archive?async=true", julyID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Background archive should be refused too")
+	})
+}
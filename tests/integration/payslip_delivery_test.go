@@ -0,0 +1,245 @@
+package integration
+
+import (
+	"bufio"
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is a minimal SMTP server keeping the received messages, failing the recipients with the queued replies first
+type smtpSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+	failures []string
+}
+
+func startSmtpSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "Failed to start smtp sink")
+
+	sink := &smtpSink{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+
+	return sink
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) failNext(replies ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, replies...)
+}
+
+func (s *smtpSink) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.messages...)
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 sink ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(command, "MAIL FROM"), strings.HasPrefix(command, "RSET"), strings.HasPrefix(command, "NOOP"):
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO"):
+			s.mu.Lock()
+			var failure string
+			if len(s.failures) > 0 {
+				failure, s.failures = s.failures[0], s.failures[1:]
+			}
+			s.mu.Unlock()
+
+			if failure != "" {
+				reply(failure)
+			} else {
+				reply("250 OK")
+			}
+		case command == "DATA":
+			reply("354 go ahead")
+
+			var message strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				message.WriteString(dataLine)
+			}
+
+			s.mu.Lock()
+			s.messages = append(s.messages, message.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestPayslipDelivery(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	sink := startSmtpSink(t)
+	testApp.Config.Smtp.Port = sink.port()
+	testApp.Config.Smtp.PayslipOnRoll = true
+
+	salary := 5000000
+	email := "employee-mail@test.example"
+	birthDate := time.Date(1990, 3, 25, 0, 0, 0, 0, time.UTC)
+	employee, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+		Username: "employee-mail",
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+			BirthDate:     &birthDate,
+			Email:         &email,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+	employeeID := *employee.Id
+
+	noEmailID, employeeToken := testApp.createEmployee(t, "employee-no-mail", 5000000)
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	payrollID := *payroll.ID
+
+	t.Run("Not Rolled Payroll", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/deliveries", payrollID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Payslips of an open payroll should not be sent")
+	})
+
+	getDeliveries := func(t *testing.T) map[uint]dto.PayslipDeliveryDto {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/%d/payslips/deliveries", payrollID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected deliveries to be returned")
+
+		var deliveries []dto.PayslipDeliveryDto
+		decodeData(t, response.Data, &deliveries)
+
+		byUser := map[uint]dto.PayslipDeliveryDto{}
+		for _, delivery := range deliveries {
+			byUser[delivery.UserID] = delivery
+		}
+		return byUser
+	}
+
+	// the first attempt hits a transient failure and is retried
+	sink.failNext("451 try again later")
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	t.Run("Delivered On Roll", func(t *testing.T) {
+		var deliveries map[uint]dto.PayslipDeliveryDto
+		require.Eventually(t, func() bool {
+			deliveries = getDeliveries(t)
+			return deliveries[employeeID].Status != string(entity.PayslipDeliveryStatusPending) &&
+				deliveries[noEmailID].Status != string(entity.PayslipDeliveryStatusPending)
+		}, 10*time.Second, 100*time.Millisecond, "Deliveries should finish")
+
+		assert.Equal(t, string(entity.PayslipDeliveryStatusSent), deliveries[employeeID].Status, "Employee payslip should be sent")
+		assert.Equal(t, 2, deliveries[employeeID].Attempts, "Transient failure should be retried")
+		assert.Equal(t, email, *deliveries[employeeID].Email, "Delivery should record the recipient")
+		assert.Equal(t, string(entity.PayslipDeliveryStatusSkipped), deliveries[noEmailID].Status, "Employee without email should be skipped")
+
+		messages := sink.received()
+		require.Len(t, messages, 1, "Sink should receive one message")
+		assert.Contains(t, messages[0], "To: "+email, "Message should be addressed to the employee")
+		assert.Contains(t, messages[0], fmt.Sprintf(`filename=payslip-%d-employee-mail.pdf`, payrollID), "Message should attach the payslip")
+	})
+
+	t.Run("Resend Payslip", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/deliveries/%d/resend", payrollID, employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected resend to succeed")
+
+		var delivery dto.PayslipDeliveryDto
+		decodeData(t, response.Data, &delivery)
+		assert.Equal(t, string(entity.PayslipDeliveryStatusSent), delivery.Status, "Resend should be sent")
+		assert.Equal(t, 3, delivery.Attempts, "Resend should count as another attempt")
+		assert.Len(t, sink.received(), 2, "Sink should receive the resent message")
+	})
+
+	t.Run("Permanent Failure", func(t *testing.T) {
+		sink.failNext("550 mailbox unavailable")
+
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/deliveries/%d/resend", payrollID, employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected resend to return the delivery")
+
+		var delivery dto.PayslipDeliveryDto
+		decodeData(t, response.Data, &delivery)
+		assert.Equal(t, string(entity.PayslipDeliveryStatusFailed), delivery.Status, "Permanent failure should fail the delivery")
+		assert.Equal(t, 4, delivery.Attempts, "Permanent failure should not be retried")
+		require.NotNil(t, delivery.LastError, "Failure should be recorded")
+		assert.Contains(t, *delivery.LastError, "mailbox unavailable", "Failure should keep the server reply")
+	})
+
+	t.Run("Employee Cannot See Deliveries", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/%d/payslips/deliveries", payrollID), nil, employeeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not see deliveries")
+	})
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"bytes"
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayslipPdf(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-pdf", 5000000)
+	otherID, _ := testApp.createEmployee(t, "employee-pdf-other", 5000000)
+
+	status, _ := testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, employeeToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected checkin to succeed")
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	payrollID := *payroll.ID
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	for _, locale := range []string{"id", "en"} {
+		t.Run("Render Payslip "+locale, func(t *testing.T) {
+			req, err := testApp.makeAuthenticatedRequest("POST", fmt.Sprintf("/payrolls/%d/payslips/pdf?user_id=%d&locale=%s", payrollID, employeeID, locale), nil, employeeToken)
+			require.NoError(t, err, "Failed to create request")
+
+			resp, err := testApp.App.Test(req, -1)
+			require.NoError(t, err, "Failed to test request")
+			require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected pdf to render")
+			assert.Equal(t, "application/pdf", resp.Header.Get(fiber.HeaderContentType), "Expected a pdf response")
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err, "Failed to read response body")
+			assert.True(t, bytes.HasPrefix(body, []byte("%PDF-")), "Body should be a pdf document")
+		})
+	}
+
+	t.Run("Invalid Locale", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/pdf?user_id=%d&locale=fr", payrollID, employeeID), nil, employeeToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "Unsupported locale should be rejected")
+	})
+
+	t.Run("Other Employee Payslip", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips/pdf?user_id=%d", payrollID, otherID), nil, employeeToken)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Employees should not render other payslips")
+	})
+}
@@ -0,0 +1,204 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayrollReports(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	createEmployee := func(t *testing.T, username string, department string, costCenter string) uint {
+		salary := 5000000
+		status, response := testApp.doJSONRequest(t, "POST", "/users", dto.CreateUserBodyDto{
+			Username: username,
+			Password: "password123",
+			Role:     "EMPLOYEE",
+			UserInfo: &dto.CreateUserInfoBodyDto{
+				MonthlySalary: &salary,
+				Department:    &department,
+				CostCenter:    &costCenter,
+			},
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected user creation to succeed")
+
+		var user dto.CreateUserResponseDto
+		decodeData(t, response.Data, &user)
+		return *user.Id
+	}
+
+	engineerID := createEmployee(t, "employee-report-engineer", "Engineering", "ENG")
+	analystID := createEmployee(t, "employee-report-analyst", "Engineering", "DATA")
+	marketerID := createEmployee(t, "employee-report-marketer", "Marketing", "MKT")
+	_, employeeToken := testApp.createEmployee(t, "employee-report-other", 5000000)
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+	var payroll dto.PayrollResponseDto
+	decodeData(t, response.Data, &payroll)
+	payrollID := *payroll.ID
+
+	for userID, amount := range map[uint]int{engineerID: 3000000, analystID: 2000000, marketerID: 1500000} {
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", payrollID), dto.CreatePayrollEarningBodyDto{
+			UserID:      userID,
+			Description: "Project allowance",
+			Amount:      amount,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+	}
+
+	getReport := func(t *testing.T, path string) (int, []byte) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/payrolls/%d/reports/%s", payrollID, path), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		return resp.StatusCode, body
+	}
+
+	t.Run("Not Rolled", func(t *testing.T) {
+		status, _ := getReport(t, "register")
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Open payrolls should not be reported")
+	})
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", payrollID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	t.Run("Json Register", func(t *testing.T) {
+		status, body := getReport(t, "register?format=json")
+		require.Equal(t, fiber.StatusOK, status, "Expected json register")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Register should be valid json")
+
+		entriesByUsername := map[string]map[string]any{}
+		for _, entry := range entries {
+			entriesByUsername[entry["username"].(string)] = entry
+		}
+
+		engineer, ok := entriesByUsername["employee-report-engineer"]
+		require.True(t, ok, "Register should list every employee of the payroll")
+		assert.Equal(t, "Engineering", engineer["department"], "Department should be listed")
+		assert.Equal(t, "ENG", engineer["cost_center"], "Cost center should be listed")
+		assert.Equal(t, float64(5000000), engineer["base_salary"], "Base salary should be listed")
+		assert.Equal(t, float64(3000000), engineer["earning"], "Earning should be listed")
+		assert.Equal(t, float64(3000000), engineer["gross_pay"], "Gross pay should match")
+		assert.Equal(t, float64(3000000), engineer["net_pay"], "Net pay should match")
+
+		other, ok := entriesByUsername["employee-report-other"]
+		require.True(t, ok, "Register should list every employee of the payroll")
+		assert.Equal(t, "UNASSIGNED", other["department"], "Employees without a department should be unassigned")
+		assert.Equal(t, "GENERAL", other["cost_center"], "Employees without a cost center use the default")
+	})
+
+	t.Run("Csv Register", func(t *testing.T) {
+		status, body := getReport(t, "register?format=csv")
+		require.Equal(t, fiber.StatusOK, status, "Expected csv register")
+
+		records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err, "Register should be valid csv")
+		assert.Equal(t, "user_id", records[0][0], "Header should come first")
+		assert.Equal(t, "net_pay", records[0][len(records[0])-1], "Net pay should be the last column")
+	})
+
+	t.Run("Xlsx Register", func(t *testing.T) {
+		status, body := getReport(t, "register?format=xlsx")
+		require.Equal(t, fiber.StatusOK, status, "Expected xlsx register")
+
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err, "Workbook should be a valid zip")
+
+		var sheet []byte
+		for _, file := range archive.File {
+			if file.Name == "xl/worksheets/sheet1.xml" {
+				rc, err := file.Open()
+				require.NoError(t, err, "Failed to open sheet")
+				sheet, err = io.ReadAll(rc)
+				require.NoError(t, err, "Failed to read sheet")
+				rc.Close()
+			}
+		}
+		require.NotNil(t, sheet, "Workbook should hold the register sheet")
+		assert.Contains(t, string(sheet), "employee-report-engineer", "Sheet should list the employees")
+	})
+
+	t.Run("Cost Report By Department", func(t *testing.T) {
+		status, body := getReport(t, "cost?group_by=department&format=json")
+		require.Equal(t, fiber.StatusOK, status, "Expected cost report")
+
+		var groups []map[string]any
+		require.NoError(t, json.Unmarshal(body, &groups), "Cost report should be valid json")
+
+		groupsByName := map[string]map[string]any{}
+		for _, group := range groups {
+			groupsByName[group["group"].(string)] = group
+		}
+
+		require.Contains(t, groupsByName, "Engineering", "Engineering should be reported")
+		assert.Equal(t, float64(2), groupsByName["Engineering"]["employee_count"], "Engineering should count two employees")
+		assert.Equal(t, float64(5000000), groupsByName["Engineering"]["gross_pay"], "Engineering gross pay should match")
+		require.Contains(t, groupsByName, "Marketing", "Marketing should be reported")
+		assert.Equal(t, float64(1500000), groupsByName["Marketing"]["net_pay"], "Marketing net pay should match")
+	})
+
+	t.Run("Cost Report By Cost Center", func(t *testing.T) {
+		status, body := getReport(t, "cost?group_by=cost_center&format=csv")
+		require.Equal(t, fiber.StatusOK, status, "Expected cost report")
+
+		records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err, "Cost report should be valid csv")
+
+		groups := map[string][]string{}
+		for _, record := range records[1:] {
+			groups[record[0]] = record
+		}
+		require.Contains(t, groups, "DATA", "Data cost center should be reported")
+		assert.Equal(t, "2000000", groups["DATA"][4], "Data gross pay should match")
+	})
+
+	t.Run("Invalid Queries", func(t *testing.T) {
+		status, _ := getReport(t, "register?format=pdf")
+		assert.Equal(t, fiber.StatusBadRequest, status, "Unsupported formats should be rejected")
+
+		status, _ = getReport(t, "cost?group_by=team")
+		assert.Equal(t, fiber.StatusBadRequest, status, "Unsupported groupings should be rejected")
+	})
+
+	t.Run("Employee Forbidden", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/%d/reports/register", payrollID), nil, employeeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not read the register")
+	})
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetroAdjustment(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-retro", 5000000)
+
+	// A full day is paid the max working duration, 1/22 of the monthly salary
+	status, _ := testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, employeeToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected checkin to succeed")
+
+	now = time.Date(2025, 6, 16, 17, 0, 0, 0, time.Local)
+	status, _ = testApp.doJSONRequest(t, "POST", "/attendances/checkout", nil, employeeToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected checkout to succeed")
+
+	createPayroll := func(t *testing.T, name string, startedAt time.Time, endedAt time.Time) uint {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      name,
+			StartedAt: startedAt,
+			EndedAt:   endedAt,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+		return *payroll.ID
+	}
+
+	juneID := createPayroll(t, "June 2025", time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local))
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", juneID), nil, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+	// The raise arrives after June is rolled but is effective since June 1st
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/users/%d/salaries", employeeID), dto.ChangeSalaryBodyDto{
+		MonthlySalary: 6000000,
+		EffectiveAt:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected salary change to succeed")
+
+	t.Run("Rolled Payslip Is Frozen", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", juneID, employeeID), nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		assert.Equal(t, 5000000, payslip.Salary, "Rolled payslip should keep the salary it was rolled with")
+	})
+
+	julyID := createPayroll(t, "July 2025", time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 7, 31, 23, 59, 59, 0, time.Local))
+
+	t.Run("Detect Retro Adjustment", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/retro-adjustments/detect", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected detection to succeed")
+
+		var adjustments []dto.PayrollRetroAdjustmentResponseDto
+		decodeData(t, response.Data, &adjustments)
+		require.Len(t, adjustments, 1, "Only the raised employee should be adjusted")
+		assert.Equal(t, employeeID, adjustments[0].UserID, "Adjustment should belong to the employee")
+		assert.Equal(t, juneID, adjustments[0].SourcePayrollID, "Adjustment should link back to June")
+		assert.InDelta(t, 1000000/22, adjustments[0].Amount, 1, "Adjustment should pay the raise for the worked day")
+
+		// Detecting again must not carry the same delta twice
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/retro-adjustments/detect", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected detection to succeed")
+		decodeData(t, response.Data, &adjustments)
+		assert.Len(t, adjustments, 0, "No new adjustment should be detected")
+	})
+
+	t.Run("Adjustment On Next Payslip", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", julyID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", julyID, employeeID), nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		assert.Equal(t, 6000000, payslip.Salary, "July should use the raised salary")
+		require.NotNil(t, payslip.RetroAdjustment, "Payslip should carry retro adjustments")
+		require.Len(t, payslip.RetroAdjustment.Details, 1, "Payslip should carry the June adjustment")
+		assert.Equal(t, juneID, payslip.RetroAdjustment.Details[0].SourcePayrollID, "Adjustment should link back to June")
+		assert.InDelta(t, float32(1000000/22), payslip.TakeHomePay, 1, "July only pays the retro adjustment")
+	})
+}
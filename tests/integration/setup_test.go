@@ -5,22 +5,41 @@ import (
 	"context"
 	"d-payroll/config"
 	"d-payroll/controller/http"
+	"d-payroll/controller/job"
 	"d-payroll/entity"
+	emailnotifier "d-payroll/notifier/email"
+	pdfrenderer "d-payroll/renderer/pdf"
 	repository "d-payroll/repository/db"
 	attendanceservice "d-payroll/service/attendance"
+	attendancedeviceservice "d-payroll/service/attendancedevice"
 	authservice "d-payroll/service/auth"
+	disbursementservice "d-payroll/service/disbursement"
+	journalservice "d-payroll/service/journal"
+	loanservice "d-payroll/service/loan"
+	notificationservice "d-payroll/service/notification"
 	overtimeservice "d-payroll/service/overtime"
 	payrollservice "d-payroll/service/payroll"
+	payslipservice "d-payroll/service/payslip"
 	reimbursementservice "d-payroll/service/reimbursement"
+	reportservice "d-payroll/service/report"
+	shiftservice "d-payroll/service/shift"
+	taxservice "d-payroll/service/tax"
+	thrservice "d-payroll/service/thr"
 	userservice "d-payroll/service/user"
+	worklocationservice "d-payroll/service/worklocation"
 	"d-payroll/utils"
+	"encoding/json"
 	"fmt"
+	"io"
 	nethttp "net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
 	testcontainers "github.com/testcontainers/testcontainers-go"
 	postgrescontainer "github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -35,10 +54,21 @@ type TestApp struct {
 	PgContainer          testcontainers.Container
 	UserService          userservice.UserService
 	AuthService          authservice.AuthService
+	ShiftService         shiftservice.ShiftService
+	WorkLocationService  worklocationservice.WorkLocationService
 	AttendanceService    attendanceservice.AttendanceService
 	OvertimeService      overtimeservice.OvertimeService
 	PayrollService       payrollservice.PayrollService
 	ReimbursementService reimbursementservice.ReimbursementService
+	ThrService           thrservice.ThrService
+	LoanService          loanservice.LoanService
+	PayslipService       payslipservice.PayslipService
+	NotificationService  notificationservice.NotificationService
+	DisbursementService  disbursementservice.DisbursementService
+	JournalService       journalservice.JournalService
+	ReportService        reportservice.ReportService
+	TaxService           taxservice.TaxService
+	AttendanceJob        *job.AttendanceJob
 	AdminToken           string
 	ctx                  context.Context
 }
@@ -74,11 +104,68 @@ func SetupTestApp(t *testing.T) (*TestApp, error) {
 			Password: "test-password",
 		},
 		Overtime: &config.OvertimeConfig{
-			MaxDurationPerDayMilis: 1000 * 60 * 60 * 3,
+			MaxDurationPerDayMilis:  1000 * 60 * 60 * 3,
+			MaxDurationPerWeekMilis: 1000 * 60 * 60 * 14,
+			PlanToleranceMilis:      1000 * 60 * 15,
 		},
 		Payroll: &config.PayrollConfig{
 			DayPerMonthProrate:    22, // preference, could be 20, 30, etc..
 			MaxWorkingMilisPerDay: 8 * 60 * 60 * 1000,
+
+			PreviewChangeThresholdPercent: 20,
+		},
+		Loan: &config.LoanConfig{
+			MinTakeHomePay: 1000000,
+		},
+		Company: &config.CompanyConfig{
+			Name:    "PT Test Company",
+			Address: "Jl. Sudirman No. 1, Jakarta",
+			Phone:   "+62 21 555 0100",
+			Email:   "hr@test.example",
+			Locale:  "id",
+		},
+		Payslip: &config.PayslipConfig{
+			OwnerPassword:       "test-owner-password",
+			ArchiveSyncMaxUsers: 50,
+			ArchiveDir:          filepath.Join(os.TempDir(), "d-payroll-payslip-archives"),
+		},
+		Smtp: &config.SmtpConfig{
+			Host:            "127.0.0.1",
+			Port:            1025,
+			From:            "payroll@test.example",
+			MaxAttempts:     3,
+			RetryDelayMilis: 10,
+		},
+		Disbursement: &config.DisbursementConfig{
+			CorporateID:        "TESTCORP01",
+			DebitBankCode:      "BCA",
+			DebitAccountNumber: "0123456789",
+			DebitAccountName:   "PT Test Company",
+		},
+		Journal: &config.JournalConfig{
+			SalaryExpenseAccount:        "6101",
+			OvertimeExpenseAccount:      "6102",
+			ReimbursementExpenseAccount: "6103",
+			LoanReceivableAccount:       "1105",
+			NetSalaryPayableAccount:     "2101",
+			DefaultCostCenter:           "GENERAL",
+			Currency:                    "IDR",
+		},
+		Tax: &config.TaxConfig{
+			Npwp:       "012345678901000",
+			SignerName: "Siti Rahayu",
+			SignerNpwp: "098765432109000",
+		},
+		Attendance: &config.AttendanceConfig{
+			WorkStartTime:   "09:00",
+			WorkEndTime:     "17:00",
+			GraceMinutes:    15,
+			MaxSessionHours: 16,
+			DefaultTimezone: "Local",
+
+			AutoClosePolicy:        "REQUIRE_CORRECTION",
+			AutoCloseCutoffMinutes: 120,
+			AutoCloseLookbackDays:  7,
 		},
 	}
 
@@ -105,14 +192,37 @@ func SetupTestApp(t *testing.T) (*TestApp, error) {
 	reimbursementDB := repository.NewReimbursementDB(db.DB)
 	overtimeDB := repository.NewOvertimeDB(db.DB)
 	payrollDB := repository.NewPayrollDB(db.DB)
+	loanDB := repository.NewLoanDB(db.DB)
+	payslipDB := repository.NewPayslipDB(db.DB)
+	shiftDB := repository.NewShiftDB(db.DB)
+	workLocationDB := repository.NewWorkLocationDB(db.DB)
+	attendanceDeviceDB := repository.NewAttendanceDeviceDB(db.DB)
+
+	// Initialize renderers
+	payslipRenderer := pdfrenderer.NewPayslipRenderer(cfg)
+	taxCertificateRenderer := pdfrenderer.NewTaxCertificateRenderer(cfg)
+
+	// Initialize notifiers
+	mailer := emailnotifier.NewSmtpMailer(cfg)
 
 	// Initialize services
 	userSvc := userservice.NewUserService(userDB)
 	authSvc := authservice.NewAuthService(cfg, userSvc)
-	attendanceSvc := attendanceservice.NewAttendanceService(attendanceDB)
+	shiftSvc := shiftservice.NewShiftService(cfg, shiftDB, userSvc)
+	workLocationSvc := worklocationservice.NewWorkLocationService(cfg, workLocationDB, userSvc)
+	attendanceSvc := attendanceservice.NewAttendanceService(cfg, attendanceDB, shiftSvc, workLocationSvc, userSvc)
+	attendanceDeviceSvc := attendancedeviceservice.NewAttendanceDeviceService(attendanceDeviceDB, attendanceSvc)
 	reimbursementSvc := reimbursementservice.NewReimbursementService(reimbursementDB)
 	overtimeSvc := overtimeservice.NewOvertimeService(cfg, overtimeDB, attendanceSvc)
-	payrollSvc := payrollservice.NewPayrollService(cfg, payrollDB, userSvc, attendanceSvc, reimbursementSvc, overtimeSvc)
+	loanSvc := loanservice.NewLoanService(cfg, loanDB, userSvc)
+	payrollSvc := payrollservice.NewPayrollService(cfg, payrollDB, userSvc, attendanceSvc, reimbursementSvc, overtimeSvc, loanSvc)
+	thrSvc := thrservice.NewThrService(userSvc, payrollSvc)
+	payslipSvc := payslipservice.NewPayslipService(cfg, payslipRenderer, payslipDB, payrollSvc, userSvc)
+	notificationSvc := notificationservice.NewNotificationService(cfg, mailer, payslipDB, payslipSvc, payrollSvc, userSvc)
+	disbursementSvc := disbursementservice.NewDisbursementService(cfg, payrollSvc, userSvc)
+	journalSvc := journalservice.NewJournalService(cfg, payrollSvc, userSvc)
+	reportSvc := reportservice.NewReportService(cfg, payrollSvc, attendanceSvc, userSvc)
+	taxSvc := taxservice.NewTaxService(cfg, taxCertificateRenderer, payrollSvc, userSvc)
 
 	// Initialize HTTP app
 	httpApp := http.NewHttpApp(cfg)
@@ -120,9 +230,23 @@ func SetupTestApp(t *testing.T) (*TestApp, error) {
 	http.NewUserHttp(httpApp, userSvc)
 	http.NewAuthHttp(httpApp, authSvc)
 	http.NewAttendanceHttp(httpApp, attendanceSvc)
+	http.NewAttendanceDeviceHttp(httpApp, attendanceDeviceSvc)
+	http.NewShiftHttp(httpApp, shiftSvc)
+	http.NewWorkLocationHttp(httpApp, workLocationSvc)
 	http.NewReimbursementHttp(httpApp, reimbursementSvc)
 	http.NewOvertimeHttp(httpApp, overtimeSvc)
-	http.NewPayrollHttp(httpApp, payrollSvc)
+	http.NewPayrollHttp(httpApp, payrollSvc, notificationSvc)
+	http.NewThrHttp(httpApp, thrSvc)
+	http.NewLoanHttp(httpApp, loanSvc)
+	http.NewPayslipHttp(httpApp, payslipSvc)
+	http.NewNotificationHttp(httpApp, notificationSvc)
+	http.NewDisbursementHttp(httpApp, disbursementSvc)
+	http.NewJournalHttp(httpApp, journalSvc)
+	http.NewReportHttp(httpApp, reportSvc)
+	http.NewTaxHttp(httpApp, taxSvc)
+
+	// Initialize scheduled jobs, run by the tests themselves
+	attendanceJob := job.NewAttendanceJob(cfg, attendanceSvc, notificationSvc)
 
 	// Create test app
 	testApp := &TestApp{
@@ -132,10 +256,21 @@ func SetupTestApp(t *testing.T) (*TestApp, error) {
 		PgContainer:          postgresContainer,
 		UserService:          userSvc,
 		AuthService:          authSvc,
+		ShiftService:         shiftSvc,
+		WorkLocationService:  workLocationSvc,
 		AttendanceService:    attendanceSvc,
 		OvertimeService:      overtimeSvc,
 		PayrollService:       payrollSvc,
 		ReimbursementService: reimbursementSvc,
+		ThrService:           thrSvc,
+		LoanService:          loanSvc,
+		PayslipService:       payslipSvc,
+		NotificationService:  notificationSvc,
+		DisbursementService:  disbursementSvc,
+		JournalService:       journalSvc,
+		ReportService:        reportSvc,
+		TaxService:           taxSvc,
+		AttendanceJob:        attendanceJob,
 		ctx:                  ctx,
 	}
 
@@ -198,12 +333,14 @@ func startPostgresContainer(ctx context.Context) (testcontainers.Container, stri
 func (app *TestApp) createAdminUser() (string, error) {
 	// Create admin user
 	salary := 5000000
+	birthDate := time.Date(1985, 1, 1, 0, 0, 0, 0, time.UTC)
 	adminUser := &entity.User{
 		Username: "admin",
 		Password: "admin123",
 		Role:     entity.UserRoleAdmin,
 		UserInfo: &entity.UserInfo{
 			MonthlySalary: &salary,
+			BirthDate:     &birthDate,
 		},
 	}
 
@@ -247,6 +384,78 @@ func (app *TestApp) makeAuthenticatedRequest(method, path string, body []byte, t
 	return req, nil
 }
 
+// createEmployee creates an employee user through the service and returns its ID and token
+func (app *TestApp) createEmployee(t *testing.T, username string, salary int) (uint, string) {
+	createdUser, err := app.UserService.CreateUser(app.ctx, &entity.User{
+		Username: username,
+		Password: "password123",
+		Role:     entity.UserRoleEmployee,
+		UserInfo: &entity.UserInfo{
+			MonthlySalary: &salary,
+		},
+	})
+	require.NoError(t, err, "Failed to create employee")
+
+	token, err := utils.GenerateToken(app.Config.Auth.JwtSecret, &entity.AuthTokenPayload{
+		ID:   *createdUser.Id,
+		Role: createdUser.Role,
+	})
+	require.NoError(t, err, "Failed to generate employee token")
+
+	return *createdUser.Id, token
+}
+
+// createFinance creates a finance user through the service and returns its ID and token
+func (app *TestApp) createFinance(t *testing.T, username string) (uint, string) {
+	createdUser, err := app.UserService.CreateUser(app.ctx, &entity.User{
+		Username: username,
+		Password: "password123",
+		Role:     entity.UserRoleFinance,
+		UserInfo: &entity.UserInfo{},
+	})
+	require.NoError(t, err, "Failed to create finance user")
+
+	token, err := utils.GenerateToken(app.Config.Auth.JwtSecret, &entity.AuthTokenPayload{
+		ID:   *createdUser.Id,
+		Role: createdUser.Role,
+	})
+	require.NoError(t, err, "Failed to generate finance token")
+
+	return *createdUser.Id, token
+}
+
+// doJSONRequest performs a request with an optional JSON body and decodes the response envelope
+func (app *TestApp) doJSONRequest(t *testing.T, method, path string, body any, token string) (int, entity.HttpResponse) {
+	var requestBody []byte
+	if body != nil {
+		var err error
+		requestBody, err = json.Marshal(body)
+		require.NoError(t, err, "Failed to marshal request body")
+	}
+
+	req, err := app.makeAuthenticatedRequest(method, path, requestBody, token)
+	require.NoError(t, err, "Failed to create request")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := app.App.Test(req, -1)
+	require.NoError(t, err, "Failed to perform request")
+
+	var response entity.HttpResponse
+	responseBody, _ := io.ReadAll(resp.Body)
+	_ = json.Unmarshal(responseBody, &response)
+
+	return resp.StatusCode, response
+}
+
+// decodeData re-decodes the generic response data into the given target
+func decodeData(t *testing.T, data any, target any) {
+	dataJson, err := json.Marshal(data)
+	require.NoError(t, err, "Failed to marshal response data")
+	require.NoError(t, json.Unmarshal(dataJson, target), "Failed to unmarshal response data")
+}
+
 func TestMain(m *testing.M) {
 	// This is where we would do global setup if needed
 	code := m.Run()
@@ -260,15 +469,25 @@ func TestMain(m *testing.M) {
 
 // applyMigrations runs the database migrations from the db/migrations folder
 func applyMigrations(db *gorm.DB) error {
-	// Get the migration files
-	upSqlFile := "../../db/migrations/20250611154246_init_database.up.sql"
-
-	// Read the migration file
-	sqlBytes, err := os.ReadFile(upSqlFile)
+	// Get the migration files, the timestamp prefix keeps them in order
+	upSqlFiles, err := filepath.Glob("../../db/migrations/*.up.sql")
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
+		return fmt.Errorf("failed to list migration files: %w", err)
+	}
+	sort.Strings(upSqlFiles)
+
+	for _, upSqlFile := range upSqlFiles {
+		// Read the migration file
+		sqlBytes, err := os.ReadFile(upSqlFile)
+		if err != nil {
+			return fmt.Errorf("failed to read migration file: %w", err)
+		}
+
+		// Execute the SQL migration
+		if err := db.Exec(string(sqlBytes)).Error; err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", filepath.Base(upSqlFile), err)
+		}
 	}
 
-	// Execute the SQL migration
-	return db.Exec(string(sqlBytes)).Error
+	return nil
 }
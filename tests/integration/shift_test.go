@@ -0,0 +1,323 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShifts(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-shift", 5000000)
+	_, otherToken := testApp.createEmployee(t, "employee-shift-other", 5000000)
+
+	createShift := func(t *testing.T, body dto.CreateShiftBodyDto) uint {
+		status, response := testApp.doJSONRequest(t, "POST", "/shifts", body, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected shift creation to succeed")
+
+		var shift dto.ShiftResponseDto
+		decodeData(t, response.Data, &shift)
+		return *shift.ID
+	}
+
+	getWorkSchedule := func(t *testing.T, date string) dto.WorkScheduleResponseDto {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/work-schedule?date=%s", employeeID, date), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected work schedule to succeed")
+
+		var schedule dto.WorkScheduleResponseDto
+		decodeData(t, response.Data, &schedule)
+		return schedule
+	}
+
+	earlyID := createShift(t, dto.CreateShiftBodyDto{
+		Name:         "Early",
+		StartTime:    "06:00",
+		EndTime:      "14:30",
+		BreakMinutes: 30,
+		GraceMinutes: 10,
+	})
+	nightID := createShift(t, dto.CreateShiftBodyDto{
+		Name:         "Night",
+		StartTime:    "22:00",
+		EndTime:      "06:00",
+		BreakMinutes: 60,
+	})
+
+	t.Run("Invalid Shift", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", "/shifts", dto.CreateShiftBodyDto{
+			Name:      "Invalid",
+			StartTime: "25:00",
+			EndTime:   "06:00",
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "Clock times should be HH:MM")
+
+		status, _ = testApp.doJSONRequest(t, "POST", "/shifts", dto.CreateShiftBodyDto{
+			Name:         "Invalid",
+			StartTime:    "09:00",
+			EndTime:      "10:00",
+			BreakMinutes: 60,
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "Breaks should be shorter than the shift")
+	})
+
+	t.Run("Invalid Rotation", func(t *testing.T) {
+		unknownID := uint(9999)
+		status, _ := testApp.doJSONRequest(t, "POST", "/shift-rotations", dto.CreateShiftRotationBodyDto{
+			Name:     "Unknown",
+			ShiftIDs: []*uint{&unknownID},
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusNotFound, status, "Rotations should only use existing shifts")
+	})
+
+	// two early days and a day off, from Monday
+	status, response := testApp.doJSONRequest(t, "POST", "/shift-rotations", dto.CreateShiftRotationBodyDto{
+		Name:     "Early 2-1",
+		ShiftIDs: []*uint{&earlyID, &earlyID, nil},
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected rotation creation to succeed")
+
+	var rotation dto.ShiftRotationResponseDto
+	decodeData(t, response.Data, &rotation)
+	require.Len(t, rotation.Days, 3, "Every day of the rotation should be stored")
+	assert.Equal(t, "Early", rotation.Days[0].Shift.Name, "Days should carry their shift")
+	assert.Nil(t, rotation.Days[2].ShiftID, "Days without shift should be days off")
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/users/%d/shift-rotations", employeeID), dto.AssignShiftRotationBodyDto{
+		RotationID:  *rotation.ID,
+		EffectiveAt: time.Date(2025, 6, 16, 0, 0, 0, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected rotation assignment to succeed")
+
+	t.Run("Work Schedule", func(t *testing.T) {
+		schedule := getWorkSchedule(t, "2025-06-13")
+		assert.Nil(t, schedule.ShiftID, "Days before the assignment should follow the company working day")
+		assert.Equal(t, 9, schedule.StartAt.Hour(), "Company working day should start at 9")
+
+		schedule = getWorkSchedule(t, "2025-06-16")
+		require.NotNil(t, schedule.ShiftID, "First day should follow the rotation")
+		assert.Equal(t, earlyID, *schedule.ShiftID, "First day should be an early shift")
+		assert.Equal(t, 6, schedule.StartAt.Hour(), "Early shift should start at 6")
+
+		assert.True(t, getWorkSchedule(t, "2025-06-18").DayOff, "Third day should be off")
+		assert.False(t, getWorkSchedule(t, "2025-06-19").DayOff, "Rotation should start over")
+		assert.True(t, getWorkSchedule(t, "2025-06-21").DayOff, "Rotation should ignore the weekends")
+		assert.False(t, getWorkSchedule(t, "2025-06-22").DayOff, "Rotation should schedule the weekends")
+
+		status, _ := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/work-schedule", employeeID), nil, otherToken)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Employees should only read their own schedule")
+	})
+
+	t.Run("Cross Midnight Shift", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/shift-rotations", dto.CreateShiftRotationBodyDto{
+			Name:     "Night",
+			ShiftIDs: []*uint{&nightID},
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected rotation creation to succeed")
+
+		var nightRotation dto.ShiftRotationResponseDto
+		decodeData(t, response.Data, &nightRotation)
+
+		nightWorkerID, _ := testApp.createEmployee(t, "employee-shift-night", 5000000)
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/users/%d/shift-rotations", nightWorkerID), dto.AssignShiftRotationBodyDto{
+			RotationID:  *nightRotation.ID,
+			EffectiveAt: time.Date(2025, 6, 16, 0, 0, 0, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected rotation assignment to succeed")
+
+		status, response = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/users/%d/work-schedule?date=2025-06-16", nightWorkerID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected work schedule to succeed")
+
+		var schedule dto.WorkScheduleResponseDto
+		decodeData(t, response.Data, &schedule)
+		assert.Equal(t, time.Date(2025, 6, 16, 22, 0, 0, 0, time.Local), schedule.StartAt.Local(), "Night shift should start in the evening")
+		assert.Equal(t, time.Date(2025, 6, 17, 6, 0, 0, 0, time.Local), schedule.EndAt.Local(), "Night shift should end the next morning")
+	})
+
+	t.Run("Attendance Against Shift", func(t *testing.T) {
+		now = time.Date(2025, 6, 16, 6, 20, 0, 0, time.Local)
+		status, _ := testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected check in to succeed")
+
+		now = time.Date(2025, 6, 16, 15, 30, 0, 0, time.Local)
+		status, _ = testApp.doJSONRequest(t, "POST", "/attendances/checkout", nil, employeeToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected checkout to succeed")
+
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/attendances/report?user_id=%d&start_date=2025-06-16&end_date=2025-06-16", employeeID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected attendance report")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+		require.Len(t, entries, 1, "Only the requested employee should be listed")
+		assert.Equal(t, float64(1), entries[0]["late_arrivals"], "Lateness should follow the shift start")
+		assert.Equal(t, float64(20), entries[0]["late_minutes"], "Lateness should be measured from the shift start")
+		assert.Equal(t, float64(0), entries[0]["early_departures"], "Leaving after the shift end should not be early")
+
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "June 2025",
+			StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *payroll.ID, employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		require.NotNil(t, payslip.Attendance, "Payslip should pay attendance")
+		assert.Equal(t, 8*60*60*1000, payslip.Attendance.TotalDurationMilis, "Attendance should be paid up to the shift working duration")
+	})
+
+	t.Run("Checkin On Day Off", func(t *testing.T) {
+		now = time.Date(2025, 6, 18, 6, 0, 0, 0, time.Local)
+		status, _ := testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, employeeToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Employees should not check in on a day off")
+
+		now = time.Date(2025, 6, 22, 6, 0, 0, 0, time.Local)
+		status, _ = testApp.doJSONRequest(t, "POST", "/attendances/checkin", nil, employeeToken)
+		assert.Equal(t, fiber.StatusOK, status, "Employees should check in on a weekend shift")
+	})
+}
+
+func TestNightShiftAttendance(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	employeeID, employeeToken := testApp.createEmployee(t, "employee-night-shift", 5000000)
+
+	status, response := testApp.doJSONRequest(t, "POST", "/shifts", dto.CreateShiftBodyDto{
+		Name:         "Night",
+		StartTime:    "22:00",
+		EndTime:      "06:00",
+		BreakMinutes: 60,
+		GraceMinutes: 10,
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected shift creation to succeed")
+
+	var shift dto.ShiftResponseDto
+	decodeData(t, response.Data, &shift)
+
+	status, response = testApp.doJSONRequest(t, "POST", "/shift-rotations", dto.CreateShiftRotationBodyDto{
+		Name:     "Night",
+		ShiftIDs: []*uint{shift.ID},
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected rotation creation to succeed")
+
+	var rotation dto.ShiftRotationResponseDto
+	decodeData(t, response.Data, &rotation)
+
+	status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/users/%d/shift-rotations", employeeID), dto.AssignShiftRotationBodyDto{
+		RotationID:  *rotation.ID,
+		EffectiveAt: time.Date(2025, 6, 16, 0, 0, 0, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected rotation assignment to succeed")
+
+	attend := func(t *testing.T, path string, at time.Time) int {
+		now = at
+		status, _ := testApp.doJSONRequest(t, "POST", path, nil, employeeToken)
+		return status
+	}
+
+	t.Run("Session Across Midnight", func(t *testing.T) {
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 22, 5, 0, 0, time.Local)), "Expected check in to succeed")
+		assert.Equal(t, fiber.StatusConflict, attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 5, 0, 0, 0, time.Local)), "The session should still be open after midnight")
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 17, 6, 10, 0, 0, time.Local)), "Checkout should close the session started yesterday")
+		assert.Equal(t, fiber.StatusConflict, attend(t, "/attendances/checkout", time.Date(2025, 6, 17, 6, 20, 0, 0, time.Local)), "The session should already be closed")
+
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 17, 22, 0, 0, 0, time.Local)), "Expected check in to succeed")
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 18, 6, 0, 0, 0, time.Local)), "Expected checkout to succeed")
+	})
+
+	t.Run("Max Session Length", func(t *testing.T) {
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 18, 22, 0, 0, 0, time.Local)), "Expected check in to succeed")
+		assert.Equal(t, fiber.StatusUnprocessableEntity, attend(t, "/attendances/checkout", time.Date(2025, 6, 19, 15, 0, 0, 0, time.Local)), "Sessions should not outlast the max session length")
+	})
+
+	now = time.Date(2025, 6, 19, 15, 0, 0, 0, time.Local)
+
+	t.Run("Report", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/attendances/report?user_id=%d&start_date=2025-06-16&end_date=2025-06-17", employeeID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected attendance report")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+		require.Len(t, entries, 1, "Only the requested employee should be listed")
+		assert.Equal(t, float64(2), entries[0]["present_days"], "Each night should be a single day")
+		assert.Equal(t, float64(0), entries[0]["missing_checkouts"], "Checkouts after midnight should close the night")
+		assert.Equal(t, float64(0), entries[0]["early_departures"], "Checkouts at the shift end should not be early")
+		assert.Equal(t, 14.08, entries[0]["total_hours"], "Nights should be worked across midnight less the break")
+	})
+
+	t.Run("Payslip", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "June 2025",
+			StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *payroll.ID, employeeID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		require.NotNil(t, payslip.Attendance, "Payslip should pay attendance")
+		require.Len(t, payslip.Attendance.Details, 3, "Each night should be paid once")
+		// two full nights of 7 hours, the night never checked out is not paid until it is closed
+		assert.Equal(t, 2*7*60*60*1000, payslip.Attendance.TotalDurationMilis, "Nights should be paid up to the shift working duration")
+	})
+}
@@ -0,0 +1,240 @@
+package integration
+
+import (
+	"bytes"
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxCertificate(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	salary := 5000000
+	fullName := "Budi Santoso"
+	nik := "3171234567890001"
+	npwp := "123456789012000"
+	gender := "MALE"
+	taxStatus := "K/1"
+	position := "Software Engineer"
+	status, response := testApp.doJSONRequest(t, "POST", "/users", dto.CreateUserBodyDto{
+		Username: "employee-tax-engineer",
+		Password: "password123",
+		Role:     "EMPLOYEE",
+		UserInfo: &dto.CreateUserInfoBodyDto{
+			MonthlySalary: &salary,
+			FullName:      &fullName,
+			Nik:           &nik,
+			Npwp:          &npwp,
+			Gender:        &gender,
+			TaxStatus:     &taxStatus,
+			Position:      &position,
+		},
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected user creation to succeed")
+
+	var engineer dto.CreateUserResponseDto
+	decodeData(t, response.Data, &engineer)
+	require.NotNil(t, engineer.UserInfo, "User info should be returned")
+	assert.Equal(t, "K/1", *engineer.UserInfo.TaxStatus, "Tax status should be stored")
+	engineerID := *engineer.Id
+
+	_, otherToken := testApp.createEmployee(t, "employee-tax-other", 5000000)
+
+	status, _ = testApp.doJSONRequest(t, "POST", "/users", dto.CreateUserBodyDto{
+		Username: "employee-tax-invalid",
+		Password: "password123",
+		Role:     "EMPLOYEE",
+		UserInfo: &dto.CreateUserInfoBodyDto{
+			MonthlySalary: &salary,
+			TaxStatus:     &position,
+		},
+	}, testApp.AdminToken)
+	assert.Equal(t, fiber.StatusBadRequest, status, "Unknown tax statuses should be rejected")
+
+	createPayroll := func(t *testing.T, body dto.CreatePayrollBodyDto, amount int) {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", body, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		if body.Type != "" {
+			status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/users", *payroll.ID), dto.AddPayrollUsersBodyDto{
+				UserIDs: []uint{engineerID},
+			}, testApp.AdminToken)
+			require.Equal(t, fiber.StatusOK, status, "Expected user selection to succeed")
+		}
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", *payroll.ID), dto.CreatePayrollEarningBodyDto{
+			UserID:      engineerID,
+			Description: "Allowance",
+			Amount:      amount,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", *payroll.ID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+	}
+
+	createPayroll(t, dto.CreatePayrollBodyDto{
+		Name:      "June 2025",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, 3000000)
+	createPayroll(t, dto.CreatePayrollBodyDto{
+		Name:      "June 2025 Bonus",
+		Type:      "BONUS",
+		StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+		EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+	}, 2000000)
+
+	t.Run("Year To Date Breakdown", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/payrolls/year-to-date?user_id=%d&year=2025", engineerID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected year to date to succeed")
+
+		var yearToDate dto.UserYearToDateResponseDto
+		decodeData(t, response.Data, &yearToDate)
+		assert.Equal(t, 2, yearToDate.PayrollCount, "Both payrolls should be counted")
+		assert.Equal(t, 6, yearToDate.FirstMonth, "First month should be June")
+		assert.Equal(t, 6, yearToDate.LastMonth, "Last month should be June")
+		assert.Equal(t, 3000000, yearToDate.Earning, "Regular earnings should be summed")
+		assert.Equal(t, 2000000, yearToDate.Bonus, "Bonus payrolls should be summed apart")
+		assert.Equal(t, 5000000, yearToDate.GrossPay, "Gross pay should add up")
+		assert.Equal(t, 5000000, yearToDate.TotalTakeHomePay, "Take home pay should add up")
+	})
+
+	t.Run("Certificate", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/tax-certificates/%d?year=2025", engineerID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected tax certificate to succeed")
+
+		var certificate dto.TaxCertificateResponseDto
+		decodeData(t, response.Data, &certificate)
+		assert.Equal(t, fmt.Sprintf("1.1-06.25-%07d", engineerID), certificate.Number, "Number should follow the withholding slip layout")
+		assert.Equal(t, "Budi Santoso", certificate.Name, "Full name should be printed")
+		assert.Equal(t, 3000000, certificate.OtherAllowance, "Earnings should be reported as allowances")
+		assert.Equal(t, 2000000, certificate.Bonus, "Bonus should be reported on its own line")
+		assert.Equal(t, 5000000, certificate.GrossIncome, "Gross income should add up")
+		assert.Equal(t, 250000, certificate.OccupationalCost, "Occupational cost should be five percent of the gross income")
+		assert.Equal(t, 4750000, certificate.NetIncome, "Net income should deduct the occupational cost")
+		assert.Equal(t, 63000000, certificate.Ptkp, "PTKP should follow the tax status")
+		assert.Equal(t, 0, certificate.TaxableIncome, "Income below the PTKP should not be taxable")
+		assert.Equal(t, 0, certificate.TaxDue, "No tax should be due")
+
+		status, _ = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/tax-certificates/%d?year=2024", engineerID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusNotFound, status, "Years without rolled payrolls should have no certificate")
+	})
+
+	t.Run("Certificate Pdf", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/tax-certificates/%d/pdf?year=2025", engineerID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected tax certificate pdf to succeed")
+		assert.Equal(t, "application/pdf", resp.Header.Get(fiber.HeaderContentType), "Content type should be pdf")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+		assert.True(t, bytes.HasPrefix(body, []byte("%PDF")), "Body should be a pdf document")
+	})
+
+	t.Run("Ebupot Csv", func(t *testing.T) {
+		req, err := testApp.makeAuthenticatedRequest("GET", "/tax-certificates/ebupot?year=2025", nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected e-Bupot csv to succeed")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		reader := csv.NewReader(bytes.NewReader(body))
+		reader.Comma = ';'
+		records, err := reader.ReadAll()
+		require.NoError(t, err, "e-Bupot file should be valid csv")
+		assert.Equal(t, "Masa Pajak", records[0][0], "Header should come first")
+
+		var row []string
+		for _, record := range records[1:] {
+			if record[7] == nik {
+				row = record
+			}
+		}
+		require.NotNil(t, row, "Every employee paid in the year should be listed")
+		assert.Equal(t, npwp, row[6], "NPWP should be listed")
+		assert.Equal(t, "K", row[11], "PTKP status should be listed")
+		assert.Equal(t, "1", row[12], "Dependents should be listed")
+		assert.Equal(t, "5000000", row[24], "Gross income should be listed as the eighth amount")
+		assert.Equal(t, "30/06/2025", row[len(row)-1], "Certificate should be dated at the end of the last month")
+	})
+
+	t.Run("Tax Not Withheld", func(t *testing.T) {
+		directorID, _ := testApp.createEmployee(t, "employee-tax-director", 5000000)
+
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "December 2024 Bonus",
+			Type:      "BONUS",
+			StartedAt: time.Date(2024, 12, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2024, 12, 31, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/users", *payroll.ID), dto.AddPayrollUsersBodyDto{
+			UserIDs: []uint{directorID},
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected user selection to succeed")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/earnings", *payroll.ID), dto.CreatePayrollEarningBodyDto{
+			UserID:      directorID,
+			Description: "Bonus",
+			Amount:      200000000,
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected earning creation to succeed")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/roll", *payroll.ID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected roll to succeed")
+
+		status, _ = testApp.doJSONRequest(t, "GET", fmt.Sprintf("/tax-certificates/%d?year=2024", directorID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "A tax due that was not withheld should not be certified")
+
+		status, response = testApp.doJSONRequest(t, "GET", "/tax-certificates/ebupot?year=2024", nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusUnprocessableEntity, status, "The e-Bupot file should not be exported")
+
+		var userIDs []uint
+		decodeData(t, response.Data, &userIDs)
+		assert.Equal(t, []uint{directorID}, userIDs, "The employees owing tax should be listed")
+	})
+
+	t.Run("Employee Access", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "GET", fmt.Sprintf("/tax-certificates/%d?year=2025", engineerID), nil, otherToken)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Employees should only read their own certificate")
+
+		status, _ = testApp.doJSONRequest(t, "GET", "/tax-certificates/ebupot?year=2025", nil, otherToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "Employees should not export the e-Bupot file")
+	})
+}
@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateThr(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	utils.TimeNow = func() time.Time {
+		return time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local) // Monday, March 10, 2025 at 9:00 AM
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	payoutAt := time.Date(2025, 3, 24, 0, 0, 0, 0, time.Local)
+	createEmployee := func(username string, religion entity.Religion, joinedAt time.Time) uint {
+		salary := 12000000
+		createdUser, err := testApp.UserService.CreateUser(testApp.ctx, &entity.User{
+			Username: username,
+			Password: "password123",
+			Role:     entity.UserRoleEmployee,
+			UserInfo: &entity.UserInfo{
+				MonthlySalary: &salary,
+				Religion:      &religion,
+				JoinedAt:      &joinedAt,
+			},
+		})
+		require.NoError(t, err, "Failed to create employee")
+		return *createdUser.Id
+	}
+
+	seniorID := createEmployee("employee-thr-senior", entity.ReligionIslam, time.Date(2020, 1, 6, 0, 0, 0, 0, time.Local))
+	juniorID := createEmployee("employee-thr-junior", entity.ReligionIslam, time.Date(2024, 9, 24, 0, 0, 0, 0, time.Local))
+	newcomerID := createEmployee("employee-thr-newcomer", entity.ReligionIslam, time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local))
+	christianID := createEmployee("employee-thr-christian", entity.ReligionCatholic, time.Date(2020, 1, 6, 0, 0, 0, 0, time.Local))
+	raisedID := createEmployee("employee-thr-raised", entity.ReligionIslam, time.Date(2020, 1, 6, 0, 0, 0, 0, time.Local))
+
+	// the raise is due between the calculation and the payout
+	status, _ := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/users/%d/salaries", raisedID), dto.ChangeSalaryBodyDto{
+		MonthlySalary: 15000000,
+		EffectiveAt:   time.Date(2025, 3, 20, 0, 0, 0, 0, time.Local),
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected salary change to succeed")
+
+	status, response := testApp.doJSONRequest(t, "POST", "/payrolls/thr", dto.CalculateThrBodyDto{
+		Name:     "THR Idul Fitri 2025",
+		Holiday:  "EID_AL_FITR",
+		PayoutAt: payoutAt,
+	}, testApp.AdminToken)
+	require.Equal(t, fiber.StatusOK, status, "Expected THR calculation to succeed")
+
+	var result dto.ThrResultDto
+	decodeData(t, response.Data, &result)
+	assert.Equal(t, "THR", result.Payroll.Type, "Payroll should be a THR payroll")
+	assert.Equal(t, false, *result.Payroll.IsRolled, "THR payroll should be left unrolled for review")
+
+	entries := map[uint]*dto.ThrEntryDto{}
+	for _, entry := range result.Entries {
+		entries[entry.UserID] = entry
+	}
+
+	require.Contains(t, entries, seniorID, "Senior employee should be calculated")
+	assert.Equal(t, 12000000, entries[seniorID].Amount, "Tenure above 12 months gets a full month")
+
+	require.Contains(t, entries, juniorID, "Junior employee should be calculated")
+	assert.Equal(t, 6, entries[juniorID].TenureMonths, "Tenure should count full months")
+	assert.Equal(t, 6000000, entries[juniorID].Amount, "Tenure below 12 months is prorated")
+
+	require.Contains(t, entries, newcomerID, "Newcomer should be listed")
+	require.NotNil(t, entries[newcomerID].ExcludedReason, "Newcomer should be excluded")
+	assert.Equal(t, "TENURE_UNDER_ONE_MONTH", *entries[newcomerID].ExcludedReason, "Newcomer is excluded by tenure")
+
+	require.Contains(t, entries, raisedID, "Raised employee should be calculated")
+	assert.Equal(t, 15000000, entries[raisedID].Amount, "THR should be based on the salary effective at the payout")
+
+	assert.NotContains(t, entries, christianID, "Employees of another holiday should not be calculated")
+	assert.Equal(t, 33000000, result.TotalAmount, "Total should sum the eligible entries")
+
+	t.Run("Review Payslip Before Rolling", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *result.Payroll.ID, juniorID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		assert.Equal(t, float32(6000000), payslip.TakeHomePay, "Payslip should pay the prorated THR")
+
+		status, _ = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *result.Payroll.ID, newcomerID), nil, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusNotFound, status, "Excluded employee should not be in the payroll")
+	})
+}
@@ -0,0 +1,161 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/entity"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceTimezones(t *testing.T) {
+	// Mock time.Now to be Monday at 9am in Jayapura (UTC+9), 7am in Jakarta (UTC+7). Neither observes daylight saving
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	createEmployee := func(t *testing.T, username string, timezone string) (uint, string) {
+		salary := 5000000
+		status, response := testApp.doJSONRequest(t, "POST", "/users", dto.CreateUserBodyDto{
+			Username: username,
+			Password: "password123",
+			Role:     string(entity.UserRoleEmployee),
+			UserInfo: &dto.CreateUserInfoBodyDto{
+				MonthlySalary: &salary,
+				Timezone:      &timezone,
+			},
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected user creation to succeed")
+
+		var user dto.CreateUserResponseDto
+		decodeData(t, response.Data, &user)
+		require.NotNil(t, user.UserInfo, "User info should be returned")
+		require.NotNil(t, user.UserInfo.Timezone, "Timezone should be returned")
+		assert.Equal(t, timezone, *user.UserInfo.Timezone, "Timezone should be stored")
+
+		token, err := utils.GenerateToken(testApp.Config.Auth.JwtSecret, &entity.AuthTokenPayload{
+			ID:   *user.Id,
+			Role: entity.UserRoleEmployee,
+		})
+		require.NoError(t, err, "Failed to generate employee token")
+
+		return *user.Id, token
+	}
+
+	jakartaID, jakartaToken := createEmployee(t, "employee-timezone-jakarta", "Asia/Jakarta")
+	jayapuraID, jayapuraToken := createEmployee(t, "employee-timezone-jayapura", "Asia/Jayapura")
+
+	attend := func(t *testing.T, path string, at time.Time, token string) int {
+		now = at
+		status, _ := testApp.doJSONRequest(t, "POST", path, nil, token)
+		return status
+	}
+
+	t.Run("Invalid Timezone", func(t *testing.T) {
+		salary := 5000000
+		timezone := "Asia/Atlantis"
+		status, _ := testApp.doJSONRequest(t, "POST", "/users", dto.CreateUserBodyDto{
+			Username: "employee-timezone-invalid",
+			Password: "password123",
+			Role:     string(entity.UserRoleEmployee),
+			UserInfo: &dto.CreateUserInfoBodyDto{
+				MonthlySalary: &salary,
+				Timezone:      &timezone,
+			},
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "Unknown timezones should be rejected")
+	})
+
+	t.Run("Day Boundary", func(t *testing.T) {
+		// 09:00 to 17:00 WIT and 08:30 to 17:00 WIB on Monday
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), jayapuraToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 1, 30, 0, 0, time.UTC), jakartaToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 16, 8, 0, 0, 0, time.UTC), jayapuraToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC), jakartaToken))
+
+		// 22:30 WIB on Monday is 00:30 WIT on Tuesday, Jakarta works a second session on Monday
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 15, 30, 0, 0, time.UTC), jakartaToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 16, 16, 0, 0, 0, time.UTC), jakartaToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkin", time.Date(2025, 6, 16, 15, 30, 0, 0, time.UTC), jayapuraToken))
+		require.Equal(t, fiber.StatusOK, attend(t, "/attendances/checkout", time.Date(2025, 6, 16, 23, 30, 0, 0, time.UTC), jayapuraToken))
+	})
+
+	t.Run("Weekend Boundary", func(t *testing.T) {
+		// 23:00 WIB on Friday is 01:00 WIT on Saturday
+		status := attend(t, "/attendances/checkin", time.Date(2025, 6, 20, 16, 0, 0, 0, time.UTC), jakartaToken)
+		assert.Equal(t, fiber.StatusOK, status, "Jakarta should still be on Friday")
+
+		status = attend(t, "/attendances/checkin", time.Date(2025, 6, 20, 16, 0, 0, 0, time.UTC), jayapuraToken)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, status, "Jayapura should already be on the weekend")
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		getReport := func(t *testing.T, userID uint) map[string]any {
+			req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/attendances/report?user_id=%d&start_date=2025-06-16&end_date=2025-06-16", userID), nil, testApp.AdminToken)
+			require.NoError(t, err, "Failed to create request")
+
+			resp, err := testApp.App.Test(req, -1)
+			require.NoError(t, err, "Failed to test request")
+			require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected attendance report")
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err, "Failed to read response body")
+
+			var entries []map[string]any
+			require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+			require.Len(t, entries, 1, "Only the requested employee should be listed")
+			return entries[0]
+		}
+
+		jayapura := getReport(t, jayapuraID)
+		assert.Equal(t, float64(1), jayapura["present_days"], "Monday should be judged in Jayapura")
+		assert.Equal(t, float64(0), jayapura["late_arrivals"], "09:00 WIT should be on time")
+		assert.Equal(t, float64(0), jayapura["early_departures"], "17:00 WIT should not be early")
+		assert.Equal(t, float64(8), jayapura["total_hours"], "Only the Monday session should be worked")
+
+		jakarta := getReport(t, jakartaID)
+		assert.Equal(t, float64(1), jakarta["present_days"], "Monday should be judged in Jakarta")
+		assert.Equal(t, float64(0), jakarta["late_arrivals"], "08:30 WIB should be on time")
+		assert.Equal(t, float64(0), jakarta["early_departures"], "23:00 WIB should not be early")
+		assert.Equal(t, float64(9), jakarta["total_hours"], "The 22:30 WIB session should be worked on Monday")
+	})
+
+	t.Run("Payslip", func(t *testing.T) {
+		status, response := testApp.doJSONRequest(t, "POST", "/payrolls", dto.CreatePayrollBodyDto{
+			Name:      "June 2025",
+			StartedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local),
+			EndedAt:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.Local),
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payroll creation to succeed")
+
+		var payroll dto.PayrollResponseDto
+		decodeData(t, response.Data, &payroll)
+
+		status, response = testApp.doJSONRequest(t, "POST", fmt.Sprintf("/payrolls/%d/payslips?user_id=%d", *payroll.ID, jayapuraID), nil, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected payslip to succeed")
+
+		var payslip dto.PayslipDto
+		decodeData(t, response.Data, &payslip)
+		require.NotNil(t, payslip.Attendance, "Payslip should pay attendance")
+		require.Len(t, payslip.Attendance.Details, 2, "Monday and Tuesday in Jayapura should be paid separately")
+		assert.True(t, payslip.Attendance.Details[0].CheckinAt.Equal(time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)), "Monday should be paid first")
+		assert.Equal(t, 2*8*60*60*1000, payslip.Attendance.TotalDurationMilis, "Both days should be paid in full")
+	})
+}
@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"d-payroll/controller/http/dto"
+	"d-payroll/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkLocations(t *testing.T) {
+	// Mock time.Now to be Monday at 9am
+	originalTimeNow := utils.TimeNow
+	defer func() { utils.TimeNow = originalTimeNow }()
+	now := time.Date(2025, 6, 16, 9, 0, 0, 0, time.Local) // Monday, June 16, 2025 at 9:00 AM
+	utils.TimeNow = func() time.Time {
+		return now
+	}
+
+	// Set up the test app
+	testApp, err := SetupTestApp(t)
+	if err != nil {
+		t.Fatalf("Failed to set up test app: %v", err)
+	}
+	defer testApp.TeardownTestApp()
+
+	officeID, officeToken := testApp.createEmployee(t, "employee-work-location-office", 5000000)
+	plantID, plantToken := testApp.createEmployee(t, "employee-work-location-plant", 5000000)
+	remoteID, remoteToken := testApp.createEmployee(t, "employee-work-location-remote", 5000000)
+
+	float := func(value float64) *float64 { return &value }
+
+	var headquarter, plant dto.WorkLocationResponseDto
+	t.Run("Create Work Locations", func(t *testing.T) {
+		status, _ := testApp.doJSONRequest(t, "POST", "/work-locations", dto.CreateWorkLocationBodyDto{
+			Name:   "Nowhere",
+			Policy: "FLAG",
+		}, testApp.AdminToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "A work location should need an area")
+
+		status, response := testApp.doJSONRequest(t, "POST", "/work-locations", dto.CreateWorkLocationBodyDto{
+			Name:         "Jakarta HQ",
+			Latitude:     float(-6.2088),
+			Longitude:    float(106.8456),
+			RadiusMeters: float(150),
+			WifiBSSIDs:   []string{"AA:BB:CC:DD:EE:FF"},
+			Policy:       "REJECT",
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected work location creation to succeed")
+		decodeData(t, response.Data, &headquarter)
+		assert.Equal(t, []string{"aa:bb:cc:dd:ee:ff"}, headquarter.WifiBSSIDs, "BSSIDs should be stored in lower case")
+
+		status, response = testApp.doJSONRequest(t, "POST", "/work-locations", dto.CreateWorkLocationBodyDto{
+			Name: "Bandung Plant",
+			Polygon: []*dto.GeoPointDto{
+				{Latitude: -6.91, Longitude: 107.61},
+				{Latitude: -6.91, Longitude: 107.63},
+				{Latitude: -6.93, Longitude: 107.63},
+				{Latitude: -6.93, Longitude: 107.61},
+			},
+			Policy: "FLAG",
+		}, testApp.AdminToken)
+		require.Equal(t, fiber.StatusOK, status, "Expected work location creation to succeed")
+		decodeData(t, response.Data, &plant)
+		require.Len(t, plant.Polygon, 4, "Polygon should be stored in order")
+	})
+
+	t.Run("Assign Work Locations", func(t *testing.T) {
+		assign := func(t *testing.T, userID uint, workLocationID *uint, remoteWork bool) int {
+			status, _ := testApp.doJSONRequest(t, "PUT", fmt.Sprintf("/users/%d/work-location", userID), dto.AssignWorkLocationBodyDto{
+				WorkLocationID: workLocationID,
+				RemoteWork:     remoteWork,
+			}, testApp.AdminToken)
+			return status
+		}
+
+		unknownID := uint(999)
+		assert.Equal(t, fiber.StatusNotFound, assign(t, officeID, &unknownID, false), "Unknown work locations should not be assigned")
+
+		require.Equal(t, fiber.StatusOK, assign(t, officeID, headquarter.ID, false))
+		require.Equal(t, fiber.StatusOK, assign(t, plantID, plant.ID, false))
+		require.Equal(t, fiber.StatusOK, assign(t, remoteID, headquarter.ID, true))
+	})
+
+	checkin := func(t *testing.T, body *dto.CheckinBodyDto, token string) (int, dto.AttendanceResponseDto) {
+		status, response := testApp.doJSONRequest(t, "POST", "/attendances/checkin", body, token)
+
+		var attendance dto.AttendanceResponseDto
+		if status == fiber.StatusOK {
+			decodeData(t, response.Data, &attendance)
+		}
+		return status, attendance
+	}
+
+	checkout := func(t *testing.T, token string) {
+		status, _ := testApp.doJSONRequest(t, "POST", "/attendances/checkout", nil, token)
+		require.Equal(t, fiber.StatusOK, status, "Expected checkout to succeed")
+	}
+
+	t.Run("Reject Out Of Area", func(t *testing.T) {
+		status, _ := checkin(t, &dto.CheckinBodyDto{Latitude: float(-6.9175), Longitude: float(107.6191)}, officeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "A check-in far from the office should be rejected")
+
+		status, _ = checkin(t, &dto.CheckinBodyDto{Latitude: float(-6.2088)}, officeToken)
+		assert.Equal(t, fiber.StatusBadRequest, status, "Coordinates should come together")
+
+		status, _ = checkin(t, nil, officeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "A check-in without evidence should be out of the area")
+
+		status, attendance := checkin(t, &dto.CheckinBodyDto{Latitude: float(-6.2090), Longitude: float(106.8457)}, officeToken)
+		require.Equal(t, fiber.StatusOK, status, "A check-in within the radius should be accepted")
+		assert.False(t, attendance.IsOutOfArea, "A check-in within the radius should not be flagged")
+		assert.Equal(t, headquarter.ID, attendance.WorkLocationID, "The work location should be stored")
+		require.NotNil(t, attendance.Latitude, "The coordinates should be stored")
+		assert.Equal(t, -6.2090, *attendance.Latitude, "The coordinates should be stored")
+		checkout(t, officeToken)
+
+		bssid := "aa:bb:cc:dd:ee:ff"
+		status, attendance = checkin(t, &dto.CheckinBodyDto{WifiBSSID: &bssid}, officeToken)
+		require.Equal(t, fiber.StatusOK, status, "A check-in from the office Wi-Fi should be accepted")
+		assert.False(t, attendance.IsOutOfArea, "A check-in from the office Wi-Fi should not be flagged")
+		checkout(t, officeToken)
+
+		status, _ = checkin(t, &dto.CheckinBodyDto{Latitude: float(-6.9175), Longitude: float(107.6191), WifiBSSID: &bssid}, officeToken)
+		assert.Equal(t, fiber.StatusForbidden, status, "The office Wi-Fi should not outweigh coordinates far from the office")
+	})
+
+	t.Run("Flag Out Of Area", func(t *testing.T) {
+		status, attendance := checkin(t, &dto.CheckinBodyDto{Latitude: float(-6.2088), Longitude: float(106.8456)}, plantToken)
+		require.Equal(t, fiber.StatusOK, status, "A check-in out of the plant should be accepted")
+		assert.True(t, attendance.IsOutOfArea, "A check-in out of the plant should be flagged")
+		checkout(t, plantToken)
+
+		status, attendance = checkin(t, &dto.CheckinBodyDto{Latitude: float(-6.92), Longitude: float(107.62)}, plantToken)
+		require.Equal(t, fiber.StatusOK, status, "A check-in in the plant should be accepted")
+		assert.False(t, attendance.IsOutOfArea, "A check-in in the plant should not be flagged")
+		checkout(t, plantToken)
+
+		req, err := testApp.makeAuthenticatedRequest("GET", fmt.Sprintf("/attendances/report?user_id=%d&start_date=2025-06-16&end_date=2025-06-16", plantID), nil, testApp.AdminToken)
+		require.NoError(t, err, "Failed to create request")
+
+		resp, err := testApp.App.Test(req, -1)
+		require.NoError(t, err, "Failed to test request")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "Expected attendance report")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+
+		var entries []map[string]any
+		require.NoError(t, json.Unmarshal(body, &entries), "Report should be valid json")
+		require.Len(t, entries, 1, "Only the requested employee should be listed")
+		assert.Equal(t, float64(1), entries[0]["out_of_area_checkins"], "Flagged check-ins should be reported")
+	})
+
+	t.Run("Remote Work", func(t *testing.T) {
+		status, attendance := checkin(t, &dto.CheckinBodyDto{Latitude: float(-8.6500), Longitude: float(115.2167)}, remoteToken)
+		require.Equal(t, fiber.StatusOK, status, "Remote employees should check in from anywhere")
+		assert.True(t, attendance.IsRemote, "The check-in should be marked remote")
+		assert.False(t, attendance.IsOutOfArea, "Remote employees should never be out of the area")
+	})
+}
@@ -1,22 +1,36 @@
 package utils
 
-import "time"
+import (
+	"time"
+	// the employee zones are loaded on servers without a zoneinfo database too
+	_ "time/tzdata"
+)
 
 var TimeNow = time.Now
 
 func GetStartOfDay() time.Time {
-	now := TimeNow()
-	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return StartOfDay(TimeNow().In(time.Local))
 }
 
 func GetEndOfDay() time.Time {
-	now := TimeNow()
-	return time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, time.Local)
+	return EndOfDay(TimeNow().In(time.Local))
+}
+
+// StartOfDay returns the midnight starting the day of t, in the location of t
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func EndOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
 }
 
 func IsWeekend() bool {
-	t := TimeNow()
+	return IsWeekendAt(TimeNow())
+}
 
+// IsWeekendAt tells whether t falls on a weekend, in the location of t
+func IsWeekendAt(t time.Time) bool {
 	switch t.Weekday() {
 	case time.Saturday, time.Sunday:
 		return true
@@ -24,3 +38,27 @@ func IsWeekend() bool {
 		return false
 	}
 }
+
+// GetFullMonthsBetween returns the number of complete months elapsed from start to end
+func GetFullMonthsBetween(start time.Time, end time.Time) int {
+	if end.Before(start) {
+		return 0
+	}
+
+	months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+	if end.Day() < start.Day() {
+		months--
+	}
+
+	return months
+}
+
+// ParseTimeOfDay parses a HH:MM clock time into its offset from midnight
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "math"
+
+const earthRadiusMeters = 6371000
+
+// DistanceMeters returns the great-circle distance between two coordinates in degrees
+func DistanceMeters(fromLatitude float64, fromLongitude float64, toLatitude float64, toLongitude float64) float64 {
+	fromLat := fromLatitude * math.Pi / 180
+	toLat := toLatitude * math.Pi / 180
+	deltaLat := (toLatitude - fromLatitude) * math.Pi / 180
+	deltaLng := (toLongitude - fromLongitude) * math.Pi / 180
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) + math.Cos(fromLat)*math.Cos(toLat)*math.Sin(deltaLng/2)*math.Sin(deltaLng/2)
+	return 2 * earthRadiusMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// PolygonContains tells whether a coordinate falls in the polygon of the given vertices, by ray casting. The polygon
+// is closed from its last vertex back to the first
+func PolygonContains(latitudes []float64, longitudes []float64, latitude float64, longitude float64) bool {
+	inside := false
+	for i, j := 0, len(latitudes)-1; i < len(latitudes); j, i = i, i+1 {
+		if (latitudes[i] > latitude) != (latitudes[j] > latitude) &&
+			longitude < (longitudes[j]-longitudes[i])*(latitude-latitudes[i])/(latitudes[j]-latitudes[i])+longitudes[i] {
+			inside = !inside
+		}
+	}
+	return inside
+}